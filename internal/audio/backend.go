@@ -30,3 +30,10 @@ type AudioBackend interface {
 	// Playback - unified interface supporting both file paths and readers
 	Play(ctx context.Context, source AudioSource) error
 }
+
+// Warmer is implemented by backends that cache decoded audio, such as the
+// malgo backend, so a soundpack's files can be decoded ahead of playback.
+type Warmer interface {
+	// Warm decodes filePath into the backend's cache without playing it.
+	Warm(ctx context.Context, filePath string) error
+}
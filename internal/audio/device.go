@@ -0,0 +1,45 @@
+package audio
+
+import "strings"
+
+// Device describes a playback device a backend can play through.
+type Device struct {
+	ID      string // backend-specific identifier, stable across runs
+	Name    string // human-readable name
+	Default bool   // the system's default output
+}
+
+// DeviceSelector is implemented by backends that can play through an
+// output device other than the system default.
+type DeviceSelector interface {
+	// SetDevice selects the output device by ID or name. An empty device
+	// means the system default. Call it before the first playback.
+	SetDevice(device string) error
+}
+
+// DeviceLister is implemented by backends that can enumerate the playback
+// devices of the system, such as the malgo backend.
+type DeviceLister interface {
+	Devices() ([]Device, error)
+}
+
+// MatchDevice finds the device the audio_device setting want names: the
+// device whose ID is want, or else the first whose name contains want,
+// ignoring case. An empty want matches nothing.
+func MatchDevice(devices []Device, want string) (Device, bool) {
+	if want == "" {
+		return Device{}, false
+	}
+	for _, d := range devices {
+		if d.ID == want {
+			return d, true
+		}
+	}
+	lower := strings.ToLower(want)
+	for _, d := range devices {
+		if strings.Contains(strings.ToLower(d.Name), lower) {
+			return d, true
+		}
+	}
+	return Device{}, false
+}
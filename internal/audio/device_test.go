@@ -0,0 +1,32 @@
+package audio
+
+import "testing"
+
+func TestMatchDevice(t *testing.T) {
+	devices := []Device{
+		{ID: "alsa_output.pci-0000_00_1f.3.analog-stereo", Name: "Built-in Audio Analog Stereo", Default: true},
+		{ID: "bluez_output.AC_80_0A.1", Name: "WH-1000XM4 Headphones"},
+		{ID: "headphones", Name: "USB Speakers"},
+	}
+	tests := []struct {
+		name   string
+		want   string
+		wantID string
+		found  bool
+	}{
+		{"exact ID", "bluez_output.AC_80_0A.1", "bluez_output.AC_80_0A.1", true},
+		{"name substring ignores case", "wh-1000", "bluez_output.AC_80_0A.1", true},
+		{"ID wins over an earlier name match", "headphones", "headphones", true},
+		{"first name match wins", "audio", "alsa_output.pci-0000_00_1f.3.analog-stereo", true},
+		{"no match", "hdmi", "", false},
+		{"empty matches nothing", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := MatchDevice(devices, tt.want)
+			if ok != tt.found || got.ID != tt.wantID {
+				t.Errorf("MatchDevice(%q) = %q, %v; want %q, %v", tt.want, got.ID, ok, tt.wantID, tt.found)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ type FakeBackend struct {
 	mu        sync.Mutex
 	plays     []FakePlay
 	volume    float32
+	device    string
 	isPlaying bool
 	closed    bool
 }
@@ -28,7 +29,12 @@ type FakeBackend struct {
 // FakePlay records a single Play call.
 type FakePlay struct {
 	SourcePath string // best-effort file path if the source implements FilePather; empty otherwise
+	Text       string // spoken text for speech sources; empty otherwise
 	Volume     float32
+	Device     string      // output device selected with SetDevice; empty = default
+	Loop       bool        // recorded by PlayLoop rather than Play
+	LoopOpts   LoopOptions // PlayLoop options; zero for Play
+	Variation  Variation   // pitch and pan the source asked for
 }
 
 // NewFakeBackend constructs a fresh FakeBackend with default volume 1.0.
@@ -38,23 +44,59 @@ func NewFakeBackend() *FakeBackend {
 
 // Play records the invocation. If the source implements FilePather, the
 // resolved file path is captured. Otherwise SourcePath remains empty.
+// Speech sources record their text instead and are never synthesized.
 func (f *FakeBackend) Play(ctx context.Context, source AudioSource) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 	if f.closed {
 		return ErrBackendClosed
 	}
+	inner := source
+	if vs, ok := source.(*VariedSource); ok {
+		inner = vs.source
+	}
+	if ss, ok := inner.(*SpeechSource); ok {
+		f.plays = append(f.plays, FakePlay{Text: ss.Text(), Volume: f.volume, Device: f.device, Variation: VariationOf(source)})
+		f.isPlaying = true
+		return nil
+	}
 	var path string
 	if fp, ok := source.(FilePather); ok {
 		if p, err := fp.FilePath(); err == nil {
 			path = p
 		}
 	}
-	f.plays = append(f.plays, FakePlay{SourcePath: path, Volume: f.volume})
+	f.plays = append(f.plays, FakePlay{SourcePath: path, Volume: f.volume, Device: f.device, Variation: VariationOf(source)})
 	f.isPlaying = true
 	return nil
 }
 
+// PlayLoop records a looping play and then blocks until ctx ends, the way
+// a real loop would.
+func (f *FakeBackend) PlayLoop(ctx context.Context, source AudioSource, opts LoopOptions) error {
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return ErrBackendClosed
+	}
+	var path string
+	if fp, ok := source.(FilePather); ok {
+		if p, err := fp.FilePath(); err == nil {
+			path = p
+		}
+	}
+	f.plays = append(f.plays, FakePlay{SourcePath: path, Volume: f.volume, Device: f.device, Loop: true, LoopOpts: opts, Variation: VariationOf(source)})
+	f.isPlaying = true
+	f.mu.Unlock()
+
+	<-ctx.Done()
+
+	f.mu.Lock()
+	f.isPlaying = false
+	f.mu.Unlock()
+	return nil
+}
+
 // Stop flips the isPlaying flag to false.
 func (f *FakeBackend) Stop() error {
 	f.mu.Lock()
@@ -97,6 +139,25 @@ func (f *FakeBackend) GetVolume() float32 {
 	return f.volume
 }
 
+// FakeDevices are the playback devices every FakeBackend reports.
+var FakeDevices = []Device{
+	{ID: "fake-speakers", Name: "Fake Speakers", Default: true},
+	{ID: "fake-headphones", Name: "Fake USB Headphones"},
+}
+
+// SetDevice stores the selected output device; later plays record it.
+func (f *FakeBackend) SetDevice(device string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.device = device
+	return nil
+}
+
+// Devices returns FakeDevices.
+func (f *FakeBackend) Devices() ([]Device, error) {
+	return append([]Device(nil), FakeDevices...), nil
+}
+
 // Plays returns a copy of recorded Play invocations under the lock.
 func (f *FakeBackend) Plays() []FakePlay {
 	f.mu.Lock()
@@ -0,0 +1,75 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// LoopOptions shape a looping playback.
+type LoopOptions struct {
+	Fade time.Duration // fade-in at the start and fade-out once stopped (0 = none)
+	Gain float32       // loop level relative to the backend volume (0 = 1.0)
+}
+
+// EffectiveGain returns the loop's relative gain, 1.0 when unset.
+func (o LoopOptions) EffectiveGain() float32 {
+	if o.Gain <= 0 {
+		return 1.0
+	}
+	return o.Gain
+}
+
+// Looper is implemented by backends that can repeat a source seamlessly,
+// such as the malgo backend.
+type Looper interface {
+	// PlayLoop plays source over and over until ctx is cancelled, fading
+	// in at the start and out once ctx ends, then returns nil.
+	PlayLoop(ctx context.Context, source AudioSource, opts LoopOptions) error
+}
+
+// PlayLoop repeats source on backend until ctx is cancelled. Backends that
+// implement Looper loop without gaps and fade; the others replay source
+// back to back and stop abruptly when ctx ends (a system command player is
+// killed mid-sound). Those backends take the loop's gain as a Variation
+// gain on each replay, leaving the backend volume, which the daemon shares
+// with other sounds, alone. Returns nil once ctx ends.
+func PlayLoop(ctx context.Context, backend AudioBackend, source AudioSource, opts LoopOptions) error {
+	if looper, ok := backend.(Looper); ok {
+		return looper.PlayLoop(ctx, source, opts)
+	}
+
+	slog.Debug("backend cannot loop natively; replaying source", "backend_type", fmt.Sprintf("%T", backend))
+	if gain := opts.EffectiveGain(); gain != 1.0 {
+		variation := VariationOf(source)
+		variation.Gain = variation.EffectiveGain() * float64(gain)
+		if vs, ok := source.(*VariedSource); ok {
+			source = vs.source
+		}
+		source = WithVariation(source, variation)
+	}
+
+	for ctx.Err() == nil {
+		started := time.Now()
+		if err := backend.Play(ctx, source); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		// A player that returns at once (an empty sound, say) must not
+		// turn this into a busy loop.
+		if time.Since(started) < minLoopPass {
+			select {
+			case <-ctx.Done():
+			case <-time.After(minLoopPass):
+			}
+		}
+	}
+	return nil
+}
+
+// minLoopPass is the shortest time one replay of a non-looping backend may
+// take before PlayLoop pauses between passes.
+const minLoopPass = 100 * time.Millisecond
@@ -0,0 +1,98 @@
+package audio
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingBackend is a non-looping backend whose Play takes a little while.
+type countingBackend struct {
+	*FakeBackend
+	plays     atomic.Int32
+	variation Variation // of the last source played
+}
+
+func (c *countingBackend) Play(ctx context.Context, source AudioSource) error {
+	c.plays.Add(1)
+	c.variation = VariationOf(source)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(20 * time.Millisecond):
+		return nil
+	}
+}
+
+func TestPlayLoop_ReplaysOnBackendsWithoutLooper(t *testing.T) {
+	backend := &countingBackend{FakeBackend: NewFakeBackend()}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	if err := PlayLoop(ctx, struct{ AudioBackend }{backend}, NewFileSource("/tmp/hum.wav"), LoopOptions{Fade: time.Second}); err != nil {
+		t.Fatalf("PlayLoop: %v", err)
+	}
+	if n := backend.plays.Load(); n < 2 {
+		t.Errorf("expected the source to be replayed, got %d plays", n)
+	}
+	if v := backend.GetVolume(); v != 1.0 {
+		t.Errorf("backend volume should be restored after the loop, got %v", v)
+	}
+}
+
+func TestPlayLoop_GainRidesOnTheSourceWithoutLooper(t *testing.T) {
+	backend := &countingBackend{FakeBackend: NewFakeBackend()}
+	source := WithVariation(NewFileSource("/tmp/hum.wav"), Variation{Pan: 0.5, Gain: 2})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- PlayLoop(ctx, struct{ AudioBackend }{backend}, source, LoopOptions{Gain: 0.25})
+	}()
+
+	// The shared backend volume never moves while the loop plays.
+	for {
+		if v := backend.GetVolume(); v != 1.0 {
+			t.Fatalf("backend volume changed to %v during the loop", v)
+		}
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("PlayLoop: %v", err)
+			}
+			if got := backend.variation; got.Gain != 0.5 || got.Pan != 0.5 {
+				t.Errorf("replayed variation = %+v, want the source's pan with gain 0.5", got)
+			}
+			return
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+func TestPlayLoop_UsesLooper(t *testing.T) {
+	fake := NewFakeBackend()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- PlayLoop(ctx, fake, NewFileSource("/tmp/hum.wav"), LoopOptions{Fade: 2 * time.Second, Gain: 0.3})
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for !fake.IsPlaying() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("PlayLoop: %v", err)
+	}
+
+	plays := fake.Plays()
+	if len(plays) != 1 || !plays[0].Loop || plays[0].LoopOpts.Fade != 2*time.Second || plays[0].SourcePath != "/tmp/hum.wav" {
+		t.Errorf("expected one looping play with a 2s fade, got %+v", plays)
+	}
+	if fake.IsPlaying() {
+		t.Error("loop should stop once ctx ends")
+	}
+}
@@ -0,0 +1,303 @@
+// Package loudness measures how loud a sound is, so the sounds of a pack
+// can be levelled against each other. Integrated loudness follows ITU-R
+// BS.1770 as used by EBU R128: K-weighted mean square over 400 ms blocks
+// with 75% overlap, gated at -70 LUFS absolute and -10 LU relative. RMS
+// and sample peak are reported alongside it.
+//
+// Decoding needs the cgo audio decoders, which register themselves with
+// RegisterDecoder; without them MeasureFile returns ErrNoDecoder.
+package loudness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+)
+
+const (
+	// DefaultTargetLUFS is the EBU R128 programme loudness.
+	DefaultTargetLUFS = -23.0
+
+	// PeakCeilingDBFS is the highest sample peak a gain may push a sound
+	// to. Gains that would boost a sound past it are limited, so levelling
+	// never clips.
+	PeakCeilingDBFS = -1.0
+
+	absoluteGateLUFS = -70.0
+	relativeGateLU   = -10.0
+)
+
+// ErrNoDecoder is returned by MeasureFile when no decoder is registered,
+// which is the case in builds without cgo.
+var ErrNoDecoder = errors.New("no audio decoder available for loudness analysis")
+
+// Measurement is the loudness of one sound. Silence measures -Inf in
+// every field.
+type Measurement struct {
+	Integrated float64 // gated integrated loudness, LUFS
+	RMS        float64 // unweighted RMS over every sample, dBFS
+	Peak       float64 // sample peak, dBFS
+}
+
+// IsSilent reports whether nothing in the sound passed the gates.
+func (m Measurement) IsSilent() bool {
+	return math.IsInf(m.Integrated, -1)
+}
+
+// Audio is a decoded sound: interleaved samples in [-1, 1].
+type Audio struct {
+	Samples    []float32
+	Channels   int
+	SampleRate int
+}
+
+// Decoder decodes the audio file at path for analysis.
+type Decoder func(ctx context.Context, path string) (*Audio, error)
+
+var (
+	decoderMu sync.RWMutex
+	decoder   Decoder
+)
+
+// RegisterDecoder installs the decoder MeasureFile uses. It is called from
+// an init() in the malgo package, so this package needs no cgo.
+func RegisterDecoder(d Decoder) {
+	decoderMu.Lock()
+	defer decoderMu.Unlock()
+	decoder = d
+}
+
+// Available reports whether MeasureFile can decode files.
+func Available() bool {
+	decoderMu.RLock()
+	defer decoderMu.RUnlock()
+	return decoder != nil
+}
+
+// MeasureFile decodes the file at path and measures it.
+func MeasureFile(ctx context.Context, path string) (Measurement, error) {
+	decoderMu.RLock()
+	decode := decoder
+	decoderMu.RUnlock()
+	if decode == nil {
+		return Measurement{}, ErrNoDecoder
+	}
+
+	audio, err := decode(ctx, path)
+	if err != nil {
+		return Measurement{}, err
+	}
+	if audio.Channels <= 0 || audio.SampleRate <= 0 {
+		return Measurement{}, fmt.Errorf("invalid audio layout: %d channels at %d Hz", audio.Channels, audio.SampleRate)
+	}
+	m := Measure(audio.Samples, audio.Channels, audio.SampleRate)
+	slog.Debug("measured sound loudness",
+		"path", path,
+		"integrated_lufs", m.Integrated,
+		"rms_dbfs", m.RMS,
+		"peak_dbfs", m.Peak)
+	return m, nil
+}
+
+// Measure returns the loudness of interleaved samples. Sounds shorter
+// than one 400 ms block, which many UI sounds are, are measured as a
+// single block spanning the whole sound.
+func Measure(samples []float32, channels, sampleRate int) Measurement {
+	silent := Measurement{Integrated: math.Inf(-1), RMS: math.Inf(-1), Peak: math.Inf(-1)}
+	if channels <= 0 || sampleRate <= 0 {
+		return silent
+	}
+	frames := len(samples) / channels
+	if frames == 0 {
+		return silent
+	}
+
+	var peak, squares float64
+	for _, s := range samples[:frames*channels] {
+		v := math.Abs(float64(s))
+		peak = math.Max(peak, v)
+		squares += v * v
+	}
+
+	// Weighted K-filtered energy per 100 ms step; a gating block is four
+	// consecutive steps.
+	step := sampleRate / 10
+	if step < 1 {
+		step = 1
+	}
+	steps := make([]float64, frames/step)
+	var total float64
+	weights := channelWeights(channels)
+	for c := 0; c < channels; c++ {
+		if weights[c] == 0 {
+			continue
+		}
+		filter := newKWeighting(float64(sampleRate))
+		for i := 0; i < frames; i++ {
+			y := filter.process(float64(samples[i*channels+c]))
+			e := weights[c] * y * y
+			total += e
+			if n := i / step; n < len(steps) {
+				steps[n] += e
+			}
+		}
+	}
+
+	var powers []float64
+	for k := 0; k+4 <= len(steps); k++ {
+		powers = append(powers, (steps[k]+steps[k+1]+steps[k+2]+steps[k+3])/float64(4*step))
+	}
+	if len(powers) == 0 {
+		powers = []float64{total / float64(frames)}
+	}
+
+	return Measurement{
+		Integrated: gatedLoudness(powers),
+		RMS:        toDB(math.Sqrt(squares / float64(frames*channels))),
+		Peak:       toDB(peak),
+	}
+}
+
+// GainFor returns the gain in dB that brings m to targetLUFS, rounded to
+// 0.1 dB. A boost is limited so the peak stays under PeakCeilingDBFS;
+// silence gets no gain.
+func GainFor(m Measurement, targetLUFS float64) float64 {
+	if m.IsSilent() {
+		return 0
+	}
+	gain := targetLUFS - m.Integrated
+	if gain > 0 {
+		gain = math.Min(gain, math.Max(PeakCeilingDBFS-m.Peak, 0))
+	}
+	return math.Round(gain*10) / 10
+}
+
+// DBToLinear converts a gain in dB to an amplitude ratio.
+func DBToLinear(db float64) float64 {
+	return math.Pow(10, db/20)
+}
+
+// channelWeights returns the BS.1770 weight of each channel. Mono counts
+// twice: Claudio plays it through both front speakers, so it sounds as
+// loud as the same signal in stereo. Surround channels are weighted
+// 1.41 and the LFE of a 5.1 layout is left out.
+func channelWeights(channels int) []float64 {
+	switch channels {
+	case 1:
+		return []float64{2}
+	case 4:
+		return []float64{1, 1, 1.41, 1.41}
+	case 5:
+		return []float64{1, 1, 1, 1.41, 1.41}
+	case 6:
+		return []float64{1, 1, 1, 0, 1.41, 1.41}
+	}
+	weights := make([]float64, channels)
+	for i := range weights {
+		weights[i] = 1
+	}
+	return weights
+}
+
+// gatedLoudness applies the absolute and relative gates to block powers
+// and returns the loudness of the blocks that pass both.
+func gatedLoudness(powers []float64) float64 {
+	var gated []float64
+	var sum float64
+	for _, p := range powers {
+		if blockLoudness(p) > absoluteGateLUFS {
+			gated = append(gated, p)
+			sum += p
+		}
+	}
+	if len(gated) == 0 {
+		return math.Inf(-1)
+	}
+
+	threshold := blockLoudness(sum/float64(len(gated))) + relativeGateLU
+	sum = 0
+	n := 0
+	for _, p := range gated {
+		if blockLoudness(p) > threshold {
+			sum += p
+			n++
+		}
+	}
+	return blockLoudness(sum / float64(n))
+}
+
+func blockLoudness(power float64) float64 {
+	if power <= 0 {
+		return math.Inf(-1)
+	}
+	return -0.691 + 10*math.Log10(power)
+}
+
+func toDB(amplitude float64) float64 {
+	if amplitude <= 0 {
+		return math.Inf(-1)
+	}
+	return 20 * math.Log10(amplitude)
+}
+
+// biquad is one second-order IIR section in transposed direct form II.
+type biquad struct {
+	b0, b1, b2, a1, a2 float64
+	z1, z2             float64
+}
+
+func (f *biquad) process(x float64) float64 {
+	y := f.b0*x + f.z1
+	f.z1 = f.b1*x - f.a1*y + f.z2
+	f.z2 = f.b2*x - f.a2*y
+	return y
+}
+
+// kWeighting is the BS.1770 pre-filter: a high shelf modelling the head
+// followed by a high-pass. The coefficients are derived for any sample
+// rate from the analogue prototypes, as libebur128 does, and match the
+// standard's tables at 48 kHz.
+type kWeighting struct {
+	shelf, highPass biquad
+}
+
+func newKWeighting(sampleRate float64) *kWeighting {
+	const (
+		shelfFreq = 1681.974450955533
+		shelfGain = 3.999843853973347
+		shelfQ    = 0.7071752369554196
+		passFreq  = 38.13547087602444
+		passQ     = 0.5003270373238773
+	)
+
+	k := math.Tan(math.Pi * shelfFreq / sampleRate)
+	vh := math.Pow(10, shelfGain/20)
+	vb := math.Pow(vh, 0.4996667741545416)
+	a0 := 1 + k/shelfQ + k*k
+	shelf := biquad{
+		b0: (vh + vb*k/shelfQ + k*k) / a0,
+		b1: 2 * (k*k - vh) / a0,
+		b2: (vh - vb*k/shelfQ + k*k) / a0,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/shelfQ + k*k) / a0,
+	}
+
+	k = math.Tan(math.Pi * passFreq / sampleRate)
+	a0 = 1 + k/passQ + k*k
+	highPass := biquad{
+		b0: 1,
+		b1: -2,
+		b2: 1,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/passQ + k*k) / a0,
+	}
+
+	return &kWeighting{shelf: shelf, highPass: highPass}
+}
+
+func (k *kWeighting) process(x float64) float64 {
+	return k.highPass.process(k.shelf.process(x))
+}
@@ -0,0 +1,113 @@
+package loudness
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+// sine returns seconds of a 997 Hz tone at amplitude in every channel.
+func sine(amplitude float64, channels, sampleRate int, seconds float64) []float32 {
+	frames := int(seconds * float64(sampleRate))
+	out := make([]float32, frames*channels)
+	for i := 0; i < frames; i++ {
+		v := float32(amplitude * math.Sin(2*math.Pi*997*float64(i)/float64(sampleRate)))
+		for c := 0; c < channels; c++ {
+			out[i*channels+c] = v
+		}
+	}
+	return out
+}
+
+func assertNear(t *testing.T, name string, got, want, tolerance float64) {
+	t.Helper()
+	if math.Abs(got-want) > tolerance {
+		t.Errorf("%s = %.2f, want %.2f ± %.2f", name, got, want, tolerance)
+	}
+}
+
+func TestMeasure_FullScaleSineReference(t *testing.T) {
+	// BS.1770: a 0 dBFS 1 kHz tone in one front channel reads -3.01 LUFS,
+	// so the same tone in both channels reads 0.
+	for _, rate := range []int{44100, 48000} {
+		m := Measure(sine(1, 2, rate, 2), 2, rate)
+		assertNear(t, "stereo integrated", m.Integrated, 0, 0.1)
+		assertNear(t, "stereo peak", m.Peak, 0, 0.01)
+		assertNear(t, "stereo RMS", m.RMS, -3.01, 0.01)
+	}
+
+	left := sine(1, 2, 48000, 2)
+	for i := 1; i < len(left); i += 2 {
+		left[i] = 0
+	}
+	assertNear(t, "left-only integrated", Measure(left, 2, 48000).Integrated, -3.01, 0.1)
+}
+
+func TestMeasure_MonoCountsAsBothSpeakers(t *testing.T) {
+	mono := Measure(sine(0.1, 1, 48000, 1), 1, 48000)
+	stereo := Measure(sine(0.1, 2, 48000, 1), 2, 48000)
+	assertNear(t, "mono integrated", mono.Integrated, stereo.Integrated, 0.01)
+	assertNear(t, "stereo integrated", stereo.Integrated, -20, 0.1)
+}
+
+func TestMeasure_ShortSoundIsOneBlock(t *testing.T) {
+	m := Measure(sine(0.5, 2, 48000, 0.15), 2, 48000)
+	assertNear(t, "integrated", m.Integrated, -6.02, 0.2)
+}
+
+func TestMeasure_GatesSilence(t *testing.T) {
+	// Half the padded sound is silence, which would cost 3 dB ungated;
+	// only the blocks straddling the end of the tone still count.
+	tone := sine(0.5, 2, 48000, 2)
+	padded := append(append([]float32(nil), tone...), make([]float32, len(tone))...)
+	assertNear(t, "padded integrated", Measure(padded, 2, 48000).Integrated,
+		Measure(tone, 2, 48000).Integrated, 0.5)
+
+	if m := Measure(make([]float32, 4800), 1, 48000); !m.IsSilent() || !math.IsInf(m.Peak, -1) {
+		t.Errorf("silence should measure -Inf, got %+v", m)
+	}
+}
+
+func TestGainFor(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Measurement
+		want float64
+	}{
+		{"attenuates a loud sound", Measurement{Integrated: -14, Peak: -1}, -9},
+		{"boosts a quiet sound", Measurement{Integrated: -30, Peak: -20}, 7},
+		{"limits a boost to the peak ceiling", Measurement{Integrated: -30, Peak: -4}, 3},
+		{"never attenuates for the ceiling", Measurement{Integrated: -30, Peak: 0}, 0},
+		{"leaves silence alone", Measurement{Integrated: math.Inf(-1), Peak: math.Inf(-1)}, 0},
+		{"rounds to a tenth", Measurement{Integrated: -20.04, Peak: -10}, -3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GainFor(tt.m, DefaultTargetLUFS); got != tt.want {
+				t.Errorf("GainFor(%+v) = %v, want %v", tt.m, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMeasureFile_UsesRegisteredDecoder(t *testing.T) {
+	decoderMu.RLock()
+	previous := decoder
+	decoderMu.RUnlock()
+	t.Cleanup(func() { RegisterDecoder(previous) })
+
+	RegisterDecoder(nil)
+	if _, err := MeasureFile(context.Background(), "a.wav"); !errors.Is(err, ErrNoDecoder) {
+		t.Fatalf("expected ErrNoDecoder without a decoder, got %v", err)
+	}
+
+	RegisterDecoder(func(ctx context.Context, path string) (*Audio, error) {
+		return &Audio{Samples: sine(0.1, 2, 48000, 1), Channels: 2, SampleRate: 48000}, nil
+	})
+	m, err := MeasureFile(context.Background(), "a.wav")
+	if err != nil {
+		t.Fatalf("MeasureFile: %v", err)
+	}
+	assertNear(t, "integrated", m.Integrated, -20, 0.1)
+}
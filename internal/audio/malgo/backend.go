@@ -6,10 +6,14 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 
 	"claudio.click/internal/audio"
+	"claudio.click/internal/audio/pcmcache"
+	"claudio.click/internal/config"
 )
 
 // init registers this backend with the parent audio package so that
@@ -43,12 +47,39 @@ type Backend struct {
 // NewBackend creates a new malgo Backend using AudioPlayer and DecoderRegistry.
 func NewBackend() *Backend {
 	slog.Debug("creating new malgo Backend with unified audio system")
+	registry := NewDefaultRegistry() // Includes AIFF support
+	registry.SetCache(pcmcache.New(pcmcache.DefaultDir(), pcmcache.DefaultMaxBytes))
+	player := NewAudioPlayer()
+	player.SetFormatCacheDir(config.NewXDGDirs().GetCachePath("device-format"))
 	return &Backend{
-		audioPlayer: NewAudioPlayer(),
-		registry:    NewDefaultRegistry(), // Includes AIFF support
+		audioPlayer: player,
+		registry:    registry,
 	}
 }
 
+// Warm decodes filePath into the decoded-audio cache without playing it,
+// so the first hook that plays it skips decoding. It implements
+// audio.Warmer.
+func (mb *Backend) Warm(ctx context.Context, filePath string) error {
+	mb.mutex.RLock()
+	closed := mb.closed
+	mb.mutex.RUnlock()
+	if closed {
+		return audio.ErrBackendClosed
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open audio file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := mb.registry.DecodeFile(ctx, filePath, f); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", filepath.Base(filePath), err)
+	}
+	return nil
+}
+
 // Stop stops any ongoing playback.
 func (mb *Backend) Stop() error {
 	mb.mutex.Lock()
@@ -116,6 +147,33 @@ func (mb *Backend) SetVolume(volume float32) error {
 	return mb.audioPlayer.SetVolume(volume)
 }
 
+// SetDevice selects the playback device by ID or name. It implements
+// audio.DeviceSelector.
+func (mb *Backend) SetDevice(device string) error {
+	mb.mutex.RLock()
+	defer mb.mutex.RUnlock()
+
+	if mb.closed {
+		return audio.ErrBackendClosed
+	}
+
+	mb.audioPlayer.SetDevice(device)
+	return nil
+}
+
+// Devices lists the playback devices of the system. It implements
+// audio.DeviceLister.
+func (mb *Backend) Devices() ([]audio.Device, error) {
+	mb.mutex.RLock()
+	defer mb.mutex.RUnlock()
+
+	if mb.closed {
+		return nil, audio.ErrBackendClosed
+	}
+
+	return mb.audioPlayer.Devices()
+}
+
 // GetVolume returns the current volume level.
 func (mb *Backend) GetVolume() float32 {
 	mb.mutex.RLock()
@@ -130,10 +188,55 @@ func (mb *Backend) GetVolume() float32 {
 
 // Play plays audio from the given source using unified audio system.
 func (mb *Backend) Play(ctx context.Context, source audio.AudioSource) error {
+	soundID, err := mb.load(ctx, source)
+	if err != nil {
+		return err
+	}
+
+	err = mb.audioPlayer.PlaySoundWithContext(ctx, soundID)
+	if err != nil {
+		// Clean up on error
+		_ = mb.audioPlayer.UnloadSound(soundID)
+		slog.Error("failed to play sound", "sound_id", soundID, "error", err)
+		return fmt.Errorf("failed to play sound: %w", err)
+	}
+
+	// Playback is synchronous: PlaySoundWithContext returns when the buffer
+	// has been consumed or ctx is cancelled. Unload inline. The previous
+	// `go func() { <-ctx.Done(); UnloadSound(soundID) }()` pattern leaked one
+	// goroutine plus a pinned *AudioData per call whenever the caller passed
+	// context.Background() (Done() is nil and the receive blocked forever) —
+	// which is exactly what the production CLI path does.
+	if uerr := mb.audioPlayer.UnloadSound(soundID); uerr != nil {
+		slog.Warn("failed to unload sound after playback", "sound_id", soundID, "error", uerr)
+	}
+
+	slog.Debug("unified playback completed successfully")
+	return nil
+}
+
+// PlayLoop repeats source without gaps until ctx is cancelled, at the
+// loop's gain and with its fades. It implements audio.Looper.
+func (mb *Backend) PlayLoop(ctx context.Context, source audio.AudioSource, opts audio.LoopOptions) error {
+	soundID, err := mb.load(ctx, source)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = mb.audioPlayer.UnloadSound(soundID) }()
+
+	if err := mb.audioPlayer.PlayLoopWithContext(ctx, soundID, opts.Fade, opts.EffectiveGain()); err != nil {
+		slog.Error("failed to loop sound", "sound_id", soundID, "error", err)
+		return fmt.Errorf("failed to loop sound: %w", err)
+	}
+	return nil
+}
+
+// load decodes source and preloads it under a fresh sound ID.
+func (mb *Backend) load(ctx context.Context, source audio.AudioSource) (string, error) {
 	mb.mutex.RLock()
 	if mb.closed {
 		mb.mutex.RUnlock()
-		return audio.ErrBackendClosed
+		return "", audio.ErrBackendClosed
 	}
 	mb.mutex.RUnlock()
 
@@ -146,12 +249,10 @@ func (mb *Backend) Play(ctx context.Context, source audio.AudioSource) error {
 	// finding #42's main consumer; both branches ultimately called
 	// registry.DecodeFile, so the fork was paying for nothing on the malgo
 	// side.
-	var err error
-
 	reader, format, rErr := source.Reader()
 	if rErr != nil {
 		slog.Error("failed to get reader from source", "error", rErr)
-		return fmt.Errorf("failed to get audio data from source: %w", rErr)
+		return "", fmt.Errorf("failed to get audio data from source: %w", rErr)
 	}
 	defer reader.Close()
 
@@ -167,12 +268,49 @@ func (mb *Backend) Play(ctx context.Context, source audio.AudioSource) error {
 	audioData, loadErr := mb.registry.DecodeFile(ctx, detectFilename, reader)
 	if loadErr != nil {
 		slog.Error("failed to load audio data", "filename", detectFilename, "error", loadErr)
-		return fmt.Errorf("failed to load audio data: %w", loadErr)
+		return "", fmt.Errorf("failed to load audio data: %w", loadErr)
 	}
 
 	if audioData == nil {
 		slog.Error("audio data is nil after loading")
-		return fmt.Errorf("audio data is nil")
+		return "", fmt.Errorf("audio data is nil")
+	}
+
+	variation := audio.VariationOf(source)
+	if variation.ShiftsPitchOrPan() {
+		varied, err := applyVariation(audioData, variation)
+		if err != nil {
+			slog.Error("failed to apply pitch and pan", "error", err)
+			return "", fmt.Errorf("failed to vary audio data: %w", err)
+		}
+		slog.Debug("applied session variation", "pitch", variation.EffectivePitch(), "pan", variation.Pan)
+		audioData = varied
+	}
+
+	// Normalize to the device's native layout so every sound plays through
+	// the same device configuration. If the device cannot be probed, the
+	// sound is preloaded as decoded and playback reports the device error.
+	if deviceFormat, err := mb.audioPlayer.DeviceFormat(); err != nil {
+		slog.Debug("skipping audio normalization: device format unknown", "error", err)
+	} else if !deviceFormat.matches(audioData) {
+		normalized, err := normalizeAudio(audioData, deviceFormat)
+		if err != nil {
+			slog.Error("failed to normalize audio data", "error", err)
+			return "", fmt.Errorf("failed to normalize audio data: %w", err)
+		}
+		slog.Debug("normalized audio to device format",
+			"from_channels", audioData.Channels, "to_channels", normalized.Channels,
+			"from_sample_rate", audioData.SampleRate, "to_sample_rate", normalized.SampleRate,
+			"from_format", audioData.Format, "to_format", normalized.Format)
+		audioData = normalized
+	}
+
+	// Level the sound against the rest of its soundpack. Gains are limited
+	// to the sound's peak headroom when they are computed, so a boost does
+	// not clip. Unsigned 8-bit audio, which has no volume path, plays as is.
+	if gain := variation.EffectiveGain(); gain != 1.0 {
+		applyVolumeToSamples(audioData.Samples, audioData.Format, float32(gain))
+		slog.Debug("applied loudness gain", "gain", gain)
 	}
 
 	// Generate unique sound ID for this playback. atomic.Uint64.Add returns
@@ -180,31 +318,10 @@ func (mb *Backend) Play(ctx context.Context, source audio.AudioSource) error {
 	// concurrent Plays regardless of buffer length.
 	soundID := fmt.Sprintf("play_%d", mb.soundIDCount.Add(1))
 
-	// Preload and play
-	err = mb.audioPlayer.PreloadSound(soundID, audioData)
-	if err != nil {
+	// Preload for playback
+	if err := mb.audioPlayer.PreloadSound(soundID, audioData); err != nil {
 		slog.Error("failed to preload sound", "sound_id", soundID, "error", err)
-		return fmt.Errorf("failed to preload sound: %w", err)
-	}
-
-	err = mb.audioPlayer.PlaySoundWithContext(ctx, soundID)
-	if err != nil {
-		// Clean up on error
-		_ = mb.audioPlayer.UnloadSound(soundID)
-		slog.Error("failed to play sound", "sound_id", soundID, "error", err)
-		return fmt.Errorf("failed to play sound: %w", err)
-	}
-
-	// Playback is synchronous: PlaySoundWithContext returns when the buffer
-	// has been consumed or ctx is cancelled. Unload inline. The previous
-	// `go func() { <-ctx.Done(); UnloadSound(soundID) }()` pattern leaked one
-	// goroutine plus a pinned *AudioData per call whenever the caller passed
-	// context.Background() (Done() is nil and the receive blocked forever) —
-	// which is exactly what the production CLI path does.
-	if uerr := mb.audioPlayer.UnloadSound(soundID); uerr != nil {
-		slog.Warn("failed to unload sound after playback", "sound_id", soundID, "error", uerr)
+		return "", fmt.Errorf("failed to preload sound: %w", err)
 	}
-
-	slog.Debug("unified playback completed successfully")
-	return nil
+	return soundID, nil
 }
@@ -8,6 +8,11 @@ import (
 	"github.com/gen2brain/malgo"
 )
 
+// contextBackends lists the host audio backends NewContext may use, in
+// order. nil lets miniaudio try every backend it was built with; tests pin
+// it to miniaudio's null backend so they run without audio hardware.
+var contextBackends []malgo.Backend
+
 // Context wraps malgo.AllocatedContext with proper lifecycle management and logging
 type Context struct {
 	ctx *malgo.AllocatedContext
@@ -18,7 +23,7 @@ func NewContext() (*Context, error) {
 	slog.Debug("initializing audio context")
 
 	// Initialize malgo context with logging callback
-	ctx, err := malgo.InitContext(nil, malgo.ContextConfig{}, func(message string) {
+	ctx, err := malgo.InitContext(contextBackends, malgo.ContextConfig{}, func(message string) {
 		slog.Debug("malgo internal", "message", message)
 	})
 	if err != nil {
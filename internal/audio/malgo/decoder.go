@@ -5,8 +5,10 @@ package malgo
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 
+	"claudio.click/internal/safeio"
 	"github.com/gen2brain/malgo"
 )
 
@@ -17,6 +19,22 @@ var (
 	ErrUnsupportedFormat = errors.New("unsupported audio format")
 )
 
+// maxDecodedSamples caps the float samples a compressed-format decoder
+// (FLAC, Vorbis, Opus) may produce. The input is already capped at
+// safeio.MaxAudioFileBytes, but compression lets a small file expand far
+// beyond that; this holds the decoded PCM to the same byte budget.
+const maxDecodedSamples = safeio.MaxAudioFileBytes / 4
+
+// errDecodedTooLarge reports a compressed file whose decoded PCM would
+// exceed maxDecodedSamples.
+var errDecodedTooLarge = fmt.Errorf("%w: decoded audio exceeds %d byte limit", ErrInvalidData, safeio.MaxAudioFileBytes)
+
+// DecoderVersion identifies the output of this package's decoders in
+// decoded-audio cache keys. Bump it whenever any decoder would produce
+// different samples for the same input, so cached audio decoded by the old
+// code is never played again.
+const DecoderVersion = 1
+
 // AudioData represents decoded audio ready for playback
 type AudioData struct {
 	Samples    []byte           // Raw PCM data
@@ -29,10 +47,11 @@ type AudioData struct {
 //
 // Decode takes a context.Context as its first argument so callers can
 // cancel a long-running or stalled decode (e.g. an MP3 source whose
-// underlying reader has hung). The MP3 decoder polls ctx between read
-// chunks; WAV and AIFF check ctx at entry (they already buffer the whole
-// input via safeio.ReadAllCapped before per-sample work begins, so the
-// only meaningful cancellation point is the entry check).
+// underlying reader has hung). The MP3, FLAC, Vorbis, and Opus decoders
+// poll ctx between read chunks, frames, or packets; WAV and AIFF check ctx
+// at entry (they already buffer the whole input via safeio.ReadAllCapped
+// before per-sample work begins, so the only meaningful cancellation point
+// is the entry check).
 //
 // The interface is internal to package audio — there are no external
 // importers — so adding the parameter is safe.
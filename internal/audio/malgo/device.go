@@ -0,0 +1,181 @@
+//go:build cgo
+
+package malgo
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+	"unsafe"
+
+	"claudio.click/internal/audio"
+	"github.com/gen2brain/malgo"
+)
+
+// SetDevice selects the playback device by ID or name, matched the way
+// audio.MatchDevice matches them; empty means the system default. The
+// device is looked up when the next device opens. One that cannot be
+// found falls back to the default with a warning, so unplugged
+// headphones do not silence playback, and is looked up again on every
+// open until it turns up.
+func (p *AudioPlayer) SetDevice(device string) {
+	p.deviceInitMutex.Lock()
+	defer p.deviceInitMutex.Unlock()
+
+	p.outputDevice = device
+	p.outputDeviceWarned = false
+	p.outputDeviceID = nil
+	slog.Debug("output device selected", "device", device)
+}
+
+// Devices lists the playback devices the audio context can open.
+func (p *AudioPlayer) Devices() ([]audio.Device, error) {
+	if err := p.ensureContext("device_list"); err != nil {
+		return nil, err
+	}
+	p.deviceInitMutex.Lock()
+	defer p.deviceInitMutex.Unlock()
+
+	_, devices, err := p.playbackDevices()
+	return devices, err
+}
+
+// playbackDevices enumerates the playback devices, returning malgo's
+// records alongside their audio.Device form. Callers hold
+// deviceInitMutex: enumeration reads the same context state device
+// initialization does.
+func (p *AudioPlayer) playbackDevices() ([]malgo.DeviceInfo, []audio.Device, error) {
+	infos, err := p.context.GetContext().Devices(malgo.Playback)
+	if err != nil {
+		slog.Error("failed to list playback devices", "error", err)
+		return nil, nil, fmt.Errorf("failed to list playback devices: %w", err)
+	}
+	devices := make([]audio.Device, len(infos))
+	for i := range infos {
+		devices[i] = audio.Device{
+			ID:      deviceIDString(infos[i].ID),
+			Name:    infos[i].Name(),
+			Default: infos[i].IsDefault != 0,
+		}
+	}
+	return infos, devices, nil
+}
+
+// initMalgoDevice opens a device. Tests replace it to make a selected
+// device fail to open.
+var initMalgoDevice = malgo.InitDevice
+
+// initDevice opens a playback device on the selected output device. A
+// selected device that no longer opens, such as headphones unplugged
+// since it was found, is forgotten so the next open looks it up again,
+// and this open falls back to the default device. malgo.InitDevice
+// touches shared C-side context state, so calls are serialized on
+// deviceInitMutex while playback callbacks keep running concurrently on
+// devices that are already open.
+func (p *AudioPlayer) initDevice(config malgo.DeviceConfig, callbacks malgo.DeviceCallbacks) (*malgo.Device, error) {
+	p.deviceInitMutex.Lock()
+	defer p.deviceInitMutex.Unlock()
+
+	ctx := p.context.GetContext().Context
+	id := p.selectedDeviceID()
+	if id == nil {
+		return initMalgoDevice(ctx, config, callbacks)
+	}
+
+	// miniaudio copies the ID during init, so it only has to stay pinned
+	// for the call; cgo refuses an unpinned Go pointer inside the config
+	// it hands to C.
+	var pinner runtime.Pinner
+	pinner.Pin(id)
+	selected := config
+	selected.Playback.DeviceID = unsafe.Pointer(id)
+	device, err := initMalgoDevice(ctx, selected, callbacks)
+	pinner.Unpin()
+	if err == nil {
+		return device, nil
+	}
+
+	p.outputDeviceID = nil
+	p.outputDeviceWarned = false
+	p.logDeviceFallback("audio device failed to open; using the default device", "error", err)
+	// DeviceFormat's lock is taken before this one, so mark its probe
+	// stale rather than clearing it here.
+	p.deviceFormatStale.Store(true)
+	return initMalgoDevice(ctx, config, callbacks)
+}
+
+// selectedDeviceKey returns the ID string of the device the next open
+// plays through, "" for the system default.
+func (p *AudioPlayer) selectedDeviceKey() string {
+	p.deviceInitMutex.Lock()
+	defer p.deviceInitMutex.Unlock()
+
+	if id := p.selectedDeviceID(); id != nil {
+		return deviceIDString(*id)
+	}
+	return ""
+}
+
+// selectedDeviceID returns the ID of the device SetDevice named, or nil
+// for the system default. A device once found is kept until the next
+// SetDevice; while it is missing, every call looks it up again. Callers
+// hold deviceInitMutex.
+func (p *AudioPlayer) selectedDeviceID() *malgo.DeviceID {
+	if p.outputDevice == "" || p.outputDeviceID != nil {
+		return p.outputDeviceID
+	}
+
+	infos, devices, err := p.playbackDevices()
+	if err != nil {
+		p.logDeviceFallback("cannot list playback devices; using the default device", "error", err)
+		return nil
+	}
+	match, ok := audio.MatchDevice(devices, p.outputDevice)
+	if !ok {
+		p.logDeviceFallback("audio device not found; using the default device", "devices", len(devices))
+		return nil
+	}
+	p.outputDeviceWarned = false
+	for i := range devices {
+		if devices[i] == match {
+			id := infos[i].ID
+			p.outputDeviceID = &id
+			break
+		}
+	}
+	slog.Debug("playback device resolved", "device", p.outputDevice, "name", match.Name, "id", match.ID)
+	return p.outputDeviceID
+}
+
+// logDeviceFallback reports that the selected device is unavailable, as
+// a warning the first time and at debug level while it stays missing.
+// Callers hold deviceInitMutex.
+func (p *AudioPlayer) logDeviceFallback(msg string, args ...any) {
+	args = append([]any{"device", p.outputDevice}, args...)
+	if p.outputDeviceWarned {
+		slog.Debug(msg, args...)
+		return
+	}
+	p.outputDeviceWarned = true
+	slog.Warn(msg, args...)
+}
+
+// deviceIDString renders a device ID for 'claudio devices' and the
+// audio_device setting. PulseAudio, ALSA, JACK and Core Audio identify
+// devices by name, which is shown as text; other backends use opaque
+// bytes, shown in hex the way malgo prints them.
+func deviceIDString(id malgo.DeviceID) string {
+	n := len(id)
+	for n > 0 && id[n-1] == 0 {
+		n--
+	}
+	if n == 0 {
+		return id.String()
+	}
+	for _, b := range id[:n] {
+		if b < 0x20 || b > 0x7e {
+			return id.String()
+		}
+	}
+	return string(id[:n])
+}
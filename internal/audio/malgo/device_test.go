@@ -0,0 +1,221 @@
+//go:build cgo
+
+package malgo
+
+import (
+	"context"
+	"testing"
+
+	"claudio.click/internal/audio"
+	"github.com/gen2brain/malgo"
+)
+
+// nullBackend is miniaudio's ma_backend_null. malgo's Backend enumeration
+// leaves out ma_backend_custom, which precedes it, so malgo.BackendNull
+// names the custom backend instead.
+const nullBackend = malgo.BackendNull + 1
+
+// useNullBackend makes the contexts a test creates use miniaudio's null
+// backend, which has one playback device and needs no audio hardware.
+func useNullBackend(t *testing.T) {
+	t.Helper()
+	previous := contextBackends
+	contextBackends = []malgo.Backend{nullBackend}
+	t.Cleanup(func() { contextBackends = previous })
+}
+
+func TestDevices_ListsNullBackendDevice(t *testing.T) {
+	useNullBackend(t)
+	backend := NewBackend()
+	defer backend.Close()
+
+	var lister audio.DeviceLister = backend
+	devices, err := lister.Devices()
+	if err != nil {
+		t.Fatalf("Devices: %v", err)
+	}
+	if len(devices) == 0 {
+		t.Fatal("the null backend should report a playback device")
+	}
+	if devices[0].Name == "" || devices[0].ID == "" {
+		t.Errorf("device should have a name and an ID, got %+v", devices[0])
+	}
+	if !devices[0].Default {
+		t.Errorf("the null backend's device should be the default, got %+v", devices[0])
+	}
+}
+
+func TestSetDevice_PlaysThroughSelectedDevice(t *testing.T) {
+	useNullBackend(t)
+	for _, tc := range []struct {
+		name   string
+		device func(audio.Device) string
+		found  bool
+	}{
+		{"by ID", func(d audio.Device) string { return d.ID }, true},
+		{"by name", func(d audio.Device) string { return d.Name[:4] }, true},
+		{"unknown falls back to default", func(audio.Device) string { return "no such device" }, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			player := NewAudioPlayer()
+			defer player.Close()
+			devices, err := player.Devices()
+			if err != nil || len(devices) == 0 {
+				t.Fatalf("Devices: %v, %v", devices, err)
+			}
+
+			player.SetDevice(tc.device(devices[0]))
+			if _, err := player.DeviceFormat(); err != nil {
+				t.Fatalf("DeviceFormat on the selected device: %v", err)
+			}
+			player.deviceInitMutex.Lock()
+			resolved := player.outputDeviceID != nil
+			player.deviceInitMutex.Unlock()
+			if resolved != tc.found {
+				t.Errorf("device resolved = %v, want %v", resolved, tc.found)
+			}
+
+			data := &AudioData{Samples: make([]byte, 480*2), Channels: 1, SampleRate: 48000, Format: malgo.FormatS16}
+			if err := player.PreloadSound("beep", data); err != nil {
+				t.Fatal(err)
+			}
+			if err := player.PlaySoundWithContext(context.Background(), "beep"); err != nil {
+				t.Errorf("PlaySound on the selected device: %v", err)
+			}
+		})
+	}
+}
+
+func TestSetDevice_MissingDeviceIsLookedUpAgain(t *testing.T) {
+	useNullBackend(t)
+	player := NewAudioPlayer()
+	defer player.Close()
+	devices, err := player.Devices()
+	if err != nil || len(devices) == 0 {
+		t.Fatalf("Devices: %v, %v", devices, err)
+	}
+
+	player.SetDevice("headphones")
+	if _, err := player.DeviceFormat(); err != nil {
+		t.Fatalf("DeviceFormat on the fallback device: %v", err)
+	}
+	if player.deviceFormatDevice != "" {
+		t.Fatalf("a missing device should probe the default, got %q", player.deviceFormatDevice)
+	}
+
+	// The headphones turn up under the name audio_device gave: stand in
+	// for that by pointing the stored name at the null device, without the
+	// reset SetDevice does.
+	player.deviceInitMutex.Lock()
+	player.outputDevice = devices[0].ID
+	player.deviceInitMutex.Unlock()
+
+	if _, err := player.DeviceFormat(); err != nil {
+		t.Fatalf("DeviceFormat after the device appeared: %v", err)
+	}
+	player.deviceInitMutex.Lock()
+	resolved := player.outputDeviceID != nil
+	player.deviceInitMutex.Unlock()
+	if !resolved {
+		t.Error("the device should be looked up again after a fallback")
+	}
+	if player.deviceFormatDevice != devices[0].ID {
+		t.Errorf("format should be probed again for the found device, got %q", player.deviceFormatDevice)
+	}
+}
+
+func TestSetDevice_ResetsProbedFormat(t *testing.T) {
+	useNullBackend(t)
+	player := NewAudioPlayer()
+	defer player.Close()
+	devices, err := player.Devices()
+	if err != nil || len(devices) == 0 {
+		t.Fatalf("Devices: %v, %v", devices, err)
+	}
+
+	if _, err := player.DeviceFormat(); err != nil {
+		t.Fatalf("DeviceFormat on the default device: %v", err)
+	}
+	if !player.deviceFormatProbed || player.deviceFormatDevice != "" {
+		t.Fatalf("expected the default device's format to be probed, got %q", player.deviceFormatDevice)
+	}
+
+	player.SetDevice(devices[0].ID)
+	if _, err := player.DeviceFormat(); err != nil {
+		t.Fatalf("DeviceFormat on the selected device: %v", err)
+	}
+	if player.deviceFormatDevice != devices[0].ID {
+		t.Errorf("selecting a device should probe its format, cached format is for %q", player.deviceFormatDevice)
+	}
+
+	player.SetDevice("")
+	if _, err := player.DeviceFormat(); err != nil {
+		t.Fatalf("DeviceFormat back on the default device: %v", err)
+	}
+	if player.deviceFormatDevice != "" {
+		t.Errorf("clearing the device should probe the default again, cached format is for %q", player.deviceFormatDevice)
+	}
+}
+
+func TestSetDevice_VanishedDeviceFallsBackToDefault(t *testing.T) {
+	useNullBackend(t)
+	player := NewAudioPlayer()
+	defer player.Close()
+	devices, err := player.Devices()
+	if err != nil || len(devices) == 0 {
+		t.Fatalf("Devices: %v, %v", devices, err)
+	}
+	player.SetDevice(devices[0].ID)
+	if _, err := player.DeviceFormat(); err != nil {
+		t.Fatalf("DeviceFormat on the selected device: %v", err)
+	}
+
+	// The device was found, then unplugged: it no longer opens.
+	previous := initMalgoDevice
+	initMalgoDevice = func(ctx malgo.Context, config malgo.DeviceConfig, callbacks malgo.DeviceCallbacks) (*malgo.Device, error) {
+		if config.Playback.DeviceID != nil {
+			return nil, malgo.ErrNoDevice
+		}
+		return previous(ctx, config, callbacks)
+	}
+	t.Cleanup(func() { initMalgoDevice = previous })
+
+	data := &AudioData{Samples: make([]byte, 480*2), Channels: 1, SampleRate: 48000, Format: malgo.FormatS16}
+	if err := player.PreloadSound("beep", data); err != nil {
+		t.Fatal(err)
+	}
+	if err := player.PlaySoundWithContext(context.Background(), "beep"); err != nil {
+		t.Errorf("PlaySound should fall back to the default device: %v", err)
+	}
+	player.deviceInitMutex.Lock()
+	forgotten := player.outputDeviceID == nil
+	player.deviceInitMutex.Unlock()
+	if !forgotten {
+		t.Error("a device that failed to open should be looked up again")
+	}
+	if !player.deviceFormatStale.Load() {
+		t.Error("a device that failed to open should make DeviceFormat probe again")
+	}
+	if _, err := player.DeviceFormat(); err != nil {
+		t.Errorf("DeviceFormat after the device vanished: %v", err)
+	}
+}
+
+func TestDeviceIDString(t *testing.T) {
+	var named malgo.DeviceID
+	copy(named[:], "alsa_output.pci-0000_00_1f.3.analog-stereo")
+	if got := deviceIDString(named); got != "alsa_output.pci-0000_00_1f.3.analog-stereo" {
+		t.Errorf("a name-like ID should print as text, got %q", got)
+	}
+
+	var opaque malgo.DeviceID
+	copy(opaque[:], []byte{0x7b, 0x00, 0x30, 0x00})
+	if got := deviceIDString(opaque); got != "7b0030" {
+		t.Errorf("an opaque ID should print in hex, got %q", got)
+	}
+
+	var zero malgo.DeviceID
+	if got := deviceIDString(zero); got != "00" {
+		t.Errorf("a zero ID should print as 00, got %q", got)
+	}
+}
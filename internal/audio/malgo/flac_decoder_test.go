@@ -0,0 +1,175 @@
+//go:build cgo
+
+package malgo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/gen2brain/malgo"
+	"github.com/mewkiz/flac"
+	"github.com/mewkiz/flac/frame"
+	"github.com/mewkiz/flac/meta"
+)
+
+// encodeTestFLAC encodes a 440 Hz sine of frames samples per channel as a
+// FLAC stream with verbatim subframes.
+func encodeTestFLAC(t *testing.T, channels, bitDepth, frames int) []byte {
+	t.Helper()
+	const sampleRate = 44100
+	info := &meta.StreamInfo{
+		BlockSizeMin:  4096,
+		BlockSizeMax:  4096,
+		SampleRate:    sampleRate,
+		NChannels:     uint8(channels),
+		BitsPerSample: uint8(bitDepth),
+		NSamples:      uint64(frames),
+	}
+	buf := &bytes.Buffer{}
+	enc, err := flac.NewEncoder(buf, info)
+	if err != nil {
+		t.Fatalf("NewEncoder: %v", err)
+	}
+
+	peak := float64(int64(1)<<(bitDepth-1) - 1)
+	layout := frame.ChannelsMono
+	if channels == 2 {
+		layout = frame.ChannelsLR
+	}
+	for offset := 0; offset < frames; offset += 4096 {
+		blockSize := min(4096, frames-offset)
+		f := &frame.Frame{
+			Header: frame.Header{
+				HasFixedBlockSize: true,
+				BlockSize:         uint16(blockSize),
+				SampleRate:        sampleRate,
+				Channels:          layout,
+				BitsPerSample:     uint8(bitDepth),
+			},
+			Subframes: make([]*frame.Subframe, channels),
+		}
+		for c := 0; c < channels; c++ {
+			samples := make([]int32, blockSize)
+			for i := range samples {
+				samples[i] = int32(math.Sin(2*math.Pi*440*float64(offset+i)/sampleRate) * peak / float64(c+1))
+			}
+			f.Subframes[c] = &frame.Subframe{
+				SubHeader: frame.SubHeader{Pred: frame.PredVerbatim},
+				Samples:   samples,
+				NSamples:  blockSize,
+			}
+		}
+		if err := enc.WriteFrame(f); err != nil {
+			t.Fatalf("WriteFrame: %v", err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestFlacDecoderInterface(t *testing.T) {
+	decoder := NewFlacDecoder()
+
+	var _ Decoder = decoder
+
+	if decoder.FormatName() != "FLAC" {
+		t.Errorf("expected format name 'FLAC', got '%s'", decoder.FormatName())
+	}
+}
+
+func TestFlacDecoderCanDecode(t *testing.T) {
+	decoder := NewFlacDecoder()
+
+	testCases := []struct {
+		filename string
+		expected bool
+	}{
+		{"audio.flac", true},
+		{"SOUND.FLAC", true},
+		{"audio.wav", false},
+		{"audio.ogg", false},
+		{"flac", false},
+		{"", false},
+	}
+
+	for _, tc := range testCases {
+		if got := decoder.CanDecode(tc.filename); got != tc.expected {
+			t.Errorf("CanDecode('%s') = %v, expected %v", tc.filename, got, tc.expected)
+		}
+	}
+}
+
+func TestFlacDecoderDecode(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		channels int
+		bitDepth int
+	}{
+		{"16-bit stereo", 2, 16},
+		{"24-bit mono", 1, 24},
+		{"12-bit mono", 1, 12},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			const frames = 5000 // spans two blocks
+			data := encodeTestFLAC(t, tc.channels, tc.bitDepth, frames)
+
+			audioData, err := NewFlacDecoder().Decode(context.Background(), bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if audioData.Channels != uint32(tc.channels) || audioData.SampleRate != 44100 {
+				t.Errorf("got %d channels at %d Hz", audioData.Channels, audioData.SampleRate)
+			}
+			if audioData.Format != malgo.FormatF32 {
+				t.Errorf("expected FormatF32, got %v", audioData.Format)
+			}
+			samples, err := pcmToFloat32(audioData.Samples, audioData.Format)
+			if err != nil {
+				t.Fatalf("pcmToFloat32: %v", err)
+			}
+			if len(samples) != frames*tc.channels {
+				t.Fatalf("expected %d samples, got %d", frames*tc.channels, len(samples))
+			}
+			// Frame 25 of a 440 Hz sine at 44.1 kHz is near the first peak.
+			want := math.Sin(2 * math.Pi * 440 * 25 / 44100)
+			if got := float64(samples[25*tc.channels]); math.Abs(got-want) > 0.01 {
+				t.Errorf("sample 25 = %f, want about %f", got, want)
+			}
+		})
+	}
+}
+
+func TestFlacDecoderDecodeInvalidData(t *testing.T) {
+	decoder := NewFlacDecoder()
+
+	for name, data := range map[string][]byte{
+		"empty":       {},
+		"wrong magic": []byte("not a flac file at all"),
+		"header only": []byte("fLaC"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			audioData, err := decoder.Decode(context.Background(), bytes.NewReader(data))
+			if err == nil {
+				t.Fatal("expected error for invalid FLAC data")
+			}
+			if audioData != nil {
+				t.Error("expected nil data on error")
+			}
+		})
+	}
+}
+
+func TestFlacDecoderCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	data := encodeTestFLAC(t, 1, 16, 100)
+	if _, err := NewFlacDecoder().Decode(ctx, bytes.NewReader(data)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
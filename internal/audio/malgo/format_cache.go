@@ -0,0 +1,112 @@
+//go:build cgo
+
+package malgo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gen2brain/malgo"
+)
+
+// deviceFormatCacheTTL is how long a format probed by one process is
+// trusted by later ones. A stale entry costs little: the device still
+// opens, and miniaudio converts to whatever the device now runs at.
+const deviceFormatCacheTTL = time.Hour
+
+// cachedFormat is the on-disk form of a probed DeviceFormat.
+type cachedFormat struct {
+	Format     malgo.FormatType `json:"format"`
+	Channels   uint32           `json:"channels"`
+	SampleRate uint32           `json:"sample_rate"`
+}
+
+// SetFormatCacheDir makes DeviceFormat share probed formats with later
+// processes through files in dir, so a hook does not open the device once
+// to probe it and again to play. Empty, the default, keeps probed formats
+// in memory only.
+func (p *AudioPlayer) SetFormatCacheDir(dir string) {
+	p.deviceFormatMutex.Lock()
+	defer p.deviceFormatMutex.Unlock()
+	p.formatCacheDir = dir
+}
+
+// formatCachePath returns the file holding device's probed format, or ""
+// when there is no format cache. Callers hold deviceFormatMutex.
+func (p *AudioPlayer) formatCachePath(device string) string {
+	if p.formatCacheDir == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(device))
+	return filepath.Join(p.formatCacheDir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// loadCachedFormat returns device's format as another process probed it
+// within deviceFormatCacheTTL. Callers hold deviceFormatMutex.
+func (p *AudioPlayer) loadCachedFormat(device string) (DeviceFormat, bool) {
+	path := p.formatCachePath(device)
+	if path == "" {
+		return DeviceFormat{}, false
+	}
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > deviceFormatCacheTTL {
+		return DeviceFormat{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return DeviceFormat{}, false
+	}
+	var cached cachedFormat
+	if err := json.Unmarshal(data, &cached); err != nil {
+		slog.Debug("ignoring unreadable cached device format", "path", path, "error", err)
+		return DeviceFormat{}, false
+	}
+	format := DeviceFormat(cached)
+	if _, err := getBytesPerSample(format.Format); err != nil || format.Channels == 0 || format.SampleRate == 0 {
+		slog.Debug("ignoring invalid cached device format", "path", path, "format", cached)
+		return DeviceFormat{}, false
+	}
+	slog.Debug("using cached playback device format",
+		"format", format.Format,
+		"channels", format.Channels,
+		"sample_rate", format.SampleRate)
+	return format, true
+}
+
+// storeCachedFormat records device's probed format for later processes.
+// Failure only means the next process probes again. Callers hold
+// deviceFormatMutex.
+func (p *AudioPlayer) storeCachedFormat(device string, format DeviceFormat) {
+	path := p.formatCachePath(device)
+	if path == "" {
+		return
+	}
+	data, err := json.Marshal(cachedFormat(format))
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(p.formatCacheDir, 0755); err != nil {
+		slog.Debug("device format not cached", "error", err)
+		return
+	}
+	tmp, err := os.CreateTemp(p.formatCacheDir, ".format-*")
+	if err != nil {
+		slog.Debug("device format not cached", "error", err)
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		slog.Debug("device format not cached", "error", err)
+	}
+}
@@ -0,0 +1,147 @@
+//go:build cgo
+
+package malgo
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gen2brain/malgo"
+)
+
+// PlayLoopWithContext plays a preloaded sound over and over without a gap
+// until ctx is cancelled or StopSound is called with its ID. The loop
+// plays at gain times the player volume, fades in over fade when it starts
+// and fades out over fade when asked to stop, then returns. A zero fade
+// starts and stops abruptly.
+func (p *AudioPlayer) PlayLoopWithContext(ctx context.Context, soundID string, fade time.Duration, gain float32) error {
+	p.mutex.RLock()
+	if p.closed {
+		p.mutex.RUnlock()
+		return fmt.Errorf("player is closed")
+	}
+	audioData, exists := p.sounds[soundID]
+	p.mutex.RUnlock()
+	if !exists {
+		return fmt.Errorf("sound not found: %s", soundID)
+	}
+
+	bytesPerSample, err := getBytesPerSample(audioData.Format)
+	if err != nil {
+		return fmt.Errorf("cannot loop sound %q: %w", soundID, err)
+	}
+	bytesPerFrame := int(audioData.Channels) * bytesPerSample
+	// Only whole frames loop; a trailing partial frame would shift every
+	// later pass out of alignment.
+	loopBytes := len(audioData.Samples) - len(audioData.Samples)%bytesPerFrame
+	if loopBytes == 0 {
+		return fmt.Errorf("cannot loop sound %q: no audio frames", soundID)
+	}
+
+	if err := p.ensureContext(soundID); err != nil {
+		return err
+	}
+
+	loopCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	fadeFrames := uint64(fade.Seconds() * float64(audioData.SampleRate))
+	// applyVolumeToSamples has no unsigned 8-bit path; such loops play
+	// without fades or volume rather than logging from the audio thread.
+	scalable := audioData.Format != malgo.FormatU8
+	var (
+		offset    int         // next byte of Samples to play; audio thread only
+		played    uint64      // frames delivered so far; audio thread only
+		stopAt    uint64      // frame at which the fade-out began; audio thread only
+		stopping  atomic.Bool // set once loopCtx ends
+		faded     = make(chan struct{})
+		fadedOnce sync.Once
+	)
+
+	onSamples := func(pOutputSample, pInputSamples []byte, framecount uint32) {
+		// REALTIME HOT PATH: no locks, no allocation, no logging.
+		n := len(pOutputSample) - len(pOutputSample)%bytesPerFrame
+		for filled := 0; filled < n; {
+			c := copy(pOutputSample[filled:n], audioData.Samples[offset:loopBytes])
+			filled += c
+			offset = (offset + c) % loopBytes
+		}
+		for i := n; i < len(pOutputSample); i++ {
+			pOutputSample[i] = 0
+		}
+
+		volume := math.Float32frombits(p.volume.Load()) * gain
+		if stopping.Load() && stopAt == 0 {
+			stopAt = played + 1 // +1 keeps 0 free to mean "not stopping"
+		}
+		for f := 0; f < n; f += bytesPerFrame {
+			gain := volume
+			if fadeFrames > 0 && played < fadeFrames {
+				gain *= float32(played) / float32(fadeFrames)
+			}
+			if stopAt != 0 {
+				out := played + 1 - stopAt
+				if out >= fadeFrames {
+					gain = 0
+				} else {
+					gain *= 1 - float32(out)/float32(fadeFrames)
+				}
+			}
+			if scalable && gain != 1.0 {
+				applyVolumeToSamples(pOutputSample[f:f+bytesPerFrame], audioData.Format, gain)
+			}
+			played++
+		}
+		if stopAt != 0 && played+1-stopAt >= fadeFrames {
+			fadedOnce.Do(func() { close(faded) })
+		}
+	}
+
+	deviceConfig := malgo.DefaultDeviceConfig(malgo.Playback)
+	deviceConfig.Playback.Format = audioData.Format
+	deviceConfig.Playback.Channels = audioData.Channels
+	deviceConfig.SampleRate = audioData.SampleRate
+	deviceConfig.Alsa.NoMMap = 1
+
+	device, err := p.initDevice(deviceConfig, malgo.DeviceCallbacks{Data: onSamples})
+	if err != nil {
+		slog.Error("failed to initialize loop device", "sound_id", soundID, "error", err)
+		return fmt.Errorf("failed to initialize playback device: %w", err)
+	}
+
+	entry := &deviceEntry{device: device, stop: cancel}
+	p.deviceMutex.Lock()
+	p.devices[soundID] = entry
+	p.deviceMutex.Unlock()
+	defer func() {
+		p.deviceMutex.Lock()
+		delete(p.devices, soundID)
+		p.deviceMutex.Unlock()
+		entry.uninit()
+	}()
+
+	if err := device.Start(); err != nil {
+		slog.Error("failed to start loop playback", "sound_id", soundID, "error", err)
+		return fmt.Errorf("failed to start playback: %w", err)
+	}
+	slog.Debug("loop playback started", "sound_id", soundID, "fade", fade, "gain", gain)
+
+	<-loopCtx.Done()
+	stopping.Store(true)
+
+	// Let the fade-out finish; the margin covers one device period. A
+	// device that StopAll already tore down never calls back again.
+	timer := time.NewTimer(fade + 500*time.Millisecond)
+	defer timer.Stop()
+	select {
+	case <-faded:
+	case <-timer.C:
+	}
+	slog.Debug("loop playback stopped", "sound_id", soundID)
+	return nil
+}
@@ -0,0 +1,113 @@
+//go:build cgo
+
+package malgo
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gen2brain/malgo"
+)
+
+func TestPlayLoopWithContext_Errors(t *testing.T) {
+	player := NewAudioPlayer()
+	defer func() { _ = player.Close() }()
+
+	if err := player.PlayLoopWithContext(context.Background(), "missing", 0, 1.0); err == nil {
+		t.Error("looping a sound that was never preloaded should fail")
+	}
+
+	// One byte of 16-bit stereo audio is not a whole frame.
+	if err := player.PreloadSound("partial", &AudioData{
+		Samples: []byte{0x01}, Channels: 2, SampleRate: 44100, Format: malgo.FormatS16,
+	}); err != nil {
+		t.Fatalf("PreloadSound: %v", err)
+	}
+	if err := player.PlayLoopWithContext(context.Background(), "partial", 0, 1.0); err == nil {
+		t.Error("looping a sound with no whole frames should fail")
+	}
+}
+
+func TestStopSound_EndsLoop(t *testing.T) {
+	skipIfWSLMalgoPlayback(t)
+
+	player := NewAudioPlayer()
+	defer func() { _ = player.Close() }()
+
+	soundID := "loop-test"
+	if err := player.PreloadSound(soundID, &AudioData{
+		Samples: make([]byte, 4410*2), Channels: 1, SampleRate: 44100, Format: malgo.FormatS16,
+	}); err != nil {
+		t.Fatalf("PreloadSound: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- player.PlayLoopWithContext(ctx, soundID, 50*time.Millisecond, 0.5) }()
+
+	// The 100ms sound must still be looping well past its own length.
+	time.Sleep(300 * time.Millisecond)
+	select {
+	case err := <-done:
+		skipIfNoAudioDevice(t, err)
+		t.Fatalf("loop returned before being stopped: %v", err)
+	default:
+	}
+	if !player.IsPlaying() {
+		t.Fatal("IsPlaying should be true while the loop runs")
+	}
+
+	if err := player.StopSound(soundID); err != nil {
+		t.Fatalf("StopSound: %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("PlayLoopWithContext: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("loop did not stop within 2s of StopSound")
+	}
+	if player.IsPlaying() {
+		t.Error("IsPlaying should be false after the loop stops")
+	}
+}
+
+func TestApplyVolumeToSamples_Float32(t *testing.T) {
+	samples := make([]byte, 8)
+	binary.LittleEndian.PutUint32(samples[0:], math.Float32bits(0.8))
+	binary.LittleEndian.PutUint32(samples[4:], math.Float32bits(-0.5))
+
+	applyVolumeToSamples(samples, malgo.FormatF32, 0.5)
+
+	if got := math.Float32frombits(binary.LittleEndian.Uint32(samples[0:])); got != 0.4 {
+		t.Errorf("first sample = %v, want 0.4", got)
+	}
+	if got := math.Float32frombits(binary.LittleEndian.Uint32(samples[4:])); got != -0.25 {
+		t.Errorf("second sample = %v, want -0.25", got)
+	}
+}
+
+func TestApplyVolumeToSamples_IntegerGainSaturates(t *testing.T) {
+	s16 := make([]byte, 6)
+	for i, v := range []int16{20000, -20000, 1000} {
+		binary.LittleEndian.PutUint16(s16[i*2:], uint16(v))
+	}
+	applyVolumeToSamples(s16, malgo.FormatS16, 2)
+	for i, want := range []int16{math.MaxInt16, math.MinInt16, 2000} {
+		if got := int16(binary.LittleEndian.Uint16(s16[i*2:])); got != want {
+			t.Errorf("S16 sample %d = %d, want %d", i, got, want)
+		}
+	}
+
+	s32 := make([]byte, 4)
+	binary.LittleEndian.PutUint32(s32, uint32(int32(2_000_000_000)))
+	applyVolumeToSamples(s32, malgo.FormatS32, 1.5)
+	if got := int32(binary.LittleEndian.Uint32(s32)); got != math.MaxInt32 {
+		t.Errorf("S32 sample = %d, want %d", got, int32(math.MaxInt32))
+	}
+}
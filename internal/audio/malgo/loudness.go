@@ -0,0 +1,43 @@
+//go:build cgo
+
+package malgo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"claudio.click/internal/audio/loudness"
+)
+
+// init lends the decoders to loudness analysis, which has no cgo of its
+// own, the same way the backend registers itself with the audio package.
+func init() {
+	loudness.RegisterDecoder(decodeForLoudness)
+}
+
+// decodeForLoudness decodes the file at path to float samples at its own
+// rate and layout. It bypasses the decoded audio cache: analysis runs
+// over whole packs at install time and should not evict what hooks play.
+func decodeForLoudness(ctx context.Context, path string) (*loudness.Audio, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audio file: %w", err)
+	}
+	defer f.Close()
+
+	data, err := NewDefaultRegistry().DecodeFile(ctx, path, f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", filepath.Base(path), err)
+	}
+	samples, err := pcmToFloat32(data.Samples, data.Format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", filepath.Base(path), err)
+	}
+	return &loudness.Audio{
+		Samples:    samples,
+		Channels:   int(data.Channels),
+		SampleRate: int(data.SampleRate),
+	}, nil
+}
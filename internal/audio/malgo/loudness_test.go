@@ -0,0 +1,38 @@
+//go:build cgo
+
+package malgo
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"claudio.click/internal/audio"
+	"claudio.click/internal/audio/loudness"
+)
+
+func TestLoudness_MeasuresThroughRegisteredDecoder(t *testing.T) {
+	if !loudness.Available() {
+		t.Fatal("the malgo package should register a loudness decoder")
+	}
+
+	m, err := loudness.MeasureFile(context.Background(), filepath.Join("testdata", "vorbis.ogg"))
+	if err != nil {
+		t.Fatalf("MeasureFile: %v", err)
+	}
+	if m.IsSilent() || m.Peak > 0 || m.Integrated > m.Peak {
+		t.Errorf("implausible measurement for a decoded tone: %+v", m)
+	}
+
+}
+
+func TestApplyVariation_GainOnlyLeavesDataForLoad(t *testing.T) {
+	data := monoS16(100, -100, 200)
+	got, err := applyVariation(data, audio.Variation{Gain: 0.5})
+	if err != nil {
+		t.Fatalf("applyVariation: %v", err)
+	}
+	if got != data {
+		t.Error("a gain-only variation should not resample or pan")
+	}
+}
@@ -0,0 +1,27 @@
+//go:build cgo
+
+package malgo
+
+import (
+	"os"
+	"testing"
+
+	"github.com/adrg/xdg"
+)
+
+// TestMain points the XDG cache home at a temporary directory so the
+// decoded-audio cache every NewBackend attaches does not write into the
+// developer's real ~/.cache/claudio during tests.
+func TestMain(m *testing.M) {
+	cacheHome, err := os.MkdirTemp("", "claudio-malgo-cache-")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_CACHE_HOME", cacheHome)
+	xdg.Reload()
+
+	code := m.Run()
+
+	os.RemoveAll(cacheHome)
+	os.Exit(code)
+}
@@ -0,0 +1,158 @@
+//go:build cgo
+
+package malgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/gen2brain/malgo"
+)
+
+func TestOpusDecoderInterface(t *testing.T) {
+	decoder := NewOpusDecoder()
+
+	var _ Decoder = decoder
+
+	if decoder.FormatName() != "OPUS" {
+		t.Errorf("expected format name 'OPUS', got '%s'", decoder.FormatName())
+	}
+}
+
+func TestOpusDecoderCanDecode(t *testing.T) {
+	decoder := NewOpusDecoder()
+
+	testCases := []struct {
+		filename string
+		expected bool
+	}{
+		{"audio.opus", true},
+		{"SOUND.OPUS", true},
+		{"audio.ogg", false}, // routed here by content sniffing, not extension
+		{"audio.wav", false},
+		{"opus", false},
+		{"", false},
+	}
+
+	for _, tc := range testCases {
+		if got := decoder.CanDecode(tc.filename); got != tc.expected {
+			t.Errorf("CanDecode('%s') = %v, expected %v", tc.filename, got, tc.expected)
+		}
+	}
+}
+
+func TestOpusDecoderDecode(t *testing.T) {
+	data, err := os.ReadFile("testdata/tiny.opus")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	audioData, err := NewOpusDecoder().Decode(context.Background(), bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if audioData.Channels != 1 || audioData.SampleRate != opusSampleRate {
+		t.Errorf("got %d channels at %d Hz, want 1 at %d", audioData.Channels, audioData.SampleRate, opusSampleRate)
+	}
+	if audioData.Format != malgo.FormatF32 {
+		t.Errorf("expected FormatF32, got %v", audioData.Format)
+	}
+	if len(audioData.Samples) == 0 || len(audioData.Samples)%4 != 0 {
+		t.Errorf("expected whole float samples, got %d bytes", len(audioData.Samples))
+	}
+}
+
+func TestOpusDecoderDecodeInvalidData(t *testing.T) {
+	vorbisData, err := os.ReadFile("testdata/vorbis.ogg")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	for name, data := range map[string][]byte{
+		"empty":         {},
+		"not ogg":       []byte("not an ogg opus file"),
+		"ogg of vorbis": vorbisData,
+	} {
+		t.Run(name, func(t *testing.T) {
+			audioData, err := NewOpusDecoder().Decode(context.Background(), bytes.NewReader(data))
+			if err == nil {
+				t.Fatal("expected error for invalid Opus data")
+			}
+			if audioData != nil {
+				t.Error("expected nil data on error")
+			}
+		})
+	}
+}
+
+func TestOpusDecoderCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := NewOpusDecoder().Decode(ctx, bytes.NewReader(nil)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+// withLastGranule returns a copy of an Ogg stream whose last page carries
+// granule, with the page checksum recomputed so the reader accepts it.
+func withLastGranule(t *testing.T, data []byte, granule uint64) []byte {
+	t.Helper()
+	out := append([]byte(nil), data...)
+	page := bytes.LastIndex(out, []byte("OggS"))
+	if page < 0 || len(out) < page+27 {
+		t.Fatal("fixture has no Ogg page")
+	}
+	binary.LittleEndian.PutUint64(out[page+6:], granule)
+	binary.LittleEndian.PutUint32(out[page+22:], 0)
+	var crc uint32
+	for _, b := range out[page:] {
+		crc = crc<<8 ^ oggCRCTable[byte(crc>>24)^b]
+	}
+	binary.LittleEndian.PutUint32(out[page+22:], crc)
+	return out
+}
+
+// oggCRCTable is the Ogg page checksum table: CRC-32 with polynomial
+// 0x04c11db7, unreflected.
+var oggCRCTable = func() (table [256]uint32) {
+	for i := range table {
+		r := uint32(i) << 24
+		for j := 0; j < 8; j++ {
+			if r&0x80000000 != 0 {
+				r = r<<1 ^ 0x04c11db7
+			} else {
+				r <<= 1
+			}
+		}
+		table[i] = r
+	}
+	return table
+}()
+
+func TestOpusDecoderHugeGranule(t *testing.T) {
+	data, err := os.ReadFile("testdata/tiny.opus")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	want, err := NewOpusDecoder().Decode(context.Background(), bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	// A granule past the decoded length trims nothing, however large.
+	for _, granule := range []uint64{1 << 40, 1 << 63, math.MaxUint64} {
+		got, err := NewOpusDecoder().Decode(context.Background(), bytes.NewReader(withLastGranule(t, data, granule)))
+		if err != nil {
+			t.Fatalf("granule %d: Decode: %v", granule, err)
+		}
+		if len(got.Samples) < len(want.Samples) {
+			t.Errorf("granule %d: got %d bytes, want at least %d", granule, len(got.Samples), len(want.Samples))
+		}
+	}
+}
@@ -23,6 +23,9 @@ import (
 type deviceEntry struct {
 	device     *malgo.Device
 	uninitOnce sync.Once
+	// stop, when set, asks the entry's playback to wind down on its own
+	// (a loop fades out) instead of being uninit'd mid-buffer by StopSound.
+	stop func()
 }
 
 // uninit stops and uninits the wrapped device exactly once, regardless of
@@ -72,6 +75,28 @@ type AudioPlayer struct {
 	// to every later observer.
 	contextInitOnce sync.Once
 	contextInitErr  error
+	// deviceFormatMutex guards the probed native format of the playback
+	// device, which deviceFormatDevice identifies ("" = system default),
+	// and formatCacheDir, where probed formats are shared with later
+	// processes; see DeviceFormat.
+	deviceFormatMutex  sync.Mutex
+	deviceFormatProbed bool
+	deviceFormatDevice string
+	deviceFormat       DeviceFormat
+	deviceFormatErr    error
+	formatCacheDir     string
+	// deviceFormatStale is set when the selected device failed to open,
+	// so the next DeviceFormat probes again even if the selection looks
+	// unchanged.
+	deviceFormatStale atomic.Bool
+	// outputDevice is the audio_device SetDevice stored. The device it
+	// names is looked up when a device opens, and outputDeviceID holds the
+	// result once found (nil = not found yet, or no device selected).
+	// outputDeviceWarned records that the fallback to the default has been
+	// logged. All are guarded by deviceInitMutex.
+	outputDevice       string
+	outputDeviceWarned bool
+	outputDeviceID     *malgo.DeviceID
 }
 
 // NewAudioPlayer creates a new audio player instance
@@ -243,26 +268,7 @@ func (p *AudioPlayer) PlaySoundWithContext(ctx context.Context, soundID string)
 	default:
 	}
 	
-	// Initialize audio context if needed. sync.Once guarantees exactly one
-	// NewContext() / malgo.InitContext call across concurrent first-Play
-	// goroutines — preventing the C-side handle leak that occurred when the
-	// nil-check + assignment was racy.
-	p.contextInitOnce.Do(func() {
-		slog.Debug("initializing audio context for playback")
-		audioCtx, err := NewContext()
-		if err != nil {
-			p.contextInitErr = err
-			return
-		}
-		p.context = audioCtx
-	})
-	if p.contextInitErr != nil {
-		slog.Error("failed to initialize audio context", "sound_id", soundID, "error", p.contextInitErr)
-		return fmt.Errorf("failed to initialize audio context: %w", p.contextInitErr)
-	}
-	if p.context == nil {
-		err := fmt.Errorf("audio context not initialized")
-		slog.Error("audio context unexpectedly nil", "sound_id", soundID, "error", err)
+	if err := p.ensureContext(soundID); err != nil {
 		return err
 	}
 	
@@ -359,12 +365,9 @@ func (p *AudioPlayer) PlaySoundWithContext(ctx context.Context, soundID string)
 		Data: onSamples,
 	}
 	
-	// Create device. InitDevice touches shared C-side context state; serialize
-	// that narrow critical section while allowing playback callbacks to run
-	// concurrently after devices are initialized.
-	p.deviceInitMutex.Lock()
-	device, err := malgo.InitDevice(p.context.GetContext().Context, deviceConfig, deviceCallbacks)
-	p.deviceInitMutex.Unlock()
+	// Create device on the selected output; initDevice serializes the
+	// C-side context access.
+	device, err := p.initDevice(deviceConfig, deviceCallbacks)
 	if err != nil {
 		slog.Error("failed to initialize playback device", "sound_id", soundID, "error", err)
 		return fmt.Errorf("failed to initialize playback device: %w", err)
@@ -426,6 +429,128 @@ func (p *AudioPlayer) PlaySoundWithContext(ctx context.Context, soundID string)
 	return nil
 }
 
+// ensureContext initializes the shared audio context on first playback.
+// sync.Once guarantees exactly one NewContext() / malgo.InitContext call
+// across concurrent first-Play goroutines — preventing the C-side handle
+// leak that occurred when the nil-check + assignment was racy.
+func (p *AudioPlayer) ensureContext(soundID string) error {
+	p.contextInitOnce.Do(func() {
+		slog.Debug("initializing audio context for playback")
+		audioCtx, err := NewContext()
+		if err != nil {
+			p.contextInitErr = err
+			return
+		}
+		p.context = audioCtx
+	})
+	if p.contextInitErr != nil {
+		slog.Error("failed to initialize audio context", "sound_id", soundID, "error", p.contextInitErr)
+		return fmt.Errorf("failed to initialize audio context: %w", p.contextInitErr)
+	}
+	if p.context == nil {
+		err := fmt.Errorf("audio context not initialized")
+		slog.Error("audio context unexpectedly nil", "sound_id", soundID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// DeviceFormat returns the format, channel count, and sample rate the
+// selected playback device runs at natively. It probes for it by opening a
+// device with no format preferences, which miniaudio resolves to the
+// device's own, and closing it again; later calls reuse the result until
+// the selected device changes, through SetDevice, a missing device turning
+// up, or the selected device failing to open. A failed probe is not kept.
+// With SetFormatCacheDir, a format another process probed recently is used
+// instead of probing again. A native format Claudio cannot write falls
+// back to 32-bit float at the device's channel count and rate, leaving
+// miniaudio to convert only the sample format.
+func (p *AudioPlayer) DeviceFormat() (DeviceFormat, error) {
+	if err := p.ensureContext("device_format_probe"); err != nil {
+		return DeviceFormat{}, err
+	}
+
+	p.deviceFormatMutex.Lock()
+	defer p.deviceFormatMutex.Unlock()
+
+	selected := p.selectedDeviceKey()
+	stale := p.deviceFormatStale.Swap(false)
+	if p.deviceFormatProbed && p.deviceFormatDevice == selected && !stale {
+		return p.deviceFormat, p.deviceFormatErr
+	}
+	if format, ok := p.loadCachedFormat(selected); ok && !stale {
+		p.deviceFormat, p.deviceFormatErr = format, nil
+	} else {
+		p.deviceFormat, p.deviceFormatErr = p.probeDeviceFormat()
+		// The probe opened whichever device now works, so a failure it
+		// fell back from is already accounted for; but the format is the
+		// default device's, not one to share under the selected key.
+		fellBack := p.deviceFormatStale.Swap(false)
+		if p.deviceFormatErr == nil && !fellBack {
+			p.storeCachedFormat(selected, p.deviceFormat)
+		}
+	}
+	p.deviceFormatProbed = p.deviceFormatErr == nil
+	p.deviceFormatDevice = selected
+	return p.deviceFormat, p.deviceFormatErr
+}
+
+// probeDeviceFormat opens and closes the selected device to read its
+// native format; see DeviceFormat.
+func (p *AudioPlayer) probeDeviceFormat() (DeviceFormat, error) {
+	deviceConfig := malgo.DefaultDeviceConfig(malgo.Playback)
+	deviceConfig.Alsa.NoMMap = 1
+
+	device, err := p.initDevice(deviceConfig, malgo.DeviceCallbacks{})
+	if err != nil {
+		slog.Error("failed to probe playback device format", "error", err)
+		return DeviceFormat{}, fmt.Errorf("failed to probe playback device format: %w", err)
+	}
+	format := DeviceFormat{
+		Format:     device.PlaybackFormat(),
+		Channels:   device.PlaybackChannels(),
+		SampleRate: device.SampleRate(),
+	}
+	device.Uninit()
+
+	if format.Channels == 0 || format.SampleRate == 0 {
+		return DeviceFormat{}, fmt.Errorf("playback device reported an invalid format: %+v", format)
+	}
+	if _, err := getBytesPerSample(format.Format); err != nil {
+		slog.Debug("native device sample format unsupported, using f32", "format", format.Format)
+		format.Format = malgo.FormatF32
+	}
+	slog.Debug("probed playback device format",
+		"format", format.Format,
+		"channels", format.Channels,
+		"sample_rate", format.SampleRate)
+	return format, nil
+}
+
+// StopSound stops one playing sound by ID. A looping sound fades out and
+// its PlayLoopWithContext call returns; a one-shot sound stops at once.
+// Stopping a sound that is not playing is not an error.
+func (p *AudioPlayer) StopSound(soundID string) error {
+	p.deviceMutex.Lock()
+	entry, ok := p.devices[soundID]
+	if ok && entry.stop == nil {
+		delete(p.devices, soundID)
+	}
+	p.deviceMutex.Unlock()
+
+	if !ok {
+		slog.Debug("stop requested for sound that is not playing", "sound_id", soundID)
+		return nil
+	}
+	if entry.stop != nil {
+		entry.stop()
+	} else {
+		entry.uninit()
+	}
+	slog.Debug("sound stopped", "sound_id", soundID)
+	return nil
+}
+
 // Stop halts all currently playing sounds. Previously this method only
 // flipped an isPlaying flag without touching any malgo device — playback
 // continued, but IsPlaying reported false. Stop is now an alias for
@@ -525,14 +650,16 @@ func getBytesPerSample(format malgo.FormatType) (int, error) {
 	}
 }
 
-// applyVolumeToSamples applies volume scaling to audio samples based on format
+// applyVolumeToSamples applies volume scaling to audio samples based on
+// format. A volume above 1.0 (a loudness or reminder gain) saturates
+// integer samples at full scale instead of wrapping around.
 func applyVolumeToSamples(samples []byte, format malgo.FormatType, volume float32) {
 	switch format {
 	case malgo.FormatS16:
 		// 16-bit signed samples
 		for i := 0; i < len(samples)-1; i += 2 {
 			sample := int16(samples[i]) | int16(samples[i+1])<<8
-			sample = int16(float32(sample) * volume)
+			sample = int16(scaleSample(float64(sample), volume, math.MinInt16, math.MaxInt16))
 			samples[i] = byte(sample)
 			samples[i+1] = byte(sample >> 8)
 		}
@@ -547,7 +674,7 @@ func applyVolumeToSamples(samples []byte, format malgo.FormatType, volume float3
 			}
 			
 			// Apply volume
-			sample = int32(float32(sample) * volume)
+			sample = int32(scaleSample(float64(sample), volume, -1<<23, 1<<23-1))
 			
 			// Write back (little endian, truncate to 24-bit)
 			samples[i] = byte(sample)
@@ -558,13 +685,35 @@ func applyVolumeToSamples(samples []byte, format malgo.FormatType, volume float3
 		// 32-bit signed samples
 		for i := 0; i < len(samples)-3; i += 4 {
 			sample := int32(samples[i]) | int32(samples[i+1])<<8 | int32(samples[i+2])<<16 | int32(samples[i+3])<<24
-			sample = int32(float32(sample) * volume)
+			sample = int32(scaleSample(float64(sample), volume, math.MinInt32, math.MaxInt32))
 			samples[i] = byte(sample)
 			samples[i+1] = byte(sample >> 8)
 			samples[i+2] = byte(sample >> 16)
 			samples[i+3] = byte(sample >> 24)
 		}
+	case malgo.FormatF32:
+		// 32-bit float samples
+		for i := 0; i < len(samples)-3; i += 4 {
+			bits := uint32(samples[i]) | uint32(samples[i+1])<<8 | uint32(samples[i+2])<<16 | uint32(samples[i+3])<<24
+			bits = math.Float32bits(math.Float32frombits(bits) * volume)
+			samples[i] = byte(bits)
+			samples[i+1] = byte(bits >> 8)
+			samples[i+2] = byte(bits >> 16)
+			samples[i+3] = byte(bits >> 24)
+		}
 	default:
 		slog.Warn("volume adjustment not implemented for format", "format", format)
 	}
 }
+
+// scaleSample scales an integer sample by volume, saturating at lo and hi.
+func scaleSample(sample float64, volume float32, lo, hi float64) float64 {
+	v := sample * float64(volume)
+	if v > hi {
+		return hi
+	}
+	if v < lo {
+		return lo
+	}
+	return v
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"log/slog"
 	"math"
+	"os"
 	"strings"
 	"sync"
 	"testing"
@@ -770,3 +771,64 @@ func TestIsPlaying_ReflectsDeviceCount(t *testing.T) {
 		t.Error("IsPlaying should be false after device map is emptied")
 	}
 }
+
+// TestAudioPlayer_DeviceFormat checks that the probed device format is one
+// normalizeAudio can target, and that the probe runs only once.
+func TestAudioPlayer_DeviceFormat(t *testing.T) {
+	skipIfWSLMalgoPlayback(t)
+
+	player := NewAudioPlayer()
+	defer player.Close()
+
+	format, err := player.DeviceFormat()
+	if err != nil {
+		t.Skipf("no audio device available: %v", err)
+	}
+	if format.Channels == 0 || format.SampleRate == 0 {
+		t.Fatalf("probed format has no channels or rate: %+v", format)
+	}
+	if _, err := getBytesPerSample(format.Format); err != nil {
+		t.Fatalf("probed format %v cannot be written: %v", format.Format, err)
+	}
+	if again, _ := player.DeviceFormat(); again != format {
+		t.Errorf("second DeviceFormat = %+v, want cached %+v", again, format)
+	}
+}
+
+// TestAudioPlayer_DeviceFormatSharedCache checks that a format another
+// process recorded is used without probing, and a stale one is not.
+func TestAudioPlayer_DeviceFormatSharedCache(t *testing.T) {
+	skipIfWSLMalgoPlayback(t)
+
+	dir := t.TempDir()
+	recorded := DeviceFormat{Format: malgo.FormatS16, Channels: 3, SampleRate: 12345}
+	writer := NewAudioPlayer()
+	writer.SetFormatCacheDir(dir)
+	writer.storeCachedFormat("", recorded)
+
+	player := NewAudioPlayer()
+	defer player.Close()
+	player.SetFormatCacheDir(dir)
+	format, err := player.DeviceFormat()
+	if err != nil {
+		t.Fatalf("DeviceFormat: %v", err)
+	}
+	if format != recorded {
+		t.Errorf("DeviceFormat = %+v, want the recorded %+v", format, recorded)
+	}
+
+	old := time.Now().Add(-2 * deviceFormatCacheTTL)
+	if err := os.Chtimes(writer.formatCachePath(""), old, old); err != nil {
+		t.Fatal(err)
+	}
+	fresh := NewAudioPlayer()
+	defer fresh.Close()
+	fresh.SetFormatCacheDir(dir)
+	format, err = fresh.DeviceFormat()
+	if err != nil {
+		t.Skipf("no audio device available: %v", err)
+	}
+	if format == recorded {
+		t.Error("a stale cached format was used instead of probing")
+	}
+}
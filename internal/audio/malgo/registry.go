@@ -11,8 +11,10 @@ import (
 	"strings"
 	"sync"
 
+	"claudio.click/internal/audio/pcmcache"
 	"claudio.click/internal/safeio"
 	"github.com/gabriel-vasile/mimetype"
+	"github.com/gen2brain/malgo"
 )
 
 // mimeToFormat maps canonical MIME types to format names recognized by the
@@ -28,6 +30,28 @@ var mimeToFormat = map[string]string{
 	"audio/mp3":      "MP3",
 	"audio/aiff":     "AIFF",
 	"audio/x-aiff":   "AIFF",
+	"audio/flac":     "FLAC",
+	"audio/x-flac":   "FLAC",
+}
+
+// oggCodecFormat names the decoder for an Ogg stream from the codec
+// signature of its first packet, which mimetype only reports as audio/ogg.
+// The first page of a Vorbis or Opus stream holds just the identification
+// header, so the packet starts right after the 27-byte page header and its
+// one-byte segment table.
+func oggCodecFormat(header []byte) (string, bool) {
+	const firstPacket = 28
+	if len(header) <= firstPacket {
+		return "", false
+	}
+	packet := header[firstPacket:]
+	switch {
+	case bytes.HasPrefix(packet, []byte("\x01vorbis")):
+		return "VORBIS", true
+	case bytes.HasPrefix(packet, []byte("OpusHead")):
+		return "OPUS", true
+	}
+	return "", false
 }
 
 // DecoderRegistry manages audio format decoders and provides format detection.
@@ -38,6 +62,7 @@ var mimeToFormat = map[string]string{
 type DecoderRegistry struct {
 	mu       sync.RWMutex
 	decoders []Decoder
+	cache    *pcmcache.Cache
 }
 
 // NewDecoderRegistry creates a new empty decoder registry
@@ -48,9 +73,18 @@ func NewDecoderRegistry() *DecoderRegistry {
 	}
 }
 
-// NewDefaultRegistry creates a registry with default WAV, MP3, and AIFF decoders
+// SetCache makes DecodeFile look decoded audio up in cache before decoding
+// and store what it decodes there. A nil cache turns caching off.
+func (r *DecoderRegistry) SetCache(cache *pcmcache.Cache) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cache = cache
+}
+
+// NewDefaultRegistry creates a registry with default WAV, MP3, AIFF, FLAC,
+// Ogg Vorbis, and Ogg Opus decoders
 func NewDefaultRegistry() *DecoderRegistry {
-	slog.Debug("creating default decoder registry with WAV, MP3, and AIFF support")
+	slog.Debug("creating default decoder registry with WAV, MP3, AIFF, FLAC, Vorbis, and Opus support")
 
 	registry := NewDecoderRegistry()
 
@@ -58,6 +92,9 @@ func NewDefaultRegistry() *DecoderRegistry {
 	registry.Register(NewWavDecoder())
 	registry.Register(NewMp3Decoder())
 	registry.Register(NewAiffDecoder())
+	registry.Register(NewFlacDecoder())
+	registry.Register(NewVorbisDecoder())
+	registry.Register(NewOpusDecoder())
 
 	slog.Debug("default decoder registry initialized",
 		"supported_formats", registry.GetSupportedFormats())
@@ -175,7 +212,11 @@ func (r *DecoderRegistry) DetectFormatWithContent(filename string, reader io.Rea
 	// allowed e.g. audio/x-wavpack to misroute to the WAV decoder.
 	mimeStr := strings.ToLower(detectedMime)
 	var formatDecoder Decoder
-	if formatName, ok := mimeToFormat[mimeStr]; ok {
+	formatName, ok := mimeToFormat[mimeStr]
+	if !ok && (mimeStr == "audio/ogg" || mimeStr == "application/ogg") {
+		formatName, ok = oggCodecFormat(buffer[:n])
+	}
+	if ok {
 		formatDecoder = r.findDecoderByFormatLocked(formatName)
 		slog.Debug("magic bytes recognized", "mime", detectedMime, "format", formatName)
 	} else {
@@ -258,6 +299,24 @@ func (r *DecoderRegistry) DecodeFile(ctx context.Context, filename string, reade
 		"filename", filename,
 		"decoder_format", decoder.FormatName())
 
+	r.mu.RLock()
+	cache := r.cache
+	r.mu.RUnlock()
+	var cacheKey string
+	if cache != nil {
+		cacheKey = pcmcache.Key(fullContent, decoder.FormatName(), DecoderVersion)
+		if entry, ok := cache.Get(cacheKey); ok {
+			audioData, err := audioDataFromCacheEntry(entry)
+			if err == nil {
+				slog.Debug("decoded audio served from cache",
+					"filename", filename,
+					"decoder_format", decoder.FormatName())
+				return audioData, nil
+			}
+			slog.Warn("ignoring unusable cached audio", "filename", filename, "error", err)
+		}
+	}
+
 	// Create fresh reader from buffered content for decoder
 	decoderReader := bytes.NewReader(fullContent)
 	audioData, err := decoder.Decode(ctx, decoderReader)
@@ -269,6 +328,18 @@ func (r *DecoderRegistry) DecodeFile(ctx context.Context, filename string, reade
 		return nil, err
 	}
 
+	if cache != nil {
+		entry := &pcmcache.Entry{
+			Format:     uint32(audioData.Format),
+			Channels:   audioData.Channels,
+			SampleRate: audioData.SampleRate,
+			Samples:    audioData.Samples,
+		}
+		if err := cache.Put(cacheKey, entry); err != nil {
+			slog.Warn("failed to cache decoded audio", "filename", filename, "error", err)
+		}
+	}
+
 	slog.Debug("file decode completed successfully",
 		"filename", filename,
 		"decoder_format", decoder.FormatName(),
@@ -278,3 +349,22 @@ func (r *DecoderRegistry) DecodeFile(ctx context.Context, filename string, reade
 
 	return audioData, nil
 }
+
+// audioDataFromCacheEntry checks that a cached entry describes audio the
+// player can use before handing it out.
+func audioDataFromCacheEntry(entry *pcmcache.Entry) (*AudioData, error) {
+	format := malgo.FormatType(entry.Format)
+	bytesPerSample, err := getBytesPerSample(format)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Channels == 0 || entry.SampleRate == 0 || len(entry.Samples)%(bytesPerSample*int(entry.Channels)) != 0 {
+		return nil, fmt.Errorf("%w: cached audio has an invalid layout", ErrInvalidData)
+	}
+	return &AudioData{
+		Samples:    entry.Samples,
+		Channels:   entry.Channels,
+		SampleRate: entry.SampleRate,
+		Format:     format,
+	}, nil
+}
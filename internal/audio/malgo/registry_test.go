@@ -5,10 +5,13 @@ package malgo
 import (
 	"bytes"
 	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"claudio.click/internal/audio/pcmcache"
 	"github.com/gen2brain/malgo"
 )
 
@@ -319,10 +322,10 @@ func TestNewDefaultRegistry(t *testing.T) {
 		t.Fatal("NewDefaultRegistry returned nil")
 	}
 
-	// Should have WAV, MP3, and AIFF decoders registered
+	// Should have WAV, MP3, AIFF, FLAC, Vorbis, and Opus decoders registered
 	formats := registry.GetSupportedFormats()
-	if len(formats) != 3 {
-		t.Errorf("expected 3 default formats, got %d", len(formats))
+	if len(formats) != 6 {
+		t.Errorf("expected 6 default formats, got %d", len(formats))
 	}
 
 	// Check for WAV support
@@ -354,6 +357,59 @@ func TestNewDefaultRegistry(t *testing.T) {
 	if aiffDecoder.FormatName() != "AIFF" {
 		t.Errorf("expected AIFF decoder, got %s", aiffDecoder.FormatName())
 	}
+
+	for filename, want := range map[string]string{
+		"test.flac": "FLAC",
+		"test.ogg":  "VORBIS",
+		"test.oga":  "VORBIS",
+		"test.opus": "OPUS",
+	} {
+		decoder := registry.DetectFormat(filename)
+		if decoder == nil || decoder.FormatName() != want {
+			t.Errorf("DetectFormat(%q) = %v, want %s decoder", filename, decoder, want)
+		}
+	}
+}
+
+// TestDetectFormatWithContent_OggCodecSniffing checks that Ogg streams are
+// routed by codec rather than extension: mimetype reports audio/ogg for
+// both Vorbis and Opus, so an .ogg file holding Opus must still reach the
+// Opus decoder.
+func TestDetectFormatWithContent_OggCodecSniffing(t *testing.T) {
+	registry := NewDefaultRegistry()
+	vorbisData, err := os.ReadFile("testdata/vorbis.ogg")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	opusData, err := os.ReadFile("testdata/tiny.opus")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	cases := []struct {
+		filename string
+		content  []byte
+		want     string
+	}{
+		{"opus-in-ogg.ogg", opusData, "OPUS"},
+		{"vorbis.opus", vorbisData, "VORBIS"},
+		{"vorbis.unknown", vorbisData, "VORBIS"},
+		{"flac.unknown", []byte("fLaC\x00\x00\x00\x22"), "FLAC"},
+	}
+	for _, tc := range cases {
+		decoder := registry.DetectFormatWithContent(tc.filename, bytes.NewReader(tc.content))
+		if decoder == nil || decoder.FormatName() != tc.want {
+			t.Errorf("DetectFormatWithContent(%q) = %v, want %s decoder", tc.filename, decoder, tc.want)
+		}
+	}
+
+	audioData, err := registry.DecodeFile(context.Background(), "opus-in-ogg.ogg", bytes.NewReader(opusData))
+	if err != nil {
+		t.Fatalf("DecodeFile of Opus in .ogg: %v", err)
+	}
+	if audioData.SampleRate != opusSampleRate {
+		t.Errorf("expected Opus decode at %d Hz, got %d", opusSampleRate, audioData.SampleRate)
+	}
 }
 
 // Helper function to check if a format is in the list
@@ -741,3 +797,64 @@ func TestDetectFormatWithContent_ExactMimeMatching(t *testing.T) {
 		})
 	}
 }
+
+// TestDecodeFile_UsesCache checks that a registry with a cache decodes a
+// file once and serves later decodes of the same content from the cache.
+func TestDecodeFile_UsesCache(t *testing.T) {
+	cache := pcmcache.New(t.TempDir(), 0)
+	decoder := &MockDecoder{
+		formatName: "MOCK",
+		extensions: []string{".mock"},
+		returnData: &AudioData{Samples: []byte{1, 2, 3, 4}, Channels: 1, SampleRate: 22050, Format: malgo.FormatS16},
+	}
+	registry := NewDecoderRegistry()
+	registry.Register(decoder)
+	registry.SetCache(cache)
+
+	content := []byte("not really audio")
+	first, err := registry.DecodeFile(context.Background(), "sound.mock", bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("first DecodeFile: %v", err)
+	}
+	if stats, _ := cache.Stats(); stats.Entries != 1 {
+		t.Fatalf("expected the decode to be cached, got %+v", stats)
+	}
+
+	// A failing decoder proves the second result comes from the cache.
+	decoder.shouldFail = true
+	second, err := registry.DecodeFile(context.Background(), "renamed.mock", bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("cached DecodeFile: %v", err)
+	}
+	if !bytes.Equal(second.Samples, first.Samples) || second.Channels != 1 ||
+		second.SampleRate != 22050 || second.Format != malgo.FormatS16 {
+		t.Errorf("cached audio = %+v, want %+v", second, first)
+	}
+
+	// Different content misses the cache and reaches the decoder.
+	if _, err := registry.DecodeFile(context.Background(), "sound.mock", bytes.NewReader([]byte("edited"))); err == nil {
+		t.Error("expected changed content to bypass the cache")
+	}
+}
+
+func TestBackend_WarmFillsCache(t *testing.T) {
+	wavBytes := generateTestWAV()
+	path := filepath.Join(t.TempDir(), "warm.wav")
+	if err := os.WriteFile(path, wavBytes, 0o644); err != nil {
+		t.Fatalf("failed to write WAV: %v", err)
+	}
+
+	backend := NewBackend()
+	defer backend.Close()
+	if err := backend.Warm(context.Background(), path); err != nil {
+		t.Fatalf("Warm: %v", err)
+	}
+
+	cache := pcmcache.New(pcmcache.DefaultDir(), 0)
+	if _, ok := cache.Get(pcmcache.Key(wavBytes, "WAV", DecoderVersion)); !ok {
+		t.Errorf("expected %s to be cached under %s", path, cache.Dir())
+	}
+	if err := backend.Warm(context.Background(), filepath.Join(t.TempDir(), "missing.wav")); err == nil {
+		t.Error("expected warming a missing file to fail")
+	}
+}
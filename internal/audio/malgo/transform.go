@@ -0,0 +1,374 @@
+//go:build cgo
+
+package malgo
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+
+	"claudio.click/internal/audio"
+	"github.com/gen2brain/malgo"
+)
+
+// DeviceFormat is the sample format, channel count, and rate a playback
+// device consumes. Sounds are normalized to it before PreloadSound so one
+// device configuration serves every sound in a pack, whatever mix of
+// rates and layouts its files were recorded in.
+type DeviceFormat struct {
+	Format     malgo.FormatType
+	Channels   uint32
+	SampleRate uint32
+}
+
+// matches reports whether data already has f's layout.
+func (f DeviceFormat) matches(data *AudioData) bool {
+	return data.Format == f.Format && data.Channels == f.Channels && data.SampleRate == f.SampleRate
+}
+
+// normalizeAudio converts data to target's sample rate, channel count, and
+// sample format. It sits after applyVariation and before PreloadSound, so
+// the realtime callback never converts anything. Data already in the
+// target layout is returned unchanged.
+func normalizeAudio(data *AudioData, target DeviceFormat) (*AudioData, error) {
+	if target.matches(data) {
+		return data, nil
+	}
+	if data.Channels == 0 || data.SampleRate == 0 {
+		return nil, fmt.Errorf("cannot normalize sound: %w", ErrInvalidData)
+	}
+	if target.Channels == 0 || target.SampleRate == 0 {
+		return nil, fmt.Errorf("cannot normalize sound: invalid device format %+v", target)
+	}
+
+	samples, err := pcmToFloat32(data.Samples, data.Format)
+	if err != nil {
+		return nil, fmt.Errorf("cannot normalize sound: %w", err)
+	}
+	samples = mixChannels(samples, int(data.Channels), int(target.Channels))
+	samples = resampleRate(samples, int(target.Channels), data.SampleRate, target.SampleRate)
+	pcm, err := encodePCM(samples, target.Format)
+	if err != nil {
+		return nil, fmt.Errorf("cannot normalize sound: %w", err)
+	}
+
+	return &AudioData{
+		Samples:    pcm,
+		Channels:   target.Channels,
+		SampleRate: target.SampleRate,
+		Format:     target.Format,
+	}, nil
+}
+
+// mixChannels remaps interleaved samples from one channel count to
+// another. Channels are assumed to follow the WAV/FLAC order (front left,
+// front right, centre, LFE, then surround pairs):
+//
+//   - anything to mono averages every channel;
+//   - mono to several channels plays the sound in both front speakers;
+//   - surround to stereo folds the centre and surround channels into the
+//     front pair at -3 dB, drops the LFE, and rescales so it cannot clip;
+//   - any other change keeps the channels both layouts share and leaves
+//     the rest silent.
+func mixChannels(samples []float32, from, to int) []float32 {
+	if from == to {
+		return samples
+	}
+	frames := len(samples) / from
+	weights := channelMixWeights(from, to)
+	out := make([]float32, frames*to)
+	for i := 0; i < frames; i++ {
+		in := samples[i*from : i*from+from]
+		for o := 0; o < to; o++ {
+			var acc float32
+			for c, w := range weights[o] {
+				acc += in[c] * w
+			}
+			out[i*to+o] = acc
+		}
+	}
+	return out
+}
+
+// channelMixWeights returns, for each output channel, the weight of each
+// input channel under the rules documented on mixChannels.
+func channelMixWeights(from, to int) [][]float32 {
+	weights := make([][]float32, to)
+	for o := range weights {
+		weights[o] = make([]float32, from)
+	}
+
+	switch {
+	case to == 1:
+		for c := range weights[0] {
+			weights[0][c] = 1 / float32(from)
+		}
+	case from == 1:
+		weights[0][0] = 1
+		weights[1][0] = 1
+	case to == 2 && from > 2:
+		const minus3dB = float32(math.Sqrt2 / 2)
+		weights[0][0], weights[1][1] = 1, 1
+		for c := 2; c < from; c++ {
+			switch {
+			case c == 2: // centre
+				weights[0][c], weights[1][c] = minus3dB, minus3dB
+			case c == 3: // LFE
+			case c%2 == 0:
+				weights[0][c] = minus3dB
+			default:
+				weights[1][c] = minus3dB
+			}
+		}
+		for o := range weights {
+			var sum float32
+			for _, w := range weights[o] {
+				sum += w
+			}
+			for c := range weights[o] {
+				weights[o][c] /= sum
+			}
+		}
+	default:
+		for c := 0; c < from && c < to; c++ {
+			weights[c][c] = 1
+		}
+	}
+	return weights
+}
+
+const (
+	// sincZeroCrossings is how many zero crossings of the sinc kernel
+	// resampleRate uses on each side of an output sample.
+	sincZeroCrossings = 16
+
+	// sincTableResolution is the number of kernel table entries per zero
+	// crossing; lookups interpolate linearly between them.
+	sincTableResolution = 512
+)
+
+var (
+	sincTableOnce sync.Once
+	sincTable     []float64
+)
+
+// sincKernel returns the Blackman-windowed sinc kernel at x, for
+// |x| < sincZeroCrossings, from a table built on first use.
+func sincKernel(x float64) float64 {
+	sincTableOnce.Do(func() {
+		n := sincZeroCrossings*sincTableResolution + 1
+		sincTable = make([]float64, n+1)
+		for i := 0; i < n; i++ {
+			u := float64(i) / sincTableResolution
+			sinc := 1.0
+			if u != 0 {
+				sinc = math.Sin(math.Pi*u) / (math.Pi * u)
+			}
+			t := u / sincZeroCrossings
+			window := 0.42 + 0.5*math.Cos(math.Pi*t) + 0.08*math.Cos(2*math.Pi*t)
+			sincTable[i] = sinc * window
+		}
+	})
+
+	pos := math.Abs(x) * sincTableResolution
+	i := int(pos)
+	if i >= len(sincTable)-1 {
+		return 0
+	}
+	frac := pos - float64(i)
+	return sincTable[i] + (sincTable[i+1]-sincTable[i])*frac
+}
+
+// resampleRate converts interleaved samples from one sample rate to
+// another with a windowed-sinc filter. When downsampling, the filter's
+// cutoff drops to the new Nyquist frequency so content the target rate
+// cannot represent is removed rather than aliased.
+func resampleRate(samples []float32, channels int, fromRate, toRate uint32) []float32 {
+	frames := len(samples) / channels
+	if fromRate == toRate || frames == 0 {
+		return samples
+	}
+
+	ratio := float64(toRate) / float64(fromRate)
+	cutoff := math.Min(1, ratio)
+	halfWidth := sincZeroCrossings / cutoff
+	outFrames := int(math.Round(float64(frames) * ratio))
+	out := make([]float32, outFrames*channels)
+	acc := make([]float64, channels)
+
+	for i := 0; i < outFrames; i++ {
+		center := float64(i) / ratio
+		lo := int(math.Ceil(center - halfWidth))
+		hi := int(math.Floor(center + halfWidth))
+		if lo < 0 {
+			lo = 0
+		}
+		if hi > frames-1 {
+			hi = frames - 1
+		}
+
+		for c := range acc {
+			acc[c] = 0
+		}
+		for j := lo; j <= hi; j++ {
+			w := cutoff * sincKernel((float64(j)-center)*cutoff)
+			if w == 0 {
+				continue
+			}
+			frame := samples[j*channels : j*channels+channels]
+			for c, s := range frame {
+				acc[c] += w * float64(s)
+			}
+		}
+		for c, v := range acc {
+			out[i*channels+c] = float32(v)
+		}
+	}
+	return out
+}
+
+// applyVariation returns data pitch-shifted and panned for v as 32-bit
+// float PCM, ready for PreloadSound. It sits between DecodeFile and
+// PreloadSound so the realtime callback only ever copies samples. A v
+// that shifts neither pitch nor pan returns data unchanged; v's gain is
+// left to load.
+//
+// The pitch shift resamples by linear interpolation and plays the result
+// at the original rate, so a higher pitch also plays slightly faster: for
+// the few-semitone shifts used to tell sessions apart that is inaudible on
+// short UI sounds. Panning turns mono into stereo; sounds with more than
+// two channels keep their layout and are only pitch-shifted.
+func applyVariation(data *AudioData, v audio.Variation) (*AudioData, error) {
+	if !v.ShiftsPitchOrPan() {
+		return data, nil
+	}
+	if data.Channels == 0 {
+		return nil, fmt.Errorf("cannot vary sound: %w", ErrInvalidData)
+	}
+
+	samples, err := pcmToFloat32(data.Samples, data.Format)
+	if err != nil {
+		return nil, fmt.Errorf("cannot vary sound: %w", err)
+	}
+	channels := int(data.Channels)
+	samples = resampleFrames(samples, channels, v.EffectivePitch())
+	if v.Pan != 0 && channels <= 2 {
+		samples = panFrames(samples, channels, v)
+		channels = 2
+	}
+
+	return &AudioData{
+		Samples:    float32ToPCM(samples),
+		Channels:   uint32(channels),
+		SampleRate: data.SampleRate,
+		Format:     malgo.FormatF32,
+	}, nil
+}
+
+// resampleFrames stretches interleaved samples by 1/ratio frames, reading
+// between source frames by linear interpolation.
+func resampleFrames(samples []float32, channels int, ratio float64) []float32 {
+	frames := len(samples) / channels
+	if ratio == 1.0 || frames < 2 {
+		return samples
+	}
+	outFrames := int(float64(frames) / ratio)
+	out := make([]float32, outFrames*channels)
+	for i := 0; i < outFrames; i++ {
+		pos := float64(i) * ratio
+		j := int(pos)
+		if j >= frames-1 {
+			j = frames - 2
+		}
+		frac := float32(pos - float64(j))
+		for c := 0; c < channels; c++ {
+			a, b := samples[j*channels+c], samples[(j+1)*channels+c]
+			out[i*channels+c] = a + (b-a)*frac
+		}
+	}
+	return out
+}
+
+// panFrames returns mono or stereo samples as stereo weighted by v's pan
+// gains.
+func panFrames(samples []float32, channels int, v audio.Variation) []float32 {
+	l, r := v.PanGains()
+	left, right := float32(l), float32(r)
+	frames := len(samples) / channels
+	out := make([]float32, frames*2)
+	for i := 0; i < frames; i++ {
+		sl := samples[i*channels]
+		sr := samples[i*channels+channels-1] // the same sample for mono
+		out[2*i] = sl * left
+		out[2*i+1] = sr * right
+	}
+	return out
+}
+
+// pcmToFloat32 converts little-endian PCM in format to samples in [-1, 1].
+func pcmToFloat32(pcm []byte, format malgo.FormatType) ([]float32, error) {
+	bytesPerSample, err := getBytesPerSample(format)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]float32, len(pcm)/bytesPerSample)
+	for i := range out {
+		b := pcm[i*bytesPerSample:]
+		switch format {
+		case malgo.FormatU8:
+			out[i] = (float32(b[0]) - 128) / 128
+		case malgo.FormatS16:
+			out[i] = float32(int16(binary.LittleEndian.Uint16(b))) / 32768
+		case malgo.FormatS24:
+			s := int32(b[0]) | int32(b[1])<<8 | int32(b[2])<<16
+			if s&0x800000 != 0 {
+				s |= ^0xFFFFFF
+			}
+			out[i] = float32(s) / 8388608
+		case malgo.FormatS32:
+			out[i] = float32(float64(int32(binary.LittleEndian.Uint32(b))) / 2147483648)
+		case malgo.FormatF32:
+			out[i] = math.Float32frombits(binary.LittleEndian.Uint32(b))
+		}
+	}
+	return out, nil
+}
+
+// float32ToPCM encodes samples as little-endian 32-bit float PCM.
+func float32ToPCM(samples []float32) []byte {
+	out := make([]byte, len(samples)*4)
+	for i, s := range samples {
+		binary.LittleEndian.PutUint32(out[i*4:], math.Float32bits(s))
+	}
+	return out
+}
+
+// encodePCM encodes samples in [-1, 1] as little-endian PCM in format.
+// Integer formats clip samples outside that range.
+func encodePCM(samples []float32, format malgo.FormatType) ([]byte, error) {
+	if format == malgo.FormatF32 {
+		return float32ToPCM(samples), nil
+	}
+	bytesPerSample, err := getBytesPerSample(format)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(samples)*bytesPerSample)
+	for i, s := range samples {
+		v := math.Max(-1, math.Min(1, float64(s)))
+		b := out[i*bytesPerSample:]
+		switch format {
+		case malgo.FormatU8:
+			b[0] = byte(math.Min(255, math.Round(v*128+128)))
+		case malgo.FormatS16:
+			binary.LittleEndian.PutUint16(b, uint16(int16(math.Min(32767, math.Round(v*32768)))))
+		case malgo.FormatS24:
+			n := int32(math.Min(8388607, math.Round(v*8388608)))
+			b[0], b[1], b[2] = byte(n), byte(n>>8), byte(n>>16)
+		case malgo.FormatS32:
+			binary.LittleEndian.PutUint32(b, uint32(int32(math.Min(2147483647, math.Round(v*2147483648)))))
+		}
+	}
+	return out, nil
+}
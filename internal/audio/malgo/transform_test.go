@@ -0,0 +1,241 @@
+//go:build cgo
+
+package malgo
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"claudio.click/internal/audio"
+	"github.com/gen2brain/malgo"
+)
+
+// monoS16 builds 16-bit mono PCM from sample values.
+func monoS16(values ...int16) *AudioData {
+	pcm := make([]byte, len(values)*2)
+	for i, v := range values {
+		binary.LittleEndian.PutUint16(pcm[i*2:], uint16(v))
+	}
+	return &AudioData{Samples: pcm, Channels: 1, SampleRate: 44100, Format: malgo.FormatS16}
+}
+
+func TestApplyVariation_NeutralIsUnchanged(t *testing.T) {
+	data := monoS16(1, 2, 3)
+	got, err := applyVariation(data, audio.Variation{})
+	if err != nil || got != data {
+		t.Errorf("neutral variation should return the data itself, got %p, %v", got, err)
+	}
+}
+
+func TestApplyVariation_PitchAndPan(t *testing.T) {
+	data := monoS16(0, 8192, 16384, 24576, 16384, 8192, 0, -8192)
+
+	got, err := applyVariation(data, audio.Variation{Pitch: 2, Pan: 0.5})
+	if err != nil {
+		t.Fatalf("applyVariation: %v", err)
+	}
+	if got.Format != malgo.FormatF32 || got.Channels != 2 || got.SampleRate != 44100 {
+		t.Fatalf("got format %v, %d channels at %d Hz; want stereo f32 at the source rate",
+			got.Format, got.Channels, got.SampleRate)
+	}
+
+	samples, err := pcmToFloat32(got.Samples, got.Format)
+	if err != nil {
+		t.Fatalf("pcmToFloat32: %v", err)
+	}
+	// Twice the pitch keeps every other frame; pan 0.5 halves the left.
+	wantRight := []float32{0, 0.5, 0.5, 0}
+	if len(samples) != len(wantRight)*2 {
+		t.Fatalf("got %d samples, want %d stereo frames", len(samples), len(wantRight))
+	}
+	for i, want := range wantRight {
+		l, r := samples[2*i], samples[2*i+1]
+		if math.Abs(float64(r-want)) > 1e-6 || math.Abs(float64(l-want/2)) > 1e-6 {
+			t.Errorf("frame %d = (%g, %g), want (%g, %g)", i, l, r, want/2, want)
+		}
+	}
+}
+
+func TestPCMToFloat32_Formats(t *testing.T) {
+	tests := []struct {
+		name   string
+		format malgo.FormatType
+		pcm    []byte
+		want   float32
+	}{
+		{"u8", malgo.FormatU8, []byte{64}, -0.5},
+		{"s16", malgo.FormatS16, []byte{0x00, 0xC0}, -0.5},
+		{"s24", malgo.FormatS24, []byte{0x00, 0x00, 0x40}, 0.5},
+		{"s32", malgo.FormatS32, []byte{0x00, 0x00, 0x00, 0xC0}, -0.5},
+		{"f32", malgo.FormatF32, float32ToPCM([]float32{0.25}), 0.25},
+	}
+	for _, tt := range tests {
+		got, err := pcmToFloat32(tt.pcm, tt.format)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(got) != 1 || got[0] != tt.want {
+			t.Errorf("%s: got %v, want [%g]", tt.name, got, tt.want)
+		}
+	}
+}
+
+// sineF32 builds frames of a sine at freq Hz and amplitude 0.5, repeated
+// across channels, as 32-bit float PCM.
+func sineF32(freq float64, rate uint32, channels, frames int) *AudioData {
+	samples := make([]float32, frames*channels)
+	for i := 0; i < frames; i++ {
+		v := float32(0.5 * math.Sin(2*math.Pi*freq*float64(i)/float64(rate)))
+		for c := 0; c < channels; c++ {
+			samples[i*channels+c] = v
+		}
+	}
+	return &AudioData{Samples: float32ToPCM(samples), Channels: uint32(channels), SampleRate: rate, Format: malgo.FormatF32}
+}
+
+func TestNormalizeAudio_MatchingIsUnchanged(t *testing.T) {
+	data := monoS16(1, 2, 3)
+	got, err := normalizeAudio(data, DeviceFormat{Format: malgo.FormatS16, Channels: 1, SampleRate: 44100})
+	if err != nil || got != data {
+		t.Errorf("matching format should return the data itself, got %p, %v", got, err)
+	}
+}
+
+func TestNormalizeAudio_MonoS16ToStereoF32(t *testing.T) {
+	data := monoS16(0, 16384, -16384, 8192)
+	data.SampleRate = 48000
+
+	got, err := normalizeAudio(data, DeviceFormat{Format: malgo.FormatF32, Channels: 2, SampleRate: 48000})
+	if err != nil {
+		t.Fatalf("normalizeAudio: %v", err)
+	}
+	if got.Format != malgo.FormatF32 || got.Channels != 2 || got.SampleRate != 48000 {
+		t.Fatalf("got format %v, %d channels at %d Hz; want stereo f32 at 48000 Hz",
+			got.Format, got.Channels, got.SampleRate)
+	}
+	samples, _ := pcmToFloat32(got.Samples, got.Format)
+	want := []float32{0, 0, 0.5, 0.5, -0.5, -0.5, 0.25, 0.25}
+	if len(samples) != len(want) {
+		t.Fatalf("got %d samples, want %d", len(samples), len(want))
+	}
+	for i := range want {
+		if samples[i] != want[i] {
+			t.Errorf("sample %d = %g, want %g", i, samples[i], want[i])
+		}
+	}
+}
+
+func TestNormalizeAudio_ResamplesPreservingPitch(t *testing.T) {
+	const freq = 1000.0
+	data := sineF32(freq, 22050, 1, 22050/10)
+
+	got, err := normalizeAudio(data, DeviceFormat{Format: malgo.FormatF32, Channels: 2, SampleRate: 48000})
+	if err != nil {
+		t.Fatalf("normalizeAudio: %v", err)
+	}
+	samples, _ := pcmToFloat32(got.Samples, got.Format)
+	frames := len(samples) / 2
+	if frames != 4800 {
+		t.Fatalf("got %d frames, want 4800 (the same 100 ms at 48 kHz)", frames)
+	}
+	// Away from the edges, where the filter runs out of input, the output
+	// is the same sine sampled at the new rate.
+	for i := 200; i < frames-200; i++ {
+		want := 0.5 * math.Sin(2*math.Pi*freq*float64(i)/48000)
+		if diff := math.Abs(float64(samples[2*i]) - want); diff > 1e-3 {
+			t.Fatalf("frame %d = %g, want %g (diff %g)", i, samples[2*i], want, diff)
+		}
+		if samples[2*i] != samples[2*i+1] {
+			t.Fatalf("frame %d channels differ: %g vs %g", i, samples[2*i], samples[2*i+1])
+		}
+	}
+}
+
+func TestNormalizeAudio_DownsampleRemovesAliases(t *testing.T) {
+	// 20 kHz is above the 11025 Hz Nyquist limit of a 22050 Hz device, so
+	// it must be filtered out rather than folded down to an audible tone.
+	data := sineF32(20000, 48000, 1, 4800)
+
+	got, err := normalizeAudio(data, DeviceFormat{Format: malgo.FormatF32, Channels: 1, SampleRate: 22050})
+	if err != nil {
+		t.Fatalf("normalizeAudio: %v", err)
+	}
+	samples, _ := pcmToFloat32(got.Samples, got.Format)
+	var sum float64
+	inner := samples[100 : len(samples)-100]
+	for _, s := range inner {
+		sum += float64(s) * float64(s)
+	}
+	if rms := math.Sqrt(sum / float64(len(inner))); rms > 0.01 {
+		t.Errorf("aliased tone RMS = %g, want < 0.01", rms)
+	}
+}
+
+func TestMixChannels(t *testing.T) {
+	tests := []struct {
+		name     string
+		samples  []float32
+		from, to int
+		want     []float32
+	}{
+		{"stereo to mono averages", []float32{1, 0, 0.5, -0.5}, 2, 1, []float32{0.5, 0}},
+		{"mono to quad uses front pair", []float32{0.5}, 1, 4, []float32{0.5, 0.5, 0, 0}},
+		{"stereo to quad keeps shared channels", []float32{0.25, -0.25}, 2, 4, []float32{0.25, -0.25, 0, 0}},
+		{"quad to three drops the rest", []float32{0.1, 0.2, 0.3, 0.4}, 4, 3, []float32{0.1, 0.2, 0.3}},
+	}
+	for _, tt := range tests {
+		got := mixChannels(tt.samples, tt.from, tt.to)
+		if len(got) != len(tt.want) {
+			t.Fatalf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+		for i := range tt.want {
+			if math.Abs(float64(got[i]-tt.want[i])) > 1e-6 {
+				t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestMixChannels_SurroundToStereoCannotClip(t *testing.T) {
+	// Every 5.1 channel at full scale must still fit in [-1, 1], with the
+	// LFE (channel 3) left out of the fold-down.
+	got := mixChannels([]float32{1, 1, 1, 1, 1, 1}, 6, 2)
+	for i, s := range got {
+		if math.Abs(float64(s)-1) > 1e-6 {
+			t.Errorf("channel %d = %g, want 1", i, s)
+		}
+	}
+	lfeOnly := mixChannels([]float32{0, 0, 0, 1, 0, 0}, 6, 2)
+	if lfeOnly[0] != 0 || lfeOnly[1] != 0 {
+		t.Errorf("LFE leaked into stereo: %v", lfeOnly)
+	}
+	centreOnly := mixChannels([]float32{0, 0, 1, 0, 0, 0}, 6, 2)
+	if centreOnly[0] <= 0 || centreOnly[0] != centreOnly[1] {
+		t.Errorf("centre should reach both sides equally: %v", centreOnly)
+	}
+}
+
+func TestEncodePCM_RoundTripsAndClips(t *testing.T) {
+	samples := []float32{-1, -0.5, 0, 0.5, 1.5}
+	for _, format := range []malgo.FormatType{malgo.FormatU8, malgo.FormatS16, malgo.FormatS24, malgo.FormatS32, malgo.FormatF32} {
+		pcm, err := encodePCM(samples, format)
+		if err != nil {
+			t.Fatalf("format %v: %v", format, err)
+		}
+		got, err := pcmToFloat32(pcm, format)
+		if err != nil {
+			t.Fatalf("format %v: %v", format, err)
+		}
+		want := []float32{-1, -0.5, 0, 0.5, 1}
+		if format == malgo.FormatF32 {
+			want[4] = 1.5 // float output is not clipped
+		}
+		for i := range want {
+			if math.Abs(float64(got[i]-want[i])) > 0.01 {
+				t.Errorf("format %v sample %d = %g, want %g", format, i, got[i], want[i])
+			}
+		}
+	}
+}
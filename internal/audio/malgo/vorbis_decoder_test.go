@@ -0,0 +1,100 @@
+//go:build cgo
+
+package malgo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/gen2brain/malgo"
+)
+
+func TestVorbisDecoderInterface(t *testing.T) {
+	decoder := NewVorbisDecoder()
+
+	var _ Decoder = decoder
+
+	if decoder.FormatName() != "VORBIS" {
+		t.Errorf("expected format name 'VORBIS', got '%s'", decoder.FormatName())
+	}
+}
+
+func TestVorbisDecoderCanDecode(t *testing.T) {
+	decoder := NewVorbisDecoder()
+
+	testCases := []struct {
+		filename string
+		expected bool
+	}{
+		{"audio.ogg", true},
+		{"SOUND.OGG", true},
+		{"audio.oga", true},
+		{"audio.opus", false},
+		{"audio.wav", false},
+		{"ogg", false},
+		{"", false},
+	}
+
+	for _, tc := range testCases {
+		if got := decoder.CanDecode(tc.filename); got != tc.expected {
+			t.Errorf("CanDecode('%s') = %v, expected %v", tc.filename, got, tc.expected)
+		}
+	}
+}
+
+func TestVorbisDecoderDecode(t *testing.T) {
+	data, err := os.ReadFile("testdata/vorbis.ogg")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	audioData, err := NewVorbisDecoder().Decode(context.Background(), bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if audioData.Channels != 1 || audioData.SampleRate != 44100 {
+		t.Errorf("got %d channels at %d Hz, want 1 at 44100", audioData.Channels, audioData.SampleRate)
+	}
+	if audioData.Format != malgo.FormatF32 {
+		t.Errorf("expected FormatF32, got %v", audioData.Format)
+	}
+	// The fixture is one second long.
+	if frames := len(audioData.Samples) / 4; frames != 44100 {
+		t.Errorf("expected 44100 frames, got %d", frames)
+	}
+}
+
+func TestVorbisDecoderDecodeInvalidData(t *testing.T) {
+	opusData, err := os.ReadFile("testdata/tiny.opus")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	for name, data := range map[string][]byte{
+		"empty":       {},
+		"not ogg":     []byte("not an ogg vorbis file"),
+		"ogg of opus": opusData,
+	} {
+		t.Run(name, func(t *testing.T) {
+			audioData, err := NewVorbisDecoder().Decode(context.Background(), bytes.NewReader(data))
+			if err == nil {
+				t.Fatal("expected error for invalid Vorbis data")
+			}
+			if audioData != nil {
+				t.Error("expected nil data on error")
+			}
+		})
+	}
+}
+
+func TestVorbisDecoderCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := NewVorbisDecoder().Decode(ctx, bytes.NewReader(nil)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
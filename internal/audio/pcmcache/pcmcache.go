@@ -0,0 +1,312 @@
+// Package pcmcache keeps decoded audio on disk so a sound is decoded once
+// rather than on every hook. Entries are keyed by the SHA-256 of the
+// source file's bytes together with the decoder that produced them, so an
+// edited file or a changed decoder never serves stale samples. The cache
+// is bounded in size and evicts the least recently used entries first;
+// recency is the entry file's modification time, which every hit
+// refreshes, so separate claudio processes share one LRU order.
+package pcmcache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"claudio.click/internal/config"
+)
+
+const (
+	// DefaultMaxBytes bounds the cache when no other limit is given.
+	DefaultMaxBytes int64 = 256 << 20
+
+	entryExtension = ".pcm"
+	entryMagic     = "CLPCM1\n"
+	// headerSize is the magic plus format, channels, and sample rate
+	// (uint32 each) and the sample byte count (uint64).
+	headerSize = len(entryMagic) + 4*3 + 8
+)
+
+// Entry is one decoded sound: raw interleaved samples and the layout
+// needed to play them. Format is the decoding backend's own sample format
+// identifier; the cache stores it without interpreting it.
+type Entry struct {
+	Format     uint32
+	Channels   uint32
+	SampleRate uint32
+	Samples    []byte
+}
+
+// Stats summarizes the cache's contents.
+type Stats struct {
+	Dir      string
+	Entries  int
+	Bytes    int64
+	MaxBytes int64
+}
+
+// Cache is a size-bounded on-disk store of decoded audio.
+type Cache struct {
+	dir      string
+	maxBytes int64
+	mu       sync.Mutex
+}
+
+// DefaultDir returns the cache directory under the XDG cache home.
+func DefaultDir() string {
+	return config.NewXDGDirs().GetCachePath("pcm")
+}
+
+// New returns a cache rooted at dir holding at most maxBytes of entries.
+// The directory is created on first write.
+func New(dir string, maxBytes int64) *Cache {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	slog.Debug("creating decoded audio cache", "dir", dir, "max_bytes", maxBytes)
+	return &Cache{dir: dir, maxBytes: maxBytes}
+}
+
+// Dir returns the directory the cache stores entries in.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Key returns the cache key for content decoded by the named decoder at
+// decoderVersion.
+func Key(content []byte, decoder string, decoderVersion int) string {
+	h := sha256.New()
+	h.Write(content)
+	fmt.Fprintf(h, "\x00%s\x00%d", decoder, decoderVersion)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Get returns the entry stored under key. A missing, unreadable, or
+// corrupt entry is a miss; corrupt entries are removed.
+func (c *Cache) Get(key string) (*Entry, bool) {
+	path := c.entryPath(key)
+	f, err := os.Open(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Warn("failed to open cached audio", "path", path, "error", err)
+		}
+		return nil, false
+	}
+	entry, err := readEntry(f)
+	f.Close()
+	if err != nil {
+		slog.Warn("discarding corrupt cached audio", "path", path, "error", err)
+		_ = os.Remove(path)
+		return nil, false
+	}
+
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil {
+		slog.Debug("failed to refresh cached audio recency", "path", path, "error", err)
+	}
+	slog.Debug("decoded audio cache hit", "key", key, "bytes", len(entry.Samples))
+	return entry, true
+}
+
+// Put stores entry under key, then evicts the least recently used
+// entries until the cache fits its size bound. An entry larger than the
+// whole cache is not stored.
+func (c *Cache) Put(key string, entry *Entry) error {
+	size := int64(headerSize + len(entry.Samples))
+	if size > c.maxBytes {
+		slog.Debug("decoded audio too large to cache", "key", key, "bytes", size, "max_bytes", c.maxBytes)
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create audio cache directory: %w", err)
+	}
+	// Write beside the entry and rename, so a concurrent reader never sees
+	// a partial entry.
+	tmp, err := os.CreateTemp(c.dir, ".pcm-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create cached audio: %w", err)
+	}
+	tmpPath := tmp.Name()
+	if err := writeEntry(tmp, entry); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write cached audio: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write cached audio: %w", err)
+	}
+	if err := os.Rename(tmpPath, c.entryPath(key)); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to store cached audio: %w", err)
+	}
+	slog.Debug("stored decoded audio in cache", "key", key, "bytes", size)
+
+	return c.evictLocked()
+}
+
+// Clear removes every entry and returns how many were removed.
+func (c *Cache) Clear() (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	files, err := c.listLocked()
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, f := range files {
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return removed, fmt.Errorf("failed to remove cached audio: %w", err)
+		}
+		removed++
+	}
+	slog.Info("decoded audio cache cleared", "dir", c.dir, "removed", removed)
+	return removed, nil
+}
+
+// Stats reports how many entries the cache holds and their total size.
+func (c *Cache) Stats() (Stats, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	files, err := c.listLocked()
+	if err != nil {
+		return Stats{}, err
+	}
+	stats := Stats{Dir: c.dir, Entries: len(files), MaxBytes: c.maxBytes}
+	for _, f := range files {
+		stats.Bytes += f.size
+	}
+	return stats, nil
+}
+
+type cacheFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// listLocked returns every entry file. A missing directory is an empty
+// cache.
+func (c *Cache) listLocked() ([]cacheFile, error) {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read audio cache directory: %w", err)
+	}
+	files := make([]cacheFile, 0, len(dirEntries))
+	for _, de := range dirEntries {
+		if de.IsDir() || !strings.HasSuffix(de.Name(), entryExtension) {
+			continue
+		}
+		info, err := de.Info()
+		if err != nil {
+			continue // removed since ReadDir
+		}
+		files = append(files, cacheFile{
+			path:    filepath.Join(c.dir, de.Name()),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+	}
+	return files, nil
+}
+
+// evictLocked removes the least recently used entries until the cache
+// fits maxBytes.
+func (c *Cache) evictLocked() error {
+	files, err := c.listLocked()
+	if err != nil {
+		return err
+	}
+	var total int64
+	for _, f := range files {
+		total += f.size
+	}
+	if total <= c.maxBytes {
+		return nil
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	evicted := 0
+	for _, f := range files {
+		if total <= c.maxBytes {
+			break
+		}
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to evict cached audio: %w", err)
+		}
+		total -= f.size
+		evicted++
+	}
+	slog.Debug("evicted decoded audio from cache", "evicted", evicted, "remaining_bytes", total)
+	return nil
+}
+
+func (c *Cache) entryPath(key string) string {
+	return filepath.Join(c.dir, key+entryExtension)
+}
+
+func writeEntry(w io.Writer, entry *Entry) error {
+	header := make([]byte, headerSize)
+	n := copy(header, entryMagic)
+	binary.LittleEndian.PutUint32(header[n:], entry.Format)
+	binary.LittleEndian.PutUint32(header[n+4:], entry.Channels)
+	binary.LittleEndian.PutUint32(header[n+8:], entry.SampleRate)
+	binary.LittleEndian.PutUint64(header[n+12:], uint64(len(entry.Samples)))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.Write(entry.Samples)
+	return err
+}
+
+var errCorruptEntry = errors.New("corrupt cache entry")
+
+func readEntry(f *os.File) (*Entry, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(f, header); err != nil {
+		return nil, fmt.Errorf("%w: %v", errCorruptEntry, err)
+	}
+	if !bytes.HasPrefix(header, []byte(entryMagic)) {
+		return nil, fmt.Errorf("%w: bad magic", errCorruptEntry)
+	}
+	n := len(entryMagic)
+	entry := &Entry{
+		Format:     binary.LittleEndian.Uint32(header[n:]),
+		Channels:   binary.LittleEndian.Uint32(header[n+4:]),
+		SampleRate: binary.LittleEndian.Uint32(header[n+8:]),
+	}
+	length := binary.LittleEndian.Uint64(header[n+12:])
+	// The recorded length must account for the rest of the file exactly,
+	// which also bounds the allocation below by the file's real size.
+	if length != uint64(info.Size()-int64(headerSize)) {
+		return nil, fmt.Errorf("%w: length %d does not match file size %d", errCorruptEntry, length, info.Size())
+	}
+	entry.Samples = make([]byte, length)
+	if _, err := io.ReadFull(f, entry.Samples); err != nil {
+		return nil, fmt.Errorf("%w: %v", errCorruptEntry, err)
+	}
+	return entry, nil
+}
@@ -0,0 +1,162 @@
+package pcmcache
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testEntry(size int) *Entry {
+	return &Entry{Format: 5, Channels: 2, SampleRate: 48000, Samples: bytes.Repeat([]byte{0xAB}, size)}
+}
+
+func TestCache_PutGetRoundTrip(t *testing.T) {
+	cache := New(t.TempDir(), 0)
+	key := Key([]byte("source"), "WAV", 1)
+
+	if _, ok := cache.Get(key); ok {
+		t.Fatal("expected a miss on an empty cache")
+	}
+	want := testEntry(64)
+	if err := cache.Put(key, want); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	got, ok := cache.Get(key)
+	if !ok {
+		t.Fatal("expected a hit after Put")
+	}
+	if got.Format != want.Format || got.Channels != want.Channels || got.SampleRate != want.SampleRate ||
+		!bytes.Equal(got.Samples, want.Samples) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestKey_DependsOnContentAndDecoder(t *testing.T) {
+	base := Key([]byte("source"), "MP3", 1)
+	for name, other := range map[string]string{
+		"content": Key([]byte("source!"), "MP3", 1),
+		"decoder": Key([]byte("source"), "WAV", 1),
+		"version": Key([]byte("source"), "MP3", 2),
+	} {
+		if other == base {
+			t.Errorf("changing the %s should change the key", name)
+		}
+	}
+	if Key([]byte("source"), "MP3", 1) != base {
+		t.Error("key should be deterministic")
+	}
+}
+
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	entrySize := int64(headerSize + 100)
+	cache := New(dir, 2*entrySize)
+
+	if err := cache.Put("old", testEntry(100)); err != nil {
+		t.Fatalf("Put old: %v", err)
+	}
+	if err := cache.Put("used", testEntry(100)); err != nil {
+		t.Fatalf("Put used: %v", err)
+	}
+	// Backdate both, then touch "used" with a hit so "old" is the least
+	// recently used when a third entry pushes the cache over its bound.
+	past := time.Now().Add(-time.Hour)
+	for _, key := range []string{"old", "used"} {
+		if err := os.Chtimes(cache.entryPath(key), past, past); err != nil {
+			t.Fatalf("Chtimes: %v", err)
+		}
+	}
+	if _, ok := cache.Get("used"); !ok {
+		t.Fatal("expected a hit for used")
+	}
+	if err := cache.Put("new", testEntry(100)); err != nil {
+		t.Fatalf("Put new: %v", err)
+	}
+
+	if _, ok := cache.Get("old"); ok {
+		t.Error("expected old to be evicted")
+	}
+	for _, key := range []string{"used", "new"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("expected %s to survive eviction", key)
+		}
+	}
+}
+
+func TestCache_SkipsEntryLargerThanCache(t *testing.T) {
+	cache := New(t.TempDir(), 64)
+	if err := cache.Put("big", testEntry(128)); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, ok := cache.Get("big"); ok {
+		t.Error("expected an entry larger than the cache not to be stored")
+	}
+}
+
+func TestCache_CorruptEntryIsMissAndRemoved(t *testing.T) {
+	dir := t.TempDir()
+	cache := New(dir, 0)
+	if err := cache.Put("key", testEntry(32)); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	path := cache.entryPath("key")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if err := os.WriteFile(path, data[:len(data)-1], 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if _, ok := cache.Get("key"); ok {
+		t.Fatal("expected a truncated entry to miss")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the corrupt entry to be removed, got %v", err)
+	}
+}
+
+func TestCache_StatsAndClear(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "pcm")
+	cache := New(dir, 1<<20)
+
+	stats, err := cache.Stats()
+	if err != nil {
+		t.Fatalf("Stats on a missing directory: %v", err)
+	}
+	if stats.Entries != 0 || stats.Bytes != 0 || stats.Dir != dir || stats.MaxBytes != 1<<20 {
+		t.Errorf("unexpected empty stats: %+v", stats)
+	}
+
+	for _, key := range []string{"a", "b"} {
+		if err := cache.Put(key, testEntry(10)); err != nil {
+			t.Fatalf("Put %s: %v", key, err)
+		}
+	}
+	// Stray files that are not entries are neither counted nor cleared.
+	stray := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(stray, []byte("keep"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	stats, err = cache.Stats()
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.Entries != 2 || stats.Bytes != int64(2*(headerSize+10)) {
+		t.Errorf("got %+v, want 2 entries of %d bytes", stats, headerSize+10)
+	}
+
+	removed, err := cache.Clear()
+	if err != nil || removed != 2 {
+		t.Fatalf("Clear = %d, %v; want 2, nil", removed, err)
+	}
+	if stats, _ := cache.Stats(); stats.Entries != 0 {
+		t.Errorf("expected an empty cache after Clear, got %+v", stats)
+	}
+	if _, err := os.Stat(stray); err != nil {
+		t.Errorf("expected Clear to leave non-entry files alone: %v", err)
+	}
+}
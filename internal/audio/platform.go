@@ -63,10 +63,11 @@ func getPreferredSystemCommandWithChecker(commandChecker func(string) bool) stri
 // commands in priority order.
 func getAvailableSystemCommandsWithChecker(commandChecker func(string) bool) []string {
 	allCommands := []string{
-		"paplay", // PulseAudio - most common on modern Linux
-		"ffplay", // FFmpeg - widely available and versatile
-		"aplay",  // ALSA - lower-level Linux audio
-		"afplay", // macOS built-in audio player
+		"paplay",  // PulseAudio - most common on modern Linux
+		"pw-play", // PipeWire - native player where pipewire-pulse is absent
+		"ffplay",  // FFmpeg - widely available and versatile
+		"aplay",   // ALSA - lower-level Linux audio
+		"afplay",  // macOS built-in audio player
 	}
 
 	var available []string
@@ -201,6 +201,11 @@ func TestGetAvailableSystemCommands(t *testing.T) {
 			availableCommands: []string{"aplay", "paplay", "afplay", "ffplay"},
 			want:              []string{"paplay", "ffplay", "aplay", "afplay"},
 		},
+		{
+			name:              "pw-play follows paplay",
+			availableCommands: []string{"ffplay", "pw-play", "paplay"},
+			want:              []string{"paplay", "pw-play", "ffplay"},
+		},
 		{
 			name:              "returns subset in priority order",
 			availableCommands: []string{"aplay", "ffplay"},
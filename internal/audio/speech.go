@@ -0,0 +1,245 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Speech engines a SpeechSource can synthesize with.
+const (
+	SpeechEngineAuto     = "auto"      // first available engine, in speechEngineOrder
+	SpeechEngineEspeakNG = "espeak-ng" // eSpeak NG; classic espeak is used when only it is installed
+	SpeechEngineSay      = "say"       // macOS say
+	SpeechEngineSpdSay   = "spd-say"   // speech-dispatcher; speaks directly, cannot render a file
+	SpeechEnginePiper    = "piper"     // piper neural TTS; needs SpeechOptions.PiperModel
+)
+
+// ErrNoSpeechEngine is returned when no usable speech engine is installed.
+var ErrNoSpeechEngine = errors.New("no speech engine available")
+
+// speechRenderTimeout bounds one render, so a hung engine cannot hold a
+// hook's playback slot. Rendering a notification takes well under a
+// second; a slow neural voice on a cold start takes a few.
+const speechRenderTimeout = 30 * time.Second
+
+// SpeechOptions selects and tunes the speech engine.
+type SpeechOptions struct {
+	Engine     string // One of the SpeechEngine* constants; empty means auto
+	Voice      string // Engine-specific voice name; empty uses the engine default
+	Rate       int    // Words per minute; 0 uses the engine default
+	PiperModel string // Path to a piper .onnx voice model
+}
+
+// SpeechSource is an AudioSource that speaks text through a local TTS
+// engine. File-rendering engines synthesize a temporary WAV on first use,
+// so any AudioBackend can play it: SystemCommandBackend through FilePath,
+// malgo through Reader. spd-say cannot render to a file; callers check
+// SpeaksDirectly and use Speak instead of a backend. Close removes the
+// temporary file.
+type SpeechSource struct {
+	ctx           context.Context
+	text          string
+	opts          SpeechOptions
+	commandExists func(string) bool
+
+	once    sync.Once
+	path    string
+	err     error
+	cleanup func()
+}
+
+// NewSpeechSource creates a SpeechSource for text. Nothing runs until the
+// audio is requested.
+func NewSpeechSource(text string, opts SpeechOptions) *SpeechSource {
+	return NewSpeechSourceContext(context.Background(), text, opts)
+}
+
+// NewSpeechSourceContext is like NewSpeechSource, but rendering stops when
+// ctx ends as well as after speechRenderTimeout.
+func NewSpeechSourceContext(ctx context.Context, text string, opts SpeechOptions) *SpeechSource {
+	slog.Debug("creating new SpeechSource", "engine", opts.Engine, "text_length", len(text))
+	return &SpeechSource{ctx: ctx, text: text, opts: opts, commandExists: CommandExists}
+}
+
+// Text returns the text the source speaks.
+func (s *SpeechSource) Text() string {
+	return s.text
+}
+
+// SpeaksDirectly reports whether the resolved engine talks to the audio
+// device itself (spd-say) rather than rendering audio for a backend.
+func (s *SpeechSource) SpeaksDirectly() bool {
+	engine, err := s.engine()
+	return err == nil && engine == SpeechEngineSpdSay
+}
+
+// Speak runs a direct engine (spd-say) at volume (0.0 to 1.0) and waits
+// for it to finish.
+func (s *SpeechSource) Speak(ctx context.Context, volume float64) error {
+	engine, err := s.engine()
+	if err != nil {
+		return err
+	}
+	if engine != SpeechEngineSpdSay {
+		return fmt.Errorf("%w: %s renders audio, play it through a backend", ErrNotSupported, engine)
+	}
+
+	args := []string{"--wait", "-i", strconv.Itoa(int(volume*200) - 100)}
+	if s.opts.Voice != "" {
+		args = append(args, "-y", s.opts.Voice)
+	}
+	if s.opts.Rate > 0 {
+		args = append(args, "-r", strconv.Itoa(spdSayRate(s.opts.Rate)))
+	}
+	args = append(args, "--", s.text)
+
+	slog.Debug("speaking with spd-say", "text_length", len(s.text))
+	if out, err := exec.CommandContext(ctx, "spd-say", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("spd-say failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// FilePath synthesizes the speech to a temporary WAV and returns its path.
+func (s *SpeechSource) FilePath() (string, error) {
+	s.once.Do(s.render)
+	return s.path, s.err
+}
+
+// Reader synthesizes the speech and opens the rendered WAV.
+func (s *SpeechSource) Reader() (io.ReadCloser, string, error) {
+	path, err := s.FilePath()
+	if err != nil {
+		return nil, "", err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open synthesized speech: %w", err)
+	}
+	return f, "wav", nil
+}
+
+// Close removes the rendered WAV, if any.
+func (s *SpeechSource) Close() error {
+	if s.cleanup != nil {
+		s.cleanup()
+		s.cleanup = nil
+	}
+	return nil
+}
+
+// render runs the engine once, writing the speech to a temporary WAV.
+func (s *SpeechSource) render() {
+	engine, err := s.engine()
+	if err != nil {
+		s.err = err
+		return
+	}
+	if engine == SpeechEngineSpdSay {
+		s.err = fmt.Errorf("%w: spd-say cannot render to a file", ErrNotSupported)
+		return
+	}
+
+	tmp, err := os.CreateTemp("", "claudio-speech-*.wav")
+	if err != nil {
+		s.err = fmt.Errorf("failed to create speech file: %w", err)
+		return
+	}
+	path := tmp.Name()
+	tmp.Close()
+
+	ctx, cancel := context.WithTimeout(s.ctx, speechRenderTimeout)
+	defer cancel()
+	name, args := speechRenderCommand(engine, s.opts, path, s.commandExists)
+	cmd := exec.CommandContext(ctx, name, args...)
+	// An engine's own children may hold the output pipe open after it is
+	// killed; stop waiting for them shortly after.
+	cmd.WaitDelay = time.Second
+	// Text goes in on stdin so nothing in it can be read as a flag.
+	cmd.Stdin = strings.NewReader(s.text)
+	slog.Debug("rendering speech", "engine", engine, "command", name, "text_length", len(s.text))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		os.Remove(path)
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		s.err = fmt.Errorf("%s failed: %w: %s", name, err, strings.TrimSpace(string(out)))
+		return
+	}
+
+	s.path = path
+	s.cleanup = func() { os.Remove(path) }
+}
+
+// engine resolves the configured engine, picking the first installed one
+// for auto.
+func (s *SpeechSource) engine() (string, error) {
+	engine := s.opts.Engine
+	if engine != "" && engine != SpeechEngineAuto {
+		if engine == SpeechEnginePiper && s.opts.PiperModel == "" {
+			return "", fmt.Errorf("%w: piper needs a model", ErrNoSpeechEngine)
+		}
+		return engine, nil
+	}
+	for _, candidate := range speechEngineOrder {
+		if candidate == SpeechEnginePiper && s.opts.PiperModel == "" {
+			continue
+		}
+		if s.commandExists(candidate) || (candidate == SpeechEngineEspeakNG && s.commandExists("espeak")) {
+			return candidate, nil
+		}
+	}
+	return "", ErrNoSpeechEngine
+}
+
+// speechEngineOrder is the auto-detection preference. A configured piper
+// model signals a deliberate choice, so piper goes first; spd-say is last
+// because it bypasses the configured audio backend.
+var speechEngineOrder = []string{SpeechEnginePiper, SpeechEngineEspeakNG, SpeechEngineSay, SpeechEngineSpdSay}
+
+// speechRenderCommand returns the command that renders text from stdin to
+// outPath with engine.
+func speechRenderCommand(engine string, opts SpeechOptions, outPath string, commandExists func(string) bool) (string, []string) {
+	switch engine {
+	case SpeechEngineSay:
+		args := []string{"-o", outPath, "--data-format=LEI16@22050", "-f", "-"}
+		if opts.Voice != "" {
+			args = append(args, "-v", opts.Voice)
+		}
+		if opts.Rate > 0 {
+			args = append(args, "-r", strconv.Itoa(opts.Rate))
+		}
+		return "say", args
+	case SpeechEnginePiper:
+		return "piper", []string{"--model", opts.PiperModel, "--output_file", outPath}
+	default:
+		name := SpeechEngineEspeakNG
+		if !commandExists(name) && commandExists("espeak") {
+			name = "espeak"
+		}
+		args := []string{"-w", outPath}
+		if opts.Voice != "" {
+			args = append(args, "-v", opts.Voice)
+		}
+		if opts.Rate > 0 {
+			args = append(args, "-s", strconv.Itoa(opts.Rate))
+		}
+		return name, append(args, "--stdin")
+	}
+}
+
+// spdSayRate maps words per minute onto spd-say's -100..100 rate scale,
+// where 0 is the module default (about 180 wpm).
+func spdSayRate(wpm int) int {
+	rate := (wpm - 180) * 100 / 180
+	return max(-100, min(100, rate))
+}
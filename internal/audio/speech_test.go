@@ -0,0 +1,127 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func installed(names ...string) func(string) bool {
+	return func(cmd string) bool {
+		for _, n := range names {
+			if n == cmd {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func TestSpeechSource_AutoEngineOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      SpeechOptions
+		installed []string
+		want      string
+	}{
+		{"piper with model wins", SpeechOptions{PiperModel: "/m.onnx"}, []string{"piper", "espeak-ng", "spd-say"}, SpeechEnginePiper},
+		{"piper without model skipped", SpeechOptions{}, []string{"piper", "say"}, SpeechEngineSay},
+		{"classic espeak counts as espeak-ng", SpeechOptions{}, []string{"espeak", "spd-say"}, SpeechEngineEspeakNG},
+		{"spd-say last", SpeechOptions{Engine: SpeechEngineAuto}, []string{"spd-say"}, SpeechEngineSpdSay},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSpeechSource("hi", tt.opts)
+			s.commandExists = installed(tt.installed...)
+			got, err := s.engine()
+			if err != nil || got != tt.want {
+				t.Errorf("engine() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+
+	s := NewSpeechSource("hi", SpeechOptions{})
+	s.commandExists = installed()
+	if _, err := s.engine(); !errors.Is(err, ErrNoSpeechEngine) {
+		t.Errorf("no engines installed: got %v, want ErrNoSpeechEngine", err)
+	}
+	if _, err := s.FilePath(); !errors.Is(err, ErrNoSpeechEngine) {
+		t.Errorf("FilePath without an engine: got %v, want ErrNoSpeechEngine", err)
+	}
+}
+
+func TestSpeechRenderCommand(t *testing.T) {
+	opts := SpeechOptions{Voice: "en-us", Rate: 200, PiperModel: "/voices/amy.onnx"}
+	tests := []struct {
+		engine    string
+		installed []string
+		wantName  string
+		wantArgs  []string
+	}{
+		{SpeechEngineEspeakNG, []string{"espeak-ng"}, "espeak-ng", []string{"-w", "/tmp/o.wav", "-v", "en-us", "-s", "200", "--stdin"}},
+		{SpeechEngineEspeakNG, []string{"espeak"}, "espeak", []string{"-w", "/tmp/o.wav", "-v", "en-us", "-s", "200", "--stdin"}},
+		{SpeechEngineSay, nil, "say", []string{"-o", "/tmp/o.wav", "--data-format=LEI16@22050", "-f", "-", "-v", "en-us", "-r", "200"}},
+		{SpeechEnginePiper, nil, "piper", []string{"--model", "/voices/amy.onnx", "--output_file", "/tmp/o.wav"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wantName, func(t *testing.T) {
+			name, args := speechRenderCommand(tt.engine, opts, "/tmp/o.wav", installed(tt.installed...))
+			if name != tt.wantName || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("speechRenderCommand(%s) = %s %v, want %s %v", tt.engine, name, args, tt.wantName, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSpeechSource_SpdSayCannotRender(t *testing.T) {
+	s := NewSpeechSource("hi", SpeechOptions{Engine: SpeechEngineSpdSay})
+	if !s.SpeaksDirectly() {
+		t.Fatal("spd-say should speak directly")
+	}
+	if _, _, err := s.Reader(); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Reader with spd-say: got %v, want ErrNotSupported", err)
+	}
+}
+
+func TestSpeechSource_RenderStopsWithContext(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the speech engine")
+	}
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "espeak-ng"), []byte("#!/bin/sh\nexec sleep 30\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	s := NewSpeechSourceContext(ctx, "hi", SpeechOptions{Engine: SpeechEngineEspeakNG})
+	defer s.Close()
+
+	start := time.Now()
+	_, err := s.FilePath()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("FilePath with a hung engine: got %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("render took %s after its context ended", elapsed)
+	}
+}
+
+func TestFakeBackend_RecordsSpeechText(t *testing.T) {
+	fake := NewFakeBackend()
+	s := NewSpeechSource("build finished", SpeechOptions{Engine: SpeechEngineEspeakNG})
+	s.commandExists = installed()
+	if err := fake.Play(t.Context(), s); err != nil {
+		t.Fatalf("Play: %v", err)
+	}
+	plays := fake.Plays()
+	if len(plays) != 1 || plays[0].Text != "build finished" || plays[0].SourcePath != "" {
+		t.Errorf("plays = %+v, want one speech play", plays)
+	}
+}
@@ -19,10 +19,13 @@ import (
 type SystemCommandBackend struct {
 	commands         []string
 	volume           float32
+	device           string // output device for players that can select one; empty = default
 	isPlaying        bool
 	closed           bool
 	mutex            sync.RWMutex
 	warnNoVolumeOnce sync.Once // one WARN per backend instance for aplay
+	warnNoVaryOnce   sync.Once // one WARN per backend instance for players without filters
+	warnNoDeviceOnce sync.Once // one WARN per backend instance for players without device selection
 }
 
 // NewSystemCommandBackend creates a new SystemCommandBackend with the specified
@@ -107,6 +110,22 @@ func (scb *SystemCommandBackend) GetVolume() float32 {
 	return scb.volume
 }
 
+// SetDevice selects the output device paplay and pw-play play through: a
+// PulseAudio sink or PipeWire node name, as 'claudio devices' lists it
+// under PulseAudio or PipeWire. It implements DeviceSelector. Other
+// players always use the system default.
+func (scb *SystemCommandBackend) SetDevice(device string) error {
+	scb.mutex.Lock()
+	defer scb.mutex.Unlock()
+
+	if scb.closed {
+		return ErrBackendClosed
+	}
+	scb.device = device
+	slog.Debug("output device selected", "device", device)
+	return nil
+}
+
 // Play plays audio from the given source using system commands
 func (scb *SystemCommandBackend) Play(ctx context.Context, source AudioSource) error {
 	scb.mutex.Lock()
@@ -129,9 +148,10 @@ func (scb *SystemCommandBackend) Play(ctx context.Context, source AudioSource) e
 	// Fast path: source can provide a file path directly (FileSource). Exec
 	// the player binary against the path without the read-then-write-temp
 	// dance.
+	variation := VariationOf(source)
 	if fp, ok := source.(FilePather); ok {
 		if filePath, err := fp.FilePath(); err == nil {
-			return scb.playFile(ctx, filePath)
+			return scb.playFile(ctx, filePath, variation)
 		}
 	}
 
@@ -143,7 +163,7 @@ func (scb *SystemCommandBackend) Play(ctx context.Context, source AudioSource) e
 	}
 	defer reader.Close()
 
-	return scb.playReaderViaTempFile(ctx, reader, format)
+	return scb.playReaderViaTempFile(ctx, reader, format, variation)
 }
 
 // loadVolume returns the current volume under RLock. The subprocess fork-exec
@@ -156,6 +176,13 @@ func (scb *SystemCommandBackend) loadVolume() float32 {
 	return scb.volume
 }
 
+// loadDevice returns the selected output device under RLock.
+func (scb *SystemCommandBackend) loadDevice() string {
+	scb.mutex.RLock()
+	defer scb.mutex.RUnlock()
+	return scb.device
+}
+
 func (scb *SystemCommandBackend) primaryCommand() string {
 	if len(scb.commands) == 0 {
 		return ""
@@ -164,8 +191,10 @@ func (scb *SystemCommandBackend) primaryCommand() string {
 }
 
 // buildPlayerArgv returns the argv (NOT including the command itself) to play
-// filePath at volume v on the primary configured backend. v is in [0.0, 1.0];
-// the function scales it to the backend's native value space. Backends without a
+// filePath at volume v on the primary configured backend. v is normally in
+// [0.0, 1.0]; a soundpack's loudness gain can lift it above 1.0, which
+// paplay and afplay amplify and ffplay caps at 100%. The function scales v to
+// the backend's native value space. Backends without a
 // native volume flag (e.g. aplay) ignore v and log a one-time WARN.
 //
 // Verified mappings (paplay, ffplay, afplay) come from each player's
@@ -181,6 +210,9 @@ func (scb *SystemCommandBackend) buildPlayerArgvForCommand(command, filePath str
 		// PulseAudio: --volume=N where N is uint32, 65536 = 100%.
 		n := uint32(math.Round(v * 65536))
 		return []string{fmt.Sprintf("--volume=%d", n), filePath}
+	case "pw-play":
+		// PipeWire: --volume=V where V is a float; 1.0 = 100%. Identity mapping.
+		return []string{"--volume=" + strconv.FormatFloat(v, 'f', 3, 64), filePath}
 	case "ffplay":
 		// ffmpeg: -volume N where N is int, 100 = 100%.
 		// -nodisp prevents ffplay opening an SDL window for audio-only input.
@@ -207,21 +239,116 @@ func (scb *SystemCommandBackend) buildPlayerArgvForCommand(command, filePath str
 	}
 }
 
+// deviceArgs returns the extra argv that makes command play through
+// device, or nil for the default device. paplay takes a sink name and
+// pw-play a node name; the other players log one WARN and play through
+// the default device.
+func (scb *SystemCommandBackend) deviceArgs(command, device string) []string {
+	if device == "" {
+		return nil
+	}
+	switch filepath.Base(command) {
+	case "paplay":
+		return []string{"--device=" + device}
+	case "pw-play":
+		return []string{"--target=" + device}
+	default:
+		scb.warnNoDeviceOnce.Do(func() {
+			slog.Warn("audio command cannot select an output device; audio_device ignored",
+				"command", command, "device", device)
+		})
+		return nil
+	}
+}
+
+// variationArgs returns the extra argv that makes command play with
+// variation's pitch and pan, or nil when there is nothing to apply. Only
+// ffplay can shift pitch and pan; the other players log one WARN and play
+// unchanged. Gain travels in the volume argument instead.
+func (scb *SystemCommandBackend) variationArgs(command string, variation Variation) []string {
+	if !variation.ShiftsPitchOrPan() {
+		return nil
+	}
+	if filepath.Base(command) != "ffplay" {
+		scb.warnNoVaryOnce.Do(func() {
+			slog.Warn("audio command cannot shift pitch or pan; session variation ignored",
+				"command", command, "pitch", variation.EffectivePitch(), "pan", variation.Pan)
+		})
+		return nil
+	}
+	return []string{"-af", ffplayFilter(variation)}
+}
+
+// ffplayFilter renders variation as an ffmpeg audio filter graph. The input
+// rate is unknown without probing the file, so the pitch shift resamples to
+// a fixed rate first, relabels that rate (which shifts pitch and tempo
+// together, as the malgo resampler does) and resamples back.
+func ffplayFilter(variation Variation) string {
+	var filters []string
+	if pitch := variation.EffectivePitch(); pitch != 1.0 {
+		const rate = 48000
+		filters = append(filters,
+			fmt.Sprintf("aresample=%d", rate),
+			fmt.Sprintf("asetrate=%d", int(math.Round(rate*pitch))),
+			fmt.Sprintf("aresample=%d", rate))
+	}
+	if variation.Pan != 0 {
+		left, right := variation.PanGains()
+		filters = append(filters,
+			"aformat=channel_layouts=stereo",
+			fmt.Sprintf("pan=stereo|c0=%.3f*c0|c1=%.3f*c1", left, right))
+	}
+	return strings.Join(filters, ",")
+}
+
+// commandCapability records which file extensions a system player can
+// decode. A player with only set plays just those; otherwise it plays
+// everything but except.
+type commandCapability struct {
+	only   []string
+	except []string
+}
+
+// commandCapabilities is the capability table for players that cannot decode
+// every format Claudio accepts. aplay is limited to WAV on the supported
+// platforms, and afplay's Core Audio has no Ogg Vorbis or Opus codec.
+// paplay (libsndfile) and ffplay also decode FLAC, Vorbis and Opus, so they
+// and unknown commands are treated as general-purpose decoders.
+var commandCapabilities = map[string]commandCapability{
+	"aplay":  {only: []string{".wav"}},
+	"afplay": {except: []string{".ogg", ".oga", ".opus"}},
+}
+
 // commandSupportsFormat reports whether a system audio command should be tried
-// for a file extension. aplay is limited to WAV on the supported platforms; the
-// other known command players are treated as general-purpose decoders.
+// for a file extension.
 func commandSupportsFormat(command, ext string) bool {
-	if filepath.Base(command) != "aplay" {
+	capability, known := commandCapabilities[filepath.Base(command)]
+	if !known {
 		return true
 	}
-	return strings.EqualFold(ext, ".wav")
+	if len(capability.only) > 0 {
+		return containsFold(capability.only, ext)
+	}
+	return !containsFold(capability.except, ext)
+}
+
+func containsFold(exts []string, ext string) bool {
+	for _, candidate := range exts {
+		if strings.EqualFold(candidate, ext) {
+			return true
+		}
+	}
+	return false
 }
 
 // playFile plays a file directly using the configured system command chain.
-func (scb *SystemCommandBackend) playFile(ctx context.Context, filePath string) error {
+func (scb *SystemCommandBackend) playFile(ctx context.Context, filePath string, variation Variation) error {
 	slog.Debug("playing file via system command", "file", filePath, "commands", scb.commands)
 
-	v := scb.loadVolume()
+	// The soundpack's loudness gain rides on the configured volume, so
+	// every player that honours a volume levels the sound too.
+	v := float64(scb.loadVolume()) * variation.EffectiveGain()
+	device := scb.loadDevice()
 	ext := filepath.Ext(filePath)
 	var lastErr error
 	var attempted int
@@ -234,7 +361,9 @@ func (scb *SystemCommandBackend) playFile(ctx context.Context, filePath string)
 		}
 
 		attempted++
-		argv := scb.buildPlayerArgvForCommand(command, filePath, float64(v))
+		argv := scb.buildPlayerArgvForCommand(command, filePath, v)
+		argv = append(scb.variationArgs(command, variation), argv...)
+		argv = append(scb.deviceArgs(command, device), argv...)
 		cmd := exec.CommandContext(ctx, command, argv...)
 		var stderr bytes.Buffer
 		cmd.Stderr = &stderr
@@ -269,7 +398,7 @@ func (scb *SystemCommandBackend) playFile(ctx context.Context, filePath string)
 }
 
 // playReaderViaTempFile writes reader data to a temporary file and plays it
-func (scb *SystemCommandBackend) playReaderViaTempFile(ctx context.Context, reader io.Reader, format string) error {
+func (scb *SystemCommandBackend) playReaderViaTempFile(ctx context.Context, reader io.Reader, format string, variation Variation) error {
 	slog.Debug("playing reader via temporary file", "format", format)
 
 	// Create temporary file with appropriate extension
@@ -304,5 +433,5 @@ func (scb *SystemCommandBackend) playReaderViaTempFile(ctx context.Context, read
 	slog.Debug("temporary file created successfully", "path", tempPath, "format", format)
 
 	// Play the temporary file
-	return scb.playFile(ctx, tempPath)
+	return scb.playFile(ctx, tempPath, variation)
 }
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"runtime"
 	"strings"
 	"testing"
 )
@@ -32,6 +33,14 @@ func TestCommandSupportsFormat(t *testing.T) {
 		{name: "paplay accepts mp3", command: "paplay", ext: ".mp3", want: true},
 		{name: "ffplay accepts aiff", command: "ffplay", ext: ".aiff", want: true},
 		{name: "unknown accepts mp3", command: "custom-player", ext: ".mp3", want: true},
+		{name: "aplay rejects flac", command: "aplay", ext: ".flac", want: false},
+		{name: "paplay accepts ogg", command: "paplay", ext: ".ogg", want: true},
+		{name: "paplay accepts opus", command: "/usr/bin/paplay", ext: ".opus", want: true},
+		{name: "ffplay accepts flac", command: "ffplay", ext: ".flac", want: true},
+		{name: "afplay accepts flac", command: "afplay", ext: ".flac", want: true},
+		{name: "afplay rejects ogg", command: "afplay", ext: ".OGG", want: false},
+		{name: "afplay rejects opus", command: "afplay", ext: ".opus", want: false},
+		{name: "afplay accepts aiff", command: "afplay", ext: ".aiff", want: true},
 	}
 
 	for _, tt := range tests {
@@ -138,6 +147,34 @@ func TestBuildPlayerArgv_AfplayIdentityNotScaled(t *testing.T) {
 	}
 }
 
+func TestBuildPlayerArgv_PwPlayIdentity(t *testing.T) {
+	scb := NewSystemCommandBackend("/usr/bin/pw-play")
+	argv := scb.buildPlayerArgv("/tmp/s.flac", 0.25)
+	want := []string{"--volume=0.250", "/tmp/s.flac"}
+	if !reflect.DeepEqual(argv, want) {
+		t.Errorf("got %v, want %v", argv, want)
+	}
+}
+
+func TestDeviceArgs(t *testing.T) {
+	scb := NewSystemCommandBackend("paplay")
+	cases := []struct {
+		cmd, device string
+		want        []string
+	}{
+		{"paplay", "bluez_output.AC_80_0A.1", []string{"--device=bluez_output.AC_80_0A.1"}},
+		{"/usr/bin/pw-play", "bluez_output.AC_80_0A.1", []string{"--target=bluez_output.AC_80_0A.1"}},
+		{"paplay", "", nil},
+		{"ffplay", "bluez_output.AC_80_0A.1", nil},
+		{"aplay", "bluez_output.AC_80_0A.1", nil},
+	}
+	for _, tc := range cases {
+		if got := scb.deviceArgs(tc.cmd, tc.device); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("deviceArgs(%q, %q) = %v, want %v", tc.cmd, tc.device, got, tc.want)
+		}
+	}
+}
+
 func TestBuildPlayerArgv_AfplayMax(t *testing.T) {
 	scb := NewSystemCommandBackend("afplay")
 	argv := scb.buildPlayerArgv("/tmp/x.aiff", 1.0)
@@ -219,6 +256,92 @@ func TestBuildPlayerArgv_VolumeReachesArgv(t *testing.T) {
 	}
 }
 
+func TestVariationArgs(t *testing.T) {
+	scb := NewSystemCommandBackend("ffplay")
+	if args := scb.variationArgs("ffplay", Variation{}); args != nil {
+		t.Errorf("neutral variation should add no args, got %v", args)
+	}
+
+	got := scb.variationArgs("/usr/bin/ffplay", Variation{Pitch: 1.05, Pan: -0.5})
+	want := []string{"-af", "aresample=48000,asetrate=50400,aresample=48000," +
+		"aformat=channel_layouts=stereo,pan=stereo|c0=1.000*c0|c1=0.500*c1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("variationArgs = %v, want %v", got, want)
+	}
+
+	if args := scb.variationArgs("paplay", Variation{Pan: 0.3}); args != nil {
+		t.Errorf("paplay cannot pan; want no args, got %v", args)
+	}
+	if args := scb.variationArgs("ffplay", Variation{Gain: 0.5}); args != nil {
+		t.Errorf("gain travels in the volume argument; want no args, got %v", args)
+	}
+}
+
+func TestPlayFile_GainScalesVolumeArg(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the player")
+	}
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "args")
+	player := filepath.Join(dir, "paplay")
+	script := "#!/bin/sh\necho \"$@\" > " + argsFile + "\n"
+	if err := os.WriteFile(player, []byte(script), 0o755); err != nil {
+		t.Fatalf("write player script: %v", err)
+	}
+	wavFile := filepath.Join(dir, "sound.wav")
+	if err := os.WriteFile(wavFile, []byte("fake wav"), 0o644); err != nil {
+		t.Fatalf("write wav fixture: %v", err)
+	}
+
+	scb := NewSystemCommandBackend(player)
+	if err := scb.SetVolume(0.5); err != nil {
+		t.Fatalf("SetVolume: %v", err)
+	}
+	if err := scb.playFile(context.Background(), wavFile, Variation{Gain: 0.5}); err != nil {
+		t.Fatalf("playFile: %v", err)
+	}
+	got, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("player did not run: %v", err)
+	}
+	if want := "--volume=16384 " + wavFile; strings.TrimSpace(string(got)) != want {
+		t.Errorf("player args = %q, want %q", strings.TrimSpace(string(got)), want)
+	}
+}
+
+func TestPlayFile_DeviceSelectsSink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the player")
+	}
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "args")
+	player := filepath.Join(dir, "paplay")
+	script := "#!/bin/sh\necho \"$@\" > " + argsFile + "\n"
+	if err := os.WriteFile(player, []byte(script), 0o755); err != nil {
+		t.Fatalf("write player script: %v", err)
+	}
+	wavFile := filepath.Join(dir, "sound.wav")
+	if err := os.WriteFile(wavFile, []byte("fake wav"), 0o644); err != nil {
+		t.Fatalf("write wav fixture: %v", err)
+	}
+
+	scb := NewSystemCommandBackend(player)
+	var _ DeviceSelector = scb
+	if err := scb.SetDevice("headphones_sink"); err != nil {
+		t.Fatalf("SetDevice: %v", err)
+	}
+	if err := scb.playFile(context.Background(), wavFile, Variation{}); err != nil {
+		t.Fatalf("playFile: %v", err)
+	}
+	got, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("player did not run: %v", err)
+	}
+	if want := "--device=headphones_sink --volume=65536 " + wavFile; strings.TrimSpace(string(got)) != want {
+		t.Errorf("player args = %q, want %q", strings.TrimSpace(string(got)), want)
+	}
+}
+
 func TestPlayFileWithFallbackChain(t *testing.T) {
 	tmpDir := t.TempDir()
 	wavFile := filepath.Join(tmpDir, "sound.wav")
@@ -228,14 +351,14 @@ func TestPlayFileWithFallbackChain(t *testing.T) {
 
 	t.Run("primary command fails then fallback succeeds", func(t *testing.T) {
 		scb := NewSystemCommandBackend("nonexistent-command-claudio", successfulNoopCommand())
-		if err := scb.playFile(context.Background(), wavFile); err != nil {
+		if err := scb.playFile(context.Background(), wavFile, Variation{}); err != nil {
 			t.Fatalf("playFile should succeed via fallback: %v", err)
 		}
 	})
 
 	t.Run("all commands fail", func(t *testing.T) {
 		scb := NewSystemCommandBackend("nonexistent-command-one", "nonexistent-command-two")
-		if err := scb.playFile(context.Background(), wavFile); err == nil {
+		if err := scb.playFile(context.Background(), wavFile, Variation{}); err == nil {
 			t.Fatal("playFile should fail when every command fails")
 		}
 	})
@@ -247,7 +370,7 @@ func TestPlayFileWithFallbackChain(t *testing.T) {
 		}
 
 		scb := NewSystemCommandBackend("aplay", successfulNoopCommand())
-		if err := scb.playFile(context.Background(), mp3File); err != nil {
+		if err := scb.playFile(context.Background(), mp3File, Variation{}); err != nil {
 			t.Fatalf("playFile should skip aplay for mp3 and use fallback: %v", err)
 		}
 	})
@@ -259,7 +382,7 @@ func TestPlayFileWithFallbackChain(t *testing.T) {
 		}
 
 		scb := NewSystemCommandBackend("aplay")
-		if err := scb.playFile(context.Background(), mp3File); err == nil {
+		if err := scb.playFile(context.Background(), mp3File, Variation{}); err == nil {
 			t.Fatal("playFile should fail when every command is format-incompatible")
 		}
 	})
@@ -0,0 +1,112 @@
+package audio
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// Variation shifts the pitch and stereo position of one play, so sounds
+// from concurrent sessions can be told apart by ear. Gain levels the sound
+// against the rest of its soundpack.
+type Variation struct {
+	Pitch float64 // playback-rate ratio; 1.0 or 0 leaves the pitch alone
+	Pan   float64 // stereo position from -1.0 (left) to 1.0 (right)
+	Gain  float64 // amplitude ratio from loudness normalization; 1.0 or 0 leaves the level alone
+}
+
+// EffectivePitch returns the playback-rate ratio, 1.0 when unset.
+func (v Variation) EffectivePitch() float64 {
+	if v.Pitch <= 0 {
+		return 1.0
+	}
+	return v.Pitch
+}
+
+// EffectiveGain returns the amplitude ratio, 1.0 when unset.
+func (v Variation) EffectiveGain() float64 {
+	if v.Gain <= 0 {
+		return 1.0
+	}
+	return v.Gain
+}
+
+// ShiftsPitchOrPan reports whether v moves the sound's pitch or stereo
+// position, which only some backends can do.
+func (v Variation) ShiftsPitchOrPan() bool {
+	return v.EffectivePitch() != 1.0 || v.Pan != 0
+}
+
+// IsNeutral reports whether v leaves the sound unchanged.
+func (v Variation) IsNeutral() bool {
+	return !v.ShiftsPitchOrPan() && v.EffectiveGain() == 1.0
+}
+
+// PanGains returns the left and right channel gains for v.Pan. Panning is
+// a balance control: the far side is attenuated and the near side stays at
+// full level, so a centered sound plays exactly as recorded.
+func (v Variation) PanGains() (left, right float64) {
+	pan := math.Max(-1, math.Min(1, v.Pan))
+	return math.Min(1, 1-pan), math.Min(1, 1+pan)
+}
+
+// DeriveVariation maps key to a fixed Variation: the same key always gets
+// the same pitch (within ±maxSemitones) and pan (within ±maxPan), and
+// different keys spread across that range.
+func DeriveVariation(key string, maxSemitones, maxPan float64) Variation {
+	sum := sha256.Sum256([]byte(key))
+	spread := func(b []byte) float64 {
+		// Map 32 hash bits onto [-1, 1].
+		return float64(binary.BigEndian.Uint32(b))/math.MaxUint32*2 - 1
+	}
+	return Variation{
+		Pitch: math.Pow(2, spread(sum[0:4])*maxSemitones/12),
+		Pan:   spread(sum[4:8]) * maxPan,
+	}
+}
+
+// VariedSource plays another source with a Variation. Backends that can
+// shift pitch or pan look for it with VariationOf; the others play the
+// underlying source unchanged.
+type VariedSource struct {
+	source    AudioSource
+	variation Variation
+}
+
+// WithVariation wraps source so it plays with v. A neutral v returns
+// source itself.
+func WithVariation(source AudioSource, v Variation) AudioSource {
+	if v.IsNeutral() {
+		return source
+	}
+	return &VariedSource{source: source, variation: v}
+}
+
+// Reader returns the underlying source's audio bytes.
+func (vs *VariedSource) Reader() (io.ReadCloser, string, error) {
+	return vs.source.Reader()
+}
+
+// FilePath returns the underlying source's file path, so exec backends
+// keep their fast path.
+func (vs *VariedSource) FilePath() (string, error) {
+	if fp, ok := vs.source.(FilePather); ok {
+		return fp.FilePath()
+	}
+	return "", ErrNotSupported
+}
+
+// Variation returns the pitch, pan, and gain to apply.
+func (vs *VariedSource) Variation() Variation {
+	return vs.variation
+}
+
+// VariationOf returns the Variation source should play with; a plain
+// source gets the neutral one.
+func VariationOf(source AudioSource) Variation {
+	if vs, ok := source.(*VariedSource); ok {
+		return vs.variation
+	}
+	return Variation{}
+}
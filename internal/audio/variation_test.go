@@ -0,0 +1,73 @@
+package audio
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDeriveVariation_StableAndBounded(t *testing.T) {
+	a := DeriveVariation("session-a", 2, 0.6)
+	if again := DeriveVariation("session-a", 2, 0.6); again != a {
+		t.Errorf("same key gave %+v then %+v", a, again)
+	}
+	if b := DeriveVariation("session-b", 2, 0.6); b == a {
+		t.Errorf("different keys should differ, both got %+v", a)
+	}
+
+	lo, hi := math.Pow(2, -2.0/12), math.Pow(2, 2.0/12)
+	for _, key := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		v := DeriveVariation(key, 2, 0.6)
+		if v.Pitch < lo || v.Pitch > hi {
+			t.Errorf("%s: pitch %g outside ±2 semitones", key, v.Pitch)
+		}
+		if math.Abs(v.Pan) > 0.6 {
+			t.Errorf("%s: pan %g outside ±0.6", key, v.Pan)
+		}
+	}
+
+	if v := DeriveVariation("session-a", 0, 0); !v.IsNeutral() {
+		t.Errorf("zero ranges should give a neutral variation, got %+v", v)
+	}
+}
+
+func TestVariation_PanGains(t *testing.T) {
+	tests := []struct {
+		pan         float64
+		left, right float64
+	}{
+		{0, 1, 1},
+		{-1, 1, 0},
+		{0.5, 0.5, 1},
+		{-0.25, 1, 0.75},
+	}
+	for _, tt := range tests {
+		l, r := Variation{Pan: tt.pan}.PanGains()
+		if l != tt.left || r != tt.right {
+			t.Errorf("PanGains(%g) = %g, %g; want %g, %g", tt.pan, l, r, tt.left, tt.right)
+		}
+	}
+}
+
+func TestWithVariation(t *testing.T) {
+	src := NewFileSource("/tmp/click.wav")
+	if got := WithVariation(src, Variation{}); got != AudioSource(src) {
+		t.Error("a neutral variation should return the source unwrapped")
+	}
+
+	varied := WithVariation(src, Variation{Pitch: 1.1, Pan: -0.3})
+	if v := VariationOf(varied); v.Pitch != 1.1 || v.Pan != -0.3 {
+		t.Errorf("VariationOf = %+v", v)
+	}
+	path, err := varied.(FilePather).FilePath()
+	if err != nil || path != "/tmp/click.wav" {
+		t.Errorf("FilePath = %q, %v; want the wrapped file", path, err)
+	}
+	if v := VariationOf(src); !v.IsNeutral() {
+		t.Errorf("plain source should be neutral, got %+v", v)
+	}
+
+	leveled := WithVariation(src, Variation{Gain: 0.8})
+	if v := VariationOf(leveled); v.EffectiveGain() != 0.8 || v.ShiftsPitchOrPan() {
+		t.Errorf("a gain-only variation should wrap and keep its gain, got %+v", v)
+	}
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"claudio.click/internal/cli/testenv"
+	"claudio.click/internal/config"
+)
+
+func TestAgentProfiles_SelectPackAndHooks(t *testing.T) {
+	root := testenv.IsolateXDG(t)
+
+	userDir := filepath.Join(root, "user")
+	codexDir := filepath.Join(root, "codex")
+	for _, dir := range []string{userDir, codexDir} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	userPack, userPhysical := writeTestJSONSoundpack(t, userDir, "default.wav", "claude/default.wav")
+	codexPack, codexPhysical := writeTestJSONSoundpack(t, codexDir, "default.wav")
+
+	cfg := config.NewConfigManager().GetDefaultConfig()
+	cfg.DefaultSoundpack = userPack
+	cfg.Agents = map[string]*config.AgentProfile{
+		"codex": {Soundpack: codexPack, EnabledHooks: []string{"Stop"}},
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+	configPath := filepath.Join(root, "config.json")
+	if err := os.WriteFile(configPath, data, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	stop := `{"session_id":"ap","cwd":"/tmp","hook_event_name":"Stop"}`
+	preTool := `{"session_id":"ap","cwd":"/tmp","hook_event_name":"PreToolUse","tool_name":"Read","tool_input":{"file_path":"/tmp/x"}}`
+
+	claudeArgs := []string{"claudio", "--config", configPath, "--hook-agent", "claude"}
+	if got := runHookForPlays(t, claudeArgs, stop); len(got) != 1 || got[0] != userPhysical["claude/default.wav"] {
+		t.Errorf("claude should prefer its agent-prefixed key, got %v", got)
+	}
+
+	codexArgs := []string{"claudio", "--config", configPath, "--hook-agent", "codex"}
+	if got := runHookForPlays(t, codexArgs, stop); len(got) != 1 || got[0] != codexPhysical["default.wav"] {
+		t.Errorf("codex should use its profile soundpack, got %v", got)
+	}
+	if got := runHookForPlays(t, codexArgs, preTool); len(got) != 0 {
+		t.Errorf("codex profile enables only Stop, got plays %v", got)
+	}
+}
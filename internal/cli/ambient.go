@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"claudio.click/internal/audio"
+	"claudio.click/internal/config"
+	"claudio.click/internal/hooks"
+	"claudio.click/internal/soundpack"
+)
+
+// startsAmbient reports whether eventName begins the agent's work, which
+// starts the ambient loop.
+func startsAmbient(eventName string) bool {
+	return eventName == "UserPromptSubmit" || eventName == "BeforeAgent"
+}
+
+// stopsAmbient reports whether eventName ends the agent's work or hands
+// control back to the user, which stops the ambient loop.
+func stopsAmbient(eventName string) bool {
+	switch eventName {
+	case "Stop", "AfterAgent", "StopFailure", "SessionEnd", "PermissionRequest":
+		return true
+	}
+	return false
+}
+
+// stopAmbient stops the session's ambient loop, wherever it runs, when
+// hookEvent ends the agent's work. It runs whether or not ambient is still
+// enabled, so turning the setting off never strands a running loop.
+func stopAmbient(hookEvent *hooks.HookEvent, cfg *config.Config) {
+	if !stopsAmbient(hookEvent.EventName) || hookEvent.SessionID == "" {
+		return
+	}
+	if err := newPlaybackCoordinator(cfg).StopAmbient(hookEvent.SessionID); err != nil {
+		slog.Warn("ambient loop not stopped (continuing)", "session_id", hookEvent.SessionID, "error", err)
+	}
+}
+
+// ambientMuteCheckInterval is how often a running ambient loop checks
+// whether a mute or quiet hours have started. A variable so tests can
+// shorten it.
+var ambientMuteCheckInterval = 5 * time.Second
+
+// playAmbient loops the configured ambient sound for the session until a
+// stopping event arrives from any process, a mute starts, the loop
+// reaches its maximum length, or ctx ends. It blocks, so it only runs in
+// processes that outlive the hook: the detached worker and the daemon.
+func (c *CLI) playAmbient(ctx context.Context, hookEvent *hooks.HookEvent, cfg *config.Config, reload configLoader) {
+	key := cfg.Ambient.EffectiveSound()
+	soundPath, err := c.soundpackResolver.ResolveSound(key)
+	if err != nil {
+		slog.Debug("no ambient sound in soundpack; skipping loop", "key", key, "error", err)
+		return
+	}
+	if soundpack.IsSpeechTemplate(soundPath) {
+		slog.Warn("ambient sound cannot be a say: template; skipping loop", "key", key)
+		return
+	}
+
+	loopCtx, cancel := context.WithTimeout(ctx, cfg.Ambient.EffectiveMaxDuration())
+	defer cancel()
+	ticket, err := newPlaybackCoordinator(cfg).AcquireAmbient(loopCtx, hookEvent.SessionID)
+	if err != nil {
+		slog.Warn("ambient loop not started", "session_id", hookEvent.SessionID, "error", err)
+		return
+	}
+	defer ticket.Release()
+	go stopAmbientWhenMuted(loopCtx, cancel, cfg, reload)
+
+	slog.Info("ambient loop started",
+		"session_id", hookEvent.SessionID,
+		"sound_path", soundPath,
+		"volume", cfg.Ambient.EffectiveVolume())
+	opts := audio.LoopOptions{
+		Fade: cfg.Ambient.EffectiveFade(),
+		Gain: float32(cfg.Ambient.EffectiveVolume()),
+	}
+	source := audio.WithVariation(audio.NewFileSource(soundPath), c.withLoudnessGain(soundPath, c.sessionVariation(hookEvent, cfg)))
+	if err := audio.PlayLoop(ticket.Context(), c.audioBackend, source, opts); err != nil {
+		slog.Error("ambient loop playback failed", "sound_path", soundPath, "error", err)
+		return
+	}
+	slog.Info("ambient loop stopped", "session_id", hookEvent.SessionID)
+}
+
+// stopAmbientWhenMuted calls stop once hooks become muted, checking every
+// ambientMuteCheckInterval until ctx ends.
+func stopAmbientWhenMuted(ctx context.Context, stop context.CancelFunc, cfg *config.Config, reload configLoader) {
+	ticker := time.NewTicker(ambientMuteCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if muted, reason := hookMuted(cfg, reload, now); muted {
+				slog.Info("ambient loop stopped by mute", "reason", reason)
+				stop()
+				return
+			}
+		}
+	}
+}
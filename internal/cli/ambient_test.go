@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"claudio.click/internal/audio"
+	"claudio.click/internal/cli/testenv"
+	"claudio.click/internal/config"
+)
+
+func TestAmbient_LoopsUntilSessionStops(t *testing.T) {
+	root := testenv.IsolateXDG(t)
+
+	packPath, physical := writeTestJSONSoundpack(t, root, "default.wav", "ambient/working.wav")
+	cfg := config.NewConfigManager().GetDefaultConfig()
+	cfg.DefaultSoundpack = packPath
+	cfg.Ambient = &config.AmbientConfig{Enabled: true, Volume: 0.2, FadeMs: 100}
+	configPath := filepath.Join(root, "config.json")
+	writeSeedConfig(t, configPath, cfg)
+
+	// --daemon-child runs the prompt hook as the detached worker would,
+	// which is the process allowed to linger for the loop.
+	audio.ResetLastFakeBackend()
+	done := make(chan int, 1)
+	go func() {
+		hookJSON := `{"session_id":"working","cwd":"/src/app","hook_event_name":"UserPromptSubmit","prompt":"go"}`
+		done <- NewCLI().Run([]string{"claudio", "--config", configPath, "--daemon-child"},
+			strings.NewReader(hookJSON), &bytes.Buffer{}, &bytes.Buffer{})
+	}()
+
+	var backend *audio.FakeBackend
+	deadline := time.Now().Add(10 * time.Second)
+	for backend == nil || !hasLoop(backend.Plays()) {
+		if time.Now().After(deadline) {
+			t.Fatal("ambient loop did not start")
+		}
+		time.Sleep(20 * time.Millisecond)
+		backend = audio.LastFakeBackend()
+	}
+
+	stop := func(sessionID string) {
+		t.Helper()
+		hookJSON := `{"session_id":"` + sessionID + `","cwd":"/src/app","hook_event_name":"Stop"}`
+		stderr := &bytes.Buffer{}
+		if code := NewCLI().Run([]string{"claudio", "--config", configPath}, strings.NewReader(hookJSON), &bytes.Buffer{}, stderr); code != 0 {
+			t.Fatalf("Stop hook exit code %d, stderr: %s", code, stderr.String())
+		}
+	}
+
+	// Another session finishing must leave this session's loop alone.
+	stop("other")
+	select {
+	case <-done:
+		t.Fatal("another session's Stop ended this session's loop")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	stop("working")
+	select {
+	case code := <-done:
+		if code != 0 {
+			t.Errorf("prompt hook exit code %d", code)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("ambient loop kept running after Stop")
+	}
+
+	var loop audio.FakePlay
+	for _, p := range backend.Plays() {
+		if p.Loop {
+			loop = p
+		}
+	}
+	if loop.SourcePath != physical["ambient/working.wav"] {
+		t.Errorf("loop played %q, want the ambient sound", loop.SourcePath)
+	}
+	if loop.LoopOpts.Gain != 0.2 || loop.LoopOpts.Fade != 100*time.Millisecond {
+		t.Errorf("loop options = %+v, want gain 0.2 and a 100ms fade", loop.LoopOpts)
+	}
+}
+
+func TestAmbient_NoLoopInHookProcess(t *testing.T) {
+	root := testenv.IsolateXDG(t)
+
+	packPath, _ := writeTestJSONSoundpack(t, root, "default.wav", "ambient/working.wav")
+	cfg := config.NewConfigManager().GetDefaultConfig()
+	cfg.DefaultSoundpack = packPath
+	cfg.Ambient = &config.AmbientConfig{Enabled: true}
+	configPath := filepath.Join(root, "config.json")
+	writeSeedConfig(t, configPath, cfg)
+
+	// Without --daemon-child the hook must play the prompt sound and return.
+	got := runHookForPlays(t, []string{"claudio", "--config", configPath},
+		`{"session_id":"s","cwd":"/src/app","hook_event_name":"UserPromptSubmit","prompt":"go"}`)
+	if len(got) != 1 {
+		t.Errorf("in-process hook should play once and return, got %v", got)
+	}
+	if hasLoop(audio.LastFakeBackend().Plays()) {
+		t.Error("in-process hook must not start the ambient loop")
+	}
+}
+
+func TestAmbient_StopsWhenMuted(t *testing.T) {
+	root := testenv.IsolateXDG(t)
+
+	packPath, _ := writeTestJSONSoundpack(t, root, "default.wav", "ambient/working.wav")
+	cfg := config.NewConfigManager().GetDefaultConfig()
+	cfg.DefaultSoundpack = packPath
+	cfg.Ambient = &config.AmbientConfig{Enabled: true}
+	configPath := filepath.Join(root, "config.json")
+	writeSeedConfig(t, configPath, cfg)
+
+	t.Run("stop event during a mute", func(t *testing.T) {
+		done := startAmbientLoop(t, configPath, "quiet-stop")
+
+		// The Stop hook returns early for the mute, but must still end
+		// the loop.
+		muted := *cfg
+		until := time.Now().Add(time.Hour)
+		muted.MutedUntil = &until
+		writeSeedConfig(t, configPath, &muted)
+		defer writeSeedConfig(t, configPath, cfg)
+
+		hookJSON := `{"session_id":"quiet-stop","cwd":"/src/app","hook_event_name":"Stop"}`
+		if code := NewCLI().Run([]string{"claudio", "--config", configPath}, strings.NewReader(hookJSON), &bytes.Buffer{}, &bytes.Buffer{}); code != 0 {
+			t.Fatalf("Stop hook exit code %d", code)
+		}
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("ambient loop kept running after a muted Stop")
+		}
+	})
+
+	t.Run("mute starts mid-loop", func(t *testing.T) {
+		interval := ambientMuteCheckInterval
+		ambientMuteCheckInterval = 20 * time.Millisecond
+		defer func() { ambientMuteCheckInterval = interval }()
+
+		done := startAmbientLoop(t, configPath, "quiet-loop")
+
+		// `claudio mute` with no duration turns audio off.
+		muted := *cfg
+		muted.Enabled = false
+		writeSeedConfig(t, configPath, &muted)
+		defer writeSeedConfig(t, configPath, cfg)
+
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("ambient loop kept running after a mute started")
+		}
+	})
+}
+
+// startAmbientLoop runs a prompt hook for sessionID as the detached worker
+// would and waits for its ambient loop to start. The returned channel
+// receives the hook's exit code once the loop ends.
+func startAmbientLoop(t *testing.T, configPath, sessionID string) <-chan int {
+	t.Helper()
+	audio.ResetLastFakeBackend()
+	done := make(chan int, 1)
+	go func() {
+		hookJSON := `{"session_id":"` + sessionID + `","cwd":"/src/app","hook_event_name":"UserPromptSubmit","prompt":"go"}`
+		done <- NewCLI().Run([]string{"claudio", "--config", configPath, "--daemon-child"},
+			strings.NewReader(hookJSON), &bytes.Buffer{}, &bytes.Buffer{})
+	}()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for backend := audio.LastFakeBackend(); backend == nil || !hasLoop(backend.Plays()); backend = audio.LastFakeBackend() {
+		if time.Now().After(deadline) {
+			t.Fatal("ambient loop did not start")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return done
+}
+
+// hasLoop reports whether plays include a looping play.
+func hasLoop(plays []audio.FakePlay) bool {
+	for _, p := range plays {
+		if p.Loop {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"claudio.click/internal/audio"
+	"claudio.click/internal/config"
+	"claudio.click/internal/hooks"
+	"claudio.click/internal/playback"
+	"claudio.click/internal/tracking"
+)
+
+// trackAttention keeps per-session activity in the tracking database when
+// attention reminders are on. An event that leaves the agent waiting on
+// the user (hooks.HookEvent.NeedsAttention) opens a request and returns
+// its time; every other event counts as activity and answers it.
+func (c *CLI) trackAttention(ctx context.Context, hookEvent *hooks.HookEvent, cfg *config.Config, now time.Time) (time.Time, bool) {
+	if cfg.Attention == nil || !cfg.Attention.Enabled || c.trackingDB == nil || hookEvent.SessionID == "" {
+		return time.Time{}, false
+	}
+
+	if !hookEvent.NeedsAttention() {
+		if err := tracking.MarkSessionActive(ctx, c.trackingDB, hookEvent.SessionID, now); err != nil {
+			slog.Warn("session activity not recorded (continuing)", "error", err)
+		}
+		return time.Time{}, false
+	}
+
+	if err := tracking.MarkAttentionRequested(ctx, c.trackingDB, hookEvent.SessionID, now); err != nil {
+		slog.Warn("attention request not recorded; no reminders (continuing)", "error", err)
+		return time.Time{}, false
+	}
+	return now, true
+}
+
+// remindUntilAnswered replays soundPath every interval, one volume step
+// louder each time, until the session moves again, a newer request takes
+// over, the reminders run out, or ctx ends. It blocks, so it only runs in
+// processes that outlive the hook: the detached worker and the daemon.
+func (c *CLI) remindUntilAnswered(ctx context.Context, interval time.Duration, hookEvent *hooks.HookEvent, cfg *config.Config, reload configLoader,
+	soundPath string, volume float64, speech *speechRequest, variation audio.Variation, since time.Time) {
+	for n := 1; n <= cfg.Attention.EffectiveMaxReminders(); n++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		pending, err := tracking.AttentionPending(ctx, c.trackingDB, hookEvent.SessionID, since)
+		if err != nil {
+			slog.Warn("attention state unavailable; stopping reminders", "error", err)
+			return
+		}
+		if !pending {
+			slog.Debug("attention answered; stopping reminders", "session_id", hookEvent.SessionID, "reminders", n-1)
+			return
+		}
+		if muted, reason := hookMuted(cfg, reload, time.Now()); muted {
+			slog.Info("attention reminders stopped by mute schedule", "reason", reason)
+			return
+		}
+
+		reminderVolume := cfg.Attention.ReminderVolume(volume, n)
+		slog.Info("replaying unanswered attention sound",
+			"session_id", hookEvent.SessionID,
+			"reminder", n,
+			"volume", reminderVolume,
+			"sound_path", soundPath)
+		if err := c.playReminder(ctx, cfg, hookEvent.SessionID, soundPath, volume, reminderVolume, speech, variation); err != nil {
+			slog.Error("attention reminder playback failed", "sound_path", soundPath, "error", err)
+			return
+		}
+	}
+}
+
+// playReminder plays one reminder at reminderVolume under the usual
+// overlap policy. The backend stays at the configured volume: the daemon
+// shares it between sessions, so the step up rides on this one sound as
+// a gain instead.
+func (c *CLI) playReminder(ctx context.Context, cfg *config.Config, sessionID, soundPath string, volume, reminderVolume float64,
+	speech *speechRequest, variation audio.Variation) error {
+	playCtx := ctx
+	ticket, err := newPlaybackCoordinator(cfg).Acquire(ctx, sessionID)
+	switch {
+	case errors.Is(err, playback.ErrDropped):
+		slog.Info("attention reminder skipped by overlap policy", "sound_path", soundPath)
+		return nil
+	case err != nil:
+		slog.Warn("overlap coordination failed; playing without it", "error", err)
+	default:
+		defer ticket.Release()
+		playCtx = ticket.Context()
+	}
+
+	if volume > 0 {
+		variation.Gain = variation.EffectiveGain() * reminderVolume / volume
+	}
+	return c.playSoundWithBackend(playCtx, soundPath, reminderVolume, speech, variation)
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"claudio.click/internal/audio"
+	"claudio.click/internal/cli/testenv"
+	"claudio.click/internal/config"
+	"claudio.click/internal/tracking"
+)
+
+func TestAttention_RemindsLouderUntilSessionMoves(t *testing.T) {
+	root := testenv.IsolateXDG(t)
+	dbPath := filepath.Join(root, "claudio.db")
+	t.Setenv("CLAUDIO_SOUND_TRACKING", "true")
+	t.Setenv("CLAUDIO_SOUND_TRACKING_DB", dbPath)
+
+	packPath, physical := writeTestJSONSoundpack(t, root, "default.wav", "interactive/permission-request.wav")
+	cfg := config.NewConfigManager().GetDefaultConfig()
+	cfg.DefaultSoundpack = packPath
+	volume := 0.5
+	cfg.Volume = &volume
+	cfg.Attention = &config.AttentionConfig{Enabled: true, AfterSeconds: 1, MaxReminders: 3, VolumeStep: 0.25}
+	configPath := filepath.Join(root, "config.json")
+	writeSeedConfig(t, configPath, cfg)
+
+	// The session moves again between the first and second reminder.
+	go func() {
+		time.Sleep(1500 * time.Millisecond)
+		db, err := tracking.NewDatabase(dbPath)
+		if err != nil {
+			t.Errorf("NewDatabase: %v", err)
+			return
+		}
+		defer db.Close()
+		if err := tracking.MarkSessionActive(context.Background(), db, "blocked", time.Now()); err != nil {
+			t.Errorf("MarkSessionActive: %v", err)
+		}
+	}()
+
+	// --daemon-child runs the hook as the detached worker would, which is
+	// the process allowed to linger for reminders.
+	audio.ResetLastFakeBackend()
+	hookJSON := `{"session_id":"blocked","cwd":"/src/app","hook_event_name":"PermissionRequest","tool_name":"Bash"}`
+	stderr := &bytes.Buffer{}
+	args := []string{"claudio", "--config", configPath, "--daemon-child"}
+	if code := NewCLI().Run(args, strings.NewReader(hookJSON), &bytes.Buffer{}, stderr); code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr.String())
+	}
+
+	plays := audio.LastFakeBackend().Plays()
+	if len(plays) != 2 {
+		t.Fatalf("want the request sound plus one reminder, got %d plays: %+v", len(plays), plays)
+	}
+	for _, p := range plays {
+		if p.SourcePath != physical["interactive/permission-request.wav"] {
+			t.Errorf("reminder should replay the request sound, got %s", p.SourcePath)
+		}
+	}
+	// The reminder steps up as a gain on its own sound; the backend, which
+	// the daemon shares, stays at the configured volume.
+	for _, p := range plays {
+		if p.Volume != 0.5 {
+			t.Errorf("backend volume = %v, want 0.5 for every play", p.Volume)
+		}
+	}
+	if g0, g1 := plays[0].Variation.EffectiveGain(), plays[1].Variation.EffectiveGain(); g0 != 1 || g1 != 1.5 {
+		t.Errorf("gains = %v, %v; want 1, 1.5", g0, g1)
+	}
+}
+
+func TestAttention_NoRemindersInHookProcess(t *testing.T) {
+	root := testenv.IsolateXDG(t)
+	t.Setenv("CLAUDIO_SOUND_TRACKING", "true")
+	t.Setenv("CLAUDIO_SOUND_TRACKING_DB", filepath.Join(root, "claudio.db"))
+
+	packPath, _ := writeTestJSONSoundpack(t, root, "default.wav")
+	cfg := config.NewConfigManager().GetDefaultConfig()
+	cfg.DefaultSoundpack = packPath
+	cfg.Attention = &config.AttentionConfig{Enabled: true, AfterSeconds: 60}
+	configPath := filepath.Join(root, "config.json")
+	writeSeedConfig(t, configPath, cfg)
+
+	// Without --daemon-child the hook must return at once.
+	start := time.Now()
+	got := runHookForPlays(t, []string{"claudio", "--config", configPath},
+		`{"session_id":"s","cwd":"/src/app","hook_event_name":"PermissionRequest"}`)
+	if len(got) != 1 || time.Since(start) > 30*time.Second {
+		t.Errorf("in-process hook should play once and return, got %v after %v", got, time.Since(start))
+	}
+}
@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+
+	"claudio.click/internal/audio"
+	"claudio.click/internal/audio/pcmcache"
+	"claudio.click/internal/config"
+	"claudio.click/internal/soundpack"
+	"github.com/spf13/cobra"
+)
+
+// newCacheCommand creates the cache command group, which manages the
+// decoded audio the malgo backend keeps under the XDG cache directory so
+// hooks skip decoding sounds they have played before.
+func newCacheCommand() *cobra.Command {
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the decoded audio cache",
+		Long: `Manage the decoded audio cache.
+
+The malgo backend stores each sound it decodes under the XDG cache
+directory, keyed by the file's content, so later plays skip decoding. The
+cache is bounded in size and drops the least recently played sounds first.`,
+	}
+	cacheCmd.AddCommand(newCacheWarmCommand())
+	cacheCmd.AddCommand(newCacheClearCommand())
+	cacheCmd.AddCommand(newCacheStatsCommand())
+	return cacheCmd
+}
+
+func newCacheWarmCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "warm [soundpack]",
+		Short: "Decode a soundpack into the cache ahead of playback",
+		Long: `Decode every sound in a soundpack into the cache, so the first hook to
+play each one does not wait on decoding. Without an argument the active
+soundpack (default_soundpack) is warmed. 'claudio soundpack use' warms the
+pack it switches to automatically.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := ""
+			if len(args) == 1 {
+				name = args[0]
+			}
+			return runCacheWarm(cmd, name)
+		},
+	}
+}
+
+func newCacheClearCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "Remove every cached sound",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			removed, err := pcmcache.New(pcmcache.DefaultDir(), pcmcache.DefaultMaxBytes).Clear()
+			if err != nil {
+				return fmt.Errorf("failed to clear audio cache: %w", err)
+			}
+			cmd.Printf("Removed %d cached sounds.\n", removed)
+			return nil
+		},
+	}
+}
+
+func newCacheStatsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stats",
+		Short: "Show cache location and size",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stats, err := pcmcache.New(pcmcache.DefaultDir(), pcmcache.DefaultMaxBytes).Stats()
+			if err != nil {
+				return fmt.Errorf("failed to read audio cache: %w", err)
+			}
+			cmd.Printf("Directory: %s\n", stats.Dir)
+			cmd.Printf("Sounds:    %d\n", stats.Entries)
+			cmd.Printf("Size:      %s of %s\n", formatCacheBytes(stats.Bytes), formatCacheBytes(stats.MaxBytes))
+			return nil
+		},
+	}
+}
+
+func runCacheWarm(cmd *cobra.Command, name string) error {
+	cfg, err := loadCacheConfig()
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		name = cfg.DefaultSoundpack
+	}
+	if name == "" {
+		return fmt.Errorf("no soundpack given and no default_soundpack configured")
+	}
+
+	backend, err := audio.NewBackend(cfg.AudioBackend)
+	if err != nil {
+		return fmt.Errorf("failed to create audio backend '%s': %w", cfg.AudioBackend, err)
+	}
+	defer backend.Close()
+	warmer, ok := backend.(audio.Warmer)
+	if !ok {
+		return fmt.Errorf("audio backend '%s' does not cache decoded audio; only the malgo backend does", cfg.AudioBackend)
+	}
+
+	warmed, failed, err := warmSoundpack(cmd.Context(), warmer, name, cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		cmd.Printf("Warmed %d sounds from '%s' (%d could not be decoded).\n", warmed, name, failed)
+	} else {
+		cmd.Printf("Warmed %d sounds from '%s'.\n", warmed, name)
+	}
+	return nil
+}
+
+// warmActiveSoundpack warms name after 'soundpack use' switches to it.
+// It is best effort: a backend without a cache is skipped silently, and a
+// pack that cannot be warmed only costs the first plays their decode.
+func warmActiveSoundpack(cmd *cobra.Command, name string) {
+	cfg, err := loadCacheConfig()
+	if err != nil {
+		slog.Debug("skipping cache warm: config unavailable", "error", err)
+		return
+	}
+	backend, err := audio.NewBackend(cfg.AudioBackend)
+	if err != nil {
+		slog.Debug("skipping cache warm: no audio backend", "backend", cfg.AudioBackend, "error", err)
+		return
+	}
+	defer backend.Close()
+	warmer, ok := backend.(audio.Warmer)
+	if !ok {
+		slog.Debug("skipping cache warm: backend has no decoded audio cache", "backend", fmt.Sprintf("%T", backend))
+		return
+	}
+
+	warmed, failed, err := warmSoundpack(cmd.Context(), warmer, name, io.Discard)
+	if err != nil {
+		slog.Warn("failed to warm audio cache", "soundpack", name, "error", err)
+		return
+	}
+	slog.Info("warmed audio cache", "soundpack", name, "warmed", warmed, "failed", failed)
+	if warmed > 0 {
+		cmd.Printf("Decoded %d sounds into the audio cache.\n", warmed)
+	}
+}
+
+// warmSoundpack decodes every file soundpack name can play into warmer's
+// cache. Files that fail to decode are reported to errOut and counted
+// rather than stopping the rest.
+func warmSoundpack(ctx context.Context, warmer audio.Warmer, name string, errOut io.Writer) (warmed, failed int, err error) {
+	pack, err := loadSoundpackForExport(name)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	for _, value := range pack.Mappings {
+		for _, file := range value.Files {
+			// say: templates are spoken at play time; there is no file to decode.
+			if soundpack.IsSpeechTemplate(file) {
+				continue
+			}
+			if file != "" && !seen[file] {
+				seen[file] = true
+				files = append(files, file)
+			}
+		}
+	}
+	sort.Strings(files)
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return warmed, failed, err
+		}
+		if err := warmer.Warm(ctx, file); err != nil {
+			slog.Debug("failed to warm sound", "file", file, "error", err)
+			fmt.Fprintf(errOut, "warning: %v\n", err)
+			failed++
+			continue
+		}
+		warmed++
+	}
+	return warmed, failed, nil
+}
+
+// loadCacheConfig loads the user config with environment overrides, so
+// the backend chosen for warming is the one hooks will play through.
+func loadCacheConfig() (*config.Config, error) {
+	cm := config.NewConfigManager()
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return cm.ApplyEnvironmentOverrides(cfg), nil
+}
+
+// formatCacheBytes renders a byte count in MiB for cache stats.
+func formatCacheBytes(n int64) string {
+	return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+}
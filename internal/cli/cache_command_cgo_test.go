@@ -0,0 +1,80 @@
+//go:build cgo
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"claudio.click/internal/audio/pcmcache"
+)
+
+// writeWarmTestPack installs a directory soundpack of decodable WAVs plus
+// one file no decoder accepts.
+func writeWarmTestPack(t *testing.T, dataDir string) string {
+	t.Helper()
+	packDir := filepath.Join(dataDir, "claudio", "soundpacks", "warm-pack")
+	files := map[string][]byte{
+		"success/success.wav":   createMinimalWAV(),
+		"error/error.wav":       append(createMinimalWAV(), 0, 0), // distinct content
+		"default.wav":           createMinimalWAV(),               // same content as success
+		"loading/loading-1.wav": []byte("not audio"),
+	}
+	for rel, data := range files {
+		path := filepath.Join(packDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+	return packDir
+}
+
+func TestCacheWarm_DecodesSoundpack(t *testing.T) {
+	dataDir, _, cleanup := setupInstallTestEnv(t)
+	defer cleanup()
+	t.Setenv("CLAUDIO_AUDIO_BACKEND", "malgo")
+	writeWarmTestPack(t, dataDir)
+
+	stdout, stderr, exitCode := runSoundpackCLI("cache", "warm", "warm-pack")
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stdout: %s, stderr: %s", exitCode, stdout, stderr)
+	}
+	if !strings.Contains(stdout, "Warmed 3 sounds from 'warm-pack' (1 could not be decoded)") {
+		t.Errorf("unexpected warm output: %s", stdout)
+	}
+	if !strings.Contains(stderr, "loading-1.wav") {
+		t.Errorf("expected the undecodable file to be reported, got: %s", stderr)
+	}
+
+	// Identical files share one entry: the cache is keyed by content.
+	stats, err := pcmcache.New(pcmcache.DefaultDir(), 0).Stats()
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.Entries != 2 {
+		t.Errorf("expected 2 cache entries, got %+v", stats)
+	}
+}
+
+func TestSoundpackUse_WarmsCache(t *testing.T) {
+	dataDir, _, cleanup := setupInstallTestEnv(t)
+	defer cleanup()
+	t.Setenv("CLAUDIO_AUDIO_BACKEND", "malgo")
+	writeWarmTestPack(t, dataDir)
+
+	stdout, stderr, exitCode := runSoundpackCLI("soundpack", "use", "warm-pack")
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stdout: %s, stderr: %s", exitCode, stdout, stderr)
+	}
+	if !strings.Contains(stdout, "Decoded 3 sounds into the audio cache") {
+		t.Errorf("expected use to report warming, got: %s", stdout)
+	}
+	if stats, _ := pcmcache.New(pcmcache.DefaultDir(), 0).Stats(); stats.Entries == 0 {
+		t.Error("expected soundpack use to fill the cache")
+	}
+}
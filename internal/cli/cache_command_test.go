@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"claudio.click/internal/audio/pcmcache"
+)
+
+func TestCacheStats_ReportsEntries(t *testing.T) {
+	root := setupCacheTestEnv(t)
+
+	cache := pcmcache.New(pcmcache.DefaultDir(), 0)
+	if err := cache.Put("entry", &pcmcache.Entry{Format: 2, Channels: 1, SampleRate: 44100, Samples: make([]byte, 1024)}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	stdout, stderr, exitCode := runSoundpackCLI("cache", "stats")
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr: %s", exitCode, stderr)
+	}
+	wantDir := filepath.Join(root, ".cache", "claudio", "pcm")
+	for _, want := range []string{wantDir, "Sounds:    1", "of 256.0 MiB"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected stats to contain %q, got:\n%s", want, stdout)
+		}
+	}
+}
+
+func TestCacheClear_RemovesEntries(t *testing.T) {
+	setupCacheTestEnv(t)
+
+	cache := pcmcache.New(pcmcache.DefaultDir(), 0)
+	for _, key := range []string{"a", "b"} {
+		if err := cache.Put(key, &pcmcache.Entry{Format: 2, Channels: 1, SampleRate: 44100, Samples: []byte{0, 0}}); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+
+	stdout, stderr, exitCode := runSoundpackCLI("cache", "clear")
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr: %s", exitCode, stderr)
+	}
+	if !strings.Contains(stdout, "Removed 2 cached sounds") {
+		t.Errorf("unexpected clear output: %s", stdout)
+	}
+	if stats, _ := cache.Stats(); stats.Entries != 0 {
+		t.Errorf("expected an empty cache, got %+v", stats)
+	}
+}
+
+func TestCacheWarm_RejectsBackendWithoutCache(t *testing.T) {
+	setupCacheTestEnv(t) // the fake backend decodes nothing
+
+	_, stderr, exitCode := runSoundpackCLI("cache", "warm", "windows")
+	if exitCode == 0 {
+		t.Fatal("expected warming through the fake backend to fail")
+	}
+	if !strings.Contains(stderr, "does not cache decoded audio") {
+		t.Errorf("expected a no-cache error, got: %s", stderr)
+	}
+}
+
+// recordingWarmer records the files it is asked to warm.
+type recordingWarmer struct {
+	files []string
+}
+
+func (w *recordingWarmer) Warm(_ context.Context, filePath string) error {
+	w.files = append(w.files, filePath)
+	return nil
+}
+
+func TestWarmSoundpack_SkipsSpeechTemplates(t *testing.T) {
+	dir := t.TempDir()
+	sound := filepath.Join(dir, "done.wav")
+	if err := os.WriteFile(sound, createMinimalWAV(), 0o644); err != nil {
+		t.Fatalf("write wav: %v", err)
+	}
+	data, err := json.Marshal(map[string]any{"name": "spoken", "mappings": map[string]any{
+		"default.wav":                   sound,
+		"completion/agent-complete.wav": "say:{project} is done",
+	}})
+	if err != nil {
+		t.Fatalf("marshal soundpack: %v", err)
+	}
+	packPath := filepath.Join(dir, "pack.json")
+	if err := os.WriteFile(packPath, data, 0o644); err != nil {
+		t.Fatalf("write soundpack: %v", err)
+	}
+
+	warmer := &recordingWarmer{}
+	errOut := &strings.Builder{}
+	warmed, failed, err := warmSoundpack(context.Background(), warmer, packPath, errOut)
+	if err != nil {
+		t.Fatalf("warmSoundpack: %v", err)
+	}
+	if warmed != 1 || failed != 0 {
+		t.Errorf("warmed %d, failed %d; want 1, 0 (warnings: %s)", warmed, failed, errOut)
+	}
+	if want := []string{sound}; !reflect.DeepEqual(warmer.files, want) {
+		t.Errorf("warmed files = %v, want %v", warmer.files, want)
+	}
+}
+
+// setupCacheTestEnv isolates XDG directories and returns the sandbox root.
+func setupCacheTestEnv(t *testing.T) string {
+	t.Helper()
+	dataDir, _, cleanup := setupInstallTestEnv(t)
+	t.Cleanup(cleanup)
+	return filepath.Dir(filepath.Dir(dataDir))
+}
@@ -3,6 +3,8 @@ package cli
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -10,10 +12,14 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"claudio.click/internal/audio"
+	"claudio.click/internal/audio/loudness"
 	"claudio.click/internal/config"
 	"claudio.click/internal/hooks"
+	"claudio.click/internal/playback"
+	"claudio.click/internal/ratelimit"
 	"claudio.click/internal/safeio"
 	"claudio.click/internal/soundpack"
 	"claudio.click/internal/sounds"
@@ -31,6 +37,10 @@ type CLI struct {
 	soundpackResolver soundpack.SoundpackResolver
 	audioBackend      audio.AudioBackend
 	trackingDB        *sql.DB // Optional tracking database
+	// lingerCtx is set in processes that may outlive the hook (the
+	// detached worker and the daemon) and bounds attention reminders and
+	// ambient loops; nil means the process must not linger, so neither.
+	lingerCtx context.Context
 }
 
 // NewCLI creates a new CLI instance
@@ -74,12 +84,25 @@ func NewCLI() *CLI {
 	// Add status subcommand
 	rootCmd.AddCommand(newStatusCommand())
 
+	// Add trust / untrust subcommands (approve per-project .claudio.json overlays)
+	rootCmd.AddCommand(newTrustCommand())
+	rootCmd.AddCommand(newUntrustCommand())
+
+	// Add daemon subcommand (persistent playback process hooks forward to)
+	rootCmd.AddCommand(newDaemonCommand())
+
 	// Add install-commands subcommand (writes the /claudio slash command markdown)
 	rootCmd.AddCommand(newInstallCommandsCommand())
 
 	// Add uninstall-commands subcommand (removes the command artifact installed above)
 	rootCmd.AddCommand(newUninstallCommandsCommand())
 
+	// Add cache subcommand (decoded audio cache warm/clear/stats)
+	rootCmd.AddCommand(newCacheCommand())
+
+	// Add devices subcommand (lists playback devices for audio_device)
+	rootCmd.AddCommand(newDevicesCommand())
+
 	// Add persistent flags to root command for backward compatibility
 	rootCmd.PersistentFlags().String("config", "", "Path to config file")
 	rootCmd.PersistentFlags().String("volume", "", "Set volume (0.0 to 1.0)")
@@ -146,24 +169,57 @@ func hasVersionFlag(args []string) bool {
 	return false
 }
 
+// hookFlags carries the per-invocation overrides a hook process accepts on
+// its command line. It exists as a plain struct (rather than being read
+// off cobra at each use) so the same overrides can travel over the daemon
+// socket and be applied by a process that never saw the original argv.
+type hookFlags struct {
+	Config    string `json:"config,omitempty"`
+	Volume    string `json:"volume,omitempty"`
+	Soundpack string `json:"soundpack,omitempty"`
+	Silent    bool   `json:"silent,omitempty"`
+	Agent     string `json:"agent,omitempty"`
+	Event     string `json:"event,omitempty"`
+}
+
+// hookFlagsFromCommand snapshots the hook-relevant flags of an already
+// parsed command.
+func hookFlagsFromCommand(cmd *cobra.Command) hookFlags {
+	var f hookFlags
+	f.Config, _ = cmd.Flags().GetString("config")
+	f.Volume, _ = cmd.Flags().GetString("volume")
+	f.Soundpack, _ = cmd.Flags().GetString("soundpack")
+	f.Silent, _ = cmd.Flags().GetBool("silent")
+	f.Agent, _ = cmd.Flags().GetString("hook-agent")
+	f.Event, _ = cmd.Flags().GetString("hook-event")
+	return f
+}
+
 // loadAndValidateConfig loads configuration from flags and files, applies overrides, and validates
 func loadAndValidateConfig(cmd *cobra.Command, cli *CLI) (*config.Config, error) {
-	// Get flag values
-	configFile, _ := cmd.Flags().GetString("config")
-	volumeStr, _ := cmd.Flags().GetString("volume")
-	soundpackFlag, _ := cmd.Flags().GetString("soundpack")
-	silent, _ := cmd.Flags().GetBool("silent")
+	return loadHookConfig(cli.configManager, hookFlagsFromCommand(cmd), "", os.Getenv, cmd.ErrOrStderr())
+}
+
+// loadHookConfig is the flag-source-agnostic body of loadAndValidateConfig.
+// User-facing errors are written to errOut; the daemon passes io.Discard
+// because its stderr is not attached to anyone. A non-empty cwd layers the
+// trusted project overlay (.claudio.json) for that directory between the
+// config file and the environment/flag overrides. getenv supplies the
+// CLAUDIO_* overrides: the process environment, or in the daemon the
+// hook's forwarded one.
+func loadHookConfig(cm *config.ConfigManager, flags hookFlags, cwd string, getenv func(string) string, errOut io.Writer) (*config.Config, error) {
+	volumeStr := flags.Volume
 
 	// Validate volume flag early to match old behavior
 	if volumeStr != "" {
 		vol, err := strconv.ParseFloat(volumeStr, 64)
 		if err != nil {
-			cmd.PrintErrf("Error: invalid volume value '%s': %v\n", volumeStr, err)
+			fmt.Fprintf(errOut, "Error: invalid volume value '%s': %v\n", volumeStr, err)
 			slog.Error("invalid volume value", "value", volumeStr, "error", err)
 			return nil, fmt.Errorf("invalid volume value '%s': %w", volumeStr, err)
 		}
 		if vol < 0.0 || vol > 1.0 {
-			cmd.PrintErrf("Error: volume must be between 0.0 and 1.0, got %f\n", vol)
+			fmt.Fprintf(errOut, "Error: volume must be between 0.0 and 1.0, got %f\n", vol)
 			slog.Error("volume out of range", "value", vol)
 			return nil, fmt.Errorf("volume must be between 0.0 and 1.0, got %f", vol)
 		}
@@ -172,24 +228,34 @@ func loadAndValidateConfig(cmd *cobra.Command, cli *CLI) (*config.Config, error)
 	// Load configuration
 	var cfg *config.Config
 	var err error
-	if configFile != "" {
-		cfg, err = cli.configManager.LoadFromFile(configFile)
+	if flags.Config != "" {
+		cfg, err = cm.LoadFromFile(flags.Config)
 		if err != nil {
 			// If config file doesn't exist, use defaults
-			slog.Warn("config file not found, using defaults", "file", configFile, "error", err)
-			cfg = cli.configManager.GetDefaultConfig()
+			slog.Warn("config file not found, using defaults", "file", flags.Config, "error", err)
+			cfg = cm.GetDefaultConfig()
 		}
 	} else {
-		cfg, err = cli.configManager.LoadConfig()
+		cfg, err = cm.LoadConfig()
 		if err != nil {
-			cmd.PrintErrf("Error loading config: %v\n", err)
+			fmt.Fprintf(errOut, "Error loading config: %v\n", err)
 			slog.Error("config load failed", "error", err)
 			return nil, fmt.Errorf("error loading config: %w", err)
 		}
 	}
 
+	// Apply the per-project overlay, if the hook's cwd has a trusted one
+	if cwd != "" {
+		cfg = cm.ApplyProjectConfig(cfg, cwd)
+	}
+
+	// Apply the invoking agent's profile on top of file settings
+	if flags.Agent != "" {
+		cfg = cm.ApplyAgentProfile(cfg, flags.Agent)
+	}
+
 	// Apply environment overrides
-	cfg = cli.configManager.ApplyEnvironmentOverrides(cfg)
+	cfg = cm.ApplyEnvironmentOverridesFrom(cfg, getenv)
 
 	// Apply command line overrides
 	if volumeStr != "" {
@@ -199,20 +265,20 @@ func loadAndValidateConfig(cmd *cobra.Command, cli *CLI) (*config.Config, error)
 		slog.Debug("volume override applied", "value", vol)
 	}
 
-	if soundpackFlag != "" {
-		cfg.DefaultSoundpack = soundpackFlag
-		slog.Debug("soundpack override applied", "value", soundpackFlag)
+	if flags.Soundpack != "" {
+		cfg.DefaultSoundpack = flags.Soundpack
+		slog.Debug("soundpack override applied", "value", flags.Soundpack)
 	}
 
-	if silent {
+	if flags.Silent {
 		cfg.Enabled = false
 		slog.Debug("silent mode enabled")
 	}
 
 	// Validate final configuration
-	err = cli.configManager.ValidateConfig(cfg)
+	err = cm.ValidateConfig(cfg)
 	if err != nil {
-		cmd.PrintErrf("Error: invalid configuration: %v\n", err)
+		fmt.Fprintf(errOut, "Error: invalid configuration: %v\n", err)
 		slog.Error("config validation failed", "error", err)
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
@@ -255,29 +321,7 @@ func initializeAudioSystem(cmd *cobra.Command, cli *CLI, cfg *config.Config) err
 	} else {
 		// Resolve soundpack name to path: if default_soundpack is a name (not a path),
 		// search soundpack_paths for a matching entry
-		resolvedPath := cfg.DefaultSoundpack
-		if _, statErr := os.Stat(resolvedPath); statErr != nil {
-			if managedPath := findManagedGitSoundpackPath(cfg.DefaultSoundpack); managedPath != "" {
-				slog.Info("resolved managed git soundpack name to path",
-					"name", cfg.DefaultSoundpack, "path", managedPath)
-				resolvedPath = managedPath
-			}
-		}
-		if _, statErr := os.Stat(resolvedPath); statErr != nil {
-			// Not a direct path — try to find it in soundpack_paths
-			for _, sp := range cfg.SoundpackPaths {
-				if _, spErr := os.Stat(sp); spErr == nil {
-					base := filepath.Base(sp)
-					name := strings.TrimSuffix(base, filepath.Ext(base))
-					if name == cfg.DefaultSoundpack {
-						slog.Debug("resolved soundpack name to path",
-							"name", cfg.DefaultSoundpack, "path", sp)
-						resolvedPath = sp
-						break
-					}
-				}
-			}
-		}
+		resolvedPath := resolveSoundpackPath(cfg, cfg.DefaultSoundpack)
 
 		// Check if resolved soundpack path exists
 		if _, statErr := os.Stat(resolvedPath); statErr != nil {
@@ -343,7 +387,14 @@ func initializeAudioSystem(cmd *cobra.Command, cli *CLI, cfg *config.Config) err
 		}
 	}
 
-	cli.soundpackResolver = soundpack.NewSoundpackResolver(mapper)
+	// Overlay packs from soundpack_stack sit above the active pack, and the
+	// packs a manifest extends sit below it.
+	mapper = stackSoundpacks(cfg, mapper)
+
+	// Round-robin and shuffle positions for multi-file sound keys outlive
+	// the hook process, so they are kept under the XDG cache dir.
+	picker := soundpack.NewPicker(filepath.Join(config.NewXDGDirs().GetCachePath("variations"), "state.json"))
+	cli.soundpackResolver = soundpack.NewSoundpackResolverWithPicker(mapper, picker)
 
 	slog.Debug("soundpack resolver initialized",
 		"soundpack_name", cfg.DefaultSoundpack,
@@ -388,9 +439,22 @@ func (c *CLI) initializeAudioSystemWithBackend(cfg *config.Config) error {
 		return fmt.Errorf("failed to set volume on backend: %w", err)
 	}
 
+	if cfg.AudioDevice != "" {
+		if selector, ok := c.audioBackend.(audio.DeviceSelector); ok {
+			if err := selector.SetDevice(cfg.AudioDevice); err != nil {
+				slog.Error("failed to select audio device", "device", cfg.AudioDevice, "error", err)
+				return fmt.Errorf("failed to select audio device: %w", err)
+			}
+		} else {
+			slog.Warn("audio backend cannot select an output device; audio_device ignored",
+				"backend_type", cfg.AudioBackend, "device", cfg.AudioDevice)
+		}
+	}
+
 	slog.Debug("audio backend initialized successfully",
 		"backend_type", fmt.Sprintf("%T", c.audioBackend),
-		"volume", volume)
+		"volume", volume,
+		"device", cfg.AudioDevice)
 
 	return nil
 }
@@ -430,6 +494,20 @@ func readHookInput(cmd *cobra.Command) ([]byte, error) {
 	return inputData, nil
 }
 
+// hookPayloadCWD returns the cwd field of a raw hook payload, or "" when
+// the payload is not JSON or has none. It is read ahead of full parsing
+// because the project overlay it selects must be in place before the
+// audio system is initialized.
+func hookPayloadCWD(inputData []byte) string {
+	var payload struct {
+		CWD string `json:"cwd"`
+	}
+	if err := json.Unmarshal(inputData, &payload); err != nil {
+		return ""
+	}
+	return payload.CWD
+}
+
 // processHookInput processes parsed hook JSON payload.
 func processHookInput(cmd *cobra.Command, cli *CLI, cfg *config.Config, inputData []byte) error {
 	// If no input and we're just testing flags/config, return success
@@ -446,6 +524,7 @@ func processHookInput(cmd *cobra.Command, cli *CLI, cfg *config.Config, inputDat
 		slog.Error("hook JSON parsing failed", "error", err)
 		return fmt.Errorf("error parsing hook JSON: %w", err)
 	}
+	hookEvent.Agent, _ = cmd.Flags().GetString("hook-agent")
 
 	slog.Info("hook event parsed",
 		"event_name", hookEvent.EventName,
@@ -453,7 +532,11 @@ func processHookInput(cmd *cobra.Command, cli *CLI, cfg *config.Config, inputDat
 		"tool_name", getStringPtr(hookEvent.ToolName))
 
 	// Process hook event.
-	cli.processHookEvent(hookEvent, cfg, cmd.OutOrStdout(), cmd.ErrOrStderr())
+	flags, cwd := hookFlagsFromCommand(cmd), hookPayloadCWD(inputData)
+	reload := func() (*config.Config, error) {
+		return loadHookConfig(cli.configManager, flags, cwd, os.Getenv, io.Discard)
+	}
+	cli.processHookEvent(hookEvent, cfg, reload, cmd.OutOrStdout(), cmd.ErrOrStderr())
 
 	return nil
 }
@@ -485,8 +568,22 @@ func runStdinModeE(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Default behavior: detach hook processing so the invoking hook returns immediately.
+	// Quiet hours and temporary mutes end the hook here, before a worker
+	// is spawned or the daemon is woken for a sound nobody will hear. The
+	// session's bookkeeping still runs, as processHookEvent would run it.
+	if muted, reason := cfg.ScheduledMute(time.Now()); muted {
+		slog.Info("hook skipped by mute schedule", "reason", reason)
+		cli.recordMutedHook(cmd, cfg, inputData)
+		return writeJSONHookSuccessResponse(cmd, inputData)
+	}
+
+	// Default behavior: detach hook processing so the invoking hook returns
+	// immediately — preferably by handing the payload to a running
+	// `claudio daemon`, otherwise by spawning a one-shot worker.
 	if shouldDetachHookProcessing(cmd, cfg, inputData) {
+		if forwardHookToDaemon(hookFlagsFromCommand(cmd), inputData) {
+			return writeJSONHookSuccessResponse(cmd, inputData)
+		}
 		if err := spawnDetachedHookWorker(cmd, inputData); err != nil {
 			cmd.PrintErrf("Error starting detached hook worker: %v\n", err)
 			slog.Error("detached hook worker start failed", "error", err)
@@ -495,6 +592,23 @@ func runStdinModeE(cmd *cobra.Command, args []string) error {
 		return writeJSONHookSuccessResponse(cmd, inputData)
 	}
 
+	// Now that the payload names the hook's cwd, resolve the config again
+	// with that project's overlay. Done after the detach decision so only
+	// the process that actually plays pays for the second load.
+	if cwd := hookPayloadCWD(inputData); cwd != "" {
+		cfg, err = loadHookConfig(cli.configManager, hookFlagsFromCommand(cmd), cwd, os.Getenv, cmd.ErrOrStderr())
+		if err != nil {
+			return err
+		}
+	}
+
+	// A detached worker has already released the hook, so it may stay
+	// around to remind about an unanswered permission request or to play
+	// the ambient loop.
+	if daemonChild, _ := cmd.Flags().GetBool("daemon-child"); daemonChild {
+		cli.lingerCtx = cmd.Context()
+	}
+
 	// Initialize tracking (before audio system initialization). Pass the
 	// already-loaded cfg so a user-supplied --config is honored
 	// (initializeTracking previously called LoadConfig itself, dropping
@@ -514,6 +628,53 @@ func runStdinModeE(cmd *cobra.Command, args []string) error {
 	return writeJSONHookSuccessResponse(cmd, inputData)
 }
 
+// recordMutedHook runs the bookkeeping processHookEvent does before any
+// sound for a hook that a mute ends early: turn timing, attention state,
+// and stopping the session's ambient loop.
+func (c *CLI) recordMutedHook(cmd *cobra.Command, cfg *config.Config, inputData []byte) {
+	if len(inputData) == 0 {
+		return
+	}
+	defaultEvent, _ := cmd.Flags().GetString("hook-event")
+	hookEvent, err := hooks.NewHookEventParser().ParseWithDefaultEvent(inputData, defaultEvent)
+	if err != nil {
+		slog.Debug("muted hook not parsed; bookkeeping skipped", "error", err)
+		return
+	}
+	hookEvent.Agent, _ = cmd.Flags().GetString("hook-agent")
+	if cwd := hookPayloadCWD(inputData); cwd != "" {
+		if projectCfg, err := loadHookConfig(c.configManager, hookFlagsFromCommand(cmd), cwd, os.Getenv, io.Discard); err == nil {
+			cfg = projectCfg
+		}
+	}
+
+	c.initializeTracking(cfg)
+	c.trackTurn(context.Background(), hookEvent, time.Now())
+	c.trackAttention(context.Background(), hookEvent, cfg, time.Now())
+	stopAmbient(hookEvent, cfg)
+}
+
+// configLoader resolves a hook's effective config again, so a process
+// that outlives the hook notices a mute that started meanwhile.
+type configLoader func() (*config.Config, error)
+
+// hookMuted reports whether cfg, or the config reload resolves now, mutes
+// hooks at now. A reload that fails keeps the answer from cfg alone.
+func hookMuted(cfg *config.Config, reload configLoader, now time.Time) (bool, string) {
+	if muted, reason := cfg.ScheduledMute(now); muted || reload == nil {
+		return muted, reason
+	}
+	current, err := reload()
+	if err != nil {
+		slog.Debug("config not reloaded for mute check", "error", err)
+		return false, ""
+	}
+	if !current.Enabled {
+		return true, "audio muted"
+	}
+	return current.ScheduledMute(now)
+}
+
 func writeJSONHookSuccessResponse(cmd *cobra.Command, inputData []byte) error {
 	if len(inputData) == 0 {
 		return nil
@@ -626,17 +787,66 @@ func (c *CLI) initializeSystems() {
 }
 
 // processHookEvent processes the parsed hook event
-func (c *CLI) processHookEvent(hookEvent *hooks.HookEvent, cfg *config.Config, stdout, stderr io.Writer) {
+func (c *CLI) processHookEvent(hookEvent *hooks.HookEvent, cfg *config.Config, reload configLoader, stdout, stderr io.Writer) {
 	slog.Debug("processing hook event", "event_name", hookEvent.EventName)
 
+	// Turn timing is bookkeeping, not sound: a prompt starts the clock
+	// even when its own hook is not in enabled_hooks.
+	turnDuration, turnFinished := c.trackTurn(context.Background(), hookEvent, time.Now())
+	attentionAt, needsAttention := c.trackAttention(context.Background(), hookEvent, cfg, time.Now())
+	stopAmbient(hookEvent, cfg)
+
+	if !cfg.IsHookEnabled(hookEvent.EventName) {
+		slog.Debug("hook not in enabled_hooks, skipping", "event_name", hookEvent.EventName, "agent", hookEvent.Agent)
+		return
+	}
+	// Checked again here for the daemon and for project overlays, which
+	// are applied after the early check in runStdinModeE.
+	if muted, reason := cfg.ScheduledMute(time.Now()); muted {
+		slog.Info("hook skipped by mute schedule", "reason", reason, "event_name", hookEvent.EventName)
+		return
+	}
+
 	// Extract hook context directly from event
+	hookEvent.CommandSelection = cfg.CommandSelection
 	eventCtx := hookEvent.GetContext()
+	if eventCtx.ToolName != "" && eventCtx.OriginalTool == "" {
+		eventCtx.FileGroup = cfg.FileGroupFor(hookEvent.ToolFilePath())
+	}
+	if turnFinished && applyTurnDuration(eventCtx, cfg.Turns, turnDuration) {
+		slog.Info("completion sound skipped for short turn",
+			"duration", turnDuration,
+			"quiet_under", cfg.Turns.QuietUnder())
+		return
+	}
 
 	slog.Debug("hook context parsed",
 		"category", eventCtx.Category.String(),
 		"operation", eventCtx.Operation,
 		"tool", eventCtx.ToolName,
-		"hint", eventCtx.SoundHint)
+		"hint", eventCtx.SoundHint,
+		"file_group", eventCtx.FileGroup)
+
+	// Rate limits key on the event's category and hint, so they run as soon
+	// as the context is known and before any chain is built or resolved: a
+	// suppressed event costs one small state-file update and nothing else.
+	if len(cfg.RateLimits) > 0 {
+		limiter := ratelimit.NewLimiter(
+			filepath.Join(config.NewXDGDirs().GetCachePath("ratelimit"), "state.json"),
+			cfg.RateLimits,
+		)
+		allowed, err := limiter.Allow(hookEvent.SessionID, eventCtx.Category.String(), eventCtx.SoundHint)
+		if err != nil {
+			slog.Warn("rate limit check failed (continuing)", "error", err)
+		}
+		if !allowed {
+			slog.Info("sound suppressed by rate limit",
+				"category", eventCtx.Category.String(),
+				"hint", eventCtx.SoundHint,
+				"session_id", hookEvent.SessionID)
+			return
+		}
+	}
 
 	// Compose the resolution pipeline. The mapper drives chain construction
 	// and resolution; the LookupBuffer (when tracking is on) wires
@@ -660,6 +870,13 @@ func (c *CLI) processHookEvent(hookEvent *hooks.HookEvent, cfg *config.Config, s
 		slog.Debug("tracking disabled; mapper resolves without an observer")
 	}
 
+	// User routing rules run ahead of the built-in chain: a matching rule's
+	// sounds are tried first and, unless the rule is exclusive, the
+	// built-in chain still follows as the fallback.
+	if rule := c.matchRoutingRule(hookEvent, eventCtx, cfg); rule != nil {
+		mapperOpts = append(mapperOpts, sounds.WithRoute(rule.SoundKeys(), rule.Exclusive))
+	}
+
 	soundMapper := sounds.NewSoundMapperWithResolver(c.soundpackResolver, mapperOpts...)
 
 	result := soundMapper.MapSound(ctx, eventCtx)
@@ -692,20 +909,68 @@ func (c *CLI) processHookEvent(hookEvent *hooks.HookEvent, cfg *config.Config, s
 		if cfg.Volume != nil {
 			playVolume = *cfg.Volume
 		}
-		err := c.playSoundWithBackend(result.SelectedPath, playVolume)
+		playCtx := ctx
+		ticket, err := newPlaybackCoordinator(cfg).Acquire(ctx, hookEvent.SessionID)
+		switch {
+		case errors.Is(err, playback.ErrDropped):
+			slog.Info("sound skipped by overlap policy",
+				"policy", cfg.Playback.EffectiveOverlapPolicy(),
+				"sound_path", result.SelectedPath)
+			return
+		case err != nil:
+			// Coordination is best-effort: a broken cache dir must not
+			// silence the hook, so play uncoordinated (mix) instead.
+			slog.Warn("overlap coordination failed; playing without it", "error", err)
+		default:
+			defer ticket.Release()
+			playCtx = ticket.Context()
+		}
+
+		speech := newSpeechRequest(cfg, hookEvent, eventCtx)
+		variation := c.sessionVariation(hookEvent, cfg)
+		err = c.playSoundWithBackend(playCtx, result.SelectedPath, playVolume, speech, variation)
 		if err != nil {
 			fmt.Fprintf(stderr, "Error playing sound: %v\n", err)
 			slog.Error("sound playback failed", "sound_path", result.SelectedPath, "error", err)
 			return
 		}
 		slog.Debug("sound played successfully", "sound_path", result.SelectedPath)
+
+		if needsAttention && c.lingerCtx != nil {
+			// Free the session's playback slot while waiting on the user.
+			if ticket != nil {
+				ticket.Release()
+			}
+			c.remindUntilAnswered(c.lingerCtx, cfg.Attention.EffectiveAfter(), hookEvent, cfg, reload,
+				result.SelectedPath, playVolume, speech, variation, attentionAt)
+		}
+		if startsAmbient(hookEvent.EventName) && cfg.Ambient != nil && cfg.Ambient.Enabled && c.lingerCtx != nil {
+			// The prompt sound is done; the loop has its own lock files.
+			if ticket != nil {
+				ticket.Release()
+			}
+			c.playAmbient(c.lingerCtx, hookEvent, cfg, reload)
+		}
 	} else {
 		slog.Debug("audio disabled, skipping sound playback")
 	}
 }
 
-// playSoundWithBackend plays the specified sound file using the configured audio backend
-func (c *CLI) playSoundWithBackend(soundPath string, volume float64) error {
+// newPlaybackCoordinator builds the cross-process overlap arbiter for cfg.
+// Its lock files live under the XDG cache dir next to the tracking DB.
+func newPlaybackCoordinator(cfg *config.Config) *playback.Coordinator {
+	return playback.NewCoordinator(
+		config.NewXDGDirs().GetCachePath("playback"),
+		cfg.Playback.EffectiveOverlapPolicy(),
+		cfg.Playback.EffectiveMaxQueueDepth(),
+	)
+}
+
+// playSoundWithBackend plays the specified sound file using the configured audio backend.
+// A path resolving to a say: template is spoken instead, filled from speech.
+// Sound files play with variation's pitch and pan, levelled by the gain the
+// soundpack stores for them.
+func (c *CLI) playSoundWithBackend(ctx context.Context, soundPath string, volume float64, speech *speechRequest, variation audio.Variation) error {
 	slog.Debug("loading and playing sound with backend", "path", soundPath, "volume", volume)
 
 	// Use unified soundpack resolver to resolve sound file path
@@ -717,13 +982,20 @@ func (c *CLI) playSoundWithBackend(soundPath string, volume float64) error {
 		}
 		return fmt.Errorf("failed to resolve sound path: %w", err)
 	}
+	if soundpack.IsSpeechTemplate(fullPath) {
+		return c.playSpeech(ctx, fullPath, volume, variation.EffectiveGain(), speech)
+	}
 
 	// Create audio source from file path; the backend owns decoding.
-	source := audio.NewFileSource(fullPath)
+	source := audio.WithVariation(audio.NewFileSource(fullPath), c.withLoudnessGain(fullPath, variation))
 
-	// Play using audio backend
-	ctx := context.Background()
+	// Play using audio backend. A cancelled ctx (interrupt-previous) ends
+	// playback early; that is the policy working, not a failure.
 	err = c.audioBackend.Play(ctx, source)
+	if err != nil && ctx.Err() != nil {
+		slog.Debug("sound playback interrupted", "path", fullPath)
+		return nil
+	}
 	if err != nil {
 		slog.Error("backend playback failed", "path", fullPath, "backend_type", fmt.Sprintf("%T", c.audioBackend), "error", err)
 		return fmt.Errorf("failed to play sound with backend: %w", err)
@@ -733,6 +1005,20 @@ func (c *CLI) playSoundWithBackend(soundPath string, volume float64) error {
 	return nil
 }
 
+// withLoudnessGain returns variation with the gain the active soundpack
+// stores for path, if it stores one, applied on top of its own.
+func (c *CLI) withLoudnessGain(path string, variation audio.Variation) audio.Variation {
+	gm, ok := c.soundpackResolver.(soundpack.GainMapper)
+	if !ok {
+		return variation
+	}
+	if gainDB, ok := gm.FileGain(path); ok {
+		slog.Debug("applying soundpack loudness gain", "path", path, "gain_db", gainDB)
+		variation.Gain = variation.EffectiveGain() * loudness.DBToLinear(gainDB)
+	}
+	return variation
+}
+
 // setupLogging configures slog with dual-level logging:
 // - stderr: ERROR level only (for genuine user-facing errors)
 // - file: configured level (for full debugging history)
@@ -982,17 +1268,19 @@ func ensureEmbeddedDefaultSoundsExtracted(data []byte) string {
 
 	destDir := config.NewXDGDirs().GetCachePath(filepath.Join("embedded-soundpacks", spFile.Name))
 	var wrote bool
-	for _, value := range spFile.Mappings {
-		soundBytes, err := config.GetEmbeddedSoundData(value)
-		if err != nil {
-			continue // absolute path or otherwise not an embedded sound
-		}
-		if err := writeCachedSoundIfMissing(filepath.Join(destDir, value), soundBytes); err != nil {
-			slog.Warn("failed to materialize embedded default sound",
-				"name", value, "dir", destDir, "error", err)
-			continue
+	for _, mapping := range spFile.Mappings {
+		for _, value := range mapping.Files {
+			soundBytes, err := config.GetEmbeddedSoundData(value)
+			if err != nil {
+				continue // absolute path or otherwise not an embedded sound
+			}
+			if err := writeCachedSoundIfMissing(filepath.Join(destDir, value), soundBytes); err != nil {
+				slog.Warn("failed to materialize embedded default sound",
+					"name", value, "dir", destDir, "error", err)
+				continue
+			}
+			wrote = true
 		}
-		wrote = true
 	}
 
 	if !wrote {
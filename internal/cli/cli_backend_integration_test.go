@@ -118,7 +118,7 @@ func TestCLIPlaySoundWithBackend(t *testing.T) {
 	if cfg.Volume != nil {
 		volume = *cfg.Volume
 	}
-	err = cli.playSoundWithBackend("/test/nonexistent.wav", volume)
+	err = cli.playSoundWithBackend(context.Background(), "/test/nonexistent.wav", volume, nil, audio.Variation{})
 
 	// We should get a "file not found" type error, but no panic
 	// The important thing is that it doesn't crash and uses the backend system
@@ -25,6 +25,10 @@ func TestNewCLI_RegistersAllExpectedSubcommands(t *testing.T) {
 		"mute",
 		"unmute",
 		"status",
+		"trust",
+		"untrust",
+		"cache",
+		"devices",
 	}
 
 	cli := NewCLI()
@@ -0,0 +1,461 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"claudio.click/internal/config"
+	"claudio.click/internal/hooks"
+	"claudio.click/internal/safeio"
+	"github.com/adrg/xdg"
+	"github.com/spf13/cobra"
+)
+
+// daemonProtocolVersion is bumped whenever daemonRequest/daemonResponse
+// change shape. A daemon refuses requests with a different version so an
+// upgraded hook binary talking to a stale daemon falls back to the
+// detached worker instead of being half-understood.
+const daemonProtocolVersion = 2
+
+// daemonDialTimeout bounds how long a hook waits to discover whether a
+// daemon is listening. A missing socket fails immediately; this only
+// matters for a wedged daemon, and the hook must not stall its agent.
+const daemonDialTimeout = 200 * time.Millisecond
+
+// daemonAckTimeout bounds the round trip after a successful dial. The
+// daemon acknowledges before it resolves or plays anything, so a healthy
+// daemon answers in well under a millisecond.
+const daemonAckTimeout = 500 * time.Millisecond
+
+// daemonRequest is one forwarded hook invocation. Payload is the raw hook
+// JSON exactly as read from stdin ([]byte, so malformed input survives the
+// trip and the daemon reports the parse error the same way a worker would).
+// Env carries the hook's CLAUDIO_* variables, which a detached worker would
+// have inherited.
+type daemonRequest struct {
+	Version int               `json:"version"`
+	Flags   hookFlags         `json:"flags"`
+	Env     map[string]string `json:"env,omitempty"`
+	Payload []byte            `json:"payload"`
+}
+
+// getenv looks key up in the hook's forwarded environment. The daemon's
+// own environment is never consulted, so every hook is configured exactly
+// as its detached worker would have been.
+func (r daemonRequest) getenv(key string) string {
+	return r.Env[key]
+}
+
+// claudioEnvironment returns the CLAUDIO_* variables of this process.
+func claudioEnvironment() map[string]string {
+	env := make(map[string]string)
+	for _, kv := range os.Environ() {
+		key, value, ok := strings.Cut(kv, "=")
+		if ok && strings.HasPrefix(key, "CLAUDIO_") {
+			env[key] = value
+		}
+	}
+	return env
+}
+
+// daemonResponse acknowledges receipt of a daemonRequest. OK means the
+// daemon has taken ownership of the hook; it says nothing about whether a
+// sound was eventually found or played.
+type daemonResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+func newDaemonCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "daemon",
+		Short: "Run a persistent playback daemon for hook events",
+		Long: "Run a long-lived process that owns the audio backend, soundpack resolver, and tracking database.\n\n" +
+			"While the daemon is listening, hook invocations forward their payload over a per-user Unix socket " +
+			"instead of spawning a detached worker per event. When no daemon is running, hooks fall back to the " +
+			"detached worker automatically.",
+		Args: cobra.NoArgs,
+		RunE: runDaemonE,
+	}
+}
+
+// daemonSocketPath returns the per-user socket the daemon listens on.
+// CLAUDIO_DAEMON_SOCKET overrides it (tests, and users who want several
+// isolated daemons); otherwise it lives under the XDG runtime dir, which
+// adrg/xdg already resolves to a per-user location on every platform.
+func daemonSocketPath() string {
+	if p := os.Getenv("CLAUDIO_DAEMON_SOCKET"); p != "" {
+		return p
+	}
+	return filepath.Join(xdg.RuntimeDir, "claudio", "daemon.sock")
+}
+
+func runDaemonE(cmd *cobra.Command, args []string) error {
+	cli := cliFromContext(cmd.Context())
+	if cli == nil {
+		slog.Error("CLI instance not found in context")
+		return fmt.Errorf("CLI instance not found in context")
+	}
+
+	cfg, err := loadAndValidateConfig(cmd, cli)
+	if err != nil {
+		return err
+	}
+	setupLogging(cfg, cmd.ErrOrStderr())
+
+	socketPath := daemonSocketPath()
+	listener, err := listenDaemonSocket(socketPath)
+	if err != nil {
+		cmd.PrintErrf("Error: %v\n", err)
+		slog.Error("daemon listen failed", "socket", socketPath, "error", err)
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	d := newHookDaemon(cli.configManager)
+	cmd.Printf("claudio daemon listening on %s\n", socketPath)
+	slog.Info("daemon started", "socket", socketPath, "pid", os.Getpid())
+
+	err = d.serve(ctx, listener)
+	d.shutdown()
+	_ = os.Remove(socketPath)
+	slog.Info("daemon stopped", "socket", socketPath)
+	return err
+}
+
+// listenDaemonSocket binds socketPath, clearing a stale socket file left
+// behind by a daemon that died without cleanup. A socket that still
+// accepts connections belongs to a live daemon and is left alone.
+func listenDaemonSocket(socketPath string) (net.Listener, error) {
+	if err := ensurePrivateSocketDir(filepath.Dir(socketPath)); err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(socketPath); err == nil {
+		if conn, dialErr := net.DialTimeout("unix", socketPath, daemonDialTimeout); dialErr == nil {
+			conn.Close()
+			return nil, fmt.Errorf("a claudio daemon is already listening on %s", socketPath)
+		}
+		slog.Debug("removing stale daemon socket", "socket", socketPath)
+		if err := os.Remove(socketPath); err != nil {
+			return nil, fmt.Errorf("failed to remove stale daemon socket: %w", err)
+		}
+	}
+
+	// The socket accepts arbitrary hook payloads; it is created private to
+	// the owning user rather than chmod'ed after the fact.
+	listener, err := listenPrivateUnix(socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on daemon socket: %w", err)
+	}
+	return listener, nil
+}
+
+// daemonGeneration is one immutable set of audio/soundpack/tracking state
+// built from the parts of an effective config that shape it (see
+// generationKey). Requests run against a generation without holding the
+// daemon lock, so playback overlaps exactly as it did with one detached
+// worker per hook. The daemon keeps the few most recently used
+// generations, so sessions whose project or agent configs pick different
+// soundpacks or volumes do not rebuild the backend on every hook, and
+// retires the least recently used one once its in-flight requests finish.
+type daemonGeneration struct {
+	key      string
+	cli      *CLI
+	inflight sync.WaitGroup
+}
+
+// close releases the generation's backend and tracking database after
+// every request that acquired it has finished.
+func (g *daemonGeneration) close() {
+	g.inflight.Wait()
+	if g.cli.audioBackend != nil {
+		if err := g.cli.audioBackend.Close(); err != nil {
+			slog.Error("error closing daemon audio backend", "error", err)
+		}
+	}
+	if g.cli.trackingDB != nil {
+		if err := g.cli.trackingDB.Close(); err != nil {
+			slog.Error("error closing daemon tracking database", "error", err)
+		}
+	}
+}
+
+// hookDaemon serves forwarded hook requests.
+type hookDaemon struct {
+	configManager *config.ConfigManager
+	ctx           context.Context // cancelled at shutdown; ends reminders and ambient loops
+
+	mu          sync.Mutex
+	generations []*daemonGeneration // most recently used first
+
+	handlers sync.WaitGroup
+}
+
+func newHookDaemon(cm *config.ConfigManager) *hookDaemon {
+	return &hookDaemon{configManager: cm}
+}
+
+// serve accepts connections until ctx is cancelled or the listener fails.
+func (d *hookDaemon) serve(ctx context.Context, listener net.Listener) error {
+	d.ctx = ctx
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("daemon accept failed: %w", err)
+		}
+		d.handlers.Add(1)
+		go func() {
+			defer d.handlers.Done()
+			d.handleConn(conn)
+		}()
+	}
+}
+
+// shutdown waits for in-flight requests and releases every generation.
+func (d *hookDaemon) shutdown() {
+	d.handlers.Wait()
+	d.mu.Lock()
+	gens := d.generations
+	d.generations = nil
+	d.mu.Unlock()
+	for _, gen := range gens {
+		gen.close()
+	}
+}
+
+// handleConn reads one request, acknowledges it, closes the connection so
+// the hook can exit, and only then does the (possibly slow) resolution and
+// playback work.
+func (d *hookDaemon) handleConn(conn net.Conn) {
+	_ = conn.SetDeadline(time.Now().Add(daemonAckTimeout))
+
+	var req daemonRequest
+	// Base64 inflates the payload by 4/3; allow headroom over the hook cap.
+	dec := json.NewDecoder(io.LimitReader(conn, 2*int64(safeio.MaxHookPayloadBytes)))
+	if err := dec.Decode(&req); err != nil {
+		slog.Warn("daemon failed to decode request", "error", err)
+		writeDaemonResponse(conn, daemonResponse{Error: "malformed request"})
+		conn.Close()
+		return
+	}
+	if req.Version != daemonProtocolVersion {
+		slog.Warn("daemon rejected request with unknown protocol version", "version", req.Version)
+		writeDaemonResponse(conn, daemonResponse{Error: fmt.Sprintf("unsupported protocol version %d", req.Version)})
+		conn.Close()
+		return
+	}
+
+	writeDaemonResponse(conn, daemonResponse{OK: true})
+	conn.Close()
+
+	d.process(req)
+}
+
+// process runs one forwarded hook against the generation matching its
+// effective config.
+func (d *hookDaemon) process(req daemonRequest) {
+	if len(req.Payload) == 0 {
+		slog.Info("daemon received empty hook payload")
+		return
+	}
+
+	cfg, err := loadHookConfig(d.configManager, req.Flags, hookPayloadCWD(req.Payload), req.getenv, io.Discard)
+	if err != nil {
+		slog.Error("daemon could not load config for request", "error", err)
+		return
+	}
+
+	gen, err := d.acquire(cfg)
+	if err != nil {
+		slog.Error("daemon could not prepare audio system", "error", err)
+		return
+	}
+	defer gen.inflight.Done()
+
+	parser := hooks.NewHookEventParser()
+	hookEvent, err := parser.ParseWithDefaultEvent(req.Payload, req.Flags.Event)
+	if err != nil {
+		slog.Error("hook JSON parsing failed", "error", err)
+		return
+	}
+	hookEvent.Agent = req.Flags.Agent
+
+	slog.Info("hook event parsed",
+		"event_name", hookEvent.EventName,
+		"session_id", hookEvent.SessionID,
+		"tool_name", getStringPtr(hookEvent.ToolName),
+		"via", "daemon")
+
+	reload := func() (*config.Config, error) {
+		return loadHookConfig(d.configManager, req.Flags, hookPayloadCWD(req.Payload), req.getenv, io.Discard)
+	}
+	gen.cli.processHookEvent(hookEvent, cfg, reload, io.Discard, io.Discard)
+}
+
+// maxDaemonGenerations bounds how many generations, each with its own
+// audio backend and tracking database handle, the daemon keeps open.
+const maxDaemonGenerations = 4
+
+// generationKey fingerprints the config fields a generation is built
+// from: the audio backend, device and volume, the soundpacks, and the
+// tracking database. Everything else is read from the per-request config,
+// so configs differing only in, say, rate limits or routing share one.
+func generationKey(cfg *config.Config) (string, error) {
+	volume := 0.5
+	if cfg.Volume != nil {
+		volume = *cfg.Volume
+	}
+	tracking := cfg.SoundTracking != nil && cfg.SoundTracking.Enabled
+	var trackingDB string
+	if tracking {
+		trackingDB = cfg.SoundTracking.DatabasePath
+	}
+	key, err := json.Marshal(struct {
+		Enabled          bool
+		AudioBackend     string
+		AudioDevice      string
+		Volume           float64
+		DefaultSoundpack string
+		SoundpackPaths   []string
+		SoundpackStack   []string
+		Tracking         bool
+		TrackingDB       string
+	}{cfg.Enabled, cfg.AudioBackend, cfg.AudioDevice, volume,
+		cfg.DefaultSoundpack, cfg.SoundpackPaths, cfg.SoundpackStack, tracking, trackingDB})
+	if err != nil {
+		return "", err
+	}
+	return string(key), nil
+}
+
+// acquire returns the generation for cfg with its inflight counter already
+// incremented, building a fresh one when no kept generation matches and
+// retiring the least recently used one beyond maxDaemonGenerations.
+func (d *hookDaemon) acquire(cfg *config.Config) (*daemonGeneration, error) {
+	key, err := generationKey(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fingerprint config: %w", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for i, gen := range d.generations {
+		if gen.key == key {
+			copy(d.generations[1:i+1], d.generations[:i])
+			d.generations[0] = gen
+			gen.inflight.Add(1)
+			return gen, nil
+		}
+	}
+
+	genCLI := &CLI{configManager: d.configManager, lingerCtx: d.ctx}
+	genCLI.initializeTracking(cfg)
+	errCmd := &cobra.Command{}
+	errCmd.SetErr(io.Discard)
+	if err := initializeAudioSystem(errCmd, genCLI, cfg); err != nil {
+		if genCLI.trackingDB != nil {
+			_ = genCLI.trackingDB.Close()
+		}
+		return nil, err
+	}
+
+	gen := &daemonGeneration{key: key, cli: genCLI}
+	gen.inflight.Add(1)
+	d.generations = append([]*daemonGeneration{gen}, d.generations...)
+	if len(d.generations) > maxDaemonGenerations {
+		old := d.generations[maxDaemonGenerations]
+		d.generations = d.generations[:maxDaemonGenerations]
+		slog.Info("daemon retiring least recently used audio system", "kept", maxDaemonGenerations)
+		go old.close()
+	}
+	return gen, nil
+}
+
+func writeDaemonResponse(w io.Writer, resp daemonResponse) {
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		slog.Debug("daemon failed to write response", "error", err)
+	}
+}
+
+// forwardHookToDaemon hands the hook payload to a running daemon. It
+// returns false (never an error) when no daemon accepted the request, so
+// the caller can fall back to spawnDetachedHookWorker; every failure mode
+// here is "daemon unavailable" from the hook's point of view.
+func forwardHookToDaemon(flags hookFlags, inputData []byte) bool {
+	if os.Getenv("CLAUDIO_DAEMON_DISABLE") == "1" {
+		return false
+	}
+
+	// The daemon does not share the hook's working directory; pin a
+	// relative --config to what it meant here.
+	if flags.Config != "" && !filepath.IsAbs(flags.Config) {
+		if abs, err := filepath.Abs(flags.Config); err == nil {
+			flags.Config = abs
+		}
+	}
+
+	// Hook payloads carry prompts and tool output; only a daemon run by
+	// this user may receive them.
+	socketPath := daemonSocketPath()
+	if err := checkDaemonSocketOwner(socketPath); err != nil {
+		if !os.IsNotExist(err) {
+			slog.Warn("refusing daemon socket; using detached worker", "socket", socketPath, "error", err)
+		}
+		return false
+	}
+	conn, err := net.DialTimeout("unix", socketPath, daemonDialTimeout)
+	if err != nil {
+		slog.Debug("no claudio daemon listening; using detached worker", "socket", socketPath, "error", err)
+		return false
+	}
+	defer conn.Close()
+	if err := checkDaemonPeer(conn); err != nil {
+		slog.Warn("refusing daemon connection; using detached worker", "socket", socketPath, "error", err)
+		return false
+	}
+	_ = conn.SetDeadline(time.Now().Add(daemonAckTimeout))
+
+	req := daemonRequest{Version: daemonProtocolVersion, Flags: flags, Env: claudioEnvironment(), Payload: inputData}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		slog.Warn("failed to send hook to daemon; using detached worker", "socket", socketPath, "error", err)
+		return false
+	}
+
+	var resp daemonResponse
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&resp); err != nil {
+		slog.Warn("no acknowledgement from daemon; using detached worker", "socket", socketPath, "error", err)
+		return false
+	}
+	if !resp.OK {
+		slog.Warn("daemon refused hook; using detached worker", "socket", socketPath, "error", resp.Error)
+		return false
+	}
+
+	slog.Debug("hook forwarded to daemon", "socket", socketPath)
+	return true
+}
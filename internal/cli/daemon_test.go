@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"claudio.click/internal/audio"
+	"claudio.click/internal/cli/testenv"
+	"claudio.click/internal/config"
+)
+
+// shortSocketPath returns a socket path short enough for the sun_path
+// limit (~104 bytes on macOS); t.TempDir() paths routinely exceed it.
+func shortSocketPath(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "cld")
+	if err != nil {
+		t.Fatalf("MkdirTemp: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "d.sock")
+}
+
+// startTestDaemon runs a hookDaemon on socketPath until the test ends.
+func startTestDaemon(t *testing.T, socketPath string) {
+	t.Helper()
+	listener, err := listenDaemonSocket(socketPath)
+	if err != nil {
+		t.Fatalf("listenDaemonSocket: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	d := newHookDaemon(config.NewConfigManager())
+	done := make(chan error, 1)
+	go func() { done <- d.serve(ctx, listener) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("serve returned error: %v", err)
+		}
+		d.shutdown()
+	})
+}
+
+func TestDaemonSocketPath_EnvOverride(t *testing.T) {
+	t.Setenv("CLAUDIO_DAEMON_SOCKET", "/tmp/custom-claudio.sock")
+	if got := daemonSocketPath(); got != "/tmp/custom-claudio.sock" {
+		t.Errorf("daemonSocketPath() = %q, want env override", got)
+	}
+}
+
+// TestForwardHookToDaemon_NoDaemonFallsBack is the fallback contract: with
+// nothing listening, forwarding reports false so the caller spawns the
+// detached worker instead.
+func TestForwardHookToDaemon_NoDaemonFallsBack(t *testing.T) {
+	t.Setenv("CLAUDIO_DAEMON_SOCKET", shortSocketPath(t))
+	if forwardHookToDaemon(hookFlags{}, []byte(`{}`)) {
+		t.Fatal("expected forwardHookToDaemon to report no daemon")
+	}
+}
+
+func TestForwardHookToDaemon_DisableEnv(t *testing.T) {
+	testenv.IsolateXDG(t)
+	socketPath := shortSocketPath(t)
+	t.Setenv("CLAUDIO_DAEMON_SOCKET", socketPath)
+	startTestDaemon(t, socketPath)
+
+	t.Setenv("CLAUDIO_DAEMON_DISABLE", "1")
+	if forwardHookToDaemon(hookFlags{}, []byte(`{}`)) {
+		t.Fatal("CLAUDIO_DAEMON_DISABLE=1 must bypass a listening daemon")
+	}
+}
+
+// TestDaemon_PlaysForwardedHook drives a hook through the socket and
+// asserts the daemon-owned backend received a Play.
+func TestDaemon_PlaysForwardedHook(t *testing.T) {
+	testenv.IsolateXDG(t)
+	audio.ResetLastFakeBackend()
+	socketPath := shortSocketPath(t)
+	t.Setenv("CLAUDIO_DAEMON_SOCKET", socketPath)
+	startTestDaemon(t, socketPath)
+
+	hookJSON := `{
+		"session_id": "daemon-test",
+		"cwd": "/test",
+		"hook_event_name": "PostToolUse",
+		"tool_name": "Bash",
+		"tool_response": {"stdout": "ok", "stderr": "", "interrupted": false}
+	}`
+	if !forwardHookToDaemon(hookFlags{}, []byte(hookJSON)) {
+		t.Fatal("expected daemon to acknowledge forwarded hook")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if fake := audio.LastFakeBackend(); fake != nil && len(fake.Plays()) > 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("daemon never played the forwarded hook")
+}
+
+func TestDaemon_RejectsUnknownProtocolVersion(t *testing.T) {
+	testenv.IsolateXDG(t)
+	socketPath := shortSocketPath(t)
+	startTestDaemon(t, socketPath)
+
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	if err := json.NewEncoder(conn).Encode(daemonRequest{Version: daemonProtocolVersion + 1}); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	var resp daemonResponse
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.OK || !strings.Contains(resp.Error, "protocol version") {
+		t.Errorf("expected protocol version rejection, got %+v", resp)
+	}
+}
+
+func TestListenDaemonSocket_RefusesLiveDaemon(t *testing.T) {
+	testenv.IsolateXDG(t)
+	socketPath := shortSocketPath(t)
+	startTestDaemon(t, socketPath)
+
+	if _, err := listenDaemonSocket(socketPath); err == nil || !strings.Contains(err.Error(), "already listening") {
+		t.Fatalf("expected already-listening error, got %v", err)
+	}
+}
+
+func TestListenDaemonSocket_ReplacesStaleSocket(t *testing.T) {
+	socketPath := shortSocketPath(t)
+	if err := os.WriteFile(socketPath, nil, 0o600); err != nil {
+		t.Fatalf("write stale socket: %v", err)
+	}
+	listener, err := listenDaemonSocket(socketPath)
+	if err != nil {
+		t.Fatalf("expected stale socket to be replaced, got %v", err)
+	}
+	listener.Close()
+}
+
+func TestHookDaemon_AcquireKeepsRecentGenerations(t *testing.T) {
+	testenv.IsolateXDG(t)
+	d := newHookDaemon(config.NewConfigManager())
+	d.ctx = context.Background()
+	defer d.shutdown()
+
+	withVolume := func(v float64) *config.Config {
+		cfg := config.NewConfigManager().GetDefaultConfig()
+		cfg.Volume = &v
+		return cfg
+	}
+	acquire := func(cfg *config.Config) *daemonGeneration {
+		t.Helper()
+		gen, err := d.acquire(cfg)
+		if err != nil {
+			t.Fatalf("acquire: %v", err)
+		}
+		gen.inflight.Done()
+		return gen
+	}
+
+	quiet := acquire(withVolume(0.2))
+	// Fields read per request, such as rate limits, share the generation.
+	limited := withVolume(0.2)
+	limited.RateLimits = []config.RateLimitRule{{Hint: "bash-*", MinIntervalMs: 500}}
+	if got := acquire(limited); got != quiet {
+		t.Error("a config differing only in rate limits should reuse the generation")
+	}
+
+	// Alternating between two volumes keeps both generations alive.
+	loud := acquire(withVolume(0.9))
+	if loud == quiet {
+		t.Fatal("a different volume should build its own generation")
+	}
+	if got := acquire(withVolume(0.2)); got != quiet {
+		t.Error("switching back should reuse the kept generation")
+	}
+
+	// Past the limit, the least recently used generation is retired.
+	for i := 0; i < maxDaemonGenerations-1; i++ {
+		acquire(withVolume(0.3 + 0.1*float64(i)))
+	}
+	if len(d.generations) != maxDaemonGenerations {
+		t.Fatalf("kept %d generations, want %d", len(d.generations), maxDaemonGenerations)
+	}
+	if got := acquire(withVolume(0.9)); got == loud {
+		t.Error("the least recently used generation should have been retired")
+	}
+}
+
+func TestDaemonRequest_AppliesHookEnvironment(t *testing.T) {
+	testenv.IsolateXDG(t)
+	t.Setenv("CLAUDIO_AUDIO_DEVICE", "hook-headphones")
+	t.Setenv("CLAUDIO_OVERLAP_POLICY", "queue")
+
+	req := daemonRequest{Version: daemonProtocolVersion, Env: claudioEnvironment()}
+	if req.Env["CLAUDIO_AUDIO_DEVICE"] != "hook-headphones" {
+		t.Fatalf("forwarded env = %v, want the hook's CLAUDIO_* variables", req.Env)
+	}
+
+	// The daemon's own environment differs; the hook's wins.
+	t.Setenv("CLAUDIO_AUDIO_DEVICE", "daemon-speakers")
+	t.Setenv("CLAUDIO_OVERLAP_POLICY", "")
+	cfg, err := loadHookConfig(config.NewConfigManager(), req.Flags, "", req.getenv, io.Discard)
+	if err != nil {
+		t.Fatalf("loadHookConfig: %v", err)
+	}
+	if cfg.AudioDevice != "hook-headphones" {
+		t.Errorf("audio device = %q, want the hook's override", cfg.AudioDevice)
+	}
+	if cfg.Playback.OverlapPolicy != "queue" {
+		t.Errorf("overlap policy = %q, want the hook's override", cfg.Playback.OverlapPolicy)
+	}
+}
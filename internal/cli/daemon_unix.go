@@ -0,0 +1,58 @@
+//go:build !windows
+
+package cli
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+// ensurePrivateSocketDir creates dir if it is missing and refuses it
+// unless it is a real directory owned by the current user with mode 0700.
+// Without XDG_RUNTIME_DIR (ssh, cron, containers) the socket falls back to
+// a shared temp dir, where another user may have created it first.
+func ensurePrivateSocketDir(dir string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create daemon socket directory: %w", err)
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return fmt.Errorf("cannot inspect daemon socket directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("daemon socket directory %s is not a directory", dir)
+	}
+	if st, ok := info.Sys().(*syscall.Stat_t); ok && int(st.Uid) != os.Getuid() {
+		return fmt.Errorf("daemon socket directory %s is owned by uid %d, not the current user", dir, st.Uid)
+	}
+	if perm := info.Mode().Perm(); perm != 0o700 {
+		return fmt.Errorf("daemon socket directory %s has mode %04o; want 0700", dir, perm)
+	}
+	return nil
+}
+
+// listenPrivateUnix listens on socketPath with a 0077 umask, so the socket
+// is never connectable by other users, not even before a chmod.
+func listenPrivateUnix(socketPath string) (net.Listener, error) {
+	old := syscall.Umask(0o077)
+	defer syscall.Umask(old)
+	return net.Listen("unix", socketPath)
+}
+
+// checkDaemonSocketOwner refuses a socket the current user does not own,
+// so a hook never hands its payload to another user's listener.
+func checkDaemonSocketOwner(socketPath string) error {
+	info, err := os.Lstat(socketPath)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s is not a socket", socketPath)
+	}
+	if st, ok := info.Sys().(*syscall.Stat_t); ok && int(st.Uid) != os.Getuid() {
+		return fmt.Errorf("socket %s is owned by uid %d, not the current user", socketPath, st.Uid)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+//go:build !windows
+
+package cli
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestListenDaemonSocket_RefusesSharedDirectory(t *testing.T) {
+	dir := filepath.Dir(shortSocketPath(t))
+	if err := os.Chmod(dir, 0o755); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+	if _, err := listenDaemonSocket(filepath.Join(dir, "d.sock")); err == nil || !strings.Contains(err.Error(), "want 0700") {
+		t.Fatalf("expected a group/world-accessible directory to be refused, got %v", err)
+	}
+}
+
+func TestListenDaemonSocket_RefusesSymlinkedDirectory(t *testing.T) {
+	real := filepath.Dir(shortSocketPath(t))
+	link := real + "-link"
+	if err := os.Symlink(real, link); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	t.Cleanup(func() { os.Remove(link) })
+	if _, err := listenDaemonSocket(filepath.Join(link, "d.sock")); err == nil || !strings.Contains(err.Error(), "not a directory") {
+		t.Fatalf("expected a symlinked directory to be refused, got %v", err)
+	}
+}
+
+func TestListenDaemonSocket_CreatesPrivateSocket(t *testing.T) {
+	socketPath := shortSocketPath(t)
+	listener, err := listenDaemonSocket(socketPath)
+	if err != nil {
+		t.Fatalf("listenDaemonSocket: %v", err)
+	}
+	defer listener.Close()
+	info, err := os.Lstat(socketPath)
+	if err != nil {
+		t.Fatalf("Lstat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm&0o077 != 0 {
+		t.Errorf("socket mode = %04o, want no group or other access", perm)
+	}
+	if err := checkDaemonSocketOwner(socketPath); err != nil {
+		t.Errorf("own socket refused: %v", err)
+	}
+
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+	if err := checkDaemonPeer(conn); err != nil {
+		t.Errorf("own daemon refused as peer: %v", err)
+	}
+}
+
+func TestForwardHookToDaemon_RefusesNonSocket(t *testing.T) {
+	socketPath := shortSocketPath(t)
+	if err := os.WriteFile(socketPath, nil, 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	t.Setenv("CLAUDIO_DAEMON_SOCKET", socketPath)
+	if err := checkDaemonSocketOwner(socketPath); err == nil {
+		t.Error("a regular file should not pass as the daemon socket")
+	}
+	if forwardHookToDaemon(hookFlags{}, []byte(`{}`)) {
+		t.Error("forwarding to a non-socket should fall back")
+	}
+}
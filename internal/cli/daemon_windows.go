@@ -0,0 +1,28 @@
+//go:build windows
+
+package cli
+
+import (
+	"fmt"
+	"net"
+	"os"
+)
+
+// ensurePrivateSocketDir creates dir if it is missing. The runtime dir on
+// Windows lives under the user's profile, which other users cannot read.
+func ensurePrivateSocketDir(dir string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create daemon socket directory: %w", err)
+	}
+	return nil
+}
+
+// listenPrivateUnix listens on socketPath; see ensurePrivateSocketDir.
+func listenPrivateUnix(socketPath string) (net.Listener, error) {
+	return net.Listen("unix", socketPath)
+}
+
+// checkDaemonSocketOwner is a no-op on Windows; see ensurePrivateSocketDir.
+func checkDaemonSocketOwner(string) error {
+	return nil
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+
+	"claudio.click/internal/audio"
+	"github.com/spf13/cobra"
+)
+
+// newDevicesCommand returns the `claudio devices` subcommand, which lists
+// the playback devices audio_device can name.
+func newDevicesCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "devices",
+		Short: "List audio playback devices",
+		Long: `List the playback devices Claudio can play through, with the ID and name
+the audio_device setting accepts.
+
+Set audio_device in config.json to a device's ID or part of its name to
+play sounds there instead of on the system default, for example to keep
+agent sounds on headphones while music plays on speakers. A device that
+is not connected falls back to the default.
+
+Devices are listed through the malgo backend. With the system_command
+backend, paplay and pw-play take audio_device as a PulseAudio sink or
+PipeWire node name, which is the ID listed under PulseAudio or PipeWire.`,
+		Args: cobra.NoArgs,
+		RunE: runDevices,
+	}
+}
+
+func runDevices(cmd *cobra.Command, _ []string) error {
+	cfg, err := loadCacheConfig()
+	if err != nil {
+		return err
+	}
+	devices, err := listPlaybackDevices(cfg.AudioBackend)
+	if err != nil {
+		return err
+	}
+	if len(devices) == 0 {
+		cmd.Println("No playback devices found.")
+		return nil
+	}
+
+	selected, found := audio.MatchDevice(devices, cfg.AudioDevice)
+	cmd.Println("Playback devices:")
+	for _, d := range devices {
+		label := d.Name
+		if d.Default {
+			label += " (default)"
+		}
+		if found && d == selected {
+			label += " (selected)"
+		}
+		cmd.Printf("  %s\n", label)
+		cmd.Printf("    ID: %s\n", d.ID)
+	}
+
+	cmd.Println()
+	switch {
+	case cfg.AudioDevice == "":
+		cmd.Println("audio_device is not set; sounds play on the default device.")
+	case found:
+		cmd.Printf("audio_device %q selects %s.\n", cfg.AudioDevice, selected.Name)
+	default:
+		cmd.Printf("audio_device %q matches no device; sounds play on the default device.\n", cfg.AudioDevice)
+	}
+	return nil
+}
+
+// listPlaybackDevices lists devices through the configured backend when it
+// can enumerate them, and through the malgo backend otherwise.
+func listPlaybackDevices(backendType string) ([]audio.Device, error) {
+	backend, err := audio.NewBackend(backendType)
+	if err == nil {
+		if lister, ok := backend.(audio.DeviceLister); ok {
+			defer backend.Close()
+			return lister.Devices()
+		}
+		backend.Close()
+	}
+	slog.Debug("configured backend cannot list devices, using malgo", "backend", backendType, "error", err)
+
+	backend, err = audio.NewBackend("malgo")
+	if err != nil {
+		return nil, fmt.Errorf("cannot list playback devices: %w", err)
+	}
+	defer backend.Close()
+	lister, ok := backend.(audio.DeviceLister)
+	if !ok {
+		return nil, fmt.Errorf("cannot list playback devices: the malgo backend does not enumerate devices")
+	}
+	return lister.Devices()
+}
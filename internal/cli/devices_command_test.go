@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"claudio.click/internal/audio"
+	"claudio.click/internal/cli/testenv"
+	"claudio.click/internal/config"
+)
+
+func TestDevices_ListsAndMarksSelection(t *testing.T) {
+	_, _, cleanup := setupInstallTestEnv(t)
+	defer cleanup()
+	t.Setenv("CLAUDIO_AUDIO_DEVICE", "usb head")
+
+	stdout, stderr, exitCode := runSoundpackCLI("devices")
+	if exitCode != 0 {
+		t.Fatalf("devices exited %d, stderr: %s", exitCode, stderr)
+	}
+	for _, want := range []string{
+		"Fake Speakers (default)",
+		"ID: fake-speakers",
+		"Fake USB Headphones (selected)",
+		"ID: fake-headphones",
+		`audio_device "usb head" selects Fake USB Headphones.`,
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, stdout)
+		}
+	}
+}
+
+func TestDevices_ReportsUnmatchedAndUnsetDevice(t *testing.T) {
+	_, _, cleanup := setupInstallTestEnv(t)
+	defer cleanup()
+
+	stdout, _, _ := runSoundpackCLI("devices")
+	if !strings.Contains(stdout, "audio_device is not set") || strings.Contains(stdout, "(selected)") {
+		t.Errorf("expected no selection without audio_device, got:\n%s", stdout)
+	}
+
+	t.Setenv("CLAUDIO_AUDIO_DEVICE", "hdmi")
+	stdout, _, _ = runSoundpackCLI("devices")
+	if !strings.Contains(stdout, `audio_device "hdmi" matches no device`) {
+		t.Errorf("expected an unmatched audio_device to be reported, got:\n%s", stdout)
+	}
+}
+
+// TestAudioDevice_ReachesBackend checks that hooks play through the
+// configured audio_device.
+func TestAudioDevice_ReachesBackend(t *testing.T) {
+	root := testenv.IsolateXDG(t)
+	packPath, _ := writeTestJSONSoundpack(t, root, "default.wav")
+
+	cfg := config.NewConfigManager().GetDefaultConfig()
+	cfg.DefaultSoundpack = packPath
+	cfg.AudioDevice = "fake-headphones"
+	configPath := filepath.Join(root, "config.json")
+	writeSeedConfig(t, configPath, cfg)
+
+	hook := `{"session_id":"device","cwd":"/tmp","hook_event_name":"PostToolUse","tool_name":"Bash","tool_input":{"command":"ls"},"tool_response":{"stdout":"ok","stderr":"","interrupted":false}}`
+	if got := runHookForPlays(t, []string{"claudio", "--config", configPath}, hook); len(got) != 1 {
+		t.Fatalf("expected one play, got %v", got)
+	}
+	if device := audio.LastFakeBackend().Plays()[0].Device; device != "fake-headphones" {
+		t.Errorf("played on device %q, want fake-headphones", device)
+	}
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"claudio.click/internal/cli/testenv"
+	"claudio.click/internal/config"
+)
+
+func TestFileTypeSounds_PlayAndTrackCandidates(t *testing.T) {
+	root := testenv.IsolateXDG(t)
+	dbPath := filepath.Join(root, "claudio.db")
+	t.Setenv("CLAUDIO_SOUND_TRACKING", "true")
+	t.Setenv("CLAUDIO_SOUND_TRACKING_DB", dbPath)
+
+	packPath, physical := writeTestJSONSoundpack(t, root, "default.wav", "loading/edit-tests.wav")
+	cfg := config.NewConfigManager().GetDefaultConfig()
+	cfg.DefaultSoundpack = packPath
+	configPath := filepath.Join(root, "config.json")
+	writeSeedConfig(t, configPath, cfg)
+	args := []string{"claudio", "--config", configPath}
+
+	editTest := `{"session_id":"ft","cwd":"/src/app","hook_event_name":"PreToolUse","tool_name":"Edit","tool_input":{"file_path":"/src/app/pkg/store_test.go"}}`
+	if got := runHookForPlays(t, args, editTest); len(got) != 1 || got[0] != physical["loading/edit-tests.wav"] {
+		t.Errorf("editing a test file should play the tests group sound, got %v", got)
+	}
+
+	editDocs := `{"session_id":"ft","cwd":"/src/app","hook_event_name":"PreToolUse","tool_name":"Edit","tool_input":{"file_path":"/src/app/docs/guide.md"}}`
+	if got := runHookForPlays(t, args, editDocs); len(got) != 1 || got[0] != physical["default.wav"] {
+		t.Errorf("editing docs has no docs sound in this pack, got %v", got)
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open tracking db: %v", err)
+	}
+	defer db.Close()
+	for _, key := range []string{"loading/edit-go.wav", "loading/edit-md.wav", "loading/edit-docs.wav"} {
+		var missing int
+		if err := db.QueryRow("SELECT COUNT(*) FROM path_lookups WHERE path = ? AND found = 0", key).Scan(&missing); err != nil {
+			t.Fatalf("query path_lookups: %v", err)
+		}
+		if missing == 0 {
+			t.Errorf("tracking should record %s as a missing candidate", key)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"log/slog"
+
+	"claudio.click/internal/audio"
+	"claudio.click/internal/config"
+	"claudio.click/internal/hooks"
+)
+
+// sessionVariation returns the pitch and pan that identify hookEvent's
+// session, or the neutral variation when session_identity is off or the
+// value it keys on is unknown.
+func (c *CLI) sessionVariation(hookEvent *hooks.HookEvent, cfg *config.Config) audio.Variation {
+	identity := cfg.SessionIdentity
+	if identity == nil || !identity.Enabled {
+		return audio.Variation{}
+	}
+	key := c.configManager.IdentityFor(identity, hookEvent.SessionID, hookEvent.CWD)
+	if key == "" {
+		slog.Debug("no session identity key; playing without variation", "key", identity.EffectiveKey())
+		return audio.Variation{}
+	}
+	v := audio.DeriveVariation(key, identity.EffectivePitchSemitones(), identity.EffectivePan())
+	slog.Debug("session variation derived", "key", identity.EffectiveKey(), "pitch", v.Pitch, "pan", v.Pan)
+	return v
+}
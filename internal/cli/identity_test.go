@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"claudio.click/internal/audio"
+	"claudio.click/internal/cli/testenv"
+	"claudio.click/internal/config"
+)
+
+func TestSessionIdentity_VariesPlaysPerSession(t *testing.T) {
+	root := testenv.IsolateXDG(t)
+
+	packPath, _ := writeTestJSONSoundpack(t, root, "default.wav")
+	cfg := config.NewConfigManager().GetDefaultConfig()
+	cfg.DefaultSoundpack = packPath
+	cfg.SessionIdentity = &config.SessionIdentityConfig{Enabled: true, PitchSemitones: 2, Pan: 0.8}
+	configPath := filepath.Join(root, "config.json")
+	writeSeedConfig(t, configPath, cfg)
+
+	playFor := func(sessionID string) audio.Variation {
+		t.Helper()
+		runHookForPlays(t, []string{"claudio", "--config", configPath},
+			`{"session_id":"`+sessionID+`","cwd":"/src/app","hook_event_name":"PreToolUse","tool_name":"Bash"}`)
+		plays := audio.LastFakeBackend().Plays()
+		if len(plays) != 1 {
+			t.Fatalf("want one play for %s, got %+v", sessionID, plays)
+		}
+		return plays[0].Variation
+	}
+
+	a, b := playFor("session-a"), playFor("session-b")
+	if want := audio.DeriveVariation("session-a", 2, 0.8); a != want {
+		t.Errorf("session-a variation = %+v, want %+v", a, want)
+	}
+	if a == b {
+		t.Errorf("two sessions should sound different, both got %+v", a)
+	}
+	if again := playFor("session-a"); again != a {
+		t.Errorf("a session should keep its variation: %+v then %+v", a, again)
+	}
+}
+
+func TestSessionIdentity_OffByDefault(t *testing.T) {
+	root := testenv.IsolateXDG(t)
+
+	packPath, _ := writeTestJSONSoundpack(t, root, "default.wav")
+	cfg := config.NewConfigManager().GetDefaultConfig()
+	cfg.DefaultSoundpack = packPath
+	configPath := filepath.Join(root, "config.json")
+	writeSeedConfig(t, configPath, cfg)
+
+	runHookForPlays(t, []string{"claudio", "--config", configPath},
+		`{"session_id":"s","cwd":"/src/app","hook_event_name":"PreToolUse","tool_name":"Bash"}`)
+	for _, p := range audio.LastFakeBackend().Plays() {
+		if !p.Variation.IsNeutral() {
+			t.Errorf("sounds should play unchanged by default, got %+v", p.Variation)
+		}
+	}
+}
@@ -3,9 +3,11 @@ package cli
 import (
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"github.com/tj/go-naturaldate"
 
 	"claudio.click/internal/config"
 )
@@ -13,9 +15,10 @@ import (
 // newMuteCommand returns the `claudio mute` subcommand. Persistent
 // equivalent of the transient `--silent` flag — sets cfg.Enabled =
 // false in config.json. CLAUDIO_ENABLED=true env var will still
-// override at runtime.
+// override at runtime. With --for or --until it instead records a
+// muted_until expiry, which reverts by itself.
 func newMuteCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "mute",
 		Short: "Persistently disable claudio audio",
 		Long: `Persistently disable claudio audio by setting enabled=false in config.json.
@@ -23,11 +26,23 @@ func newMuteCommand() *cobra.Command {
 Persistent equivalent of the transient --silent flag. To re-enable,
 run 'claudio unmute' or set enabled=true in your config file.
 
+With --for or --until, mute only until a point in time, recorded as
+muted_until in config.json. Hooks play again after that without
+another command:
+
+  claudio mute --for 45m
+  claudio mute --until 14:00
+  claudio mute --until "tomorrow 9am"
+
 Note: the CLAUDIO_ENABLED=true environment variable, if set, will
-still override this at runtime.`,
+still override a plain mute at runtime.`,
 		Args: cobra.NoArgs,
 		RunE: runMuteE,
 	}
+	cmd.Flags().Duration("for", 0, "Mute for a duration, such as 45m or 2h")
+	cmd.Flags().String("until", "", "Mute until a time, such as 14:00 or \"tomorrow 9am\"")
+	cmd.MarkFlagsMutuallyExclusive("for", "until")
+	return cmd
 }
 
 // newUnmuteCommand returns the `claudio unmute` subcommand. Symmetric
@@ -38,7 +53,8 @@ func newUnmuteCommand() *cobra.Command {
 		Short: "Persistently enable claudio audio",
 		Long: `Persistently enable claudio audio by setting enabled=true in config.json.
 
-Symmetric counterpart to 'claudio mute'.
+Symmetric counterpart to 'claudio mute'. Also ends a temporary mute
+set with --for or --until. Quiet hours in the schedule still apply.
 
 Note: the CLAUDIO_ENABLED=false environment variable, if set, will
 still override this at runtime.`,
@@ -48,17 +64,64 @@ still override this at runtime.`,
 }
 
 func runMuteE(cmd *cobra.Command, _ []string) error {
-	return setEnabledAndPersist(cmd, false, "audio muted")
+	now := time.Now()
+	var until time.Time
+	if cmd.Flags().Changed("for") {
+		d, _ := cmd.Flags().GetDuration("for")
+		if d <= 0 {
+			return fmt.Errorf("--for must be positive, got %s", d)
+		}
+		until = now.Add(d)
+	} else if s, _ := cmd.Flags().GetString("until"); s != "" {
+		t, err := parseMuteUntil(s, now)
+		if err != nil {
+			return err
+		}
+		until = t
+	}
+
+	if until.IsZero() {
+		return setEnabledAndPersist(cmd, "audio muted", func(cfg *config.Config) {
+			cfg.Enabled = false
+		})
+	}
+	return setEnabledAndPersist(cmd, "audio muted until "+config.FormatMuteTime(until, now), func(cfg *config.Config) {
+		cfg.MutedUntil = &until
+	})
 }
 
 func runUnmuteE(cmd *cobra.Command, _ []string) error {
-	return setEnabledAndPersist(cmd, true, "audio unmuted")
+	return setEnabledAndPersist(cmd, "audio unmuted", func(cfg *config.Config) {
+		cfg.Enabled = true
+		cfg.MutedUntil = nil
+	})
+}
+
+// parseMuteUntil parses a --until value relative to now. A time of day
+// that has already passed today means tomorrow.
+func parseMuteUntil(value string, now time.Time) (time.Time, error) {
+	t, err := naturaldate.Parse(value, now, naturaldate.WithDirection(naturaldate.Future))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse --until '%s': %w", value, err)
+	}
+	// naturaldate returns the reference time for input it does not
+	// understand, and today's date for a bare time of day.
+	if t.Equal(now) {
+		return time.Time{}, fmt.Errorf("failed to parse --until '%s': not a recognizable time", value)
+	}
+	if !t.After(now) && t.Add(24*time.Hour).After(now) {
+		t = t.Add(24 * time.Hour)
+	}
+	if !t.After(now) {
+		return time.Time{}, fmt.Errorf("--until '%s' is in the past", value)
+	}
+	return t, nil
 }
 
 // setEnabledAndPersist is the shared core for mute/unmute. Acquires
-// the config lock, loads existing config, flips Enabled, writes
+// the config lock, loads existing config, applies update, writes
 // atomically.
-func setEnabledAndPersist(cmd *cobra.Command, enabled bool, successMsg string) error {
+func setEnabledAndPersist(cmd *cobra.Command, successMsg string, update func(*config.Config)) error {
 	cli := cliFromContext(cmd.Context())
 	if cli == nil {
 		return fmt.Errorf("CLI instance not found in context")
@@ -85,12 +148,15 @@ func setEnabledAndPersist(cmd *cobra.Command, enabled bool, successMsg string) e
 		return err
 	}
 
-	cfg.Enabled = enabled
+	update(cfg)
 	if err := config.WriteConfigFile(afero.NewOsFs(), configPath, cfg); err != nil {
 		return fmt.Errorf("save config: %w", err)
 	}
 
 	fmt.Fprintln(cmd.OutOrStdout(), successMsg)
-	slog.Info("enabled persisted", "path", configPath, "enabled", enabled)
+	if muted, reason := cfg.ScheduledMute(time.Now()); muted && cfg.Enabled && cfg.MutedUntil == nil {
+		fmt.Fprintf(cmd.OutOrStdout(), "note: hooks stay silent now: %s\n", reason)
+	}
+	slog.Info("enabled persisted", "path", configPath, "enabled", cfg.Enabled, "muted_until", cfg.MutedUntil)
 	return nil
 }
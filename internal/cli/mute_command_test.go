@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"claudio.click/internal/cli/testenv"
 	"claudio.click/internal/config"
@@ -134,3 +135,120 @@ func TestMuteIdempotent(t *testing.T) {
 		t.Error("after two mutes, Enabled should still be false")
 	}
 }
+
+func TestMuteCommand_ForSetsExpiry(t *testing.T) {
+	testenv.IsolateXDG(t)
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeSeedConfig(t, configPath, &config.Config{
+		DefaultSoundpack: "x",
+		Enabled:          true,
+		LogLevel:         "warn",
+		AudioBackend:     "auto",
+	})
+
+	before := time.Now()
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	code := NewCLI().Run([]string{"claudio", "mute", "--for", "45m", "--config", configPath}, strings.NewReader(""), stdout, stderr)
+	if code != 0 {
+		t.Fatalf("exit code = %d; stderr=%s", code, stderr.String())
+	}
+
+	persisted := readPersistedConfig(t, configPath)
+	if !persisted.Enabled {
+		t.Error("a timed mute should leave enabled alone so it can revert")
+	}
+	if persisted.MutedUntil == nil {
+		t.Fatal("muted_until not persisted")
+	}
+	if d := persisted.MutedUntil.Sub(before); d < 45*time.Minute || d > 46*time.Minute {
+		t.Errorf("muted_until is %s after the command, want 45m", d)
+	}
+	if !strings.Contains(stdout.String(), "muted until") {
+		t.Errorf("expected output to mention 'muted until', got: %q", stdout.String())
+	}
+
+	code = NewCLI().Run([]string{"claudio", "unmute", "--config", configPath}, strings.NewReader(""), &bytes.Buffer{}, stderr)
+	if code != 0 {
+		t.Fatalf("unmute exit code = %d; stderr=%s", code, stderr.String())
+	}
+	if readPersistedConfig(t, configPath).MutedUntil != nil {
+		t.Error("unmute should clear muted_until")
+	}
+}
+
+func TestMuteCommand_ForRejectsNonPositive(t *testing.T) {
+	testenv.IsolateXDG(t)
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeSeedConfig(t, configPath, &config.Config{
+		DefaultSoundpack: "x",
+		Enabled:          true,
+		LogLevel:         "warn",
+		AudioBackend:     "auto",
+	})
+
+	for _, d := range []string{"0s", "-5m"} {
+		stderr := &bytes.Buffer{}
+		code := NewCLI().Run([]string{"claudio", "mute", "--for", d, "--config", configPath}, strings.NewReader(""), &bytes.Buffer{}, stderr)
+		if code == 0 {
+			t.Errorf("mute --for %s succeeded; want an error", d)
+		}
+		if !strings.Contains(stderr.String(), "--for must be positive") {
+			t.Errorf("mute --for %s stderr = %q", d, stderr.String())
+		}
+		if persisted := readPersistedConfig(t, configPath); !persisted.Enabled || persisted.MutedUntil != nil {
+			t.Errorf("mute --for %s changed the config: enabled=%v muted_until=%v", d, persisted.Enabled, persisted.MutedUntil)
+		}
+	}
+}
+
+func TestParseMuteUntil(t *testing.T) {
+	now := time.Date(2026, 10, 16, 13, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"14:00", time.Date(2026, 10, 16, 14, 0, 0, 0, time.UTC)},
+		{"10am", time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)},
+		{"tomorrow 9am", time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseMuteUntil(tt.value, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseMuteUntil(%q) = %v, %v; want %v", tt.value, got, err, tt.want)
+		}
+	}
+	if _, err := parseMuteUntil("whenever", now); err == nil {
+		t.Error("unrecognized --until should fail")
+	}
+}
+
+func TestScheduledMute_SkipsHook(t *testing.T) {
+	root := testenv.IsolateXDG(t)
+	packPath, _ := writeTestJSONSoundpack(t, root, "default.wav")
+
+	cfg := config.NewConfigManager().GetDefaultConfig()
+	cfg.DefaultSoundpack = packPath
+	until := time.Now().Add(time.Hour)
+	cfg.MutedUntil = &until
+	configPath := filepath.Join(root, "config.json")
+	writeSeedConfig(t, configPath, cfg)
+
+	stop := `{"session_id":"qh","cwd":"/tmp","hook_event_name":"Stop"}`
+	if got := runHookForPlays(t, []string{"claudio", "--config", configPath}, stop); len(got) != 0 {
+		t.Errorf("muted hook should not play, got %v", got)
+	}
+
+	cfg.MutedUntil = nil
+	cfg.Schedule = &config.ScheduleConfig{QuietHours: []config.QuietHours{{Start: "00:00", End: "00:00"}}}
+	writeSeedConfig(t, configPath, cfg)
+	if got := runHookForPlays(t, []string{"claudio", "--config", configPath}, stop); len(got) != 0 {
+		t.Errorf("hook during quiet hours should not play, got %v", got)
+	}
+
+	cfg.Schedule = nil
+	writeSeedConfig(t, configPath, cfg)
+	if got := runHookForPlays(t, []string{"claudio", "--config", configPath}, stop); len(got) != 1 {
+		t.Errorf("unmuted hook should play once, got %v", got)
+	}
+}
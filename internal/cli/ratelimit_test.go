@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"claudio.click/internal/audio"
+	"claudio.click/internal/cli/testenv"
+	"claudio.click/internal/config"
+)
+
+// TestRateLimits_SuppressRepeatedLoadingSounds runs two hook processes'
+// worth of PreToolUse/Read events through separate CLI instances; the
+// second is within the rule's window and must not reach the backend.
+func TestRateLimits_SuppressRepeatedLoadingSounds(t *testing.T) {
+	root := testenv.IsolateXDG(t)
+
+	cfg := config.NewConfigManager().GetDefaultConfig()
+	cfg.RateLimits = []config.RateLimitRule{{Category: "loading", MinIntervalMs: 60_000}}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+	configPath := filepath.Join(root, "ratelimit-config.json")
+	if err := os.WriteFile(configPath, data, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	hookJSON := `{"session_id":"rl","cwd":"/tmp","hook_event_name":"PreToolUse","tool_name":"Read","tool_input":{"file_path":"/tmp/a.go"}}`
+
+	plays := func() int {
+		audio.ResetLastFakeBackend()
+		stderr := &bytes.Buffer{}
+		if code := NewCLI().Run([]string{"claudio", "--config", configPath}, strings.NewReader(hookJSON), &bytes.Buffer{}, stderr); code != 0 {
+			t.Fatalf("exit code %d, stderr: %s", code, stderr.String())
+		}
+		fake := audio.LastFakeBackend()
+		if fake == nil {
+			return 0
+		}
+		return len(fake.Plays())
+	}
+
+	if got := plays(); got == 0 {
+		t.Fatal("first loading event should play")
+	}
+	if got := plays(); got != 0 {
+		t.Errorf("second loading event within the window should be suppressed, got %d plays", got)
+	}
+}
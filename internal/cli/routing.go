@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"log/slog"
+
+	"claudio.click/internal/config"
+	"claudio.click/internal/hooks"
+)
+
+// matchRoutingRule returns the first user routing rule matching hookEvent,
+// or nil. Rules are reloaded per event so edits to routing.json apply to
+// the next hook without restarting the daemon. A broken rules file is
+// logged and ignored: the built-in chains still produce a sound.
+func (c *CLI) matchRoutingRule(hookEvent *hooks.HookEvent, eventCtx *hooks.EventContext, cfg *config.Config) *config.RoutingRule {
+	cm := c.configManager
+	if cm == nil {
+		cm = config.NewConfigManager()
+	}
+	rules, err := cm.LoadRoutingRules(cfg)
+	if err != nil {
+		slog.Warn("failed to load routing rules (continuing with built-in chains)", "error", err)
+		return nil
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	commandInfo := hookEvent.CommandInfo()
+	input := config.RoutingInput{
+		Event:      hookEvent.EventName,
+		Tool:       hookEvent.NormalizedToolName(),
+		Command:    commandInfo.Command,
+		Subcommand: commandInfo.Subcommand,
+		Extension:  eventCtx.FileType,
+		CWD:        hookEvent.CWD,
+		Agent:      eventCtx.Agent,
+		Category:   eventCtx.Category.String(),
+	}
+	rule := config.MatchRoutingRule(rules, input)
+	if rule == nil {
+		slog.Debug("no routing rule matched", "event_name", input.Event, "tool", input.Tool)
+		return nil
+	}
+	slog.Debug("routing rule matched",
+		"rule", rule.Name,
+		"sounds", rule.SoundKeys(),
+		"exclusive", rule.Exclusive)
+	return rule
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"claudio.click/internal/audio"
+	"claudio.click/internal/cli/testenv"
+	"claudio.click/internal/config"
+)
+
+// writeTestJSONSoundpack writes a JSON soundpack mapping each logical key
+// to its own minimal WAV under dir and returns the pack path plus the
+// physical path of every key.
+func writeTestJSONSoundpack(t *testing.T, dir string, keys ...string) (string, map[string]string) {
+	t.Helper()
+	physical := make(map[string]string, len(keys))
+	mappings := make(map[string]string, len(keys))
+	for i, key := range keys {
+		name := "sound-" + string(rune('a'+i)) + ".wav"
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, createMinimalWAV(), 0o644); err != nil {
+			t.Fatalf("write wav: %v", err)
+		}
+		physical[key] = path
+		mappings[key] = name
+	}
+	data, err := json.Marshal(map[string]any{"name": "routing-test", "mappings": mappings})
+	if err != nil {
+		t.Fatalf("marshal soundpack: %v", err)
+	}
+	packPath := filepath.Join(dir, "pack.json")
+	if err := os.WriteFile(packPath, data, 0o644); err != nil {
+		t.Fatalf("write soundpack: %v", err)
+	}
+	return packPath, physical
+}
+
+// runHookForPlays runs one hook through a fresh CLI and returns the source
+// paths the fake backend was asked to play.
+func runHookForPlays(t *testing.T, args []string, hookJSON string) []string {
+	t.Helper()
+	audio.ResetLastFakeBackend()
+	stderr := &bytes.Buffer{}
+	if code := NewCLI().Run(args, strings.NewReader(hookJSON), &bytes.Buffer{}, stderr); code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr.String())
+	}
+	fake := audio.LastFakeBackend()
+	if fake == nil {
+		return nil
+	}
+	var paths []string
+	for _, p := range fake.Plays() {
+		paths = append(paths, p.SourcePath)
+	}
+	return paths
+}
+
+func TestRoutingRules_OverrideBuiltinChain(t *testing.T) {
+	root := testenv.IsolateXDG(t)
+
+	packPath, physical := writeTestJSONSoundpack(t, root, "default.wav", "custom/cargo-tests.wav")
+
+	routingPath := filepath.Join(root, "routing.json")
+	routing := `{"rules":[{"name":"cargo tests","match":{"event":"PostToolUse","tool":"Bash","command":"cargo","subcommand":"test"},"sound":"custom/cargo-tests.wav"}]}`
+	if err := os.WriteFile(routingPath, []byte(routing), 0o644); err != nil {
+		t.Fatalf("write routing: %v", err)
+	}
+
+	cfg := config.NewConfigManager().GetDefaultConfig()
+	cfg.DefaultSoundpack = packPath
+	cfg.RoutingFile = routingPath
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+	configPath := filepath.Join(root, "config.json")
+	if err := os.WriteFile(configPath, data, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	args := []string{"claudio", "--config", configPath}
+
+	cargo := `{"session_id":"rt","cwd":"/tmp","hook_event_name":"PostToolUse","tool_name":"Bash","tool_input":{"command":"cargo test --all"},"tool_response":{"stdout":"ok","stderr":"","interrupted":false}}`
+	if got := runHookForPlays(t, args, cargo); len(got) != 1 || got[0] != physical["custom/cargo-tests.wav"] {
+		t.Errorf("cargo test should play the routed sound, got %v", got)
+	}
+
+	npm := `{"session_id":"rt","cwd":"/tmp","hook_event_name":"PostToolUse","tool_name":"Bash","tool_input":{"command":"npm test"},"tool_response":{"stdout":"ok","stderr":"","interrupted":false}}`
+	if got := runHookForPlays(t, args, npm); len(got) != 1 || got[0] != physical["default.wav"] {
+		t.Errorf("unmatched event should use the built-in chain, got %v", got)
+	}
+}
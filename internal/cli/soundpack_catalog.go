@@ -0,0 +1,315 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"claudio.click/internal/config"
+	"claudio.click/internal/safeio"
+	"github.com/spf13/cobra"
+)
+
+const (
+	soundpackCatalogVersion      = 1
+	soundpackCatalogFetchTimeout = 30 * time.Second
+)
+
+// soundpackCatalogTransport carries catalog fetches; nil means
+// http.DefaultTransport. Tests point it at a TLS test server.
+var soundpackCatalogTransport http.RoundTripper
+
+// soundpackCatalog is an index of git soundpacks, read from each entry of
+// config soundpack_catalogs.
+type soundpackCatalog struct {
+	Version int                     `json:"version"`
+	Packs   []soundpackCatalogEntry `json:"packs"`
+}
+
+// soundpackCatalogEntry describes one installable pack. Checksum pins the
+// commit that Ref must resolve to, so a catalog can vouch for exactly what
+// it lists even when the repository moves on.
+type soundpackCatalogEntry struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	URL         string   `json:"url"`
+	Ref         string   `json:"ref,omitempty"`
+	Subdir      string   `json:"subdir,omitempty"`
+	Checksum    string   `json:"checksum,omitempty"`
+	License     string   `json:"license,omitempty"`
+	Preview     []string `json:"preview,omitempty"`
+
+	Catalog string `json:"-"` // catalog the entry was read from
+}
+
+func newSoundpackSearchCommand() *cobra.Command {
+	searchCmd := &cobra.Command{
+		Use:   "search [term]",
+		Short: "Search soundpack catalogs",
+		Long: `Search the catalogs listed in config soundpack_catalogs for soundpacks whose
+name or description contains term. Without a term, every catalog entry is
+listed. Install a result with 'claudio soundpack add <name>'.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			term := ""
+			if len(args) == 1 {
+				term = args[0]
+			}
+			return runSoundpackSearch(cmd, term)
+		},
+	}
+	return searchCmd
+}
+
+func runSoundpackSearch(cmd *cobra.Command, term string) error {
+	entries, err := loadSoundpackCatalogEntries()
+	if err != nil {
+		return err
+	}
+
+	matches := make([]soundpackCatalogEntry, 0, len(entries))
+	needle := strings.ToLower(strings.TrimSpace(term))
+	for _, entry := range entries {
+		if needle == "" ||
+			strings.Contains(strings.ToLower(entry.Name), needle) ||
+			strings.Contains(strings.ToLower(entry.Description), needle) {
+			matches = append(matches, entry)
+		}
+	}
+	if len(matches) == 0 {
+		cmd.Printf("No soundpacks match %q\n", term)
+		return nil
+	}
+
+	for i, entry := range matches {
+		if i > 0 {
+			cmd.Println()
+		}
+		if entry.License != "" {
+			cmd.Printf("%s (%s)\n", entry.Name, entry.License)
+		} else {
+			cmd.Printf("%s\n", entry.Name)
+		}
+		if entry.Description != "" {
+			cmd.Printf("  %s\n", entry.Description)
+		}
+		cmd.Printf("  Source:  %s @ %s\n", entry.URL, displayRef(entry.Ref))
+		if len(entry.Preview) > 0 {
+			cmd.Printf("  Preview: %s\n", strings.Join(entry.Preview, ", "))
+		}
+	}
+	return nil
+}
+
+// loadSoundpackCatalogEntries reads every configured catalog in order. A
+// name listed by more than one catalog resolves to the first, so a local
+// catalog placed ahead of a shared one can override its entries.
+func loadSoundpackCatalogEntries() ([]soundpackCatalogEntry, error) {
+	cm := config.NewConfigManager()
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if len(cfg.SoundpackCatalogs) == 0 {
+		return nil, fmt.Errorf("no soundpack catalogs configured; add a URL or file to soundpack_catalogs in config.json")
+	}
+
+	entries := make([]soundpackCatalogEntry, 0)
+	seen := make(map[string]bool)
+	for _, location := range cfg.SoundpackCatalogs {
+		catalog, err := readSoundpackCatalog(location)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range catalog.Packs {
+			if err := validateSoundpackCatalogEntry(entry); err != nil {
+				slog.Warn("skipping invalid soundpack catalog entry", "catalog", location, "name", entry.Name, "error", err)
+				continue
+			}
+			if seen[entry.Name] {
+				slog.Debug("soundpack catalog entry shadowed by earlier catalog", "catalog", location, "name", entry.Name)
+				continue
+			}
+			seen[entry.Name] = true
+			entry.Catalog = location
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// findSoundpackCatalogEntry looks name up in the configured catalogs.
+func findSoundpackCatalogEntry(name string) (soundpackCatalogEntry, error) {
+	entries, err := loadSoundpackCatalogEntries()
+	if err != nil {
+		return soundpackCatalogEntry{}, err
+	}
+	for _, entry := range entries {
+		if entry.Name == name {
+			return entry, nil
+		}
+	}
+	return soundpackCatalogEntry{}, fmt.Errorf("soundpack %q not found in configured catalogs", name)
+}
+
+// readSoundpackCatalog loads a catalog from an https URL or a local file.
+// Plain http is refused: a catalog names the commits add trusts, so it
+// must not be open to rewriting in transit.
+func readSoundpackCatalog(location string) (*soundpackCatalog, error) {
+	var data []byte
+	var err error
+	if isRemoteCatalogLocation(location) {
+		if !strings.HasPrefix(strings.ToLower(location), "https://") {
+			return nil, fmt.Errorf("soundpack catalog %s must use https", location)
+		}
+		data, err = fetchSoundpackCatalog(location)
+	} else {
+		data, err = readLocalSoundpackCatalog(location)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := &soundpackCatalog{}
+	if err := json.Unmarshal(data, catalog); err != nil {
+		return nil, fmt.Errorf("failed to parse soundpack catalog %s: %w", location, err)
+	}
+	if catalog.Version > soundpackCatalogVersion {
+		return nil, fmt.Errorf("soundpack catalog %s has unsupported version %d", location, catalog.Version)
+	}
+	slog.Debug("loaded soundpack catalog", "catalog", location, "packs", len(catalog.Packs))
+	return catalog, nil
+}
+
+func readLocalSoundpackCatalog(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open soundpack catalog: %w", err)
+	}
+	defer f.Close()
+
+	data, err := safeio.ReadAllCapped(f, safeio.MaxSoundpackJSONBytes, "soundpack catalog")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read soundpack catalog %s: %w", path, err)
+	}
+	return data, nil
+}
+
+func fetchSoundpackCatalog(url string) ([]byte, error) {
+	client := &http.Client{Transport: soundpackCatalogTransport, Timeout: soundpackCatalogFetchTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch soundpack catalog: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch soundpack catalog %s: %s", url, resp.Status)
+	}
+	data, err := safeio.ReadAllCapped(resp.Body, safeio.MaxSoundpackJSONBytes, "soundpack catalog")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read soundpack catalog %s: %w", url, err)
+	}
+	return data, nil
+}
+
+func isRemoteCatalogLocation(location string) bool {
+	lower := strings.ToLower(location)
+	return strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "http://")
+}
+
+func validateSoundpackCatalogEntry(entry soundpackCatalogEntry) error {
+	if err := validateManagedSoundpackName(entry.Name); err != nil {
+		return err
+	}
+	if strings.TrimSpace(entry.URL) == "" {
+		return fmt.Errorf("url cannot be empty")
+	}
+	if err := validateCatalogURL(entry.URL); err != nil {
+		return err
+	}
+	if err := validateGitSubdir(entry.Subdir); err != nil {
+		return err
+	}
+	if entry.Checksum != "" && !isCommitChecksum(entry.Checksum) {
+		return fmt.Errorf("checksum must be a full 40 or 64 digit hexadecimal git commit id, got %q", entry.Checksum)
+	}
+	return nil
+}
+
+// validateCatalogURL accepts the sources a catalog may point soundpack add
+// at: https://, ssh:// and file:// URLs, scp-style [user@]host:path, and
+// gh:owner/repo. Catalogs can come from any server, so other git
+// transports (ext::, fd::, a remote helper) and anything git could read
+// as an option are refused.
+func validateCatalogURL(url string) error {
+	if strings.HasPrefix(url, "-") {
+		return fmt.Errorf("url %q cannot start with '-'", url)
+	}
+	lower := strings.ToLower(url)
+	for _, scheme := range []string{"https://", "ssh://", "file://"} {
+		if strings.HasPrefix(lower, scheme) {
+			return nil
+		}
+	}
+	if strings.HasPrefix(url, "gh:") {
+		return validateGitHubAliasRepo(strings.TrimPrefix(url, "gh:"))
+	}
+	if isSCPStyleGitURL(url) {
+		return nil
+	}
+	return fmt.Errorf("url %q must be an https, ssh, file, scp-style (user@host:path) or gh: URL", url)
+}
+
+// isSCPStyleGitURL reports whether url is git's scp-like ssh syntax,
+// [user@]host:path, with no slash before the colon.
+func isSCPStyleGitURL(url string) bool {
+	colon := strings.IndexByte(url, ':')
+	if colon <= 0 || colon == len(url)-1 || strings.Contains(url, "::") || strings.Contains(url, "://") {
+		return false
+	}
+	if slash := strings.IndexByte(url, '/'); slash >= 0 && slash < colon {
+		return false
+	}
+	host := url[:colon]
+	if at := strings.LastIndexByte(host, '@'); at >= 0 {
+		host = host[at+1:]
+	}
+	return host != "" && !strings.HasPrefix(host, "-") && !strings.ContainsAny(host, " \t")
+}
+
+// isCommitChecksum accepts a full commit id: 40 hex digits for SHA-1
+// repositories, 64 for SHA-256. An abbreviated id would let a catalog
+// match any commit that shares its prefix.
+func isCommitChecksum(checksum string) bool {
+	if len(checksum) != 40 && len(checksum) != 64 {
+		return false
+	}
+	for _, r := range checksum {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'f' || r >= 'A' && r <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// commitMatchesChecksum reports whether the checked-out commit is the one
+// the catalog pinned.
+func commitMatchesChecksum(commit, checksum string) bool {
+	return strings.EqualFold(commit, checksum)
+}
+
+// isCatalogSoundpackName reports whether an add source should be looked up
+// in the catalogs: a bare soundpack name, which a gh: alias or URL never is,
+// that does not name an existing local repository.
+func isCatalogSoundpackName(source string) bool {
+	if validateManagedSoundpackName(source) != nil {
+		return false
+	}
+	_, err := os.Stat(source)
+	return os.IsNotExist(err)
+}
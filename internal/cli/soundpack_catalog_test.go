@@ -0,0 +1,319 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"claudio.click/internal/config"
+)
+
+func TestSoundpackSearch_MatchesNameAndDescription(t *testing.T) {
+	_, configDir, cleanup := setupInstallTestEnv(t)
+	defer cleanup()
+
+	catalogPath := writeTestCatalog(t, []soundpackCatalogEntry{
+		{Name: "startrek-bridge", Description: "Bridge chirps and alerts", URL: "https://example.com/startrek.git", License: "CC-BY-4.0", Preview: []string{"success/success.wav"}},
+		{Name: "retro-arcade", Description: "Eight-bit blips", URL: "https://example.com/arcade.git"},
+	})
+	writeCatalogConfig(t, configDir, catalogPath)
+
+	stdout, stderr, exitCode := runSoundpackCLI("soundpack", "search", "CHIRPS")
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr: %s", exitCode, stderr)
+	}
+	for _, want := range []string{"startrek-bridge (CC-BY-4.0)", "Bridge chirps and alerts", "https://example.com/startrek.git", "Preview: success/success.wav"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected search output to contain %q, got:\n%s", want, stdout)
+		}
+	}
+	if strings.Contains(stdout, "retro-arcade") {
+		t.Errorf("expected retro-arcade not to match, got:\n%s", stdout)
+	}
+
+	stdout, _, _ = runSoundpackCLI("soundpack", "search")
+	if !strings.Contains(stdout, "startrek-bridge") || !strings.Contains(stdout, "retro-arcade") {
+		t.Errorf("expected search without a term to list every pack, got:\n%s", stdout)
+	}
+}
+
+func TestSoundpackSearch_ReadsCatalogFromURL(t *testing.T) {
+	_, configDir, cleanup := setupInstallTestEnv(t)
+	defer cleanup()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(soundpackCatalog{
+			Version: soundpackCatalogVersion,
+			Packs:   []soundpackCatalogEntry{{Name: "remote-pack", URL: "https://example.com/remote.git"}},
+		})
+	}))
+	defer server.Close()
+	soundpackCatalogTransport = server.Client().Transport
+	defer func() { soundpackCatalogTransport = nil }()
+	writeCatalogConfig(t, configDir, server.URL+"/catalog.json")
+
+	stdout, stderr, exitCode := runSoundpackCLI("soundpack", "search", "remote")
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr: %s", exitCode, stderr)
+	}
+	if !strings.Contains(stdout, "remote-pack") {
+		t.Errorf("expected remote catalog entry, got:\n%s", stdout)
+	}
+}
+
+func TestSoundpackSearch_RefusesPlainHTTPCatalog(t *testing.T) {
+	_, configDir, cleanup := setupInstallTestEnv(t)
+	defer cleanup()
+
+	fetched := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetched = true
+	}))
+	defer server.Close()
+	writeCatalogConfig(t, configDir, server.URL+"/catalog.json")
+
+	_, stderr, exitCode := runSoundpackCLI("soundpack", "search", "remote")
+	if exitCode == 0 {
+		t.Fatal("expected search of an http catalog to fail")
+	}
+	if !strings.Contains(stderr, "must use https") {
+		t.Errorf("expected an https error, got: %s", stderr)
+	}
+	if fetched {
+		t.Error("http catalog was fetched")
+	}
+}
+
+func TestIsCommitChecksum(t *testing.T) {
+	tests := []struct {
+		checksum string
+		ok       bool
+	}{
+		{strings.Repeat("a", 40), true},
+		{strings.Repeat("F", 64), true},
+		{"4f1c2a9", false},
+		{strings.Repeat("a", 39), false},
+		{strings.Repeat("a", 41), false},
+		{strings.Repeat("g", 40), false},
+	}
+	for _, tt := range tests {
+		if got := isCommitChecksum(tt.checksum); got != tt.ok {
+			t.Errorf("isCommitChecksum(%q) = %v, want %v", tt.checksum, got, tt.ok)
+		}
+	}
+	if commitMatchesChecksum(strings.Repeat("a", 40), strings.Repeat("a", 7)) {
+		t.Error("a commit must not match an abbreviated checksum")
+	}
+	if !commitMatchesChecksum(strings.Repeat("a", 40), strings.Repeat("A", 40)) {
+		t.Error("checksum comparison should ignore case")
+	}
+}
+
+func TestSoundpackSearch_RequiresCatalog(t *testing.T) {
+	_, _, cleanup := setupInstallTestEnv(t)
+	defer cleanup()
+
+	_, stderr, exitCode := runSoundpackCLI("soundpack", "search", "anything")
+	if exitCode == 0 {
+		t.Fatal("expected search without catalogs to fail")
+	}
+	if !strings.Contains(stderr, "soundpack_catalogs") {
+		t.Errorf("expected error to name soundpack_catalogs, got: %s", stderr)
+	}
+}
+
+func TestSoundpackAdd_InstallsByCatalogName(t *testing.T) {
+	dataDir, configDir, cleanup := setupInstallTestEnv(t)
+	defer cleanup()
+
+	bareRepo, commit := createTestBareSoundpackRepo(t)
+	repoURL := "file://" + filepath.ToSlash(bareRepo)
+	catalogPath := writeTestCatalog(t, []soundpackCatalogEntry{
+		{Name: "catalog-pack", URL: repoURL, Checksum: commit},
+	})
+	writeCatalogConfig(t, configDir, catalogPath)
+
+	stdout, stderr, exitCode := runSoundpackCLI("soundpack", "add", "catalog-pack")
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stdout: %s, stderr: %s", exitCode, stdout, stderr)
+	}
+
+	expectedClone := filepath.Join(dataDir, "claudio", "soundpack-repos", "catalog-pack")
+	if _, err := os.Stat(filepath.Join(expectedClone, "success", "success.wav")); err != nil {
+		t.Fatalf("expected cloned sound file, got error: %v", err)
+	}
+	registry, err := loadSoundpackRegistry()
+	if err != nil {
+		t.Fatalf("failed to load registry: %v", err)
+	}
+	record, exists := registry.Packs["catalog-pack"]
+	if !exists {
+		t.Fatalf("expected registry to contain catalog-pack: %#v", registry.Packs)
+	}
+	if record.URL != repoURL || record.ResolvedCommit != commit {
+		t.Errorf("expected record for %s at %s, got %#v", repoURL, commit, record)
+	}
+	cfg := loadTestConfig(t, configDir)
+	if !containsPath(cfg.SoundpackPaths, expectedClone) {
+		t.Errorf("expected soundpack_paths to contain %s, got %v", expectedClone, cfg.SoundpackPaths)
+	}
+}
+
+func TestSoundpackAdd_RejectsCatalogChecksumMismatch(t *testing.T) {
+	dataDir, configDir, cleanup := setupInstallTestEnv(t)
+	defer cleanup()
+
+	bareRepo, _ := createTestBareSoundpackRepo(t)
+	catalogPath := writeTestCatalog(t, []soundpackCatalogEntry{
+		{Name: "catalog-pack", URL: "file://" + filepath.ToSlash(bareRepo), Checksum: strings.Repeat("0", 40)},
+	})
+	writeCatalogConfig(t, configDir, catalogPath)
+
+	_, stderr, exitCode := runSoundpackCLI("soundpack", "add", "catalog-pack")
+	if exitCode == 0 {
+		t.Fatal("expected checksum mismatch to fail")
+	}
+	if !strings.Contains(stderr, "checksum mismatch") {
+		t.Errorf("expected checksum mismatch error, got: %s", stderr)
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "claudio", "soundpack-repos", "catalog-pack")); !os.IsNotExist(err) {
+		t.Errorf("expected the rejected clone to be removed, got: %v", err)
+	}
+}
+
+func TestSoundpackAdd_ExplicitRefSaysChecksumIsNotVerified(t *testing.T) {
+	_, configDir, cleanup := setupInstallTestEnv(t)
+	defer cleanup()
+
+	bareRepo, commit := createTestBareSoundpackRepo(t)
+	catalogPath := writeTestCatalog(t, []soundpackCatalogEntry{
+		{Name: "catalog-pack", URL: "file://" + filepath.ToSlash(bareRepo), Checksum: strings.Repeat("0", 40)},
+	})
+	writeCatalogConfig(t, configDir, catalogPath)
+
+	stdout, stderr, exitCode := runSoundpackCLI("soundpack", "add", "catalog-pack", "--ref", commit)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stdout: %s, stderr: %s", exitCode, stdout, stderr)
+	}
+	if !strings.Contains(stderr, "checksum "+strings.Repeat("0", 40)+" is not verified") {
+		t.Errorf("expected a note that the checksum is not verified, got: %s", stderr)
+	}
+}
+
+func TestSoundpackAdd_UnknownCatalogName(t *testing.T) {
+	_, configDir, cleanup := setupInstallTestEnv(t)
+	defer cleanup()
+
+	catalogPath := writeTestCatalog(t, nil)
+	writeCatalogConfig(t, configDir, catalogPath)
+
+	_, stderr, exitCode := runSoundpackCLI("soundpack", "add", "missing-pack")
+	if exitCode == 0 {
+		t.Fatal("expected unknown catalog name to fail")
+	}
+	if !strings.Contains(stderr, "not found in configured catalogs") {
+		t.Errorf("expected not-found error, got: %s", stderr)
+	}
+}
+
+func TestValidateCatalogURL(t *testing.T) {
+	tests := []struct {
+		url string
+		ok  bool
+	}{
+		{"https://github.com/owner/pack.git", true},
+		{"HTTPS://example.com/pack.git", true},
+		{"ssh://git@example.com/owner/pack.git", true},
+		{"git@github.com:owner/pack.git", true},
+		{"example.com:pack.git", true},
+		{"file:///srv/git/pack.git", true},
+		{"gh:owner/pack", true},
+		{"gh:owner/../pack", false},
+		{"http://example.com/pack.git", false},
+		{"git://example.com/pack.git", false},
+		{"ext::sh -c touch% /tmp/pwned", false},
+		{"fd::3", false},
+		{"--upload-pack=touch /tmp/pwned", false},
+		{"-oProxyCommand=sh", false},
+		{"git@-oProxyCommand=sh:pack.git", false},
+		{"/srv/git/pack.git", false},
+		{"./pack", false},
+	}
+	for _, tt := range tests {
+		err := validateCatalogURL(tt.url)
+		if (err == nil) != tt.ok {
+			t.Errorf("validateCatalogURL(%q) = %v, want ok=%v", tt.url, err, tt.ok)
+		}
+	}
+}
+
+func TestSoundpackSearch_SkipsEntriesWithUnsafeURL(t *testing.T) {
+	_, configDir, cleanup := setupInstallTestEnv(t)
+	defer cleanup()
+
+	catalogPath := writeTestCatalog(t, []soundpackCatalogEntry{
+		{Name: "sneaky-pack", URL: "ext::sh -c touch% /tmp/pwned"},
+		{Name: "honest-pack", URL: "https://example.com/honest.git"},
+	})
+	writeCatalogConfig(t, configDir, catalogPath)
+
+	stdout, stderr, exitCode := runSoundpackCLI("soundpack", "search")
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr: %s", exitCode, stderr)
+	}
+	if strings.Contains(stdout, "sneaky-pack") || !strings.Contains(stdout, "honest-pack") {
+		t.Errorf("expected only the entry with a safe URL, got:\n%s", stdout)
+	}
+}
+
+func runSoundpackCLI(args ...string) (stdout, stderr string, exitCode int) {
+	cli := NewCLI()
+	outBuf := &bytes.Buffer{}
+	errBuf := &bytes.Buffer{}
+	exitCode = cli.Run(append([]string{"claudio"}, args...), nil, outBuf, errBuf)
+	return outBuf.String(), errBuf.String(), exitCode
+}
+
+func writeTestCatalog(t *testing.T, packs []soundpackCatalogEntry) string {
+	t.Helper()
+	data, err := json.MarshalIndent(soundpackCatalog{Version: soundpackCatalogVersion, Packs: packs}, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal catalog: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("failed to write catalog: %v", err)
+	}
+	return path
+}
+
+func writeCatalogConfig(t *testing.T, configDir string, catalogs ...string) {
+	t.Helper()
+	writeSeedConfig(t, filepath.Join(configDir, "claudio", "config.json"), &config.Config{
+		DefaultSoundpack:  "default",
+		Enabled:           true,
+		AudioBackend:      "auto",
+		SoundpackCatalogs: catalogs,
+	})
+}
+
+// createTestBareSoundpackRepo publishes createTestGitSoundpackRepo as a
+// bare repository, the way a catalog's source would be hosted.
+func createTestBareSoundpackRepo(t *testing.T) (string, string) {
+	t.Helper()
+	repoPath := createTestGitSoundpackRepo(t)
+	commit, err := currentGitCommit(repoPath)
+	if err != nil {
+		t.Fatalf("failed to read commit: %v", err)
+	}
+	barePath := filepath.Join(t.TempDir(), "pack.git")
+	if _, err := runGit("", "clone", "--bare", repoPath, barePath); err != nil {
+		t.Fatalf("git clone --bare failed: %v", err)
+	}
+	return barePath, commit
+}
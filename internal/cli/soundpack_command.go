@@ -7,9 +7,9 @@ import (
 // newSoundpackCommand creates the soundpack command group and wires every
 // subcommand factory into it. Each subcommand is defined in its own file
 // (soundpack_init.go, soundpack_list.go, soundpack_validate.go,
-// soundpack_install.go, soundpack_use.go) and the add/update/remove/status
+// soundpack_install.go, soundpack_export.go, soundpack_use.go) and the add/update/remove/status
 // subcommands live alongside the git-managed soundpack code in
-// soundpack_git.go. Shared discovery helpers are in soundpack_helpers.go.
+// soundpack_git.go, with catalog search in soundpack_catalog.go. Shared discovery helpers are in soundpack_helpers.go.
 func newSoundpackCommand() *cobra.Command {
 	soundpackCmd := &cobra.Command{
 		Use:   "soundpack",
@@ -20,10 +20,13 @@ func newSoundpackCommand() *cobra.Command {
 	soundpackCmd.AddCommand(newSoundpackListCommand())
 	soundpackCmd.AddCommand(newSoundpackValidateCommand())
 	soundpackCmd.AddCommand(newSoundpackInstallCommand())
+	soundpackCmd.AddCommand(newSoundpackExportCommand())
+	soundpackCmd.AddCommand(newSoundpackNormalizeCommand())
 	soundpackCmd.AddCommand(newSoundpackUseCommand())
 	soundpackCmd.AddCommand(newSoundpackAddCommand())
 	soundpackCmd.AddCommand(newSoundpackUpdateCommand())
 	soundpackCmd.AddCommand(newSoundpackRemoveCommand())
 	soundpackCmd.AddCommand(newSoundpackStatusCommand())
+	soundpackCmd.AddCommand(newSoundpackSearchCommand())
 	return soundpackCmd
 }
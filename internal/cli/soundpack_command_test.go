@@ -111,7 +111,7 @@ func TestSoundpackInit_MappingValuesAreEmpty(t *testing.T) {
 	}
 
 	for key, val := range spFile.Mappings {
-		if val != "" {
+		if !val.IsEmpty() {
 			t.Errorf("expected mapping value for %q to be empty, got %q", key, val)
 		}
 	}
@@ -141,7 +141,7 @@ func TestSoundpackInit_FromPlatformPreFillsValues(t *testing.T) {
 
 	nonEmpty := 0
 	for _, val := range spFile.Mappings {
-		if val != "" {
+		if !val.IsEmpty() {
 			nonEmpty++
 		}
 	}
@@ -352,7 +352,7 @@ func TestSoundpackValidate_ValidJSON(t *testing.T) {
 		Name:        "test-valid",
 		Description: "Test soundpack",
 		Version:     "1.0.0",
-		Mappings:    make(map[string]string),
+		Mappings:    make(map[string]soundpack.MappingValue),
 	}
 
 	// Get all known keys to populate mappings
@@ -361,11 +361,11 @@ func TestSoundpackValidate_ValidJSON(t *testing.T) {
 		t.Fatalf("ExtractAllSoundKeys() failed: %v", err)
 	}
 	for _, key := range keys {
-		spFile.Mappings[key] = ""
+		spFile.Mappings[key] = soundpack.SingleFile("")
 	}
 	// Set a few to real files
-	spFile.Mappings["loading/bash-start.wav"] = wav1
-	spFile.Mappings["success/bash-success.wav"] = wav2
+	spFile.Mappings["loading/bash-start.wav"] = soundpack.SingleFile(wav1)
+	spFile.Mappings["success/bash-success.wav"] = soundpack.SingleFile(wav2)
 
 	jsonData, err := json.MarshalIndent(spFile, "", "  ")
 	if err != nil {
@@ -408,8 +408,8 @@ func TestSoundpackValidate_RelativeJSONMappings(t *testing.T) {
 		Name:        "relative-valid",
 		Description: "Test soundpack with relative mappings",
 		Version:     "1.0.0",
-		Mappings: map[string]string{
-			"loading/bash-start.wav": filepath.Join("sounds", "click.wav"),
+		Mappings: map[string]soundpack.MappingValue{
+			"loading/bash-start.wav": soundpack.SingleFile(filepath.Join("sounds", "click.wav")),
 		},
 	}
 
@@ -445,10 +445,10 @@ func TestSoundpackValidate_MissingFiles(t *testing.T) {
 		Name:        "broken-pack",
 		Description: "Pack with broken references",
 		Version:     "1.0.0",
-		Mappings: map[string]string{
-			"loading/bash-start.wav":   filepath.Join(tmpDir, "nonexistent", "missing.wav"),
-			"success/bash-success.wav": filepath.Join(tmpDir, "also", "missing.wav"),
-			"default.wav":              "",
+		Mappings: map[string]soundpack.MappingValue{
+			"loading/bash-start.wav":   soundpack.SingleFile(filepath.Join(tmpDir, "nonexistent", "missing.wav")),
+			"success/bash-success.wav": soundpack.SingleFile(filepath.Join(tmpDir, "also", "missing.wav")),
+			"default.wav":              soundpack.SingleFile(""),
 		},
 	}
 
@@ -485,7 +485,7 @@ func TestSoundpackValidate_EmptyMappings(t *testing.T) {
 		Name:        "empty-pack",
 		Description: "Empty soundpack template",
 		Version:     "1.0.0",
-		Mappings:    make(map[string]string),
+		Mappings:    make(map[string]soundpack.MappingValue),
 	}
 
 	keys, err := ExtractAllSoundKeys()
@@ -493,7 +493,7 @@ func TestSoundpackValidate_EmptyMappings(t *testing.T) {
 		t.Fatalf("ExtractAllSoundKeys() failed: %v", err)
 	}
 	for _, key := range keys {
-		spFile.Mappings[key] = ""
+		spFile.Mappings[key] = soundpack.SingleFile("")
 	}
 
 	jsonData, err := json.MarshalIndent(spFile, "", "  ")
@@ -542,15 +542,15 @@ func TestSoundpackValidate_CoverageCalculation(t *testing.T) {
 		Name:        "coverage-test",
 		Description: "Coverage test pack",
 		Version:     "1.0.0",
-		Mappings:    make(map[string]string),
+		Mappings:    make(map[string]soundpack.MappingValue),
 	}
 	for _, key := range keys {
-		spFile.Mappings[key] = ""
+		spFile.Mappings[key] = soundpack.SingleFile("")
 	}
 
 	// Fill first 10 keys with real files
 	for i := 0; i < 10 && i < len(keys); i++ {
-		spFile.Mappings[keys[i]] = wavFiles[i]
+		spFile.Mappings[keys[i]] = soundpack.SingleFile(wavFiles[i])
 	}
 
 	jsonData, err := json.MarshalIndent(spFile, "", "  ")
@@ -680,9 +680,9 @@ func TestSoundpackValidate_FormatCheck(t *testing.T) {
 		Name:        "format-test",
 		Description: "Format check test",
 		Version:     "1.0.0",
-		Mappings: map[string]string{
-			"loading/bash-start.wav": txtFile,
-			"default.wav":            "",
+		Mappings: map[string]soundpack.MappingValue{
+			"loading/bash-start.wav": soundpack.SingleFile(txtFile),
+			"default.wav":            soundpack.SingleFile(""),
 		},
 	}
 
@@ -783,7 +783,7 @@ func createTestJSONSoundpack(t *testing.T, dir, name string) string {
 		Name:        name,
 		Description: "Test soundpack for install",
 		Version:     "1.0.0",
-		Mappings:    map[string]string{},
+		Mappings:    map[string]soundpack.MappingValue{},
 	}
 	jsonData, err := json.MarshalIndent(spFile, "", "  ")
 	if err != nil {
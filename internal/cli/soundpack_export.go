@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"claudio.click/internal/config"
+	"claudio.click/internal/soundpack"
+	"github.com/spf13/cobra"
+)
+
+// newSoundpackExportCommand creates the soundpack export subcommand
+func newSoundpackExportCommand() *cobra.Command {
+	var output string
+	var format string
+
+	exportCmd := &cobra.Command{
+		Use:   "export <name>",
+		Short: "Export a soundpack as a portable .claudiopack archive",
+		Long: `Export an installed, embedded, or on-disk soundpack as a single .claudiopack file.
+
+The archive holds a JSON manifest, every audio file the pack maps, and SHA-256
+checksums. Absolute mapping paths are rewritten into relative ones, so the
+archive installs on any machine with 'claudio soundpack install'.
+
+Examples:
+  claudio soundpack export startrek-bridge -o startrek-bridge.claudiopack
+  claudio soundpack export ./my-pack --format tar.gz`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSoundpackExport(cmd, args[0], output, format)
+		},
+	}
+
+	exportCmd.Flags().StringVarP(&output, "output", "o", "", "Archive to write (default: <name>.claudiopack)")
+	exportCmd.Flags().StringVar(&format, "format", soundpack.ArchiveFormatZip, "Archive format: "+strings.Join(soundpack.GetArchiveFormats(), " or "))
+
+	return exportCmd
+}
+
+// runSoundpackExport executes the soundpack export command
+func runSoundpackExport(cmd *cobra.Command, name, output, format string) error {
+	slog.Debug("running soundpack export", "name", name, "output", output, "format", format)
+
+	source, err := loadSoundpackForExport(name)
+	if err != nil {
+		return err
+	}
+	manifest, files, err := soundpack.PlanArchive(source)
+	if err != nil {
+		return fmt.Errorf("cannot export soundpack: %w", err)
+	}
+
+	if output == "" {
+		output = manifest.Name + soundpack.ArchiveExtension
+	}
+	// Write next to the target and rename, so a failed export never leaves
+	// a truncated archive behind.
+	if err := os.MkdirAll(filepath.Dir(output), 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(output), ".claudiopack-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	tmpPath := tmp.Name()
+	if err := soundpack.WriteArchive(tmp, format, manifest, files); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to close archive: %w", err)
+	}
+	if err := os.Rename(tmpPath, output); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	slog.Info("soundpack exported", "name", manifest.Name, "output", output, "files", len(files))
+	cmd.Printf("Exported soundpack '%s' (%d mappings, %d files) to %s\n",
+		manifest.Name, len(manifest.Mappings), len(files), output)
+	return nil
+}
+
+// loadSoundpackForExport finds the pack called name, or at path name, and
+// returns it with every mapping resolved to an absolute path.
+func loadSoundpackForExport(name string) (soundpack.JSONSoundpackFile, error) {
+	packPath, packType, err := findSoundpackPath(name)
+	if err != nil {
+		return soundpack.JSONSoundpackFile{}, err
+	}
+
+	if packType == "embedded" {
+		return loadEmbeddedSoundpackForExport(name)
+	}
+
+	info, err := os.Stat(packPath)
+	if err != nil {
+		return soundpack.JSONSoundpackFile{}, fmt.Errorf("cannot access soundpack path: %w", err)
+	}
+	if info.IsDir() {
+		return loadDirectorySoundpackForExport(packPath)
+	}
+	if !strings.HasSuffix(strings.ToLower(packPath), ".json") {
+		return soundpack.JSONSoundpackFile{}, fmt.Errorf("unsupported soundpack path: %s", packPath)
+	}
+
+	// The pack is the user's own, so absolute paths are allowed here;
+	// they are exactly what export turns into relative ones.
+	spFile, err := soundpack.PeekJSONSoundpackFromFile(packPath)
+	if err != nil {
+		return soundpack.JSONSoundpackFile{}, err
+	}
+	soundpack.ResolveJSONSoundpackMappings(spFile, filepath.Dir(packPath))
+	return *spFile, nil
+}
+
+// findSoundpackPath returns where the soundpack name lives. A name that is
+// an existing path is used as it is, with an empty type; otherwise the
+// name is looked up among the discovered packs.
+func findSoundpackPath(name string) (packPath, packType string, err error) {
+	if _, err := os.Stat(name); err == nil {
+		return name, "", nil
+	}
+	packs, err := discoverSoundpacks()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to discover soundpacks: %w", err)
+	}
+	var available []string
+	for _, p := range packs {
+		if p.Name == name {
+			return p.Path, p.Type, nil
+		}
+		available = append(available, p.Name)
+	}
+	sort.Strings(available)
+	return "", "", fmt.Errorf("soundpack '%s' not found. Available soundpacks: %s", name, strings.Join(available, ", "))
+}
+
+// loadEmbeddedSoundpackForExport loads a built-in platform pack, whose
+// mappings point at this machine's system sounds.
+func loadEmbeddedSoundpackForExport(name string) (soundpack.JSONSoundpackFile, error) {
+	identifier, ok := embeddedPlatformSoundpackIdentifier(name)
+	if !ok {
+		return soundpack.JSONSoundpackFile{}, fmt.Errorf("soundpack '%s' is not a built-in pack", name)
+	}
+	filename := strings.TrimPrefix(identifier, "embedded:")
+	data, err := config.GetEmbeddedPlatformSoundpackData(filename)
+	if err != nil {
+		return soundpack.JSONSoundpackFile{}, fmt.Errorf("failed to read embedded platform soundpack: %w", err)
+	}
+	spFile, err := soundpack.PeekJSONSoundpackFromBytes(data)
+	if err != nil {
+		return soundpack.JSONSoundpackFile{}, err
+	}
+	mapper, err := loadEmbeddedPlatformSoundpack(identifier)
+	if err != nil {
+		return soundpack.JSONSoundpackFile{}, err
+	}
+	jsonMapper, ok := mapper.(*soundpack.JSONMapper)
+	if !ok {
+		return soundpack.JSONSoundpackFile{}, fmt.Errorf("embedded soundpack '%s' is not a JSON pack", name)
+	}
+	spFile.Mappings = jsonMapper.Mappings()
+	return *spFile, nil
+}
+
+// loadDirectorySoundpackForExport maps every sound in a directory pack to
+// the files the resolver would play for it: the key's own file, or its
+// numbered variations.
+func loadDirectorySoundpackForExport(dirPath string) (soundpack.JSONSoundpackFile, error) {
+	name := filepath.Base(dirPath)
+	mapper := soundpack.NewDirectoryMapper(name, []string{dirPath})
+	resolver := soundpack.NewSoundpackResolver(mapper)
+
+	keys := make(map[string]struct{})
+	walkErr := filepath.Walk(dirPath, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if !soundpack.IsAudioFile(p) {
+			return nil
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("directory soundpack contains symlinked audio file: %s", p)
+		}
+		rel, err := filepath.Rel(dirPath, p)
+		if err != nil {
+			return nil
+		}
+		key := filepath.ToSlash(rel)
+		if base, ok := soundpack.VariationBaseKey(key); ok {
+			key = base
+		}
+		// Sound keys name .wav files; other formats stand in for them.
+		keys[strings.TrimSuffix(key, path.Ext(key))+".wav"] = struct{}{}
+		return nil
+	})
+	if walkErr != nil {
+		return soundpack.JSONSoundpackFile{}, fmt.Errorf("failed to scan directory soundpack: %w", walkErr)
+	}
+
+	mappings := make(map[string]soundpack.MappingValue, len(keys))
+	for key := range keys {
+		if variations, ok := mapper.(soundpack.VariationMapper).MapVariations(key); ok {
+			mappings[key] = variations
+			continue
+		}
+		file, err := resolver.ResolveSound(key)
+		if err != nil {
+			return soundpack.JSONSoundpackFile{}, fmt.Errorf("failed to resolve %s: %w", key, err)
+		}
+		mappings[key] = soundpack.SingleFile(file)
+	}
+	sp := soundpack.JSONSoundpackFile{Name: name, Mappings: mappings}
+
+	loudness, err := soundpack.ReadLoudnessFile(filepath.Join(dirPath, soundpack.LoudnessFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return soundpack.JSONSoundpackFile{}, err
+	}
+	if loudness != nil {
+		sp.Loudness = loudness
+		soundpack.ResolveJSONSoundpackMappings(&sp, dirPath)
+	}
+	return sp, nil
+}
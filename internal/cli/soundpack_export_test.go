@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSoundpackExport_InstallRoundTrip exports a pack whose mappings are
+// absolute paths, removes the originals, installs the archive, and checks
+// a hook plays the installed copy.
+func TestSoundpackExport_InstallRoundTrip(t *testing.T) {
+	dataDir, _, cleanup := setupInstallTestEnv(t)
+	defer cleanup()
+
+	sounds := t.TempDir()
+	success := filepath.Join(sounds, "machine-specific", "ok.wav")
+	createDummyWAV(t, success)
+	defaultSound := filepath.Join(sounds, "fallback.wav")
+	createDummyWAV(t, defaultSound)
+
+	packDir := t.TempDir()
+	manifest := map[string]any{
+		"name":    "absolute-pack",
+		"version": "1.0.0",
+		"mappings": map[string]any{
+			"success/bash-success.wav": success,
+			"default.wav":              map[string]any{"files": []string{defaultSound, success}, "mode": "round-robin"},
+		},
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatalf("marshal soundpack: %v", err)
+	}
+	packPath := filepath.Join(packDir, "absolute-pack.json")
+	if err := os.WriteFile(packPath, data, 0o644); err != nil {
+		t.Fatalf("write soundpack: %v", err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "absolute-pack.claudiopack")
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	if code := NewCLI().Run([]string{"claudio", "soundpack", "export", packPath, "-o", archive}, nil, stdout, stderr); code != 0 {
+		t.Fatalf("export exit code %d, stderr: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Exported soundpack 'absolute-pack'") {
+		t.Errorf("unexpected export output: %s", stdout.String())
+	}
+
+	// The archive must stand alone.
+	if err := os.RemoveAll(sounds); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := NewCLI().Run([]string{"claudio", "soundpack", "install", archive, "--default"}, nil, stdout, stderr); code != 0 {
+		t.Fatalf("install exit code %d, stdout: %s, stderr: %s", code, stdout.String(), stderr.String())
+	}
+	installDir := filepath.Join(dataDir, "claudio", "soundpacks", "absolute-pack")
+	if !strings.Contains(stdout.String(), filepath.Join(installDir, "absolute-pack.json")) {
+		t.Errorf("unexpected install output: %s", stdout.String())
+	}
+
+	installed, err := os.ReadFile(filepath.Join(installDir, "absolute-pack.json"))
+	if err != nil {
+		t.Fatalf("read installed manifest: %v", err)
+	}
+	if strings.Contains(string(installed), sounds) {
+		t.Errorf("installed manifest still holds absolute paths:\n%s", installed)
+	}
+
+	hook := `{"session_id":"export","cwd":"/tmp","hook_event_name":"PostToolUse","tool_name":"Bash","tool_input":{"command":"ls"},"tool_response":{"stdout":"ok","stderr":"","interrupted":false}}`
+	got := runHookForPlays(t, []string{"claudio"}, hook)
+	if len(got) != 1 || !strings.HasPrefix(got[0], installDir) {
+		t.Errorf("hook should play from the installed archive, got %v", got)
+	}
+}
+
+func TestSoundpackInstall_RejectsCorruptArchive(t *testing.T) {
+	dataDir, _, cleanup := setupInstallTestEnv(t)
+	defer cleanup()
+
+	archive := filepath.Join(t.TempDir(), "broken.claudiopack")
+	if err := os.WriteFile(archive, []byte("PK not really a zip"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	if code := NewCLI().Run([]string{"claudio", "soundpack", "install", archive}, nil, stdout, stderr); code == 0 {
+		t.Fatal("a corrupt archive should fail to install")
+	}
+	entries, _ := os.ReadDir(filepath.Join(dataDir, "claudio", "soundpacks"))
+	if len(entries) != 0 {
+		t.Errorf("a failed install left %d entries behind", len(entries))
+	}
+}
@@ -51,12 +51,16 @@ func newSoundpackAddCommand() *cobra.Command {
 	var replace bool
 
 	addCmd := &cobra.Command{
-		Use:   "add <git-url>",
+		Use:   "add <git-url|name>",
 		Short: "Add a git-backed soundpack",
 		Long: `Clone a soundpack from a git repository into Claudio's managed data directory.
 
 The cloned soundpack remains updateable with 'claudio soundpack update'. The
-playable soundpack path is added to config soundpack_paths.`,
+playable soundpack path is added to config soundpack_paths.
+
+A bare name is looked up in the catalogs listed in config soundpack_catalogs
+(see 'claudio soundpack search'), which supply its repository, ref, and subdir.
+When the catalog pins a checksum, the checked-out commit must match it.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runSoundpackAdd(cmd, args[0], name, ref, subdir, setDefault, skipValidate, replace)
@@ -147,6 +151,29 @@ func runSoundpackAdd(cmd *cobra.Command, source, requestedName, ref, subdir stri
 		return err
 	}
 
+	checksum := ""
+	if isCatalogSoundpackName(source) {
+		entry, err := findSoundpackCatalogEntry(source)
+		if err != nil {
+			return err
+		}
+		slog.Debug("resolved soundpack from catalog", "name", entry.Name, "catalog", entry.Catalog, "url", entry.URL)
+		source = entry.URL
+		if requestedName == "" {
+			requestedName = entry.Name
+		}
+		if ref == "" {
+			ref = entry.Ref
+			checksum = entry.Checksum
+		} else if entry.Checksum != "" {
+			// The checksum pins the catalog's ref; an explicit --ref opts out.
+			cmd.PrintErrf("warning: --ref %s overrides the catalog's ref; its checksum %s is not verified\n", ref, entry.Checksum)
+		}
+		if subdir == "" {
+			subdir = entry.Subdir
+		}
+	}
+
 	url, err := expandGitSoundpackSource(source)
 	if err != nil {
 		return err
@@ -184,7 +211,7 @@ func runSoundpackAdd(cmd *cobra.Command, source, requestedName, ref, subdir stri
 	if err := os.MkdirAll(filepath.Dir(clonePath), 0755); err != nil {
 		return fmt.Errorf("failed to create git soundpack directory: %w", err)
 	}
-	if _, err := runGit("", "clone", url, clonePath); err != nil {
+	if _, err := runGit("", "clone", "--", url, clonePath); err != nil {
 		return fmt.Errorf("failed to clone soundpack repo: %w", err)
 	}
 	if ref != "" {
@@ -193,6 +220,17 @@ func runSoundpackAdd(cmd *cobra.Command, source, requestedName, ref, subdir stri
 			return fmt.Errorf("failed to check out ref %q: %w", ref, err)
 		}
 	}
+	if checksum != "" {
+		commit, err := currentGitCommit(clonePath)
+		if err != nil {
+			_ = removeManagedGitClone(clonePath)
+			return err
+		}
+		if !commitMatchesChecksum(commit, checksum) {
+			_ = removeManagedGitClone(clonePath)
+			return fmt.Errorf("checksum mismatch for %q: catalog pins %s, got %s", name, checksum, commit)
+		}
+	}
 
 	playablePath, err := determineGitSoundpackPath(clonePath, name, subdir)
 	if err != nil {
@@ -119,12 +119,7 @@ func discoverEmbeddedSoundpacks() ([]soundpackInfo, error) {
 		}
 
 		// Count non-empty mapping values
-		soundCount := 0
-		for _, val := range spFile.Mappings {
-			if val != "" {
-				soundCount++
-			}
-		}
+		soundCount := countNonEmptyMappings(spFile.Mappings)
 
 		name := strings.TrimSuffix(file, ".json")
 		slog.Debug("discovered embedded soundpack", "name", name, "sounds", soundCount)
@@ -292,8 +287,7 @@ func countAudioFiles(dir string) int {
 		if info.IsDir() {
 			return nil
 		}
-		ext := strings.ToLower(filepath.Ext(path))
-		if ext == ".wav" || ext == ".mp3" || ext == ".aiff" {
+		if soundpack.IsAudioFile(path) {
 			count++
 		}
 		return nil
@@ -302,10 +296,10 @@ func countAudioFiles(dir string) int {
 }
 
 // countNonEmptyMappings counts how many mapping values are non-empty
-func countNonEmptyMappings(mappings map[string]string) int {
+func countNonEmptyMappings(mappings map[string]soundpack.MappingValue) int {
 	count := 0
 	for _, val := range mappings {
-		if val != "" {
+		if !val.IsEmpty() {
 			count++
 		}
 	}
@@ -63,9 +63,9 @@ func runSoundpackInit(cmd *cobra.Command, name, dir string, fromPlatform bool) e
 	slog.Info("extracted sound keys", "count", len(keys))
 
 	// Build mappings
-	mappings := make(map[string]string, len(keys))
+	mappings := make(map[string]soundpack.MappingValue, len(keys))
 	for _, key := range keys {
-		mappings[key] = ""
+		mappings[key] = soundpack.SingleFile("")
 	}
 
 	// If --from-platform, pre-fill from current platform's embedded soundpack
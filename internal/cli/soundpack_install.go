@@ -20,19 +20,26 @@ func newSoundpackInstallCommand() *cobra.Command {
 
 	installCmd := &cobra.Command{
 		Use:   "install <path>",
-		Short: "Install a soundpack from a JSON file or directory",
+		Short: "Install a soundpack from a JSON file, directory, or .claudiopack archive",
 		Long: `Install a soundpack by copying it to the XDG data directory and updating config.
 
 JSON soundpacks are copied to <XDG_DATA_HOME>/claudio/<name>.json
 Directory soundpacks are copied to <XDG_DATA_HOME>/claudio/soundpacks/<name>/
+.claudiopack archives (from 'claudio soundpack export') are checked against
+their SHA-256 checksums and extracted to <XDG_DATA_HOME>/claudio/soundpacks/<name>/
 
 The installed path is added to config soundpack_paths (idempotent).
 Use --default to also set the soundpack as the default.
 
+Unless the pack ships its own loudness gains, every sound is measured and
+the gains that level them to -23 LUFS are stored with the installed copy
+(see 'claudio soundpack normalize').
+
 Examples:
   claudio soundpack install my-pack.json
   claudio soundpack install /path/to/soundpack-dir
   claudio soundpack install my-pack.json --default
+  claudio soundpack install startrek-bridge.claudiopack
   claudio soundpack install my-pack.json --skip-validate`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -58,6 +65,9 @@ func runSoundpackInstall(cmd *cobra.Command, srcPath string, setDefault, skipVal
 	}
 
 	isDir := srcInfo.IsDir()
+	if !isDir && strings.HasSuffix(strings.ToLower(srcPath), soundpack.ArchiveExtension) {
+		return runSoundpackArchiveInstall(cmd, srcPath, setDefault, skipValidate)
+	}
 
 	// Determine soundpack name
 	var name string
@@ -128,6 +138,7 @@ func runSoundpackInstall(cmd *cobra.Command, srcPath string, setDefault, skipVal
 		}
 	}
 	slog.Info("soundpack copied successfully", "install_path", installPath)
+	storeInstalledLoudness(cmd, installPath)
 
 	// Update config
 	if err := updateConfigForInstall(installPath, name, setDefault); err != nil {
@@ -139,6 +150,69 @@ func runSoundpackInstall(cmd *cobra.Command, srcPath string, setDefault, skipVal
 	return nil
 }
 
+// runSoundpackArchiveInstall installs a .claudiopack archive. It is
+// extracted into a staging directory beside the target, validated, and
+// only then swapped into place, so a bad archive never replaces a working
+// install.
+func runSoundpackArchiveInstall(cmd *cobra.Command, archivePath string, setDefault, skipValidate bool) error {
+	soundpacksDir := filepath.Join(xdg.DataHome, "claudio", "soundpacks")
+	if err := os.MkdirAll(soundpacksDir, 0755); err != nil {
+		return fmt.Errorf("failed to create soundpack directory: %w", err)
+	}
+	stagingDir, err := os.MkdirTemp(soundpacksDir, ".install-*")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(stagingDir)
+
+	spFile, err := soundpack.ExtractArchive(archivePath, stagingDir)
+	if err != nil {
+		slog.Error("failed to extract soundpack archive", "path", archivePath, "error", err)
+		return fmt.Errorf("failed to extract soundpack archive: %w", err)
+	}
+	name := spFile.Name
+	stagedManifest := filepath.Join(stagingDir, name+".json")
+
+	// The loader is the trust boundary for mapping values; it runs even
+	// with --skip-validate, which only skips the coverage report.
+	if _, err := soundpack.LoadJSONSoundpack(stagedManifest); err != nil {
+		slog.Error("archive soundpack failed to load", "error", err)
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	if !skipValidate {
+		if _, valErr := validateJSONSoundpackFile(stagedManifest); valErr != nil {
+			slog.Error("validation failed", "error", valErr)
+			return fmt.Errorf("validation failed: %w", valErr)
+		}
+		slog.Info("soundpack validation passed")
+	}
+
+	// MkdirTemp creates the staging directory private; the installed pack
+	// gets the usual permissions.
+	if err := os.Chmod(stagingDir, 0755); err != nil {
+		return fmt.Errorf("failed to set soundpack permissions: %w", err)
+	}
+	installDir := filepath.Join(soundpacksDir, name)
+	if err := os.RemoveAll(installDir); err != nil {
+		return fmt.Errorf("failed to replace existing soundpack: %w", err)
+	}
+	if err := os.Rename(stagingDir, installDir); err != nil {
+		slog.Error("failed to move soundpack into place", "src", stagingDir, "dst", installDir, "error", err)
+		return fmt.Errorf("failed to install soundpack: %w", err)
+	}
+	installPath := filepath.Join(installDir, name+".json")
+	slog.Info("soundpack archive installed", "install_path", installPath)
+	storeInstalledLoudness(cmd, installPath)
+
+	if err := updateConfigForInstall(installPath, name, setDefault); err != nil {
+		slog.Error("failed to update config", "error", err)
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+
+	cmd.Printf("Installed soundpack '%s' to %s\n", name, installPath)
+	return nil
+}
+
 // updateConfigForInstall loads the config, adds the install path, optionally sets default, and saves.
 func updateConfigForInstall(installPath, name string, setDefault bool) error {
 	slog.Debug("updating config for install", "install_path", installPath, "name", name, "set_default", setDefault)
@@ -0,0 +1,358 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"claudio.click/internal/audio/loudness"
+	"claudio.click/internal/soundpack"
+	"github.com/spf13/cobra"
+)
+
+// newSoundpackNormalizeCommand creates the soundpack normalize subcommand
+func newSoundpackNormalizeCommand() *cobra.Command {
+	var target string
+
+	normalizeCmd := &cobra.Command{
+		Use:   "normalize <name>",
+		Short: "Measure a soundpack's loudness and store per-sound gains",
+		Long: `Measure the loudness of every sound in a soundpack and store the gain that
+brings each one to a common target, so no sound jumps out at playback.
+
+Loudness is the EBU R128 integrated loudness (ITU-R BS.1770). A gain that
+would boost a sound is limited so its peak stays below -1 dBFS. JSON packs
+store the gains in their manifest's "loudness" field; directory packs store
+them in loudness.json at the pack root. Both audio backends apply them.
+
+'claudio soundpack install' measures packs that carry no gains of their own,
+at the default target of -23 LUFS.
+
+Examples:
+  claudio soundpack normalize my-pack
+  claudio soundpack normalize ./my-pack.json --target -18LUFS`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			targetLUFS, err := parseLUFS(target)
+			if err != nil {
+				return err
+			}
+			return runSoundpackNormalize(cmd, args[0], targetLUFS)
+		},
+	}
+
+	normalizeCmd.Flags().StringVar(&target, "target", formatLUFS(loudness.DefaultTargetLUFS), "Target integrated loudness, e.g. -23LUFS")
+
+	return normalizeCmd
+}
+
+// runSoundpackNormalize executes the soundpack normalize command
+func runSoundpackNormalize(cmd *cobra.Command, name string, targetLUFS float64) error {
+	slog.Debug("running soundpack normalize", "name", name, "target_lufs", targetLUFS)
+
+	if !loudness.Available() {
+		return fmt.Errorf("cannot measure loudness: %w (this build has no audio decoders)", loudness.ErrNoDecoder)
+	}
+	packPath, packType, err := findSoundpackPath(name)
+	if err != nil {
+		return err
+	}
+	if packType == "embedded" {
+		return fmt.Errorf("soundpack '%s' is built in and cannot store gains; create an editable copy with 'claudio soundpack init <name> --from-platform'", name)
+	}
+	pack, err := openLoudnessPack(packPath)
+	if err != nil {
+		return err
+	}
+
+	results, failed, err := measurePackLoudness(cmd.Context(), pack, targetLUFS, cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("no sounds in '%s' could be measured", name)
+	}
+	cmd.Printf("Loudness (target %s):\n", formatLUFS(targetLUFS))
+	printLoudnessResults(cmd, results)
+
+	written, err := pack.writeGains(targetLUFS, results)
+	if err != nil {
+		return err
+	}
+	cmd.Println()
+	if failed > 0 {
+		cmd.Printf("Stored gains for %d sounds in %s (%d could not be measured).\n", written, pack.gainsPath(), failed)
+	} else {
+		cmd.Printf("Stored gains for %d sounds in %s.\n", written, pack.gainsPath())
+	}
+	return nil
+}
+
+// loudnessPack is a soundpack on disk whose sounds can be measured and
+// whose gains can be stored.
+type loudnessPack struct {
+	path   string              // manifest file, or root of a directory pack
+	isDir  bool                // gains go in LoudnessFileName rather than the manifest
+	files  map[string]string   // file name as the pack names it -> absolute path
+	stored *soundpack.Loudness // gains already stored, nil if none
+}
+
+// openLoudnessPack lists the sound files of the JSON or directory pack at
+// path. JSON files keep the names their mappings give them, directory
+// files their path under the pack root, which is how gains are keyed.
+func openLoudnessPack(path string) (*loudnessPack, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access soundpack path: %w", err)
+	}
+	pack := &loudnessPack{path: path, isDir: info.IsDir(), files: make(map[string]string)}
+
+	if !pack.isDir {
+		if !strings.HasSuffix(strings.ToLower(path), ".json") {
+			return nil, fmt.Errorf("unsupported soundpack path: %s", path)
+		}
+		spFile, err := soundpack.PeekJSONSoundpackFromFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load JSON soundpack: %w", err)
+		}
+		for _, mapping := range spFile.Mappings {
+			for _, file := range mapping.Files {
+				if file == "" || soundpack.IsSpeechTemplate(file) {
+					continue
+				}
+				abs := file
+				if !filepath.IsAbs(abs) {
+					abs = filepath.Clean(filepath.Join(filepath.Dir(path), file))
+				}
+				pack.files[file] = abs
+			}
+		}
+		pack.stored = spFile.Loudness
+		return pack, nil
+	}
+
+	walkErr := filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !soundpack.IsAudioFile(p) {
+			return nil
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("directory soundpack contains symlinked audio file: %s", p)
+		}
+		rel, err := filepath.Rel(path, p)
+		if err != nil {
+			return nil
+		}
+		pack.files[filepath.ToSlash(rel)] = p
+		return nil
+	})
+	if walkErr != nil {
+		return nil, fmt.Errorf("failed to scan directory soundpack: %w", walkErr)
+	}
+	stored, err := soundpack.ReadLoudnessFile(pack.gainsPath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	pack.stored = stored
+	return pack, nil
+}
+
+// gainsPath returns the file the pack's gains are stored in.
+func (p *loudnessPack) gainsPath() string {
+	if p.isDir {
+		return filepath.Join(p.path, soundpack.LoudnessFileName)
+	}
+	return p.path
+}
+
+// hasGains reports whether the pack already stores gains.
+func (p *loudnessPack) hasGains() bool {
+	return p.stored != nil && len(p.stored.Gains) > 0
+}
+
+// writeGains stores the gain of every measured sound that is not silent
+// and returns how many it stored.
+func (p *loudnessPack) writeGains(targetLUFS float64, results []loudnessResult) (int, error) {
+	stored := &soundpack.Loudness{TargetLUFS: targetLUFS, Gains: make(map[string]float64, len(results))}
+	for _, r := range results {
+		if !r.measurement.IsSilent() {
+			stored.Gains[r.name] = r.gain
+		}
+	}
+	var err error
+	if p.isDir {
+		err = soundpack.WriteLoudnessFile(p.gainsPath(), stored)
+	} else {
+		err = soundpack.WriteManifestLoudness(p.path, stored)
+	}
+	if err != nil {
+		return 0, err
+	}
+	slog.Info("stored soundpack loudness gains", "path", p.gainsPath(), "target_lufs", targetLUFS, "gains", len(stored.Gains))
+	p.stored = stored
+	return len(stored.Gains), nil
+}
+
+// loudnessResult is the measured loudness of one sound and the gain that
+// brings it to the target.
+type loudnessResult struct {
+	name        string
+	measurement loudness.Measurement
+	gain        float64
+}
+
+// measurePackLoudness measures every sound in pack, sorted by name. Files
+// that cannot be decoded are reported to errOut and counted rather than
+// stopping the rest.
+func measurePackLoudness(ctx context.Context, pack *loudnessPack, targetLUFS float64, errOut io.Writer) (results []loudnessResult, failed int, err error) {
+	names := make([]string, 0, len(pack.files))
+	for name := range pack.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return results, failed, err
+		}
+		m, err := loudness.MeasureFile(ctx, pack.files[name])
+		if errors.Is(err, loudness.ErrNoDecoder) {
+			return nil, 0, err
+		}
+		if err != nil {
+			slog.Debug("failed to measure sound", "file", pack.files[name], "error", err)
+			fmt.Fprintf(errOut, "warning: cannot measure %s: %v\n", name, err)
+			failed++
+			continue
+		}
+		results = append(results, loudnessResult{name: name, measurement: m, gain: loudness.GainFor(m, targetLUFS)})
+	}
+	return results, failed, nil
+}
+
+// printLoudnessResults prints one line per measured sound.
+func printLoudnessResults(cmd *cobra.Command, results []loudnessResult) {
+	for _, r := range results {
+		if r.measurement.IsSilent() {
+			cmd.Printf("  %s: silent\n", r.name)
+			continue
+		}
+		cmd.Printf("  %s: %.1f LUFS, peak %.1f dBFS, gain %+.1f dB\n",
+			r.name, r.measurement.Integrated, r.measurement.Peak, r.gain)
+	}
+}
+
+// parseLUFS parses a loudness target such as "-23LUFS", "-23 LUFS" or "-23".
+func parseLUFS(value string) (float64, error) {
+	trimmed := strings.TrimSpace(value)
+	for _, unit := range []string{"LUFS", "LKFS"} {
+		if len(trimmed) >= len(unit) && strings.EqualFold(trimmed[len(trimmed)-len(unit):], unit) {
+			trimmed = strings.TrimSpace(trimmed[:len(trimmed)-len(unit)])
+			break
+		}
+	}
+	target, err := strconv.ParseFloat(trimmed, 64)
+	if err != nil || math.IsNaN(target) || target > 0 || target < -70 {
+		return 0, fmt.Errorf("invalid loudness target %q: want a value from -70 to 0 LUFS, e.g. -23LUFS", value)
+	}
+	return target, nil
+}
+
+// formatLUFS renders a loudness target the way --target accepts it.
+func formatLUFS(target float64) string {
+	return strconv.FormatFloat(target, 'f', -1, 64) + "LUFS"
+}
+
+// printSoundpackLoudness measures the pack at path for 'soundpack
+// validate'. It only reports: a sound that cannot be measured is a
+// warning, and nothing is stored.
+func printSoundpackLoudness(cmd *cobra.Command, path string) {
+	cmd.Println()
+	if !loudness.Available() {
+		cmd.Println("Loudness: not measured (this build has no audio decoders)")
+		return
+	}
+	pack, err := openLoudnessPack(path)
+	if err != nil {
+		cmd.Printf("Loudness: not measured (%v)\n", err)
+		return
+	}
+	targetLUFS := loudness.DefaultTargetLUFS
+	if pack.stored != nil && pack.stored.TargetLUFS != 0 {
+		targetLUFS = pack.stored.TargetLUFS
+	}
+	results, _, err := measurePackLoudness(cmd.Context(), pack, targetLUFS, cmd.ErrOrStderr())
+	if err != nil {
+		cmd.Printf("Loudness: not measured (%v)\n", err)
+		return
+	}
+	if len(results) == 0 {
+		return
+	}
+
+	cmd.Printf("Loudness (target %s):\n", formatLUFS(targetLUFS))
+	printLoudnessResults(cmd, results)
+
+	// Stored gains are rounded to 0.1 dB; allow for that and for decoder
+	// differences before calling one outdated.
+	outdated := 0
+	for _, r := range results {
+		if r.measurement.IsSilent() {
+			continue
+		}
+		stored, ok := 0.0, false
+		if pack.stored != nil {
+			stored, ok = pack.stored.Gains[r.name]
+		}
+		if !ok || math.Abs(stored-r.gain) > 0.5 {
+			outdated++
+		}
+	}
+	if outdated > 0 {
+		cmd.Printf("  %d sound(s) have no stored gain or an outdated one; run 'claudio soundpack normalize %s' to store them\n", outdated, path)
+	}
+}
+
+// storeInstalledLoudness measures a freshly installed pack and stores its
+// gains at the default target. A pack that ships gains keeps them. It is
+// best effort: the install stands even if nothing can be measured.
+func storeInstalledLoudness(cmd *cobra.Command, installPath string) {
+	if !loudness.Available() {
+		slog.Debug("skipping loudness analysis: no audio decoders in this build")
+		return
+	}
+	pack, err := openLoudnessPack(installPath)
+	if err != nil {
+		slog.Warn("skipping loudness analysis", "path", installPath, "error", err)
+		return
+	}
+	if pack.hasGains() {
+		slog.Debug("installed soundpack ships its own loudness gains", "path", installPath, "gains", len(pack.stored.Gains))
+		return
+	}
+
+	results, failed, err := measurePackLoudness(cmd.Context(), pack, loudness.DefaultTargetLUFS, io.Discard)
+	if err != nil || len(results) == 0 {
+		slog.Warn("could not measure installed soundpack", "path", installPath, "failed", failed, "error", err)
+		return
+	}
+	written, err := pack.writeGains(loudness.DefaultTargetLUFS, results)
+	if err != nil {
+		cmd.PrintErrf("warning: failed to store loudness gains: %v\n", err)
+		return
+	}
+	cmd.Printf("Levelled %d sounds to %s.\n", written, formatLUFS(loudness.DefaultTargetLUFS))
+}
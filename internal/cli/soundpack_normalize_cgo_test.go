@@ -0,0 +1,168 @@
+//go:build cgo
+
+package cli
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"claudio.click/internal/soundpack"
+)
+
+// writeToneWAV writes half a second of a 997 Hz tone at amplitude as
+// 48 kHz mono 16-bit PCM.
+func writeToneWAV(t *testing.T, path string, amplitude float64) {
+	t.Helper()
+	const rate, frames = 48000, 24000
+	data := make([]byte, 44+frames*2)
+	copy(data[0:], "RIFF")
+	binary.LittleEndian.PutUint32(data[4:], uint32(len(data)-8))
+	copy(data[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(data[16:], 16)
+	binary.LittleEndian.PutUint16(data[20:], 1) // PCM
+	binary.LittleEndian.PutUint16(data[22:], 1) // mono
+	binary.LittleEndian.PutUint32(data[24:], rate)
+	binary.LittleEndian.PutUint32(data[28:], rate*2)
+	binary.LittleEndian.PutUint16(data[32:], 2)
+	binary.LittleEndian.PutUint16(data[34:], 16)
+	copy(data[36:], "data")
+	binary.LittleEndian.PutUint32(data[40:], frames*2)
+	for i := 0; i < frames; i++ {
+		v := amplitude * math.Sin(2*math.Pi*997*float64(i)/rate)
+		binary.LittleEndian.PutUint16(data[44+i*2:], uint16(int16(v*32767)))
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+// A 997 Hz mono tone at amplitude 0.5 reads -6 LUFS and one at 0.05 reads
+// -26, so at -23 LUFS they need -17 dB and +3 dB.
+func assertGain(t *testing.T, gains map[string]float64, file string, want float64) {
+	t.Helper()
+	got, ok := gains[file]
+	if !ok || math.Abs(got-want) > 0.3 {
+		t.Errorf("gain for %s = %v (stored %v), want %v", file, got, ok, want)
+	}
+}
+
+func TestSoundpackNormalize_DirectoryWritesSidecar(t *testing.T) {
+	_, _, cleanup := setupInstallTestEnv(t)
+	defer cleanup()
+
+	packDir := filepath.Join(t.TempDir(), "tones")
+	writeToneWAV(t, filepath.Join(packDir, "success", "success.wav"), 0.5)
+	writeToneWAV(t, filepath.Join(packDir, "default.wav"), 0.05)
+	if err := os.WriteFile(filepath.Join(packDir, "error.wav"), []byte("not audio"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, exitCode := runSoundpackCLI("soundpack", "normalize", packDir, "--target", "-23LUFS")
+	if exitCode != 0 {
+		t.Fatalf("normalize exited %d, stdout: %s, stderr: %s", exitCode, stdout, stderr)
+	}
+	if !strings.Contains(stderr, "cannot measure error.wav") {
+		t.Errorf("expected a warning for the undecodable file, got stderr: %s", stderr)
+	}
+	if !strings.Contains(stdout, "Stored gains for 2 sounds") || !strings.Contains(stdout, "(1 could not be measured)") {
+		t.Errorf("unexpected output: %s", stdout)
+	}
+
+	stored, err := soundpack.ReadLoudnessFile(filepath.Join(packDir, soundpack.LoudnessFileName))
+	if err != nil {
+		t.Fatalf("ReadLoudnessFile: %v", err)
+	}
+	if stored.TargetLUFS != -23 || len(stored.Gains) != 2 {
+		t.Errorf("unexpected sidecar: %+v", stored)
+	}
+	assertGain(t, stored.Gains, "success/success.wav", -17)
+	assertGain(t, stored.Gains, "default.wav", 3)
+}
+
+func TestSoundpackNormalize_JSONWritesManifest(t *testing.T) {
+	_, _, cleanup := setupInstallTestEnv(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	writeToneWAV(t, filepath.Join(dir, "sounds", "loud.wav"), 0.5)
+	manifest := filepath.Join(dir, "pack.json")
+	if err := os.WriteFile(manifest, []byte(`{"name": "tones", "description": "kept",
+  "mappings": {"success/success.wav": "sounds/loud.wav", "default.wav": "sounds/loud.wav"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, exitCode := runSoundpackCLI("soundpack", "normalize", manifest, "--target", "-18 LUFS")
+	if exitCode != 0 {
+		t.Fatalf("normalize exited %d, stdout: %s, stderr: %s", exitCode, stdout, stderr)
+	}
+	sp, err := soundpack.PeekJSONSoundpackFromFile(manifest)
+	if err != nil {
+		t.Fatalf("PeekJSONSoundpackFromFile: %v", err)
+	}
+	if sp.Description != "kept" || len(sp.Mappings) != 2 {
+		t.Errorf("normalize should keep the rest of the manifest, got %+v", sp)
+	}
+	if sp.Loudness == nil || sp.Loudness.TargetLUFS != -18 {
+		t.Fatalf("expected loudness at -18 LUFS in the manifest, got %+v", sp.Loudness)
+	}
+	assertGain(t, sp.Loudness.Gains, "sounds/loud.wav", -12)
+}
+
+func TestSoundpackInstall_StoresLoudness(t *testing.T) {
+	dataDir, _, cleanup := setupInstallTestEnv(t)
+	defer cleanup()
+
+	srcDir := filepath.Join(t.TempDir(), "tone-pack")
+	writeToneWAV(t, filepath.Join(srcDir, "success", "success.wav"), 0.5)
+
+	stdout, stderr, exitCode := runSoundpackCLI("soundpack", "install", srcDir, "--skip-validate")
+	if exitCode != 0 {
+		t.Fatalf("install exited %d, stdout: %s, stderr: %s", exitCode, stdout, stderr)
+	}
+	if !strings.Contains(stdout, "Levelled 1 sounds to -23LUFS") {
+		t.Errorf("expected install to report levelling, got: %s", stdout)
+	}
+
+	installed := filepath.Join(dataDir, "claudio", "soundpacks", "tone-pack", soundpack.LoudnessFileName)
+	stored, err := soundpack.ReadLoudnessFile(installed)
+	if err != nil {
+		t.Fatalf("ReadLoudnessFile: %v", err)
+	}
+	assertGain(t, stored.Gains, "success/success.wav", -17)
+	if _, err := os.Stat(filepath.Join(srcDir, soundpack.LoudnessFileName)); !os.IsNotExist(err) {
+		t.Errorf("install should not write into the source pack, stat err: %v", err)
+	}
+}
+
+func TestSoundpackValidate_ReportsLoudness(t *testing.T) {
+	_, _, cleanup := setupInstallTestEnv(t)
+	defer cleanup()
+
+	packDir := filepath.Join(t.TempDir(), "tones")
+	writeToneWAV(t, filepath.Join(packDir, "success", "success.wav"), 0.5)
+
+	stdout, stderr, exitCode := runSoundpackCLI("soundpack", "validate", packDir)
+	if exitCode != 0 {
+		t.Fatalf("validate exited %d, stdout: %s, stderr: %s", exitCode, stdout, stderr)
+	}
+	for _, want := range []string{"Loudness (target -23LUFS):", "success/success.wav: -6.", "1 sound(s) have no stored gain"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected validate output to contain %q, got: %s", want, stdout)
+		}
+	}
+
+	if _, _, exitCode := runSoundpackCLI("soundpack", "normalize", packDir); exitCode != 0 {
+		t.Fatalf("normalize exited %d", exitCode)
+	}
+	stdout, _, _ = runSoundpackCLI("soundpack", "validate", packDir)
+	if strings.Contains(stdout, "no stored gain") {
+		t.Errorf("a normalized pack should not be flagged, got: %s", stdout)
+	}
+}
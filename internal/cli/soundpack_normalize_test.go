@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"claudio.click/internal/audio"
+	"claudio.click/internal/audio/loudness"
+	"claudio.click/internal/cli/testenv"
+	"claudio.click/internal/config"
+)
+
+func TestParseLUFS(t *testing.T) {
+	for input, want := range map[string]float64{
+		"-23LUFS":  -23,
+		"-23 LUFS": -23,
+		"-18.5":    -18.5,
+		"-16lufs":  -16,
+		"-24LKFS":  -24,
+	} {
+		got, err := parseLUFS(input)
+		if err != nil || got != want {
+			t.Errorf("parseLUFS(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "LUFS", "loud", "3LUFS", "-90LUFS", "NaN"} {
+		if _, err := parseLUFS(input); err == nil {
+			t.Errorf("parseLUFS(%q) should fail", input)
+		}
+	}
+	if got, err := parseLUFS(formatLUFS(loudness.DefaultTargetLUFS)); err != nil || got != loudness.DefaultTargetLUFS {
+		t.Errorf("the default --target should parse back, got %v, %v", got, err)
+	}
+}
+
+// TestLoudnessGain_ReachesBackend checks that a hook plays a sound with the
+// gain its soundpack stores for it.
+func TestLoudnessGain_ReachesBackend(t *testing.T) {
+	root := testenv.IsolateXDG(t)
+
+	if err := os.WriteFile(filepath.Join(root, "click.wav"), createMinimalWAV(), 0o644); err != nil {
+		t.Fatalf("write wav: %v", err)
+	}
+	pack := map[string]any{
+		"name":     "levelled",
+		"mappings": map[string]any{"default.wav": "click.wav"},
+		"loudness": map[string]any{"target_lufs": -23, "gains": map[string]float64{"click.wav": -6}},
+	}
+	data, err := json.Marshal(pack)
+	if err != nil {
+		t.Fatalf("marshal soundpack: %v", err)
+	}
+	packPath := filepath.Join(root, "pack.json")
+	if err := os.WriteFile(packPath, data, 0o644); err != nil {
+		t.Fatalf("write soundpack: %v", err)
+	}
+
+	cfg := config.NewConfigManager().GetDefaultConfig()
+	cfg.DefaultSoundpack = packPath
+	configPath := filepath.Join(root, "config.json")
+	writeSeedConfig(t, configPath, cfg)
+
+	hook := `{"session_id":"gain","cwd":"/tmp","hook_event_name":"PostToolUse","tool_name":"Bash","tool_input":{"command":"ls"},"tool_response":{"stdout":"ok","stderr":"","interrupted":false}}`
+	if got := runHookForPlays(t, []string{"claudio", "--config", configPath}, hook); len(got) != 1 {
+		t.Fatalf("expected one play, got %v", got)
+	}
+	play := audio.LastFakeBackend().Plays()[0]
+	if want := math.Pow(10, -6.0/20); math.Abs(play.Variation.EffectiveGain()-want) > 1e-9 {
+		t.Errorf("played with gain %v, want %v", play.Variation.EffectiveGain(), want)
+	}
+	if play.Variation.ShiftsPitchOrPan() {
+		t.Errorf("a loudness gain should not shift pitch or pan, got %+v", play.Variation)
+	}
+}
+
+func TestSoundpackNormalize_RejectsBadTarget(t *testing.T) {
+	_, _, cleanup := setupInstallTestEnv(t)
+	defer cleanup()
+
+	_, stderr, exitCode := runSoundpackCLI("soundpack", "normalize", t.TempDir(), "--target", "+3LUFS")
+	if exitCode == 0 || !strings.Contains(stderr, "invalid loudness target") {
+		t.Errorf("expected an invalid target error, got exit %d, stderr: %s", exitCode, stderr)
+	}
+}
@@ -100,6 +100,7 @@ func runSoundpackUse(cmd *cobra.Command, name string) error {
 	} else {
 		cmd.Printf("Switched active soundpack to '%s'.\n", name)
 	}
+	warmActiveSoundpack(cmd, name)
 
 	slog.Info("soundpack use completed", "name", name, "was_already_active", alreadyActive)
 	return nil
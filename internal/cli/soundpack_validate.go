@@ -23,10 +23,15 @@ Checks:
   1. JSON structure: valid JSON that parses into a soundpack
   2. Referenced files exist: non-empty mappings point to real files
   3. Coverage gaps: compare mappings against all known sound keys
-  4. Format check: referenced files should be .wav, .mp3, or .aiff
+  4. Format check: referenced files should be .wav, .mp3, .aiff, .flac,
+     .ogg, or .opus
+  5. Variations: list and object mappings have a known mode and
+     one non-negative weight per file
+  6. Loudness: every sound's integrated loudness (EBU R128), peak, and
+     the gain that levels it, noting gains that are missing or outdated
 
-Exit code 0 if no broken references, non-zero if broken references found.
-Empty mappings are informational, not errors.
+Exit code 0 if no broken references or invalid mappings, non-zero otherwise.
+Empty mappings and loudness are informational, not errors.
 
 Examples:
   claudio soundpack validate my-pack.json
@@ -43,11 +48,13 @@ Examples:
 type validateResult struct {
 	Name           string
 	Version        string
+	Extends        []string          // packs searched for keys this one does not map
 	Mappings       map[string]string // all mappings from the soundpack
 	AllKeys        []string          // all known keys
 	MappedKeys     map[string]string // keys with non-empty values
 	BrokenRefs     map[string]string // key -> path for files that don't exist
 	FormatWarnings map[string]string // key -> path for files with non-audio extensions
+	Invalid        map[string]string // key -> reason for malformed variation mappings
 	IsDirectory    bool
 }
 
@@ -74,11 +81,15 @@ func runSoundpackValidate(cmd *cobra.Command, path string) error {
 
 	// Print the validation report
 	printValidateReport(cmd, result)
+	printSoundpackLoudness(cmd, path)
 
 	// Exit with non-zero if there are broken references
 	if len(result.BrokenRefs) > 0 {
 		return fmt.Errorf("validation failed: %d broken reference(s)", len(result.BrokenRefs))
 	}
+	if len(result.Invalid) > 0 {
+		return fmt.Errorf("validation failed: %d invalid mapping(s)", len(result.Invalid))
+	}
 
 	return nil
 }
@@ -108,27 +119,44 @@ func validateJSONSoundpackFile(path string) (validateResult, error) {
 		return validateResult{}, fmt.Errorf("failed to extract sound keys: %w", err)
 	}
 
-	// Identify mapped (non-empty) keys, broken refs, and format warnings
+	// Identify mapped (non-empty) keys, broken refs, and format warnings.
+	// A key with variations is reported by its first problem file.
+	mappings := make(map[string]string, len(spFile.Mappings))
 	mappedKeys := make(map[string]string)
 	brokenRefs := make(map[string]string)
 	formatWarnings := make(map[string]string)
+	invalid := make(map[string]string)
 
-	for key, val := range spFile.Mappings {
-		if val == "" {
+	for key, mapping := range spFile.Mappings {
+		mappings[key] = mapping.String()
+		if mapping.IsEmpty() {
 			continue
 		}
-		mappedKeys[key] = val
+		mappedKeys[key] = mapping.String()
+		if err := mapping.Validate(); err != nil {
+			slog.Warn("invalid mapping", "key", key, "error", err)
+			invalid[key] = err.Error()
+		}
+
+		for _, val := range mapping.Files {
+			if soundpack.IsSpeechTemplate(val) {
+				continue // spoken at play time; no file to check
+			}
 
-		// Check if file exists
-		if _, statErr := os.Stat(val); statErr != nil {
-			slog.Warn("broken reference", "key", key, "path", val)
-			brokenRefs[key] = val
-		} else {
+			// Check if file exists
+			if _, statErr := os.Stat(val); statErr != nil {
+				slog.Warn("broken reference", "key", key, "path", val)
+				if _, seen := brokenRefs[key]; !seen {
+					brokenRefs[key] = val
+				}
+				continue
+			}
 			// Check file format
-			ext := strings.ToLower(filepath.Ext(val))
-			if ext != ".wav" && ext != ".mp3" && ext != ".aiff" {
-				slog.Warn("non-audio format", "key", key, "path", val, "ext", ext)
-				formatWarnings[key] = val
+			if !soundpack.IsAudioFile(val) {
+				slog.Warn("non-audio format", "key", key, "path", val, "ext", filepath.Ext(val))
+				if _, seen := formatWarnings[key]; !seen {
+					formatWarnings[key] = val
+				}
 			}
 		}
 	}
@@ -136,11 +164,13 @@ func validateJSONSoundpackFile(path string) (validateResult, error) {
 	return validateResult{
 		Name:           spFile.Name,
 		Version:        spFile.Version,
-		Mappings:       spFile.Mappings,
+		Extends:        spFile.Extends,
+		Mappings:       mappings,
 		AllKeys:        allKeys,
 		MappedKeys:     mappedKeys,
 		BrokenRefs:     brokenRefs,
 		FormatWarnings: formatWarnings,
+		Invalid:        invalid,
 		IsDirectory:    false,
 	}, nil
 }
@@ -175,8 +205,7 @@ func validateDirectorySoundpack(dirPath string) (validateResult, error) {
 			return nil
 		}
 
-		ext := strings.ToLower(filepath.Ext(path))
-		if ext != ".wav" && ext != ".mp3" && ext != ".aiff" {
+		if !soundpack.IsAudioFile(path) {
 			return nil
 		}
 		if info.Mode()&os.ModeSymlink != 0 {
@@ -188,11 +217,18 @@ func validateDirectorySoundpack(dirPath string) (validateResult, error) {
 		if err != nil {
 			return nil
 		}
-		// Normalize to forward slashes for key matching
+		// Normalize to forward slashes for key matching. Numbered
+		// variations (bash-success.2.wav) count toward their sound's key.
 		key := filepath.ToSlash(rel)
+		if base, ok := soundpack.VariationBaseKey(key); ok {
+			key = base
+		}
 
 		slog.Debug("found audio file in directory", "key", key, "path", path)
 
+		if _, seen := mappedKeys[key]; seen {
+			return nil // the sound's first file stands for it in the report
+		}
 		allMappings[key] = path
 		mappedKeys[key] = path
 
@@ -229,6 +265,9 @@ func printValidateReport(cmd *cobra.Command, result validateResult) {
 	if result.Version != "" {
 		cmd.Printf("Version: %s\n", result.Version)
 	}
+	if len(result.Extends) > 0 {
+		cmd.Printf("Extends: %s\n", strings.Join(result.Extends, ", "))
+	}
 	cmd.Println()
 
 	// Coverage Summary
@@ -301,6 +340,20 @@ func printValidateReport(cmd *cobra.Command, result validateResult) {
 		}
 	}
 
+	// Invalid Mappings
+	if len(result.Invalid) > 0 {
+		cmd.Println()
+		cmd.Println("Invalid Mappings:")
+		invalidKeys := make([]string, 0, len(result.Invalid))
+		for key := range result.Invalid {
+			invalidKeys = append(invalidKeys, key)
+		}
+		sort.Strings(invalidKeys)
+		for _, key := range invalidKeys {
+			cmd.Printf("  %s: %s\n", key, result.Invalid[key])
+		}
+	}
+
 	// Empty Mappings
 	emptyKeys := make([]string, 0)
 	for _, key := range result.AllKeys {
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"claudio.click/internal/audio"
+	"claudio.click/internal/config"
+	"claudio.click/internal/hooks"
+	"claudio.click/internal/soundpack"
+)
+
+// speechRequest carries what a say: template needs at play time: the
+// event's placeholder values and the configured engine.
+type speechRequest struct {
+	fields  map[string]string
+	options audio.SpeechOptions
+}
+
+// newSpeechRequest builds the speech inputs for one hook event.
+func newSpeechRequest(cfg *config.Config, hookEvent *hooks.HookEvent, eventCtx *hooks.EventContext) *speechRequest {
+	speech := cfg.Speech
+	if speech == nil {
+		speech = config.GetDefaultSpeechConfig()
+	}
+	return &speechRequest{
+		fields: hookEvent.SpeechFields(eventCtx),
+		options: audio.SpeechOptions{
+			Engine:     speech.Engine,
+			Voice:      speech.Voice,
+			Rate:       speech.Rate,
+			PiperModel: speech.PiperModel,
+		},
+	}
+}
+
+// playSpeech renders a resolved say: template and speaks it. File-rendering
+// engines play through the configured backend like any sound, scaled by
+// gain; spd-say speaks on its own at volume.
+func (c *CLI) playSpeech(ctx context.Context, template string, volume, gain float64, req *speechRequest) error {
+	if req == nil {
+		slog.Warn("speech template without event context, skipping", "template", template)
+		return nil
+	}
+
+	text := hooks.RenderSpeech(soundpack.SpeechTemplateText(template), req.fields)
+	if text == "" {
+		slog.Debug("speech template rendered empty, skipping", "template", template)
+		return nil
+	}
+
+	source := audio.NewSpeechSourceContext(ctx, text, req.options)
+	defer source.Close()
+
+	var err error
+	if source.SpeaksDirectly() {
+		err = source.Speak(ctx, volume)
+	} else {
+		err = c.audioBackend.Play(ctx, audio.WithVariation(source, audio.Variation{Gain: gain}))
+	}
+	if err != nil && ctx.Err() != nil {
+		slog.Debug("speech interrupted", "text", text)
+		return nil
+	}
+	if err != nil {
+		slog.Error("speech playback failed", "engine", req.options.Engine, "error", err)
+		return fmt.Errorf("failed to speak notification: %w", err)
+	}
+
+	slog.Debug("speech played successfully", "text", text, "backend_type", fmt.Sprintf("%T", c.audioBackend))
+	return nil
+}
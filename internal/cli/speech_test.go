@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"claudio.click/internal/audio"
+	"claudio.click/internal/cli/testenv"
+	"claudio.click/internal/config"
+)
+
+// runHookForSpeech runs one hook through a fresh CLI and returns the text
+// of every speech play the fake backend saw.
+func runHookForSpeech(t *testing.T, args []string, hookJSON string) []string {
+	t.Helper()
+	audio.ResetLastFakeBackend()
+	stderr := &bytes.Buffer{}
+	if code := NewCLI().Run(args, strings.NewReader(hookJSON), &bytes.Buffer{}, stderr); code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr.String())
+	}
+	fake := audio.LastFakeBackend()
+	if fake == nil {
+		return nil
+	}
+	var texts []string
+	for _, p := range fake.Plays() {
+		if p.Text != "" {
+			texts = append(texts, p.Text)
+		}
+	}
+	return texts
+}
+
+func TestSpeechTemplates_MappingAndRoutingRule(t *testing.T) {
+	root := testenv.IsolateXDG(t)
+
+	if err := os.WriteFile(filepath.Join(root, "default.wav"), createMinimalWAV(), 0o644); err != nil {
+		t.Fatalf("write wav: %v", err)
+	}
+	pack := `{"name":"speech-test","mappings":{"default.wav":"default.wav","completion/agent-complete.wav":"say:{{agent}} finished in {{cwd_basename}}"}}`
+	packPath := filepath.Join(root, "pack.json")
+	if err := os.WriteFile(packPath, []byte(pack), 0o644); err != nil {
+		t.Fatalf("write soundpack: %v", err)
+	}
+	routingPath := filepath.Join(root, "routing.json")
+	routing := `{"rules":[{"name":"speak failures","match":{"event":"PostToolUseFailure"},"sound":"say:{{tool}} failed"}]}`
+	if err := os.WriteFile(routingPath, []byte(routing), 0o644); err != nil {
+		t.Fatalf("write routing: %v", err)
+	}
+
+	cfg := config.NewConfigManager().GetDefaultConfig()
+	cfg.DefaultSoundpack = packPath
+	cfg.RoutingFile = routingPath
+	cfg.Speech = &config.SpeechConfig{Engine: config.SpeechEngineEspeakNG}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+	configPath := filepath.Join(root, "config.json")
+	if err := os.WriteFile(configPath, data, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	stop := `{"session_id":"sp","cwd":"/home/u/src/claudio","hook_event_name":"Stop"}`
+	if got := runHookForSpeech(t, []string{"claudio", "--config", configPath, "--hook-agent", "codex"}, stop); len(got) != 1 || got[0] != "codex finished in claudio" {
+		t.Errorf("Stop should speak the mapped template, got %v", got)
+	}
+
+	failure := `{"session_id":"sp","cwd":"/tmp","hook_event_name":"PostToolUseFailure","tool_name":"Bash","tool_input":{"command":"make"},"error":"exit 2"}`
+	if got := runHookForSpeech(t, []string{"claudio", "--config", configPath}, failure); len(got) != 1 || got[0] != "Bash failed" {
+		t.Errorf("routing rule should speak its template, got %v", got)
+	}
+}
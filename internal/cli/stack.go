@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"claudio.click/internal/config"
+	"claudio.click/internal/soundpack"
+)
+
+// stackSoundpacks layers the soundpack_stack overlays above base, the
+// active pack, and expands every pack's "extends" list below it. With no
+// overlays and no extends, base is returned as it is.
+func stackSoundpacks(cfg *config.Config, base soundpack.PathMapper) soundpack.PathMapper {
+	load := func(name, baseDir string) (soundpack.PathMapper, error) {
+		return loadSoundpackLayer(cfg, name, baseDir)
+	}
+
+	var roots []soundpack.PathMapper
+	for _, name := range cfg.SoundpackStack {
+		overlay, err := load(name, "")
+		if err != nil {
+			slog.Warn("failed to load soundpack_stack entry, skipping", "name", name, "error", err)
+			continue
+		}
+		roots = append(roots, overlay)
+	}
+	roots = append(roots, base)
+
+	layers := soundpack.ExpandStack(roots, load)
+	slog.Debug("soundpack stack built", "layers_count", len(layers))
+	return soundpack.NewStackMapper(layers)
+}
+
+// loadSoundpackLayer loads one pack of a stack. A path, relative ones
+// resolved against baseDir when it is set, is loaded from there. A bare
+// name is looked up where default_soundpack names are, plus the XDG
+// soundpack directories, but never in the working directory, which is
+// whatever project the hook happens to run in. The name "default", when
+// no installed pack has it, is the platform pack.
+func loadSoundpackLayer(cfg *config.Config, name, baseDir string) (soundpack.PathMapper, error) {
+	if strings.HasPrefix(name, "embedded:") {
+		return loadEmbeddedPlatformSoundpack(name)
+	}
+	if identifier, ok := embeddedPlatformSoundpackIdentifier(name); ok {
+		return loadEmbeddedPlatformSoundpack(identifier)
+	}
+
+	if isSoundpackPathName(name) {
+		path := name
+		if baseDir != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(baseDir, path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("soundpack %q not found: %w", name, err)
+		}
+		return soundpack.CreateSoundpackMapper(name, path)
+	}
+
+	if path := resolveSoundpackName(cfg, name); path != "" {
+		return soundpack.CreateSoundpackMapper(name, path)
+	}
+	for _, dir := range config.NewXDGDirs().GetSoundpackPaths(name) {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return soundpack.NewDirectoryMapper(name, []string{dir}), nil
+		}
+	}
+
+	if name == "default" {
+		platformSoundpack := config.NewConfigManager().GetPlatformSoundpack(getPlatformExecutableDirectory())
+		if platformSoundpack != "default" {
+			return loadSoundpackLayer(cfg, platformSoundpack, "")
+		}
+	}
+	return nil, fmt.Errorf("soundpack %q not found", name)
+}
+
+// resolveSoundpackPath turns a soundpack name into a path: the name itself
+// when it exists, else what resolveSoundpackName finds. Unresolved names
+// are returned as they are.
+func resolveSoundpackPath(cfg *config.Config, name string) string {
+	if _, statErr := os.Stat(name); statErr == nil {
+		return name
+	}
+	if path := resolveSoundpackName(cfg, name); path != "" {
+		return path
+	}
+	return name
+}
+
+// resolveSoundpackName finds the pack a name refers to: a managed git pack,
+// else the soundpack_paths entry whose base name matches. It returns ""
+// when neither exists.
+func resolveSoundpackName(cfg *config.Config, name string) string {
+	if managedPath := findManagedGitSoundpackPath(name); managedPath != "" {
+		if _, statErr := os.Stat(managedPath); statErr == nil {
+			slog.Info("resolved managed git soundpack name to path",
+				"name", name, "path", managedPath)
+			return managedPath
+		}
+	}
+	for _, sp := range cfg.SoundpackPaths {
+		if _, spErr := os.Stat(sp); spErr == nil {
+			base := filepath.Base(sp)
+			spName := strings.TrimSuffix(base, filepath.Ext(base))
+			if spName == name {
+				slog.Debug("resolved soundpack name to path",
+					"name", name, "path", sp)
+				return sp
+			}
+		}
+	}
+	return ""
+}
+
+// isSoundpackPathName reports whether a stack or extends entry is a path
+// rather than a bare pack name: absolute, containing a separator, a dot
+// directory, or a JSON manifest's file name.
+func isSoundpackPathName(name string) bool {
+	return filepath.IsAbs(name) || strings.ContainsAny(name, `/\`) ||
+		name == "." || name == ".." || strings.EqualFold(filepath.Ext(name), ".json")
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"claudio.click/internal/cli/testenv"
+	"claudio.click/internal/config"
+)
+
+// writeStackPack writes a JSON pack called name in its own directory,
+// mapping each key to a fresh WAV, and returns the manifest path plus the
+// physical file per key.
+func writeStackPack(t *testing.T, root, name string, extends []string, keys ...string) (string, map[string]string) {
+	t.Helper()
+	dir := filepath.Join(root, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	physical := make(map[string]string, len(keys))
+	mappings := make(map[string]string, len(keys))
+	for i, key := range keys {
+		file := "sound-" + string(rune('a'+i)) + ".wav"
+		physical[key] = filepath.Join(dir, file)
+		mappings[key] = file
+		if err := os.WriteFile(physical[key], createMinimalWAV(), 0o644); err != nil {
+			t.Fatalf("write wav: %v", err)
+		}
+	}
+	data, err := json.Marshal(map[string]any{"name": name, "extends": extends, "mappings": mappings})
+	if err != nil {
+		t.Fatalf("marshal soundpack: %v", err)
+	}
+	packPath := filepath.Join(dir, name+".json")
+	if err := os.WriteFile(packPath, data, 0o644); err != nil {
+		t.Fatalf("write soundpack: %v", err)
+	}
+	return packPath, physical
+}
+
+func TestSoundpackStack_OverlayAndExtends(t *testing.T) {
+	root := testenv.IsolateXDG(t)
+
+	basePath, base := writeStackPack(t, root, "base", nil, "default.wav", "success/bash-success.wav")
+	gitPath, git := writeStackPack(t, root, "git-sounds", []string{"base"}, "success/git-success.wav")
+	loudPath, loud := writeStackPack(t, root, "loud", nil, "success/bash-success.wav")
+
+	cfg := config.NewConfigManager().GetDefaultConfig()
+	cfg.DefaultSoundpack = gitPath
+	cfg.SoundpackPaths = []string{basePath, loudPath}
+	configPath := filepath.Join(root, "config.json")
+	writeSeedConfig(t, configPath, cfg)
+	args := []string{"claudio", "--config", configPath}
+
+	hook := func(command string) string {
+		return `{"session_id":"stack","cwd":"/tmp","hook_event_name":"PostToolUse","tool_name":"Bash","tool_input":{"command":"` + command + `"},"tool_response":{"stdout":"ok","stderr":"","interrupted":false}}`
+	}
+
+	if got := runHookForPlays(t, args, hook("git status")); len(got) != 1 || got[0] != git["success/git-success.wav"] {
+		t.Errorf("git should play the overlay sound, got %v", got)
+	}
+	if got := runHookForPlays(t, args, hook("ls")); len(got) != 1 || got[0] != base["success/bash-success.wav"] {
+		t.Errorf("bash should fall through to the extended pack, got %v", got)
+	}
+
+	// soundpack_stack puts another overlay above the active pack.
+	cfg.SoundpackStack = []string{"loud"}
+	writeSeedConfig(t, configPath, cfg)
+	if got := runHookForPlays(t, args, hook("ls")); len(got) != 1 || got[0] != loud["success/bash-success.wav"] {
+		t.Errorf("bash should play the stacked overlay, got %v", got)
+	}
+	if got := runHookForPlays(t, args, hook("git status")); len(got) != 1 || got[0] != git["success/git-success.wav"] {
+		t.Errorf("git should still play the active pack's sound, got %v", got)
+	}
+}
+
+func TestSoundpackStack_ExtendsResolvesAgainstManifest(t *testing.T) {
+	root := testenv.IsolateXDG(t)
+
+	// The hook runs in a project that has packs of its own called base
+	// and ../base; neither may stand in for the ones the manifests name.
+	project := filepath.Join(root, "work", "project")
+	writeStackPack(t, project, "base", nil, "default.wav", "success/bash-success.wav")
+	writeStackPack(t, filepath.Join(root, "work"), "base", nil, "default.wav", "success/bash-success.wav")
+	t.Chdir(project)
+
+	basePath, base := writeStackPack(t, filepath.Join(root, "packs"), "base", nil, "default.wav", "success/bash-success.wav")
+	relativePath, _ := writeStackPack(t, filepath.Join(root, "packs"), "relative", []string{"../base/base.json"}, "success/git-success.wav")
+	namedPath, _ := writeStackPack(t, filepath.Join(root, "packs"), "named", []string{"base"}, "success/git-success.wav")
+
+	hook := `{"session_id":"stack","cwd":"` + project + `","hook_event_name":"PostToolUse","tool_name":"Bash","tool_input":{"command":"ls"},"tool_response":{"stdout":"ok","stderr":"","interrupted":false}}`
+	for _, active := range []string{relativePath, namedPath} {
+		cfg := config.NewConfigManager().GetDefaultConfig()
+		cfg.DefaultSoundpack = active
+		cfg.SoundpackPaths = []string{basePath}
+		configPath := filepath.Join(root, "config.json")
+		writeSeedConfig(t, configPath, cfg)
+
+		got := runHookForPlays(t, []string{"claudio", "--config", configPath}, hook)
+		if len(got) != 1 || got[0] != base["success/bash-success.wav"] {
+			t.Errorf("%s: bash should fall through to packs/base, got %v", filepath.Base(active), got)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -54,13 +55,23 @@ func runStatusE(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	// Apply env overrides so the report reflects runtime-effective values.
+	// Layer the current directory's project overlay, as a hook run here
+	// would, then env overrides so the report reflects runtime-effective
+	// values.
+	projectDisplay := ""
+	if wd, err := os.Getwd(); err == nil {
+		projectDisplay = describeProjectConfig(cli, cfg, wd)
+		cfg = cli.configManager.ApplyProjectConfig(cfg, wd)
+	}
 	cfg = cli.configManager.ApplyEnvironmentOverrides(cfg)
 
 	out := cmd.OutOrStdout()
 	fmt.Fprintln(out, "claudio status")
 	fmt.Fprintln(out)
 	fmt.Fprintf(out, "  config file:    %s\n", configPathDisplay)
+	if projectDisplay != "" {
+		fmt.Fprintf(out, "  project config: %s\n", projectDisplay)
+	}
 
 	// Enabled — with the literal MUTED token when false. Screen-reader cue.
 	if cfg.Enabled {
@@ -70,6 +81,10 @@ func runStatusE(cmd *cobra.Command, _ []string) error {
 		// the audible screen-reader cue, not a visual decoration.
 		fmt.Fprintln(out, "  enabled:        false (MUTED)")
 	}
+	// Same cue for a temporary mute or quiet hours in effect right now.
+	if muted, reason := cfg.ScheduledMute(time.Now()); muted {
+		fmt.Fprintf(out, "  schedule:       MUTED (%s)\n", reason)
+	}
 
 	// Volume — annotate the source so the user understands precedence.
 	volStr, volSource := describeVolume(cfg)
@@ -78,6 +93,11 @@ func runStatusE(cmd *cobra.Command, _ []string) error {
 	fmt.Fprintf(out, "  soundpack:      %s\n", cfg.DefaultSoundpack)
 	fmt.Fprintf(out, "  log level:      %s\n", cfg.LogLevel)
 	fmt.Fprintf(out, "  audio backend:  %s\n", cfg.AudioBackend)
+	if cfg.AudioDevice != "" {
+		fmt.Fprintf(out, "  audio device:   %s\n", cfg.AudioDevice)
+	} else {
+		fmt.Fprintln(out, "  audio device:   system default")
+	}
 
 	if cfg.FileLogging != nil && cfg.FileLogging.Enabled {
 		path := cli.configManager.ResolveLogFilePath(cfg.FileLogging.Filename)
@@ -96,6 +116,12 @@ func runStatusE(cmd *cobra.Command, _ []string) error {
 		fmt.Fprintln(out, "  tracking:       disabled")
 	}
 
+	if policy := cfg.Playback.EffectiveOverlapPolicy(); policy == config.OverlapPolicyQueue {
+		fmt.Fprintf(out, "  overlap:        %s (max %d waiting)\n", policy, cfg.Playback.EffectiveMaxQueueDepth())
+	} else {
+		fmt.Fprintf(out, "  overlap:        %s\n", policy)
+	}
+
 	fmt.Fprintf(out, "  version:        %s\n", Version)
 
 	slog.Debug("status reported", "enabled", cfg.Enabled, "volume", cfg.Volume)
@@ -126,6 +152,23 @@ func loadConfigForStatus(cmd *cobra.Command, cli *CLI) (string, *config.Config,
 	return "(none - using defaults)", cli.configManager.GetDefaultConfig(), nil
 }
 
+// describeProjectConfig names the project overlay that applies in dir
+// and whether it is trusted, or returns "" when there is none.
+func describeProjectConfig(cli *CLI, cfg *config.Config, dir string) string {
+	path := cli.configManager.FindProjectConfig(dir)
+	if path == "" {
+		return ""
+	}
+	_, data, err := cli.configManager.LoadProjectConfig(path)
+	if err != nil {
+		return fmt.Sprintf("%s (invalid: %v)", path, err)
+	}
+	if !cfg.IsProjectTrusted(path, data) {
+		return fmt.Sprintf("%s (untrusted - run 'claudio trust' to apply)", path)
+	}
+	return fmt.Sprintf("%s (trusted)", path)
+}
+
 // describeVolume returns a printable value and a source annotation
 // (env / file / default) for the status report.
 func describeVolume(cfg *config.Config) (string, string) {
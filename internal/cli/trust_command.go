@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"claudio.click/internal/config"
+)
+
+// newTrustCommand returns the `claudio trust` subcommand. Approves the
+// project overlay (.claudio.json) found from a directory by recording its
+// path and content hash in the user config's trusted_projects.
+func newTrustCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trust [dir]",
+		Short: "Allow a project's .claudio.json to change claudio settings",
+		Long: `Allow the project overlay (.claudio.json) found from dir to change
+claudio settings when hooks run inside that project.
+
+The overlay is looked up from dir (default: the current directory) up to
+the repository root. Its path and a hash of its contents are recorded in
+trusted_projects in config.json. If the file changes later, it is ignored
+again until you run 'claudio trust' once more.
+
+Use --list to show the trusted overlays.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runTrustE,
+	}
+	cmd.Flags().Bool("list", false, "List trusted project overlays")
+	return cmd
+}
+
+// newUntrustCommand returns the `claudio untrust` subcommand. Symmetric
+// to trust — drops the overlay from trusted_projects.
+func newUntrustCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "untrust [dir]",
+		Short: "Stop applying a project's .claudio.json",
+		Long: `Remove the project overlay (.claudio.json) found from dir (default:
+the current directory) from trusted_projects in config.json.
+
+Symmetric counterpart to 'claudio trust'.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runUntrustE,
+	}
+}
+
+func runTrustE(cmd *cobra.Command, args []string) error {
+	if list, _ := cmd.Flags().GetBool("list"); list {
+		return listTrustedProjects(cmd)
+	}
+	return updateTrustAndPersist(cmd, args, true)
+}
+
+func runUntrustE(cmd *cobra.Command, args []string) error {
+	return updateTrustAndPersist(cmd, args, false)
+}
+
+// updateTrustAndPersist is the shared core for trust/untrust. Locates the
+// overlay, then adds or removes its trusted_projects entry under the
+// config lock.
+func updateTrustAndPersist(cmd *cobra.Command, args []string, trust bool) error {
+	cli := cliFromContext(cmd.Context())
+	if cli == nil {
+		return fmt.Errorf("CLI instance not found in context")
+	}
+	cli.initializeConfigManager()
+
+	dir := ""
+	if len(args) > 0 {
+		dir = args[0]
+	} else {
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("get working directory: %w", err)
+		}
+		dir = wd
+	}
+
+	overlayPath := cli.configManager.FindProjectConfig(dir)
+	if overlayPath == "" {
+		return fmt.Errorf("no %s found from %s", config.ProjectConfigFileName, dir)
+	}
+
+	var hash string
+	if trust {
+		// Load through the same reader hooks use, so a file hooks would
+		// reject is rejected here too instead of being trusted.
+		_, data, err := cli.configManager.LoadProjectConfig(overlayPath)
+		if err != nil {
+			return err
+		}
+		hash = config.HashProjectConfig(data)
+	}
+
+	configPath, err := resolveWritableConfigPath(cmd, cli)
+	if err != nil {
+		return err
+	}
+
+	lock, err := config.LockConfigDir(configPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := lock.Unlock(); err != nil {
+			slog.Warn("failed to release config lock", "err", err)
+		}
+	}()
+
+	cfg, err := loadConfigForVerb(cli, configPath)
+	if err != nil {
+		return err
+	}
+
+	kept := cfg.TrustedProjects[:0]
+	removed := false
+	for _, tp := range cfg.TrustedProjects {
+		if tp.Path == overlayPath {
+			removed = true
+			continue
+		}
+		kept = append(kept, tp)
+	}
+	cfg.TrustedProjects = kept
+	if trust {
+		cfg.TrustedProjects = append(cfg.TrustedProjects, config.TrustedProject{Path: overlayPath, SHA256: hash})
+	} else if !removed {
+		fmt.Fprintf(cmd.OutOrStdout(), "not trusted: %s\n", overlayPath)
+		return nil
+	}
+
+	if err := config.WriteConfigFile(afero.NewOsFs(), configPath, cfg); err != nil {
+		return fmt.Errorf("save config: %w", err)
+	}
+
+	if trust {
+		fmt.Fprintf(cmd.OutOrStdout(), "trusted: %s\n", overlayPath)
+	} else {
+		fmt.Fprintf(cmd.OutOrStdout(), "untrusted: %s\n", overlayPath)
+	}
+	slog.Info("project trust persisted", "path", configPath, "project_config", overlayPath, "trusted", trust)
+	return nil
+}
+
+// listTrustedProjects prints each trusted overlay and whether its current
+// contents still match the approved hash.
+func listTrustedProjects(cmd *cobra.Command) error {
+	cli := cliFromContext(cmd.Context())
+	if cli == nil {
+		return fmt.Errorf("CLI instance not found in context")
+	}
+	cli.initializeConfigManager()
+
+	configPath, err := resolveWritableConfigPath(cmd, cli)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfigForVerb(cli, configPath)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if len(cfg.TrustedProjects) == 0 {
+		fmt.Fprintln(out, "no trusted project configs")
+		return nil
+	}
+	for _, tp := range cfg.TrustedProjects {
+		state := "trusted"
+		if _, data, err := cli.configManager.LoadProjectConfig(tp.Path); err != nil {
+			state = "missing"
+		} else if !cfg.IsProjectTrusted(tp.Path, data) {
+			state = "changed since trusted"
+		}
+		fmt.Fprintf(out, "%s (%s)\n", tp.Path, state)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"claudio.click/internal/cli/testenv"
+	"claudio.click/internal/config"
+)
+
+func TestProjectConfig_AppliedOnlyWhenTrusted(t *testing.T) {
+	root := testenv.IsolateXDG(t)
+
+	userPackDir := filepath.Join(root, "user-pack")
+	projectDir := filepath.Join(root, "project")
+	projectPackDir := filepath.Join(projectDir, "sounds")
+	workDir := filepath.Join(projectDir, "src", "pkg")
+	for _, dir := range []string{userPackDir, projectPackDir, workDir, filepath.Join(projectDir, ".git")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	userPack, userPhysical := writeTestJSONSoundpack(t, userPackDir, "default.wav")
+	_, projectPhysical := writeTestJSONSoundpack(t, projectPackDir, "default.wav")
+
+	overlayPath := filepath.Join(projectDir, config.ProjectConfigFileName)
+	if err := os.WriteFile(overlayPath, []byte(`{"default_soundpack":"./sounds/pack.json"}`), 0o644); err != nil {
+		t.Fatalf("write overlay: %v", err)
+	}
+
+	cfg := config.NewConfigManager().GetDefaultConfig()
+	cfg.DefaultSoundpack = userPack
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+	configPath := filepath.Join(root, "config.json")
+	if err := os.WriteFile(configPath, data, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	args := []string{"claudio", "--config", configPath}
+
+	hookJSON := `{"session_id":"pc","cwd":` + jsonString(t, workDir) + `,"hook_event_name":"Stop"}`
+
+	if got := runHookForPlays(t, args, hookJSON); len(got) != 1 || got[0] != userPhysical["default.wav"] {
+		t.Fatalf("untrusted overlay must be ignored, got %v", got)
+	}
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	if code := NewCLI().Run([]string{"claudio", "trust", workDir, "--config", configPath}, strings.NewReader(""), stdout, stderr); code != 0 {
+		t.Fatalf("trust exit code %d, stderr: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), overlayPath) {
+		t.Errorf("trust output should name the overlay, got %q", stdout.String())
+	}
+
+	if got := runHookForPlays(t, args, hookJSON); len(got) != 1 || got[0] != projectPhysical["default.wav"] {
+		t.Fatalf("trusted overlay should select the project pack, got %v", got)
+	}
+
+	// Editing the overlay revokes trust until it is approved again.
+	if err := os.WriteFile(overlayPath, []byte(`{"default_soundpack":"./sounds/pack.json","volume":0.2}`), 0o644); err != nil {
+		t.Fatalf("rewrite overlay: %v", err)
+	}
+	if got := runHookForPlays(t, args, hookJSON); len(got) != 1 || got[0] != userPhysical["default.wav"] {
+		t.Fatalf("changed overlay must be ignored, got %v", got)
+	}
+
+	stdout.Reset()
+	if code := NewCLI().Run([]string{"claudio", "trust", "--list", "--config", configPath}, strings.NewReader(""), stdout, stderr); code != 0 {
+		t.Fatalf("trust --list exit code %d, stderr: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "changed since trusted") {
+		t.Errorf("trust --list should flag the edited overlay, got %q", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := NewCLI().Run([]string{"claudio", "untrust", workDir, "--config", configPath}, strings.NewReader(""), stdout, stderr); code != 0 {
+		t.Fatalf("untrust exit code %d, stderr: %s", code, stderr.String())
+	}
+	if persisted := readPersistedConfig(t, configPath); len(persisted.TrustedProjects) != 0 {
+		t.Errorf("untrust should clear trusted_projects, got %v", persisted.TrustedProjects)
+	}
+}
+
+func jsonString(t *testing.T, s string) string {
+	t.Helper()
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("marshal string: %v", err)
+	}
+	return string(b)
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"claudio.click/internal/config"
+	"claudio.click/internal/hooks"
+	"claudio.click/internal/tracking"
+)
+
+// trackTurn keeps per-session turn timing in the tracking database: a
+// prompt (UserPromptSubmit, BeforeAgent) starts a turn and Stop or
+// AfterAgent finishes it. For a finished turn it returns how long it ran.
+// StopFailure and SessionEnd drop the open turn without a duration.
+// Timing is best-effort and off when tracking is disabled.
+func (c *CLI) trackTurn(ctx context.Context, hookEvent *hooks.HookEvent, now time.Time) (time.Duration, bool) {
+	if c.trackingDB == nil || hookEvent.SessionID == "" {
+		return 0, false
+	}
+
+	switch hookEvent.EventName {
+	case "UserPromptSubmit", "BeforeAgent":
+		if err := tracking.RecordTurnStart(ctx, c.trackingDB, hookEvent.SessionID, now); err != nil {
+			slog.Warn("turn start not recorded (continuing)", "error", err)
+		}
+	case "Stop", "AfterAgent":
+		d, ok, err := tracking.FinishTurn(ctx, c.trackingDB, hookEvent.SessionID, now)
+		if err != nil {
+			slog.Warn("turn duration unavailable (continuing)", "error", err)
+			return 0, false
+		}
+		if ok {
+			slog.Debug("turn finished", "session_id", hookEvent.SessionID, "duration", d)
+		}
+		return d, ok
+	case "StopFailure", "SessionEnd":
+		if _, _, err := tracking.FinishTurn(ctx, c.trackingDB, hookEvent.SessionID, now); err != nil {
+			slog.Warn("open turn not cleared (continuing)", "error", err)
+		}
+	}
+	return 0, false
+}
+
+// applyTurnDuration sets the turn fields on eventCtx and reports whether
+// the completion sound should be skipped because the turn was shorter
+// than turns.quiet_under_seconds.
+func applyTurnDuration(eventCtx *hooks.EventContext, turns *config.TurnsConfig, d time.Duration) (quiet bool) {
+	eventCtx.TurnDuration = d
+	eventCtx.LongTurn = d >= turns.EffectiveLongTurn()
+	return d < turns.QuietUnder()
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"claudio.click/internal/cli/testenv"
+	"claudio.click/internal/config"
+	"claudio.click/internal/tracking"
+)
+
+func TestTurnDuration_LongAndShortTurns(t *testing.T) {
+	root := testenv.IsolateXDG(t)
+	dbPath := filepath.Join(root, "claudio.db")
+	t.Setenv("CLAUDIO_SOUND_TRACKING", "true")
+	t.Setenv("CLAUDIO_SOUND_TRACKING_DB", dbPath)
+
+	packPath, physical := writeTestJSONSoundpack(t, root,
+		"default.wav", "completion/agent-complete.wav", "completion/agent-complete-long.wav")
+	cfg := config.NewConfigManager().GetDefaultConfig()
+	cfg.DefaultSoundpack = packPath
+	cfg.Turns = &config.TurnsConfig{LongTurnSeconds: 300, QuietUnderSeconds: 5}
+	configPath := filepath.Join(root, "config.json")
+	writeSeedConfig(t, configPath, cfg)
+	args := []string{"claudio", "--config", configPath}
+
+	// A turn that started ten minutes ago is long.
+	db, err := tracking.NewDatabase(dbPath)
+	if err != nil {
+		t.Fatalf("NewDatabase: %v", err)
+	}
+	if err := tracking.RecordTurnStart(context.Background(), db, "long", time.Now().Add(-10*time.Minute)); err != nil {
+		t.Fatalf("RecordTurnStart: %v", err)
+	}
+	db.Close()
+
+	stop := func(session string) string {
+		return `{"session_id":"` + session + `","cwd":"/src/app","hook_event_name":"Stop"}`
+	}
+	if got := runHookForPlays(t, args, stop("long")); len(got) != 1 || got[0] != physical["completion/agent-complete-long.wav"] {
+		t.Errorf("a long turn should play agent-complete-long, got %v", got)
+	}
+
+	// A turn that ends right after its prompt is under quiet_under_seconds.
+	runHookForPlays(t, args, `{"session_id":"short","cwd":"/src/app","hook_event_name":"UserPromptSubmit","prompt":"hi"}`)
+	if got := runHookForPlays(t, args, stop("short")); len(got) != 0 {
+		t.Errorf("a short turn should be silent, got %v", got)
+	}
+
+	// Without a recorded start the completion plays as usual.
+	if got := runHookForPlays(t, args, stop("unknown")); len(got) != 1 || got[0] != physical["completion/agent-complete.wav"] {
+		t.Errorf("a turn of unknown length should play agent-complete, got %v", got)
+	}
+}
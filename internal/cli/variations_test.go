@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"claudio.click/internal/cli/testenv"
+	"claudio.click/internal/config"
+)
+
+// TestVariations_RoundRobinAcrossHooks runs separate CLI instances, as
+// separate hook processes would, and checks a round-robin mapping keeps
+// its place between them.
+func TestVariations_RoundRobinAcrossHooks(t *testing.T) {
+	root := testenv.IsolateXDG(t)
+
+	var files []string
+	for _, name := range []string{"one.wav", "two.wav", "three.wav"} {
+		path := filepath.Join(root, name)
+		if err := os.WriteFile(path, createMinimalWAV(), 0o644); err != nil {
+			t.Fatalf("write wav: %v", err)
+		}
+		files = append(files, path)
+	}
+	pack := map[string]any{
+		"name": "variations-test",
+		"mappings": map[string]any{
+			"default.wav": map[string]any{"files": []string{"one.wav", "two.wav", "three.wav"}, "mode": "round-robin"},
+		},
+	}
+	data, err := json.Marshal(pack)
+	if err != nil {
+		t.Fatalf("marshal soundpack: %v", err)
+	}
+	packPath := filepath.Join(root, "pack.json")
+	if err := os.WriteFile(packPath, data, 0o644); err != nil {
+		t.Fatalf("write soundpack: %v", err)
+	}
+
+	cfg := config.NewConfigManager().GetDefaultConfig()
+	cfg.DefaultSoundpack = packPath
+	configPath := filepath.Join(root, "config.json")
+	writeSeedConfig(t, configPath, cfg)
+	args := []string{"claudio", "--config", configPath}
+
+	hook := `{"session_id":"var","cwd":"/tmp","hook_event_name":"PostToolUse","tool_name":"Bash","tool_input":{"command":"npm test"},"tool_response":{"stdout":"ok","stderr":"","interrupted":false}}`
+	for i, want := range append(files, files[0]) {
+		got := runHookForPlays(t, args, hook)
+		if len(got) != 1 || got[0] != want {
+			t.Fatalf("hook %d played %v, want %s", i+1, got, want)
+		}
+	}
+}
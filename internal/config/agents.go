@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+)
+
+// AgentProfile overrides settings for hooks invoked by one agent, so agents
+// running side by side can sound different. Unset fields keep the value
+// from the rest of the config.
+type AgentProfile struct {
+	Soundpack    string   `json:"soundpack,omitempty"`     // Replaces default_soundpack
+	Volume       *float64 `json:"volume,omitempty"`        // Replaces volume
+	EnabledHooks []string `json:"enabled_hooks,omitempty"` // Replaces enabled_hooks
+	RoutingFile  string   `json:"routing_file,omitempty"`  // Replaces routing_file
+}
+
+// NormalizeAgentName canonicalizes an agent name the way hook flags and
+// EventContext.Agent carry it.
+func NormalizeAgentName(agent string) string {
+	return strings.ToLower(strings.TrimSpace(agent))
+}
+
+// AgentProfile returns the profile configured for agent, or nil. Profile
+// keys match case-insensitively.
+func (c *Config) AgentProfile(agent string) *AgentProfile {
+	agent = NormalizeAgentName(agent)
+	if agent == "" {
+		return nil
+	}
+	for name, profile := range c.Agents {
+		if NormalizeAgentName(name) == agent {
+			return profile
+		}
+	}
+	return nil
+}
+
+// IsHookEnabled reports whether hooks named eventName should make a sound.
+// An empty enabled_hooks list enables every hook.
+func (c *Config) IsHookEnabled(eventName string) bool {
+	if len(c.EnabledHooks) == 0 {
+		return true
+	}
+	for _, name := range c.EnabledHooks {
+		if strings.EqualFold(name, eventName) {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyAgentProfile merges the profile for agent over cfg. cfg is returned
+// unchanged when the agent has no profile.
+func (cm *ConfigManager) ApplyAgentProfile(cfg *Config, agent string) *Config {
+	profile := cfg.AgentProfile(agent)
+	if profile == nil {
+		return cfg
+	}
+
+	slog.Debug("applying agent profile", "agent", NormalizeAgentName(agent))
+	return cm.MergeConfigs(cfg, &Config{
+		Volume:           profile.Volume,
+		DefaultSoundpack: profile.Soundpack,
+		EnabledHooks:     profile.EnabledHooks,
+		RoutingFile:      profile.RoutingFile,
+	})
+}
+
+// validateAgentProfiles returns one message per invalid profile setting.
+func validateAgentProfiles(agents map[string]*AgentProfile) []string {
+	names := make([]string, 0, len(agents))
+	for name := range agents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []string
+	for _, name := range names {
+		profile := agents[name]
+		if NormalizeAgentName(name) == "" {
+			errs = append(errs, "agents: empty agent name")
+			continue
+		}
+		if profile == nil {
+			continue
+		}
+		if profile.Volume != nil {
+			v := *profile.Volume
+			if math.IsNaN(v) || math.IsInf(v, 0) || v < 0.0 || v > 1.0 {
+				errs = append(errs, fmt.Sprintf("agents.%s: volume must be between 0.0 and 1.0, got %f", name, v))
+			}
+		}
+		errs = append(errs, validateHookNames(fmt.Sprintf("agents.%s.enabled_hooks", name), profile.EnabledHooks)...)
+	}
+	return errs
+}
+
+// validateHookNames rejects blank entries in an enabled_hooks list. Names
+// are not checked against a fixed set: each agent has its own events.
+func validateHookNames(field string, names []string) []string {
+	var errs []string
+	for i, name := range names {
+		if strings.TrimSpace(name) == "" {
+			errs = append(errs, fmt.Sprintf("%s[%d]: empty hook name", field, i))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfig_AgentProfile(t *testing.T) {
+	quiet := 0.1
+	cfg := &Config{Agents: map[string]*AgentProfile{
+		"Codex": {Soundpack: "codex-pack", Volume: &quiet},
+	}}
+
+	if p := cfg.AgentProfile(" codex "); p == nil || p.Soundpack != "codex-pack" {
+		t.Errorf("profile lookup should ignore case and space, got %+v", p)
+	}
+	if p := cfg.AgentProfile("claude"); p != nil {
+		t.Errorf("unexpected profile for claude: %+v", p)
+	}
+	if p := cfg.AgentProfile(""); p != nil {
+		t.Errorf("empty agent must not match, got %+v", p)
+	}
+}
+
+func TestApplyAgentProfile(t *testing.T) {
+	cm := NewConfigManager()
+	quiet := 0.1
+	base := cm.GetDefaultConfig()
+	base.DefaultSoundpack = "default"
+	base.RoutingFile = "/etc/routing.json"
+	base.Agents = map[string]*AgentProfile{
+		"codex": {
+			Soundpack:    "codex-pack",
+			Volume:       &quiet,
+			EnabledHooks: []string{"Stop", "PermissionRequest"},
+		},
+	}
+
+	got := cm.ApplyAgentProfile(base, "codex")
+	if got.DefaultSoundpack != "codex-pack" || got.Volume == nil || *got.Volume != quiet {
+		t.Errorf("profile not applied: soundpack=%q volume=%v", got.DefaultSoundpack, got.Volume)
+	}
+	if got.RoutingFile != "/etc/routing.json" {
+		t.Errorf("unset profile fields must keep the base value, routing_file=%q", got.RoutingFile)
+	}
+	if !got.IsHookEnabled("stop") || got.IsHookEnabled("PreToolUse") {
+		t.Errorf("enabled_hooks not applied: %v", got.EnabledHooks)
+	}
+	if base.DefaultSoundpack != "default" {
+		t.Error("ApplyAgentProfile must not modify its input")
+	}
+
+	if same := cm.ApplyAgentProfile(base, "gemini"); same != base {
+		t.Error("an agent without a profile should get the config unchanged")
+	}
+	if !base.IsHookEnabled("PreToolUse") {
+		t.Error("empty enabled_hooks should enable every hook")
+	}
+}
+
+func TestMergeConfigs_AgentProfilesMergePerAgent(t *testing.T) {
+	cm := NewConfigManager()
+	base := cm.GetDefaultConfig()
+	base.Agents = map[string]*AgentProfile{
+		"claude": {Soundpack: "claude-pack"},
+		"codex":  {Soundpack: "codex-pack"},
+	}
+	override := &Config{Agents: map[string]*AgentProfile{
+		"codex": {Soundpack: "project-codex-pack"},
+	}}
+
+	merged := cm.MergeConfigs(base, override)
+	if merged.Agents["claude"].Soundpack != "claude-pack" {
+		t.Errorf("claude profile lost: %+v", merged.Agents["claude"])
+	}
+	if merged.Agents["codex"].Soundpack != "project-codex-pack" {
+		t.Errorf("codex profile not overridden: %+v", merged.Agents["codex"])
+	}
+	if base.Agents["codex"].Soundpack != "codex-pack" {
+		t.Error("MergeConfigs must not modify the base profiles map")
+	}
+}
+
+func TestValidateConfig_AgentProfiles(t *testing.T) {
+	cm := NewConfigManager()
+	loud := 1.5
+	cfg := cm.GetDefaultConfig()
+	cfg.EnabledHooks = []string{"Stop", " "}
+	cfg.Agents = map[string]*AgentProfile{
+		"codex": {Volume: &loud, EnabledHooks: []string{""}},
+		" ":     {},
+	}
+
+	err := cm.ValidateConfig(cfg)
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{
+		"enabled_hooks[1]: empty hook name",
+		"agents.codex: volume must be between 0.0 and 1.0",
+		"agents.codex.enabled_hooks[0]: empty hook name",
+		"agents: empty agent name",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Ambient loop defaults used when the matching field is unset.
+const (
+	DefaultAmbientSound      = "ambient/working.wav"
+	DefaultAmbientVolume     = 0.3
+	DefaultAmbientFadeMs     = 1500
+	DefaultAmbientMaxMinutes = 30
+)
+
+// AmbientConfig loops a quiet background sound from the moment a prompt is
+// submitted until the agent stops, fails, ends the session or asks for
+// permission. Each session has its own loop.
+type AmbientConfig struct {
+	Enabled    bool    `json:"enabled"`               // Whether the loop plays while the agent works
+	Sound      string  `json:"sound,omitempty"`       // Soundpack key of the loop (empty = default)
+	Volume     float64 `json:"volume,omitempty"`      // Loop level relative to the main volume (0 = default)
+	FadeMs     int     `json:"fade_ms,omitempty"`     // Fade-in and fade-out length (0 = default)
+	MaxMinutes int     `json:"max_minutes,omitempty"` // Safety cap on one loop (0 = default)
+}
+
+// EffectiveSound returns the soundpack key that is looped.
+func (a *AmbientConfig) EffectiveSound() string {
+	if a == nil || a.Sound == "" {
+		return DefaultAmbientSound
+	}
+	return a.Sound
+}
+
+// EffectiveVolume returns the loop level relative to the main volume.
+func (a *AmbientConfig) EffectiveVolume() float64 {
+	if a == nil || a.Volume <= 0 {
+		return DefaultAmbientVolume
+	}
+	return a.Volume
+}
+
+// EffectiveFade returns the fade-in and fade-out length.
+func (a *AmbientConfig) EffectiveFade() time.Duration {
+	if a == nil || a.FadeMs <= 0 {
+		return DefaultAmbientFadeMs * time.Millisecond
+	}
+	return time.Duration(a.FadeMs) * time.Millisecond
+}
+
+// EffectiveMaxDuration returns how long one loop may run if nothing stops it.
+func (a *AmbientConfig) EffectiveMaxDuration() time.Duration {
+	if a == nil || a.MaxMinutes <= 0 {
+		return DefaultAmbientMaxMinutes * time.Minute
+	}
+	return time.Duration(a.MaxMinutes) * time.Minute
+}
+
+// validateAmbient returns one message per invalid ambient setting.
+func validateAmbient(a *AmbientConfig) []string {
+	if a == nil {
+		return nil
+	}
+	var errs []string
+	if a.Volume < 0 || a.Volume > 1 {
+		errs = append(errs, fmt.Sprintf("ambient.volume must be between 0.0 and 1.0, got %g", a.Volume))
+	}
+	if a.FadeMs < 0 {
+		errs = append(errs, fmt.Sprintf("ambient.fade_ms must be >= 0, got %d", a.FadeMs))
+	}
+	if a.MaxMinutes < 0 {
+		errs = append(errs, fmt.Sprintf("ambient.max_minutes must be >= 0, got %d", a.MaxMinutes))
+	}
+	return errs
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAmbientConfig_Defaults(t *testing.T) {
+	var unset *AmbientConfig
+	if got := unset.EffectiveSound(); got != DefaultAmbientSound {
+		t.Errorf("EffectiveSound() = %q, want default", got)
+	}
+	if got := unset.EffectiveVolume(); got != DefaultAmbientVolume {
+		t.Errorf("EffectiveVolume() = %g, want default", got)
+	}
+	if got := unset.EffectiveFade(); got != DefaultAmbientFadeMs*time.Millisecond {
+		t.Errorf("EffectiveFade() = %v, want default", got)
+	}
+	if got := unset.EffectiveMaxDuration(); got != DefaultAmbientMaxMinutes*time.Minute {
+		t.Errorf("EffectiveMaxDuration() = %v, want default", got)
+	}
+
+	a := &AmbientConfig{Sound: "ambient/rain.wav", Volume: 0.1, FadeMs: 200, MaxMinutes: 5}
+	if a.EffectiveSound() != "ambient/rain.wav" || a.EffectiveVolume() != 0.1 ||
+		a.EffectiveFade() != 200*time.Millisecond || a.EffectiveMaxDuration() != 5*time.Minute {
+		t.Errorf("explicit ambient settings not honoured: %+v", a)
+	}
+}
+
+func TestValidateConfig_Ambient(t *testing.T) {
+	cfg := NewConfigManager().GetDefaultConfig()
+	cfg.Ambient = &AmbientConfig{Enabled: true, Volume: 2, FadeMs: -1}
+	err := NewConfigManager().ValidateConfig(cfg)
+	if err == nil {
+		t.Fatal("expected invalid ambient settings to be rejected")
+	}
+	for _, want := range []string{"ambient.volume", "ambient.fade_ms"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %s", err, want)
+		}
+	}
+}
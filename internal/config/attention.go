@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Attention reminder defaults used when the matching field is unset.
+const (
+	DefaultAttentionAfterSeconds = 30
+	DefaultAttentionMaxReminders = 5
+	DefaultAttentionVolumeStep   = 0.15
+	DefaultAttentionMaxVolume    = 1.0
+)
+
+// AttentionConfig replays the sound for a permission request or a
+// permission/idle notification while the session stays blocked on the
+// user. Each reminder is louder than the last, up to MaxVolume, and they
+// stop as soon as the session produces another hook event.
+type AttentionConfig struct {
+	Enabled      bool    `json:"enabled"`                 // Whether unanswered requests are repeated
+	AfterSeconds int     `json:"after_seconds,omitempty"` // Wait before each reminder (0 = default)
+	MaxReminders int     `json:"max_reminders,omitempty"` // Reminders per request (0 = default)
+	VolumeStep   float64 `json:"volume_step,omitempty"`   // Volume added per reminder (0 = default)
+	MaxVolume    float64 `json:"max_volume,omitempty"`    // Reminder volume cap (0 = default)
+}
+
+// EffectiveAfter returns the wait before each reminder.
+func (a *AttentionConfig) EffectiveAfter() time.Duration {
+	if a == nil || a.AfterSeconds <= 0 {
+		return DefaultAttentionAfterSeconds * time.Second
+	}
+	return time.Duration(a.AfterSeconds) * time.Second
+}
+
+// EffectiveMaxReminders returns how many reminders one request gets.
+func (a *AttentionConfig) EffectiveMaxReminders() int {
+	if a == nil || a.MaxReminders <= 0 {
+		return DefaultAttentionMaxReminders
+	}
+	return a.MaxReminders
+}
+
+// ReminderVolume returns the volume of reminder n (1-based) for a sound
+// first played at base: one step louder per reminder, capped at max_volume
+// but never quieter than base.
+func (a *AttentionConfig) ReminderVolume(base float64, n int) float64 {
+	step, limit := DefaultAttentionVolumeStep, DefaultAttentionMaxVolume
+	if a != nil && a.VolumeStep > 0 {
+		step = a.VolumeStep
+	}
+	if a != nil && a.MaxVolume > 0 {
+		limit = a.MaxVolume
+	}
+	v := base + step*float64(n)
+	if v > limit {
+		v = limit
+	}
+	if v < base {
+		v = base
+	}
+	return v
+}
+
+// validateAttention returns one message per invalid attention setting.
+func validateAttention(a *AttentionConfig) []string {
+	if a == nil {
+		return nil
+	}
+	var errs []string
+	if a.AfterSeconds < 0 {
+		errs = append(errs, fmt.Sprintf("attention.after_seconds must be >= 0, got %d", a.AfterSeconds))
+	}
+	if a.MaxReminders < 0 {
+		errs = append(errs, fmt.Sprintf("attention.max_reminders must be >= 0, got %d", a.MaxReminders))
+	}
+	if a.VolumeStep < 0 || a.VolumeStep > 1 {
+		errs = append(errs, fmt.Sprintf("attention.volume_step must be between 0.0 and 1.0, got %g", a.VolumeStep))
+	}
+	if a.MaxVolume < 0 || a.MaxVolume > 1 {
+		errs = append(errs, fmt.Sprintf("attention.max_volume must be between 0.0 and 1.0, got %g", a.MaxVolume))
+	}
+	return errs
+}
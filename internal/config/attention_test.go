@@ -0,0 +1,51 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAttentionConfig_Defaults(t *testing.T) {
+	var unset *AttentionConfig
+	if got := unset.EffectiveAfter(); got != DefaultAttentionAfterSeconds*time.Second {
+		t.Errorf("EffectiveAfter() = %v, want default", got)
+	}
+	if got := unset.EffectiveMaxReminders(); got != DefaultAttentionMaxReminders {
+		t.Errorf("EffectiveMaxReminders() = %d, want default", got)
+	}
+}
+
+func TestAttentionConfig_ReminderVolume(t *testing.T) {
+	a := &AttentionConfig{VolumeStep: 0.2, MaxVolume: 0.9}
+	tests := []struct {
+		base float64
+		n    int
+		want float64
+	}{
+		{0.5, 1, 0.7},
+		{0.5, 2, 0.9},
+		{0.5, 3, 0.9},
+		{0.95, 1, 0.95}, // already above the cap: never quieter than the first play
+	}
+	for _, tt := range tests {
+		got := a.ReminderVolume(tt.base, tt.n)
+		if got < tt.want-1e-9 || got > tt.want+1e-9 {
+			t.Errorf("ReminderVolume(%g, %d) = %g, want %g", tt.base, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestValidateConfig_Attention(t *testing.T) {
+	cfg := NewConfigManager().GetDefaultConfig()
+	cfg.Attention = &AttentionConfig{Enabled: true, AfterSeconds: -1, MaxVolume: 1.5}
+	err := NewConfigManager().ValidateConfig(cfg)
+	if err == nil {
+		t.Fatal("expected invalid attention settings to be rejected")
+	}
+	for _, want := range []string{"attention.after_seconds", "attention.max_volume"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %s", err, want)
+		}
+	}
+}
@@ -12,9 +12,11 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/afero"
 
+	"claudio.click/internal/hooks"
 	"claudio.click/internal/platform"
 )
 
@@ -45,11 +47,29 @@ type Config struct {
 	Volume           *float64             `json:"volume,omitempty"`        // Audio volume (0.0 to 1.0), nil means use default
 	DefaultSoundpack string               `json:"default_soundpack"`       // Default soundpack to use
 	SoundpackPaths   []string             `json:"soundpack_paths"`         // Additional paths to search for soundpacks
+	SoundpackStack   []string             `json:"soundpack_stack,omitempty"` // Overlay packs searched before default_soundpack, highest priority first
+	SoundpackCatalogs []string            `json:"soundpack_catalogs,omitempty"` // Catalog URLs or files for `soundpack search` and `soundpack add <name>`
 	Enabled          bool                 `json:"enabled"`                 // Whether Claudio is enabled
 	LogLevel         string               `json:"log_level"`               // Log level (debug, info, warn, error)
 	AudioBackend     string               `json:"audio_backend"`           // Audio backend (auto, system_command, malgo)
+	AudioDevice      string               `json:"audio_device,omitempty"`  // Output device ID or name substring (empty = system default)
 	FileLogging      *FileLoggingConfig   `json:"file_logging,omitempty"`  // File logging configuration
 	SoundTracking    *SoundTrackingConfig `json:"sound_tracking,omitempty"` // Sound tracking configuration
+	Playback         *PlaybackConfig      `json:"playback,omitempty"`       // Overlap policy for concurrent sounds
+	Speech           *SpeechConfig        `json:"speech,omitempty"`         // Text-to-speech engine for say: templates
+	RateLimits       []RateLimitRule      `json:"rate_limits,omitempty"`    // Debounce/throttle rules applied before sound mapping
+	RoutingFile      string               `json:"routing_file,omitempty"`   // Routing rules file (default: routing.json beside config.json)
+	CommandSelection string               `json:"command_selection,omitempty"` // Which command of a compound Bash line names the hint: first, last, most-specific
+	TrustedProjects  []TrustedProject     `json:"trusted_projects,omitempty"`  // Project .claudio.json overlays approved with `claudio trust`
+	EnabledHooks     []string             `json:"enabled_hooks,omitempty"`     // Hook events that make sounds (empty = all)
+	Agents           map[string]*AgentProfile `json:"agents,omitempty"`        // Per-agent overrides keyed by agent name
+	Schedule         *ScheduleConfig      `json:"schedule,omitempty"`          // Quiet hours and active days
+	MutedUntil       *time.Time           `json:"muted_until,omitempty"`       // Temporary mute set by `claudio mute --for/--until`
+	FileGroups       []FileGroup          `json:"file_groups,omitempty"`       // Path-pattern groups for file-type sound keys (unset = defaults)
+	Turns            *TurnsConfig         `json:"turns,omitempty"`             // Long-turn and short-turn completion sounds
+	Attention        *AttentionConfig     `json:"attention,omitempty"`         // Reminders for unanswered permission requests
+	Ambient          *AmbientConfig       `json:"ambient,omitempty"`           // Background loop while the agent works
+	SessionIdentity  *SessionIdentityConfig `json:"session_identity,omitempty"` // Per-session pitch and pan
 }
 
 // XDGInterface defines the interface for XDG directory operations
@@ -113,6 +133,8 @@ func (cm *ConfigManager) GetDefaultConfig() *Config {
 			Compress:   true,
 		},
 		SoundTracking: GetDefaultSoundTrackingConfig(),
+		Playback:      GetDefaultPlaybackConfig(),
+		Speech:        GetDefaultSpeechConfig(),
 	}
 
 	slog.Debug("generated default config",
@@ -244,6 +266,16 @@ func (cm *ConfigManager) ValidateConfig(config *Config) error {
 	if config.DefaultSoundpack == "" {
 		errors = append(errors, "default soundpack cannot be empty")
 	}
+	for i, name := range config.SoundpackStack {
+		if strings.TrimSpace(name) == "" {
+			errors = append(errors, fmt.Sprintf("soundpack_stack[%d] cannot be empty", i))
+		}
+	}
+	for i, location := range config.SoundpackCatalogs {
+		if strings.TrimSpace(location) == "" {
+			errors = append(errors, fmt.Sprintf("soundpack_catalogs[%d] cannot be empty", i))
+		}
+	}
 
 	// Validate log level
 	validLogLevels := []string{"debug", "info", "warn", "error"}
@@ -285,6 +317,62 @@ func (cm *ConfigManager) ValidateConfig(config *Config) error {
 		}
 	}
 
+	// Validate playback configuration
+	if config.Playback != nil {
+		if !IsValidOverlapPolicy(config.Playback.OverlapPolicy) {
+			errors = append(errors, fmt.Sprintf("invalid playback overlap_policy '%s', must be one of: %s",
+				config.Playback.OverlapPolicy, strings.Join(GetOverlapPolicies(), ", ")))
+		}
+		if config.Playback.MaxQueueDepth < 0 {
+			errors = append(errors, fmt.Sprintf("playback max_queue_depth must be >= 0, got %d", config.Playback.MaxQueueDepth))
+		}
+	}
+
+	// Validate speech configuration
+	if config.Speech != nil {
+		if !IsValidSpeechEngine(config.Speech.Engine) {
+			errors = append(errors, fmt.Sprintf("invalid speech engine '%s', must be one of: %s",
+				config.Speech.Engine, strings.Join(GetSpeechEngines(), ", ")))
+		}
+		if config.Speech.Rate < 0 {
+			errors = append(errors, fmt.Sprintf("speech rate must be >= 0, got %d", config.Speech.Rate))
+		}
+		if config.Speech.Engine == SpeechEnginePiper && config.Speech.PiperModel == "" {
+			errors = append(errors, "speech engine 'piper' requires piper_model")
+		}
+	}
+
+	// Validate command selection
+	if !hooks.IsValidCommandSelection(config.CommandSelection) {
+		errors = append(errors, fmt.Sprintf("invalid command_selection '%s', must be one of: %s",
+			config.CommandSelection, strings.Join(hooks.GetCommandSelectionModes(), ", ")))
+	}
+
+	// Validate rate limit rules
+	errors = append(errors, validateRateLimitRules(config.RateLimits)...)
+
+	// Validate hook filters and agent profiles
+	errors = append(errors, validateHookNames("enabled_hooks", config.EnabledHooks)...)
+	errors = append(errors, validateAgentProfiles(config.Agents)...)
+
+	// Validate mute schedule
+	errors = append(errors, validateSchedule(config.Schedule)...)
+
+	// Validate file groups
+	errors = append(errors, validateFileGroups(config.FileGroups)...)
+
+	// Validate turn thresholds
+	errors = append(errors, validateTurns(config.Turns)...)
+
+	// Validate attention reminders
+	errors = append(errors, validateAttention(config.Attention)...)
+
+	// Validate the ambient loop
+	errors = append(errors, validateAmbient(config.Ambient)...)
+
+	// Validate per-session pitch and pan
+	errors = append(errors, validateSessionIdentity(config.SessionIdentity)...)
+
 	if len(errors) > 0 {
 		errMsg := strings.Join(errors, "; ")
 		slog.Error("config validation failed", "errors", errMsg)
@@ -318,6 +406,11 @@ func (cm *ConfigManager) MergeConfigs(base, override *Config) *Config {
 		slog.Debug("merged soundpack paths override", "paths", override.SoundpackPaths)
 	}
 
+	if len(override.SoundpackStack) > 0 {
+		merged.SoundpackStack = override.SoundpackStack
+		slog.Debug("merged soundpack stack override", "stack", override.SoundpackStack)
+	}
+
 	if override.LogLevel != "" {
 		merged.LogLevel = override.LogLevel
 		slog.Debug("merged log level override", "value", override.LogLevel)
@@ -328,6 +421,90 @@ func (cm *ConfigManager) MergeConfigs(base, override *Config) *Config {
 		slog.Debug("merged audio backend override", "value", override.AudioBackend)
 	}
 
+	if override.AudioDevice != "" {
+		merged.AudioDevice = override.AudioDevice
+		slog.Debug("merged audio device override", "value", override.AudioDevice)
+	}
+
+	if override.Playback != nil {
+		merged.Playback = override.Playback
+		slog.Debug("merged playback override", "overlap_policy", override.Playback.OverlapPolicy)
+	}
+
+	if len(override.FileGroups) > 0 {
+		merged.FileGroups = override.FileGroups
+		slog.Debug("merged file groups override", "groups", len(override.FileGroups))
+	}
+
+	if override.Schedule != nil {
+		merged.Schedule = override.Schedule
+		slog.Debug("merged schedule override", "quiet_hours", len(override.Schedule.QuietHours), "days", override.Schedule.Days)
+	}
+
+	if override.MutedUntil != nil {
+		merged.MutedUntil = override.MutedUntil
+		slog.Debug("merged muted_until override", "value", *override.MutedUntil)
+	}
+
+	if override.Turns != nil {
+		merged.Turns = override.Turns
+		slog.Debug("merged turns override", "long_turn_seconds", override.Turns.LongTurnSeconds, "quiet_under_seconds", override.Turns.QuietUnderSeconds)
+	}
+
+	if override.Attention != nil {
+		merged.Attention = override.Attention
+		slog.Debug("merged attention override", "enabled", override.Attention.Enabled, "after_seconds", override.Attention.AfterSeconds)
+	}
+
+	if override.Ambient != nil {
+		merged.Ambient = override.Ambient
+		slog.Debug("merged ambient override", "enabled", override.Ambient.Enabled, "sound", override.Ambient.Sound)
+	}
+
+	if override.SessionIdentity != nil {
+		merged.SessionIdentity = override.SessionIdentity
+		slog.Debug("merged session identity override", "enabled", override.SessionIdentity.Enabled, "key", override.SessionIdentity.Key)
+	}
+
+	if override.Speech != nil {
+		merged.Speech = override.Speech
+		slog.Debug("merged speech override", "engine", override.Speech.Engine)
+	}
+
+	if len(override.RateLimits) > 0 {
+		merged.RateLimits = override.RateLimits
+		slog.Debug("merged rate limits override", "rules", len(override.RateLimits))
+	}
+
+	if override.RoutingFile != "" {
+		merged.RoutingFile = override.RoutingFile
+		slog.Debug("merged routing file override", "value", override.RoutingFile)
+	}
+
+	if override.CommandSelection != "" {
+		merged.CommandSelection = override.CommandSelection
+		slog.Debug("merged command selection override", "value", override.CommandSelection)
+	}
+
+	if len(override.EnabledHooks) > 0 {
+		merged.EnabledHooks = override.EnabledHooks
+		slog.Debug("merged enabled hooks override", "hooks", override.EnabledHooks)
+	}
+
+	if len(override.Agents) > 0 {
+		// Profiles merge per agent, so a project can retune one agent
+		// without repeating the user's profiles for the others.
+		agents := make(map[string]*AgentProfile, len(base.Agents)+len(override.Agents))
+		for name, profile := range base.Agents {
+			agents[name] = profile
+		}
+		for name, profile := range override.Agents {
+			agents[name] = profile
+		}
+		merged.Agents = agents
+		slog.Debug("merged agent profiles override", "agents", len(override.Agents))
+	}
+
 	// Note: Enabled is a bool, so we need special handling
 	// In JSON, explicit false would override true from base
 	// This is handled naturally by the struct unmarshaling
@@ -338,13 +515,20 @@ func (cm *ConfigManager) MergeConfigs(base, override *Config) *Config {
 
 // ApplyEnvironmentOverrides applies environment variable overrides to config
 func (cm *ConfigManager) ApplyEnvironmentOverrides(config *Config) *Config {
+	return cm.ApplyEnvironmentOverridesFrom(config, os.Getenv)
+}
+
+// ApplyEnvironmentOverridesFrom applies the overrides getenv reports, for a
+// process acting on another's environment: the daemon applies the CLAUDIO_*
+// variables each forwarded hook carries.
+func (cm *ConfigManager) ApplyEnvironmentOverridesFrom(config *Config, getenv func(string) string) *Config {
 	slog.Debug("applying environment variable overrides")
 
 	// Create a copy to modify
 	result := *config
 
 	// CLAUDIO_VOLUME
-	if volStr := os.Getenv("CLAUDIO_VOLUME"); volStr != "" {
+	if volStr := getenv("CLAUDIO_VOLUME"); volStr != "" {
 		if vol, err := strconv.ParseFloat(volStr, 64); err == nil {
 			result.Volume = &vol
 			slog.Debug("applied volume override from environment", "value", vol)
@@ -354,13 +538,13 @@ func (cm *ConfigManager) ApplyEnvironmentOverrides(config *Config) *Config {
 	}
 
 	// CLAUDIO_SOUNDPACK
-	if soundpack := os.Getenv("CLAUDIO_SOUNDPACK"); soundpack != "" {
+	if soundpack := getenv("CLAUDIO_SOUNDPACK"); soundpack != "" {
 		result.DefaultSoundpack = soundpack
 		slog.Debug("applied soundpack override from environment", "value", soundpack)
 	}
 
 	// CLAUDIO_ENABLED
-	if enabledStr := os.Getenv("CLAUDIO_ENABLED"); enabledStr != "" {
+	if enabledStr := getenv("CLAUDIO_ENABLED"); enabledStr != "" {
 		if enabled, err := strconv.ParseBool(enabledStr); err == nil {
 			result.Enabled = enabled
 			slog.Debug("applied enabled override from environment", "value", enabled)
@@ -370,13 +554,13 @@ func (cm *ConfigManager) ApplyEnvironmentOverrides(config *Config) *Config {
 	}
 
 	// CLAUDIO_LOG_LEVEL
-	if logLevel := os.Getenv("CLAUDIO_LOG_LEVEL"); logLevel != "" {
+	if logLevel := getenv("CLAUDIO_LOG_LEVEL"); logLevel != "" {
 		result.LogLevel = logLevel
 		slog.Debug("applied log level override from environment", "value", logLevel)
 	}
 
 	// CLAUDIO_AUDIO_BACKEND
-	if audioBackend := os.Getenv("CLAUDIO_AUDIO_BACKEND"); audioBackend != "" {
+	if audioBackend := getenv("CLAUDIO_AUDIO_BACKEND"); audioBackend != "" {
 		// Validate the backend before applying
 		if cm.IsValidAudioBackend(audioBackend) {
 			result.AudioBackend = audioBackend
@@ -386,11 +570,27 @@ func (cm *ConfigManager) ApplyEnvironmentOverrides(config *Config) *Config {
 		}
 	}
 
+	// CLAUDIO_AUDIO_DEVICE
+	if audioDevice := getenv("CLAUDIO_AUDIO_DEVICE"); audioDevice != "" {
+		result.AudioDevice = audioDevice
+		slog.Debug("applied audio device override from environment", "value", audioDevice)
+	}
+
+	// CLAUDIO_COMMAND_SELECTION
+	if selection := getenv("CLAUDIO_COMMAND_SELECTION"); selection != "" {
+		if hooks.IsValidCommandSelection(selection) {
+			result.CommandSelection = selection
+			slog.Debug("applied command selection override from environment", "value", selection)
+		} else {
+			slog.Warn("invalid CLAUDIO_COMMAND_SELECTION environment variable", "value", selection)
+		}
+	}
+
 	// CLAUDIO_FILE_LOGGING — opt-out switch so test environments can
 	// disable the lumberjack file handle that would otherwise block
 	// t.TempDir() cleanup on Windows. Recognised values match
 	// strconv.ParseBool ("1"/"0", "true"/"false", etc.).
-	if fileLoggingStr := os.Getenv("CLAUDIO_FILE_LOGGING"); fileLoggingStr != "" {
+	if fileLoggingStr := getenv("CLAUDIO_FILE_LOGGING"); fileLoggingStr != "" {
 		if enabled, err := strconv.ParseBool(fileLoggingStr); err == nil {
 			if result.FileLogging == nil {
 				result.FileLogging = &FileLoggingConfig{}
@@ -406,7 +606,19 @@ func (cm *ConfigManager) ApplyEnvironmentOverrides(config *Config) *Config {
 	if result.SoundTracking == nil {
 		result.SoundTracking = GetDefaultSoundTrackingConfig()
 	}
-	result.SoundTracking = ApplySoundTrackingEnvironmentOverrides(result.SoundTracking)
+	result.SoundTracking = applySoundTrackingEnvironment(result.SoundTracking, getenv)
+
+	// Apply playback environment overrides
+	if result.Playback == nil {
+		result.Playback = GetDefaultPlaybackConfig()
+	}
+	result.Playback = applyPlaybackEnvironment(result.Playback, getenv)
+
+	// Apply speech environment overrides
+	if result.Speech == nil {
+		result.Speech = GetDefaultSpeechConfig()
+	}
+	result.Speech = applySpeechEnvironment(result.Speech, getenv)
 
 	slog.Debug("environment overrides applied")
 	return &result
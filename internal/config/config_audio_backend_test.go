@@ -200,6 +200,28 @@ func TestConfigAudioBackendMerging(t *testing.T) {
 	}
 }
 
+func TestConfigAudioDevice(t *testing.T) {
+	mgr := NewConfigManager()
+
+	if device := mgr.GetDefaultConfig().AudioDevice; device != "" {
+		t.Errorf("expected the default device to be empty (system default), got '%s'", device)
+	}
+
+	base := mgr.GetDefaultConfig()
+	base.AudioDevice = "Speakers"
+	if merged := mgr.MergeConfigs(base, &Config{AudioDevice: "Headphones"}); merged.AudioDevice != "Headphones" {
+		t.Errorf("expected merged audio device 'Headphones', got '%s'", merged.AudioDevice)
+	}
+	if merged := mgr.MergeConfigs(base, &Config{}); merged.AudioDevice != "Speakers" {
+		t.Errorf("expected audio device to remain 'Speakers' with empty override, got '%s'", merged.AudioDevice)
+	}
+
+	t.Setenv("CLAUDIO_AUDIO_DEVICE", "bluez_output.AC_80_0A.1")
+	if result := mgr.ApplyEnvironmentOverrides(base); result.AudioDevice != "bluez_output.AC_80_0A.1" {
+		t.Errorf("expected CLAUDIO_AUDIO_DEVICE to set the device, got '%s'", result.AudioDevice)
+	}
+}
+
 func TestGetSupportedAudioBackends(t *testing.T) {
 	mgr := NewConfigManager()
 
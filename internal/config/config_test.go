@@ -324,6 +324,49 @@ func TestValidateConfig_RejectsInf(t *testing.T) {
 	}
 }
 
+func TestValidateConfig_SoundpackStack(t *testing.T) {
+	mgr := NewConfigManager()
+	cfg := &Config{
+		DefaultSoundpack: "default",
+		SoundpackStack:   []string{"git-sounds", " "},
+		Enabled:          true,
+		AudioBackend:     "auto",
+	}
+	err := mgr.ValidateConfig(cfg)
+	if err == nil || !strings.Contains(err.Error(), "soundpack_stack[1]") {
+		t.Errorf("expected the blank stack entry to be rejected, got: %v", err)
+	}
+
+	cfg.SoundpackStack = []string{"git-sounds"}
+	if err := mgr.ValidateConfig(cfg); err != nil {
+		t.Errorf("valid stack rejected: %v", err)
+	}
+
+	merged := mgr.MergeConfigs(&Config{DefaultSoundpack: "default", SoundpackStack: []string{"base"}}, cfg)
+	if len(merged.SoundpackStack) != 1 || merged.SoundpackStack[0] != "git-sounds" {
+		t.Errorf("merged soundpack_stack = %v, want the override", merged.SoundpackStack)
+	}
+}
+
+func TestValidateConfig_SoundpackCatalogs(t *testing.T) {
+	mgr := NewConfigManager()
+	cfg := &Config{
+		DefaultSoundpack:  "default",
+		SoundpackCatalogs: []string{"https://example.com/catalog.json", ""},
+		Enabled:           true,
+		AudioBackend:      "auto",
+	}
+	err := mgr.ValidateConfig(cfg)
+	if err == nil || !strings.Contains(err.Error(), "soundpack_catalogs[1]") {
+		t.Errorf("expected the blank catalog entry to be rejected, got: %v", err)
+	}
+
+	cfg.SoundpackCatalogs = []string{"https://example.com/catalog.json"}
+	if err := mgr.ValidateConfig(cfg); err != nil {
+		t.Errorf("valid catalogs rejected: %v", err)
+	}
+}
+
 func TestSaveConfig(t *testing.T) {
 	mgr := NewConfigManager()
 
@@ -954,3 +997,32 @@ func ptrFloat64(v float64) *float64 {
 func contains(s, substr string) bool {
 	return strings.Contains(s, substr)
 }
+
+func TestConfig_CommandSelection(t *testing.T) {
+	cm := NewConfigManager()
+
+	cfg := cm.GetDefaultConfig()
+	cfg.CommandSelection = "loudest"
+	if err := cm.ValidateConfig(cfg); err == nil || !strings.Contains(err.Error(), "invalid command_selection") {
+		t.Errorf("expected command_selection validation error, got %v", err)
+	}
+
+	cfg.CommandSelection = "last"
+	if err := cm.ValidateConfig(cfg); err != nil {
+		t.Errorf("last should be valid: %v", err)
+	}
+
+	merged := cm.MergeConfigs(cm.GetDefaultConfig(), &Config{CommandSelection: "first"})
+	if merged.CommandSelection != "first" {
+		t.Errorf("merged CommandSelection = %q, want first", merged.CommandSelection)
+	}
+
+	t.Setenv("CLAUDIO_COMMAND_SELECTION", "first")
+	if got := cm.ApplyEnvironmentOverrides(cm.GetDefaultConfig()).CommandSelection; got != "first" {
+		t.Errorf("env override CommandSelection = %q, want first", got)
+	}
+	t.Setenv("CLAUDIO_COMMAND_SELECTION", "bogus")
+	if got := cm.ApplyEnvironmentOverrides(cm.GetDefaultConfig()).CommandSelection; got != "" {
+		t.Errorf("invalid env value should be ignored, got %q", got)
+	}
+}
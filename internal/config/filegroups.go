@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// FileGroup names a class of files by path pattern, so a soundpack can
+// give edits to tests or docs their own sound (success/edit-tests.wav).
+// Groups are tried in order and the first match wins.
+type FileGroup struct {
+	Name     string   `json:"name"`     // Group name used in sound keys, e.g. "tests"
+	Patterns []string `json:"patterns"` // Globs; without a '/' they match the base name
+}
+
+// fileGroupNamePattern restricts group names to what a sound key can hold.
+var fileGroupNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// GetDefaultFileGroups returns the groups used when file_groups is unset.
+// tests comes first so foo_test.go is a test, not just code.
+func GetDefaultFileGroups() []FileGroup {
+	return []FileGroup{
+		{Name: "tests", Patterns: []string{
+			"*_test.go", "test_*.py", "*_test.py", "*.test.*", "*.spec.*",
+			"**/test/**", "**/tests/**", "**/__tests__/**", "**/spec/**",
+		}},
+		{Name: "docs", Patterns: []string{
+			"*.md", "*.mdx", "*.rst", "*.adoc", "*.txt", "README*", "CHANGELOG*", "**/docs/**",
+		}},
+		{Name: "config", Patterns: []string{
+			"*.json", "*.yaml", "*.yml", "*.toml", "*.ini", "*.cfg", "*.conf", "*.env", ".env*",
+			"Dockerfile", "Makefile", "go.mod", "go.sum", "package-lock.json",
+		}},
+		{Name: "code", Patterns: []string{
+			"*.go", "*.py", "*.js", "*.jsx", "*.ts", "*.tsx", "*.mjs", "*.cjs", "*.rs", "*.java",
+			"*.kt", "*.swift", "*.c", "*.h", "*.cc", "*.cpp", "*.hpp", "*.cs", "*.rb", "*.php",
+			"*.sh", "*.bash", "*.zsh", "*.lua", "*.sql", "*.html", "*.css", "*.scss", "*.vue", "*.svelte",
+		}},
+	}
+}
+
+// FileGroupFor returns the name of the first group matching filePath, or ""
+// when none does. An unset file_groups uses GetDefaultFileGroups.
+func (c *Config) FileGroupFor(filePath string) string {
+	if filePath == "" {
+		return ""
+	}
+	groups := c.FileGroups
+	if groups == nil {
+		groups = GetDefaultFileGroups()
+	}
+
+	slashed := filepath.ToSlash(filePath)
+	if !strings.HasPrefix(slashed, "/") {
+		// Relative paths still match "**/docs/**" style patterns.
+		slashed = "/" + slashed
+	}
+	base := path.Base(slashed)
+	for _, g := range groups {
+		for _, pattern := range g.Patterns {
+			if matchFileGroupPattern(pattern, slashed, base) {
+				return g.Name
+			}
+		}
+	}
+	return ""
+}
+
+func matchFileGroupPattern(pattern, slashed, base string) bool {
+	pattern = filepath.ToSlash(expandHome(pattern))
+	if !strings.Contains(pattern, "/") {
+		return matchPathGlob(pattern, base)
+	}
+	return matchPathGlob(pattern, slashed)
+}
+
+// validateFileGroups returns one message per invalid group.
+func validateFileGroups(groups []FileGroup) []string {
+	var errs []string
+	for i, g := range groups {
+		if !fileGroupNamePattern.MatchString(g.Name) {
+			errs = append(errs, fmt.Sprintf("file_groups[%d]: invalid name '%s' (use lowercase letters, digits, and '-')", i, g.Name))
+		}
+		if len(g.Patterns) == 0 {
+			errs = append(errs, fmt.Sprintf("file_groups[%d]: no patterns", i))
+		}
+		for _, p := range g.Patterns {
+			if strings.TrimSpace(p) == "" {
+				errs = append(errs, fmt.Sprintf("file_groups[%d]: empty pattern", i))
+			} else if _, err := globToRegexp(p); err != nil {
+				errs = append(errs, fmt.Sprintf("file_groups[%d]: invalid pattern '%s': %v", i, p, err))
+			}
+		}
+	}
+	return errs
+}
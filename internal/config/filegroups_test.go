@@ -0,0 +1,59 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFileGroupFor_Defaults(t *testing.T) {
+	cfg := &Config{}
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/src/app/store_test.go", "tests"},
+		{"/src/app/tests/fixtures.py", "tests"},
+		{"web/src/App.spec.ts", "tests"},
+		{"/src/app/README.md", "docs"},
+		{"/src/app/docs/setup.go", "docs"},
+		{"/src/app/config.yaml", "config"},
+		{"/src/app/Makefile", "config"},
+		{"/src/app/main.go", "code"},
+		{"/src/app/logo.png", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := cfg.FileGroupFor(tt.path); got != tt.want {
+			t.Errorf("FileGroupFor(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestFileGroupFor_ConfiguredGroupsReplaceDefaults(t *testing.T) {
+	cfg := &Config{FileGroups: []FileGroup{
+		{Name: "migrations", Patterns: []string{"**/migrations/**"}},
+		{Name: "code", Patterns: []string{"*.go"}},
+	}}
+	if got := cfg.FileGroupFor("/src/app/db/migrations/0001_init.sql"); got != "migrations" {
+		t.Errorf("got %q, want migrations", got)
+	}
+	if got := cfg.FileGroupFor("/src/app/README.md"); got != "" {
+		t.Errorf("default groups should not apply once file_groups is set, got %q", got)
+	}
+}
+
+func TestValidateConfig_FileGroups(t *testing.T) {
+	cm := NewConfigManager()
+	cfg := cm.GetDefaultConfig()
+	cfg.FileGroups = []FileGroup{{Name: "My Tests", Patterns: nil}}
+
+	err := cm.ValidateConfig(cfg)
+	if err == nil {
+		t.Fatal("expected file_groups validation errors")
+	}
+	for _, want := range []string{"invalid name 'My Tests'", "no patterns"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %q", err, want)
+		}
+	}
+}
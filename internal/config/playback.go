@@ -0,0 +1,112 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+)
+
+// Overlap policies decide what happens when a sound is requested while
+// another sound for the same session is still playing.
+const (
+	OverlapPolicyMix               = "mix"                // play immediately, on top of anything already playing
+	OverlapPolicyQueue             = "queue"              // wait for the current sound, up to max_queue_depth waiters
+	OverlapPolicyInterruptPrevious = "interrupt-previous" // stop the current sound and play the new one
+	OverlapPolicyDropIfBusy        = "drop-if-busy"       // skip the new sound while another is playing
+)
+
+// DefaultMaxQueueDepth is the number of sounds allowed to wait behind the
+// playing one under the queue policy when max_queue_depth is unset.
+const DefaultMaxQueueDepth = 3
+
+// PlaybackConfig controls how overlapping sounds are arbitrated across
+// concurrent hook processes.
+type PlaybackConfig struct {
+	OverlapPolicy string `json:"overlap_policy"`  // mix, queue, interrupt-previous, or drop-if-busy
+	MaxQueueDepth int    `json:"max_queue_depth"` // queue policy: max waiting sounds per session (0 = default)
+}
+
+// GetDefaultPlaybackConfig returns the default playback configuration.
+// mix preserves the historical behavior of every hook playing independently.
+func GetDefaultPlaybackConfig() *PlaybackConfig {
+	return &PlaybackConfig{
+		OverlapPolicy: OverlapPolicyMix,
+		MaxQueueDepth: DefaultMaxQueueDepth,
+	}
+}
+
+// GetOverlapPolicies returns the accepted overlap_policy values.
+func GetOverlapPolicies() []string {
+	return []string{
+		OverlapPolicyMix,
+		OverlapPolicyQueue,
+		OverlapPolicyInterruptPrevious,
+		OverlapPolicyDropIfBusy,
+	}
+}
+
+// IsValidOverlapPolicy reports whether policy is an accepted overlap_policy.
+// The empty string is valid and means the default.
+func IsValidOverlapPolicy(policy string) bool {
+	if policy == "" {
+		return true
+	}
+	for _, p := range GetOverlapPolicies() {
+		if p == policy {
+			return true
+		}
+	}
+	return false
+}
+
+// EffectiveOverlapPolicy returns the configured policy, or mix when unset.
+func (p *PlaybackConfig) EffectiveOverlapPolicy() string {
+	if p == nil || p.OverlapPolicy == "" {
+		return OverlapPolicyMix
+	}
+	return p.OverlapPolicy
+}
+
+// EffectiveMaxQueueDepth returns the configured queue depth, or
+// DefaultMaxQueueDepth when unset.
+func (p *PlaybackConfig) EffectiveMaxQueueDepth() int {
+	if p == nil || p.MaxQueueDepth <= 0 {
+		return DefaultMaxQueueDepth
+	}
+	return p.MaxQueueDepth
+}
+
+// ApplyPlaybackEnvironmentOverrides applies environment variable overrides to playback config
+func ApplyPlaybackEnvironmentOverrides(config *PlaybackConfig) *PlaybackConfig {
+	return applyPlaybackEnvironment(config, os.Getenv)
+}
+
+// applyPlaybackEnvironment applies the overrides getenv reports.
+func applyPlaybackEnvironment(config *PlaybackConfig, getenv func(string) string) *PlaybackConfig {
+	slog.Debug("applying playback environment variable overrides")
+
+	// Create a copy to modify
+	result := *config
+
+	// CLAUDIO_OVERLAP_POLICY
+	if policy := getenv("CLAUDIO_OVERLAP_POLICY"); policy != "" {
+		if IsValidOverlapPolicy(policy) {
+			result.OverlapPolicy = policy
+			slog.Debug("applied overlap policy override from environment", "value", policy)
+		} else {
+			slog.Warn("invalid CLAUDIO_OVERLAP_POLICY environment variable", "value", policy)
+		}
+	}
+
+	// CLAUDIO_MAX_QUEUE_DEPTH
+	if depthStr := getenv("CLAUDIO_MAX_QUEUE_DEPTH"); depthStr != "" {
+		if depth, err := strconv.Atoi(depthStr); err == nil && depth > 0 {
+			result.MaxQueueDepth = depth
+			slog.Debug("applied max queue depth override from environment", "value", depth)
+		} else {
+			slog.Warn("invalid CLAUDIO_MAX_QUEUE_DEPTH environment variable", "value", depthStr)
+		}
+	}
+
+	return &result
+}
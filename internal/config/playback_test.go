@@ -0,0 +1,63 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlaybackConfig_DefaultValues(t *testing.T) {
+	cfg := GetDefaultPlaybackConfig()
+	if cfg.OverlapPolicy != OverlapPolicyMix {
+		t.Errorf("expected default overlap policy %q, got %q", OverlapPolicyMix, cfg.OverlapPolicy)
+	}
+	if cfg.MaxQueueDepth != DefaultMaxQueueDepth {
+		t.Errorf("expected default max queue depth %d, got %d", DefaultMaxQueueDepth, cfg.MaxQueueDepth)
+	}
+}
+
+func TestPlaybackConfig_EffectiveValuesOnNil(t *testing.T) {
+	var cfg *PlaybackConfig
+	if got := cfg.EffectiveOverlapPolicy(); got != OverlapPolicyMix {
+		t.Errorf("nil EffectiveOverlapPolicy() = %q, want mix", got)
+	}
+	if got := cfg.EffectiveMaxQueueDepth(); got != DefaultMaxQueueDepth {
+		t.Errorf("nil EffectiveMaxQueueDepth() = %d, want %d", got, DefaultMaxQueueDepth)
+	}
+}
+
+func TestApplyPlaybackEnvironmentOverrides(t *testing.T) {
+	t.Setenv("CLAUDIO_OVERLAP_POLICY", "drop-if-busy")
+	t.Setenv("CLAUDIO_MAX_QUEUE_DEPTH", "7")
+
+	result := ApplyPlaybackEnvironmentOverrides(GetDefaultPlaybackConfig())
+	if result.OverlapPolicy != OverlapPolicyDropIfBusy {
+		t.Errorf("expected overlap policy override, got %q", result.OverlapPolicy)
+	}
+	if result.MaxQueueDepth != 7 {
+		t.Errorf("expected max queue depth override 7, got %d", result.MaxQueueDepth)
+	}
+}
+
+func TestApplyPlaybackEnvironmentOverrides_InvalidIgnored(t *testing.T) {
+	t.Setenv("CLAUDIO_OVERLAP_POLICY", "shuffle")
+	t.Setenv("CLAUDIO_MAX_QUEUE_DEPTH", "-2")
+
+	result := ApplyPlaybackEnvironmentOverrides(GetDefaultPlaybackConfig())
+	if result.OverlapPolicy != OverlapPolicyMix {
+		t.Errorf("invalid policy should be ignored, got %q", result.OverlapPolicy)
+	}
+	if result.MaxQueueDepth != DefaultMaxQueueDepth {
+		t.Errorf("invalid depth should be ignored, got %d", result.MaxQueueDepth)
+	}
+}
+
+func TestValidateConfig_RejectsUnknownOverlapPolicy(t *testing.T) {
+	cm := NewConfigManager()
+	cfg := cm.GetDefaultConfig()
+	cfg.Playback = &PlaybackConfig{OverlapPolicy: "loudest-wins"}
+
+	err := cm.ValidateConfig(cfg)
+	if err == nil || !strings.Contains(err.Error(), "overlap_policy") {
+		t.Fatalf("expected overlap_policy validation error, got %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"claudio.click/internal/safeio"
+)
+
+// ProjectConfigFileName is the per-project overlay Claudio looks for in the
+// hook's working directory and its parents, up to the repository root.
+const ProjectConfigFileName = ".claudio.json"
+
+// TrustedProject records a project overlay the user has approved with
+// `claudio trust`. The hash pins the approved contents: an overlay that
+// changes afterwards (a pull, a checkout of someone else's branch) is
+// ignored again until it is re-approved.
+type TrustedProject struct {
+	Path   string `json:"path"`   // Absolute path of the .claudio.json file
+	SHA256 string `json:"sha256"` // Hex SHA-256 of the approved contents
+}
+
+// HashProjectConfig returns the digest TrustedProject.SHA256 records.
+func HashProjectConfig(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// FindProjectConfig returns the nearest .claudio.json between cwd and the
+// repository root (the closest parent holding .git), or "" if there is
+// none. Outside a repository only cwd itself is checked, so a stray file
+// in a home or temp directory is not picked up by everything beneath it.
+func (cm *ConfigManager) FindProjectConfig(cwd string) string {
+	if cwd == "" {
+		return ""
+	}
+	start, err := filepath.Abs(cwd)
+	if err != nil {
+		slog.Debug("cannot resolve hook cwd for project config", "cwd", cwd, "error", err)
+		return ""
+	}
+
+	root := cm.RepoRoot(start)
+	if root == "" {
+		root = start
+	}
+
+	for dir := start; ; dir = filepath.Dir(dir) {
+		candidate := filepath.Join(dir, ProjectConfigFileName)
+		if info, err := cm.fs.Stat(candidate); err == nil && !info.IsDir() {
+			slog.Debug("found project config", "path", candidate)
+			return candidate
+		}
+		if dir == root || filepath.Dir(dir) == dir {
+			break
+		}
+	}
+	return ""
+}
+
+// LoadProjectConfig reads a project overlay and returns it with its raw
+// bytes, which the trust check hashes. Relative paths in the overlay
+// resolve against the overlay's directory, so a pack committed next to the
+// code can be named as "./sounds/quiet".
+func (cm *ConfigManager) LoadProjectConfig(path string) (*Config, []byte, error) {
+	f, err := cm.fs.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open project config: %w", err)
+	}
+	defer f.Close()
+
+	data, err := safeio.ReadAllCapped(f, safeio.MaxSoundpackJSONBytes, "project config")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read project config: %w", err)
+	}
+
+	var overlay Config
+	if err := json.Unmarshal(data, &overlay); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse project config %s: %w", path, err)
+	}
+	if len(overlay.TrustedProjects) > 0 {
+		// Trust is the user's decision; a project cannot vouch for itself.
+		slog.Warn("ignoring trusted_projects in project config", "path", path)
+		overlay.TrustedProjects = nil
+	}
+
+	dir := filepath.Dir(path)
+	if isRelativePathValue(overlay.DefaultSoundpack) {
+		overlay.DefaultSoundpack = filepath.Join(dir, overlay.DefaultSoundpack)
+	}
+	for i, p := range overlay.SoundpackPaths {
+		overlay.SoundpackPaths[i] = resolveProjectPath(dir, p)
+	}
+	for i, name := range overlay.SoundpackStack {
+		if isRelativePathValue(name) {
+			overlay.SoundpackStack[i] = filepath.Join(dir, name)
+		}
+	}
+	overlay.RoutingFile = resolveProjectPath(dir, overlay.RoutingFile)
+	if overlay.Speech != nil {
+		overlay.Speech.PiperModel = resolveProjectPath(dir, overlay.Speech.PiperModel)
+	}
+	for _, profile := range overlay.Agents {
+		if profile == nil {
+			continue
+		}
+		if isRelativePathValue(profile.Soundpack) {
+			profile.Soundpack = filepath.Join(dir, profile.Soundpack)
+		}
+		profile.RoutingFile = resolveProjectPath(dir, profile.RoutingFile)
+	}
+
+	return &overlay, data, nil
+}
+
+// IsProjectTrusted reports whether the overlay at path with contents data
+// was approved by the user, as recorded in cfg.TrustedProjects.
+func (c *Config) IsProjectTrusted(path string, data []byte) bool {
+	hash := HashProjectConfig(data)
+	for _, tp := range c.TrustedProjects {
+		if filepath.Clean(tp.Path) == filepath.Clean(path) && strings.EqualFold(tp.SHA256, hash) {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyProjectConfig layers the trusted project overlay for cwd on top of
+// base. The overlay is skipped, with a warning, when it is untrusted,
+// unreadable, or would make the merged config invalid; base is then
+// returned unchanged.
+func (cm *ConfigManager) ApplyProjectConfig(base *Config, cwd string) *Config {
+	path := cm.FindProjectConfig(cwd)
+	if path == "" {
+		return base
+	}
+
+	overlay, data, err := cm.LoadProjectConfig(path)
+	if err != nil {
+		slog.Warn("ignoring project config", "path", path, "error", err)
+		return base
+	}
+	if !base.IsProjectTrusted(path, data) {
+		slog.Warn("ignoring untrusted project config; run `claudio trust` in the project to allow it",
+			"path", path)
+		return base
+	}
+
+	merged := cm.MergeConfigs(base, overlay)
+	if err := cm.ValidateConfig(merged); err != nil {
+		slog.Warn("ignoring invalid project config", "path", path, "error", err)
+		return base
+	}
+
+	slog.Debug("applied project config", "path", path)
+	return merged
+}
+
+// resolveProjectPath joins a relative file path from an overlay onto the
+// overlay's directory. Empty, absolute, and ~ paths are returned as is.
+func resolveProjectPath(dir, p string) string {
+	if p == "" || filepath.IsAbs(p) || strings.HasPrefix(p, "~") {
+		return p
+	}
+	return filepath.Join(dir, p)
+}
+
+// isRelativePathValue reports whether a default_soundpack value is a
+// relative path rather than a soundpack name: names never contain a
+// separator, so "quiet" stays a name and "./sounds/quiet" becomes a path.
+func isRelativePathValue(value string) bool {
+	if value == "" || filepath.IsAbs(value) || strings.HasPrefix(value, "~") {
+		return false
+	}
+	return strings.ContainsAny(value, `/\`)
+}
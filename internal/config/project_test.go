@@ -0,0 +1,140 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeProjectFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestFindProjectConfig(t *testing.T) {
+	cm := NewConfigManager()
+	root := t.TempDir()
+
+	repo := filepath.Join(root, "repo")
+	deep := filepath.Join(repo, "a", "b")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(deep, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	// A file above the repository root is out of reach.
+	writeProjectFile(t, filepath.Join(root, ProjectConfigFileName), `{}`)
+	if got := cm.FindProjectConfig(deep); got != "" {
+		t.Errorf("lookup must stop at the repo root, got %q", got)
+	}
+
+	rootOverlay := filepath.Join(repo, ProjectConfigFileName)
+	writeProjectFile(t, rootOverlay, `{}`)
+	if got := cm.FindProjectConfig(deep); got != rootOverlay {
+		t.Errorf("FindProjectConfig(deep) = %q, want %q", got, rootOverlay)
+	}
+
+	nearer := filepath.Join(repo, "a", ProjectConfigFileName)
+	writeProjectFile(t, nearer, `{}`)
+	if got := cm.FindProjectConfig(deep); got != nearer {
+		t.Errorf("nearest overlay should win, got %q want %q", got, nearer)
+	}
+
+	// Outside any repository only cwd itself counts.
+	loose := filepath.Join(root, "scratch", "dir")
+	if err := os.MkdirAll(loose, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got := cm.FindProjectConfig(loose); got != "" {
+		t.Errorf("outside a repo parents must not be searched, got %q", got)
+	}
+	if got := cm.FindProjectConfig(""); got != "" {
+		t.Errorf("empty cwd should find nothing, got %q", got)
+	}
+}
+
+func TestLoadProjectConfig_ResolvesRelativePaths(t *testing.T) {
+	cm := NewConfigManager()
+	dir := t.TempDir()
+	path := filepath.Join(dir, ProjectConfigFileName)
+	writeProjectFile(t, path, `{
+		"default_soundpack": "./sounds/quiet",
+		"soundpack_paths": ["packs", "/abs/packs"],
+		"soundpack_stack": ["./packs/git-sounds.json", "startrek-bridge"],
+		"routing_file": "routing.json",
+		"trusted_projects": [{"path": "/elsewhere/.claudio.json", "sha256": "00"}]
+	}`)
+
+	overlay, data, err := cm.LoadProjectConfig(path)
+	if err != nil {
+		t.Fatalf("LoadProjectConfig: %v", err)
+	}
+	if len(data) == 0 {
+		t.Error("raw bytes should be returned for hashing")
+	}
+	if want := filepath.Join(dir, "sounds", "quiet"); overlay.DefaultSoundpack != want {
+		t.Errorf("default_soundpack = %q, want %q", overlay.DefaultSoundpack, want)
+	}
+	if overlay.SoundpackPaths[0] != filepath.Join(dir, "packs") || overlay.SoundpackPaths[1] != "/abs/packs" {
+		t.Errorf("soundpack_paths = %v", overlay.SoundpackPaths)
+	}
+	if overlay.SoundpackStack[0] != filepath.Join(dir, "packs", "git-sounds.json") || overlay.SoundpackStack[1] != "startrek-bridge" {
+		t.Errorf("soundpack_stack = %v", overlay.SoundpackStack)
+	}
+	if overlay.RoutingFile != filepath.Join(dir, "routing.json") {
+		t.Errorf("routing_file = %q", overlay.RoutingFile)
+	}
+	if overlay.TrustedProjects != nil {
+		t.Error("an overlay must not be able to add trusted projects")
+	}
+
+	writeProjectFile(t, path, `{"default_soundpack": "quiet"}`)
+	overlay, _, err = cm.LoadProjectConfig(path)
+	if err != nil {
+		t.Fatalf("LoadProjectConfig: %v", err)
+	}
+	if overlay.DefaultSoundpack != "quiet" {
+		t.Errorf("a bare soundpack name must stay a name, got %q", overlay.DefaultSoundpack)
+	}
+}
+
+func TestApplyProjectConfig_RequiresTrust(t *testing.T) {
+	cm := NewConfigManager()
+	dir := t.TempDir()
+	path := filepath.Join(dir, ProjectConfigFileName)
+	content := `{"default_soundpack": "quiet", "volume": 0.2}`
+	writeProjectFile(t, path, content)
+
+	base := cm.GetDefaultConfig()
+	base.DefaultSoundpack = "default"
+
+	if got := cm.ApplyProjectConfig(base, dir); got.DefaultSoundpack != "default" {
+		t.Errorf("untrusted overlay applied: soundpack = %q", got.DefaultSoundpack)
+	}
+
+	base.TrustedProjects = []TrustedProject{{Path: path, SHA256: HashProjectConfig([]byte(content))}}
+	got := cm.ApplyProjectConfig(base, dir)
+	if got.DefaultSoundpack != "quiet" || got.Volume == nil || *got.Volume != 0.2 {
+		t.Errorf("trusted overlay not applied: soundpack=%q volume=%v", got.DefaultSoundpack, got.Volume)
+	}
+
+	writeProjectFile(t, path, `{"default_soundpack": "quiet", "volume": 2}`)
+	if got := cm.ApplyProjectConfig(base, dir); got.DefaultSoundpack != "default" {
+		t.Errorf("changed overlay applied: soundpack = %q", got.DefaultSoundpack)
+	}
+
+	// Trusted but invalid once merged: ignored rather than breaking hooks.
+	invalid := `{"volume": 2}`
+	writeProjectFile(t, path, invalid)
+	base.TrustedProjects = []TrustedProject{{Path: path, SHA256: HashProjectConfig([]byte(invalid))}}
+	if got := cm.ApplyProjectConfig(base, dir); got.Volume == nil || *got.Volume != 0.5 {
+		t.Errorf("invalid overlay should be skipped, volume = %v", got.Volume)
+	}
+}
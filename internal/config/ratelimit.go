@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"path"
+)
+
+// Rate limit scopes decide which hook events share one limiter bucket.
+const (
+	RateLimitScopeSession = "session" // each session is limited independently (default)
+	RateLimitScopeGlobal  = "global"  // every session shares one bucket
+)
+
+// RateLimitRule throttles sounds for hook events matching Category and
+// Hint. All events matching one rule share a bucket (per session, unless
+// Scope is global), so {"category": "loading", "min_interval_ms": 2000}
+// means "at most one loading sound per two seconds", whichever tool
+// triggered it.
+//
+// MinIntervalMs is a throttle measured from the last sound the rule
+// allowed. CoalesceMs is a debounce measured from the last matching event,
+// allowed or not: a steady stream of events closer together than the
+// window plays once at the start and then stays quiet until the stream
+// pauses. A rule may set either or both.
+type RateLimitRule struct {
+	Category      string `json:"category,omitempty"`        // EventCategory name, e.g. "loading"; empty matches any
+	Hint          string `json:"hint,omitempty"`            // SoundHint or glob, e.g. "read-start" or "bash-*"; empty matches any
+	Scope         string `json:"scope,omitempty"`           // "session" (default) or "global"
+	MinIntervalMs int    `json:"min_interval_ms,omitempty"` // minimum gap between allowed sounds
+	CoalesceMs    int    `json:"coalesce_ms,omitempty"`     // quiet period that must pass before a repeat plays
+}
+
+// Matches reports whether the rule applies to an event with the given
+// category and sound hint.
+func (r RateLimitRule) Matches(category, hint string) bool {
+	if r.Category != "" && r.Category != category {
+		return false
+	}
+	if r.Hint != "" {
+		ok, err := path.Match(r.Hint, hint)
+		if err != nil || !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Key identifies the rule's limiter bucket. It is derived from the rule's
+// match clauses rather than its position so reordering the config does not
+// reset or cross-wire persisted state.
+func (r RateLimitRule) Key() string {
+	return r.Category + "|" + r.Hint
+}
+
+// IsGlobal reports whether all sessions share the rule's bucket.
+func (r RateLimitRule) IsGlobal() bool {
+	return r.Scope == RateLimitScopeGlobal
+}
+
+// validateRateLimitRules returns one message per invalid rule.
+func validateRateLimitRules(rules []RateLimitRule) []string {
+	var errs []string
+	for i, r := range rules {
+		if r.MinIntervalMs < 0 || r.CoalesceMs < 0 {
+			errs = append(errs, fmt.Sprintf("rate_limits[%d]: min_interval_ms and coalesce_ms must be >= 0", i))
+		}
+		if r.MinIntervalMs == 0 && r.CoalesceMs == 0 {
+			errs = append(errs, fmt.Sprintf("rate_limits[%d]: set min_interval_ms or coalesce_ms", i))
+		}
+		if r.Scope != "" && r.Scope != RateLimitScopeSession && r.Scope != RateLimitScopeGlobal {
+			errs = append(errs, fmt.Sprintf("rate_limits[%d]: invalid scope '%s', must be one of: %s, %s",
+				i, r.Scope, RateLimitScopeSession, RateLimitScopeGlobal))
+		}
+		if r.Hint != "" {
+			if _, err := path.Match(r.Hint, ""); err != nil {
+				errs = append(errs, fmt.Sprintf("rate_limits[%d]: invalid hint pattern '%s': %v", i, r.Hint, err))
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRateLimitRule_Matches(t *testing.T) {
+	tests := []struct {
+		rule     RateLimitRule
+		category string
+		hint     string
+		want     bool
+	}{
+		{RateLimitRule{Category: "loading"}, "loading", "read-start", true},
+		{RateLimitRule{Category: "loading"}, "success", "read-success", false},
+		{RateLimitRule{Hint: "read-start"}, "loading", "read-start", true},
+		{RateLimitRule{Hint: "bash-*"}, "loading", "bash-start", true},
+		{RateLimitRule{Hint: "bash-*"}, "loading", "grep-start", false},
+		{RateLimitRule{Category: "loading", Hint: "read-*"}, "success", "read-success", false},
+		{RateLimitRule{}, "system", "session-start", true},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.Matches(tt.category, tt.hint); got != tt.want {
+			t.Errorf("%+v.Matches(%q, %q) = %v, want %v", tt.rule, tt.category, tt.hint, got, tt.want)
+		}
+	}
+}
+
+func TestValidateConfig_RateLimits(t *testing.T) {
+	cm := NewConfigManager()
+
+	tests := []struct {
+		name    string
+		rule    RateLimitRule
+		wantErr string
+	}{
+		{"no window", RateLimitRule{Category: "loading"}, "set min_interval_ms or coalesce_ms"},
+		{"negative", RateLimitRule{Category: "loading", CoalesceMs: -1}, "must be >= 0"},
+		{"bad scope", RateLimitRule{Category: "loading", CoalesceMs: 100, Scope: "project"}, "invalid scope"},
+		{"bad glob", RateLimitRule{Hint: "[", CoalesceMs: 100}, "invalid hint pattern"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := cm.GetDefaultConfig()
+			cfg.RateLimits = []RateLimitRule{tt.rule}
+			err := cm.ValidateConfig(cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	cfg := cm.GetDefaultConfig()
+	cfg.RateLimits = []RateLimitRule{{Category: "loading", MinIntervalMs: 2000}}
+	if err := cm.ValidateConfig(cfg); err != nil {
+		t.Errorf("valid rule rejected: %v", err)
+	}
+}
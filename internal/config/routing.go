@@ -0,0 +1,226 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"claudio.click/internal/safeio"
+)
+
+// RoutingFileName is the rules file discovered next to config.json when
+// routing_file is not set.
+const RoutingFileName = "routing.json"
+
+// MaxRoutingRules caps the number of rules in one routing file. Rules are
+// evaluated linearly on every hook, so an accidental (or hostile) giant
+// file should fail loudly instead of slowing every tool call.
+const MaxRoutingRules = 1000
+
+// RoutingFile is the on-disk shape of routing.json.
+type RoutingFile struct {
+	Rules []RoutingRule `json:"rules"`
+}
+
+// RoutingRule maps hook events matching Match to explicit sound keys.
+// Sound and Candidates are soundpack-relative keys, the same shape as the
+// built-in chain paths ("error/cargo-test-failed.wav"). The first rule
+// whose Match accepts the event wins; its candidates are tried before the
+// built-in chain, which still follows as a fallback unless Exclusive is set.
+type RoutingRule struct {
+	Name       string       `json:"name,omitempty"`       // Optional label for logs
+	Match      RoutingMatch `json:"match"`                // All set clauses must match
+	Sound      string       `json:"sound,omitempty"`      // Single sound key
+	Candidates []string     `json:"candidates,omitempty"` // Ordered sound keys, tried after Sound
+	Exclusive  bool         `json:"exclusive,omitempty"`  // Do not fall back to the built-in chain
+}
+
+// RoutingMatch holds a rule's match clauses. Empty clauses match anything.
+// String clauses compare case-insensitively except Event, which uses the
+// canonical hook event names. CWD is a glob where * stays inside one path
+// segment and ** spans segments; a leading ~ expands to the home dir.
+type RoutingMatch struct {
+	Event      string `json:"event,omitempty"`      // Hook event name, e.g. "PostToolUse"
+	Tool       string `json:"tool,omitempty"`       // Normalized tool name, e.g. "Bash", "Edit", "mcp"
+	Command    string `json:"command,omitempty"`    // Bash command, e.g. "cargo"
+	Subcommand string `json:"subcommand,omitempty"` // Bash subcommand, e.g. "test"
+	Extension  string `json:"extension,omitempty"`  // File extension without the dot, e.g. "go"
+	CWD        string `json:"cwd,omitempty"`        // Glob on the hook's working directory
+	Agent      string `json:"agent,omitempty"`      // Invoking agent, e.g. "claude", "codex"
+	Category   string `json:"category,omitempty"`   // Event category, e.g. "error", "success"
+}
+
+// RoutingInput is the event data routing rules match against.
+type RoutingInput struct {
+	Event      string
+	Tool       string
+	Command    string
+	Subcommand string
+	Extension  string
+	CWD        string
+	Agent      string
+	Category   string
+}
+
+// SoundKeys returns the rule's sound keys in the order they are tried.
+func (r RoutingRule) SoundKeys() []string {
+	var keys []string
+	if r.Sound != "" {
+		keys = append(keys, r.Sound)
+	}
+	return append(keys, r.Candidates...)
+}
+
+// Matches reports whether every clause set on the rule accepts in.
+func (m RoutingMatch) Matches(in RoutingInput) bool {
+	if m.Event != "" && m.Event != in.Event {
+		return false
+	}
+	for _, clause := range [][2]string{
+		{m.Tool, in.Tool},
+		{m.Command, in.Command},
+		{m.Subcommand, in.Subcommand},
+		{strings.TrimPrefix(m.Extension, "."), in.Extension},
+		{m.Agent, in.Agent},
+		{m.Category, in.Category},
+	} {
+		if clause[0] != "" && !strings.EqualFold(clause[0], clause[1]) {
+			return false
+		}
+	}
+	if m.CWD != "" {
+		if in.CWD == "" || !matchPathGlob(expandHome(m.CWD), filepath.ToSlash(filepath.Clean(in.CWD))) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchRoutingRule returns the first rule that matches in, or nil.
+func MatchRoutingRule(rules []RoutingRule, in RoutingInput) *RoutingRule {
+	for i := range rules {
+		if rules[i].Match.Matches(in) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// LoadRoutingRules loads the routing rules that apply to cfg: the file
+// named by routing_file when set (a missing file is then an error), else
+// the first routing.json on the config search path. No file means no
+// rules, not an error.
+func (cm *ConfigManager) LoadRoutingRules(cfg *Config) ([]RoutingRule, error) {
+	if cfg != nil && cfg.RoutingFile != "" {
+		return cm.LoadRoutingFile(expandHome(cfg.RoutingFile))
+	}
+	for _, path := range cm.xdg.GetConfigPaths(RoutingFileName) {
+		if _, err := cm.fs.Stat(path); err == nil {
+			return cm.LoadRoutingFile(path)
+		}
+	}
+	slog.Debug("no routing rules file found")
+	return nil, nil
+}
+
+// LoadRoutingFile reads and validates one routing rules file.
+func (cm *ConfigManager) LoadRoutingFile(path string) ([]RoutingRule, error) {
+	f, err := cm.fs.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open routing file: %w", err)
+	}
+	defer f.Close()
+
+	data, err := safeio.ReadAllCapped(f, safeio.MaxSoundpackJSONBytes, "routing file")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read routing file: %w", err)
+	}
+
+	var rf RoutingFile
+	if err := json.Unmarshal(data, &rf); err != nil {
+		return nil, fmt.Errorf("failed to parse routing file %s: %w", path, err)
+	}
+	if errs := validateRoutingRules(rf.Rules); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid routing file %s: %s", path, strings.Join(errs, "; "))
+	}
+
+	slog.Debug("routing rules loaded", "path", path, "rules", len(rf.Rules))
+	return rf.Rules, nil
+}
+
+// validateRoutingRules returns one message per invalid rule.
+func validateRoutingRules(rules []RoutingRule) []string {
+	if len(rules) > MaxRoutingRules {
+		return []string{fmt.Sprintf("too many rules (%d > %d)", len(rules), MaxRoutingRules)}
+	}
+	var errs []string
+	for i, r := range rules {
+		keys := r.SoundKeys()
+		if len(keys) == 0 {
+			errs = append(errs, fmt.Sprintf("rules[%d]: set sound or candidates", i))
+		}
+		for _, k := range keys {
+			if strings.TrimSpace(k) == "" {
+				errs = append(errs, fmt.Sprintf("rules[%d]: empty sound key", i))
+			}
+		}
+		if r.Match.CWD != "" {
+			if _, err := globToRegexp(r.Match.CWD); err != nil {
+				errs = append(errs, fmt.Sprintf("rules[%d]: invalid cwd glob '%s': %v", i, r.Match.CWD, err))
+			}
+		}
+	}
+	return errs
+}
+
+// matchPathGlob matches a slash-separated path against a glob in which *
+// and ? never cross '/', and ** matches any run of characters including
+// '/'. A pattern ending in "/**" also matches the directory itself.
+func matchPathGlob(pattern, path string) bool {
+	re, err := globToRegexp(pattern)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(path)
+}
+
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	pattern = filepath.ToSlash(pattern)
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '/' && strings.HasPrefix(pattern[i:], "/**") && i+3 == len(pattern):
+			b.WriteString("(/.*)?")
+			i += 2
+		case c == '*' && i+1 < len(pattern) && pattern[i+1] == '*':
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// expandHome replaces a leading "~" with the user's home directory.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(filepath.Join(home, strings.TrimPrefix(path, "~")))
+}
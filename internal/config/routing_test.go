@@ -0,0 +1,141 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestRoutingMatch_Matches(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	in := RoutingInput{
+		Event:      "PostToolUse",
+		Tool:       "Bash",
+		Command:    "cargo",
+		Subcommand: "test",
+		CWD:        "/work/rust/app",
+		Agent:      "codex",
+		Category:   "error",
+	}
+
+	tests := []struct {
+		name  string
+		match RoutingMatch
+		in    RoutingInput
+		want  bool
+	}{
+		{"empty matches anything", RoutingMatch{}, in, true},
+		{"event", RoutingMatch{Event: "PostToolUse"}, in, true},
+		{"event is case-sensitive", RoutingMatch{Event: "posttooluse"}, in, false},
+		{"tool case-insensitive", RoutingMatch{Tool: "bash"}, in, true},
+		{"command and subcommand", RoutingMatch{Command: "cargo", Subcommand: "test"}, in, true},
+		{"wrong subcommand", RoutingMatch{Command: "cargo", Subcommand: "build"}, in, false},
+		{"extension with dot", RoutingMatch{Extension: ".go"}, RoutingInput{Extension: "go"}, true},
+		{"extension mismatch", RoutingMatch{Extension: "go"}, RoutingInput{Extension: "md"}, false},
+		{"agent", RoutingMatch{Agent: "codex"}, in, true},
+		{"category", RoutingMatch{Category: "success"}, in, false},
+		{"cwd star stays in segment", RoutingMatch{CWD: "/work/*"}, in, false},
+		{"cwd double star spans segments", RoutingMatch{CWD: "/work/**"}, in, true},
+		{"cwd trailing double star matches dir itself", RoutingMatch{CWD: "/work/rust/app/**"}, in, true},
+		{"cwd middle double star", RoutingMatch{CWD: "/work/**/app"}, in, true},
+		{"cwd requires input", RoutingMatch{CWD: "/**"}, RoutingInput{}, false},
+		{"cwd tilde", RoutingMatch{CWD: "~/src/**"}, RoutingInput{CWD: filepath.Join(home, "src", "x")}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.match.Matches(tt.in); got != tt.want {
+				t.Errorf("%+v.Matches(%+v) = %v, want %v", tt.match, tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchRoutingRule_FirstMatchWins(t *testing.T) {
+	rules := []RoutingRule{
+		{Name: "cargo", Match: RoutingMatch{Command: "cargo"}, Sound: "a.wav"},
+		{Name: "bash", Match: RoutingMatch{Tool: "Bash"}, Sound: "b.wav"},
+	}
+	got := MatchRoutingRule(rules, RoutingInput{Tool: "Bash", Command: "cargo"})
+	if got == nil || got.Name != "cargo" {
+		t.Fatalf("expected cargo rule, got %+v", got)
+	}
+	if got := MatchRoutingRule(rules, RoutingInput{Tool: "Edit"}); got != nil {
+		t.Fatalf("expected no match, got %+v", got)
+	}
+}
+
+func TestRoutingRule_SoundKeys(t *testing.T) {
+	r := RoutingRule{Sound: "a.wav", Candidates: []string{"b.wav", "c.wav"}}
+	if got := strings.Join(r.SoundKeys(), ","); got != "a.wav,b.wav,c.wav" {
+		t.Errorf("SoundKeys() = %s", got)
+	}
+}
+
+func TestLoadRoutingRules(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	cm := NewConfigManagerWithFilesystem(fs)
+
+	t.Run("no file means no rules", func(t *testing.T) {
+		rules, err := cm.LoadRoutingRules(&Config{})
+		if err != nil || rules != nil {
+			t.Fatalf("expected no rules and no error, got %v, %v", rules, err)
+		}
+	})
+
+	t.Run("discovered beside config.json", func(t *testing.T) {
+		path := cm.xdg.GetConfigPaths(RoutingFileName)[0]
+		data := `{"rules":[{"match":{"command":"cargo"},"sound":"error/cargo.wav"}]}`
+		if err := afero.WriteFile(fs, path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		defer fs.Remove(path)
+
+		rules, err := cm.LoadRoutingRules(&Config{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rules) != 1 || rules[0].Sound != "error/cargo.wav" {
+			t.Fatalf("unexpected rules: %+v", rules)
+		}
+	})
+
+	t.Run("explicit routing_file must exist", func(t *testing.T) {
+		if _, err := cm.LoadRoutingRules(&Config{RoutingFile: "/missing/routing.json"}); err == nil {
+			t.Fatal("expected error for missing routing_file")
+		}
+	})
+
+	t.Run("invalid rules rejected", func(t *testing.T) {
+		tests := []struct {
+			data    string
+			wantErr string
+		}{
+			{`{"rules":[{"match":{"tool":"Bash"}}]}`, "set sound or candidates"},
+			{`{"rules":[{"match":{},"candidates":[" "]}]}`, "empty sound key"},
+			{`{"rules":[`, "failed to parse"},
+		}
+		for _, tt := range tests {
+			if err := afero.WriteFile(fs, "/r.json", []byte(tt.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := cm.LoadRoutingRules(&Config{RoutingFile: "/r.json"})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: expected error containing %q, got %v", tt.data, tt.wantErr, err)
+			}
+		}
+	})
+}
+
+func TestMergeConfigs_RoutingFile(t *testing.T) {
+	cm := NewConfigManager()
+	merged := cm.MergeConfigs(cm.GetDefaultConfig(), &Config{RoutingFile: "/etc/claudio/routing.json"})
+	if merged.RoutingFile != "/etc/claudio/routing.json" {
+		t.Errorf("RoutingFile = %q", merged.RoutingFile)
+	}
+}
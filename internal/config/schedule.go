@@ -0,0 +1,140 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ScheduleConfig mutes hooks on a recurring schedule. Times are local
+// wall-clock times of the machine running the hook.
+type ScheduleConfig struct {
+	QuietHours []QuietHours `json:"quiet_hours,omitempty"` // Windows during which hooks are silent
+	Days       []string     `json:"days,omitempty"`        // Days hooks may play, e.g. ["weekdays"] (empty = every day)
+}
+
+// QuietHours is one recurring silent window. A window whose end is not
+// after its start runs past midnight, so 22:00–08:00 covers the night;
+// equal start and end cover the whole day.
+type QuietHours struct {
+	Start string   `json:"start"`          // HH:MM, 24-hour
+	End   string   `json:"end"`            // HH:MM, 24-hour
+	Days  []string `json:"days,omitempty"` // Days the window starts on (empty = every day)
+}
+
+// dayNames maps accepted day names to the weekdays they cover.
+var dayNames = map[string][]time.Weekday{
+	"mon": {time.Monday}, "monday": {time.Monday},
+	"tue": {time.Tuesday}, "tuesday": {time.Tuesday},
+	"wed": {time.Wednesday}, "wednesday": {time.Wednesday},
+	"thu": {time.Thursday}, "thursday": {time.Thursday},
+	"fri": {time.Friday}, "friday": {time.Friday},
+	"sat": {time.Saturday}, "saturday": {time.Saturday},
+	"sun": {time.Sunday}, "sunday": {time.Sunday},
+	"weekdays": {time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	"weekends": {time.Saturday, time.Sunday},
+}
+
+// ScheduledMute reports whether hooks are muted at now by muted_until or
+// the schedule, with a short reason for logs and `claudio status`. An
+// expired muted_until is simply ignored, so a temporary mute reverts on
+// its own.
+func (c *Config) ScheduledMute(now time.Time) (bool, string) {
+	if c.MutedUntil != nil && now.Before(*c.MutedUntil) {
+		return true, "muted until " + FormatMuteTime(*c.MutedUntil, now)
+	}
+	if c.Schedule == nil {
+		return false, ""
+	}
+
+	if len(c.Schedule.Days) > 0 && !dayListContains(c.Schedule.Days, now.Weekday()) {
+		return true, "not a scheduled day (" + strings.Join(c.Schedule.Days, ", ") + ")"
+	}
+
+	minute := now.Hour()*60 + now.Minute()
+	for _, qh := range c.Schedule.QuietHours {
+		start, err1 := parseClock(qh.Start)
+		end, err2 := parseClock(qh.End)
+		if err1 != nil || err2 != nil {
+			continue // rejected by ValidateConfig
+		}
+		onDay := func(d time.Weekday) bool {
+			return len(qh.Days) == 0 || dayListContains(qh.Days, d)
+		}
+
+		var quiet bool
+		switch {
+		case start < end:
+			quiet = minute >= start && minute < end && onDay(now.Weekday())
+		case start == end:
+			quiet = onDay(now.Weekday())
+		default:
+			// Past midnight: the early part belongs to yesterday's window.
+			quiet = (minute >= start && onDay(now.Weekday())) ||
+				(minute < end && onDay((now.Weekday()+6)%7))
+		}
+		if quiet {
+			return true, fmt.Sprintf("quiet hours %s-%s", qh.Start, qh.End)
+		}
+	}
+	return false, ""
+}
+
+// FormatMuteTime formats a mute expiry relative to now: the time alone
+// for later today, otherwise with the date.
+func FormatMuteTime(t, now time.Time) string {
+	t = t.In(now.Location())
+	if y, m, d := t.Date(); y == now.Year() && m == now.Month() && d == now.Day() {
+		return t.Format("15:04")
+	}
+	return t.Format("Mon Jan 2 15:04")
+}
+
+// validateSchedule returns one message per invalid schedule setting.
+func validateSchedule(s *ScheduleConfig) []string {
+	if s == nil {
+		return nil
+	}
+	var errs []string
+	errs = append(errs, validateDayNames("schedule.days", s.Days)...)
+	for i, qh := range s.QuietHours {
+		if _, err := parseClock(qh.Start); err != nil {
+			errs = append(errs, fmt.Sprintf("schedule.quiet_hours[%d].start: %v", i, err))
+		}
+		if _, err := parseClock(qh.End); err != nil {
+			errs = append(errs, fmt.Sprintf("schedule.quiet_hours[%d].end: %v", i, err))
+		}
+		errs = append(errs, validateDayNames(fmt.Sprintf("schedule.quiet_hours[%d].days", i), qh.Days)...)
+	}
+	return errs
+}
+
+func validateDayNames(field string, days []string) []string {
+	var errs []string
+	for _, d := range days {
+		if _, ok := dayNames[strings.ToLower(strings.TrimSpace(d))]; !ok {
+			errs = append(errs, fmt.Sprintf("%s: unknown day '%s'", field, d))
+		}
+	}
+	return errs
+}
+
+func dayListContains(days []string, day time.Weekday) bool {
+	for _, name := range days {
+		for _, d := range dayNames[strings.ToLower(strings.TrimSpace(name))] {
+			if d == day {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseClock parses HH:MM into minutes after midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time '%s', want HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
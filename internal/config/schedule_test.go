@@ -0,0 +1,79 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScheduledMute(t *testing.T) {
+	// 2026-10-16 is a Friday.
+	at := func(day int, hh, mm int) time.Time {
+		return time.Date(2026, 10, day, hh, mm, 0, 0, time.UTC)
+	}
+	night := &ScheduleConfig{QuietHours: []QuietHours{{Start: "22:00", End: "08:00"}}}
+	lunch := &ScheduleConfig{QuietHours: []QuietHours{{Start: "12:00", End: "13:00", Days: []string{"weekdays"}}}}
+	fridayNight := &ScheduleConfig{QuietHours: []QuietHours{{Start: "22:00", End: "08:00", Days: []string{"fri"}}}}
+	weekdaysOnly := &ScheduleConfig{Days: []string{"weekdays"}}
+
+	tests := []struct {
+		name     string
+		schedule *ScheduleConfig
+		now      time.Time
+		want     bool
+	}{
+		{"before night window", night, at(16, 21, 59), false},
+		{"night window start", night, at(16, 22, 0), true},
+		{"after midnight", night, at(17, 7, 59), true},
+		{"night window end", night, at(17, 8, 0), false},
+		{"weekday lunch", lunch, at(16, 12, 30), true},
+		{"weekend lunch", lunch, at(17, 12, 30), false},
+		{"window started yesterday", fridayNight, at(17, 3, 0), true},
+		{"window not started yesterday", fridayNight, at(18, 3, 0), false},
+		{"weekday with weekdays only", weekdaysOnly, at(16, 10, 0), false},
+		{"weekend with weekdays only", weekdaysOnly, at(18, 10, 0), true},
+		{"whole-day window", &ScheduleConfig{QuietHours: []QuietHours{{Start: "00:00", End: "00:00"}}}, at(16, 10, 0), true},
+		{"no schedule", nil, at(16, 23, 0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Schedule: tt.schedule}
+			if got, reason := cfg.ScheduledMute(tt.now); got != tt.want {
+				t.Errorf("ScheduledMute(%s) = %v (%s), want %v", tt.now.Format("Mon 15:04"), got, reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduledMute_MutedUntilExpires(t *testing.T) {
+	now := time.Date(2026, 10, 16, 13, 0, 0, 0, time.UTC)
+	until := now.Add(45 * time.Minute)
+	cfg := &Config{MutedUntil: &until}
+
+	muted, reason := cfg.ScheduledMute(now)
+	if !muted || reason != "muted until 13:45" {
+		t.Errorf("ScheduledMute before expiry = %v, %q", muted, reason)
+	}
+	if muted, _ := cfg.ScheduledMute(until); muted {
+		t.Error("mute should revert at its expiry")
+	}
+}
+
+func TestValidateConfig_Schedule(t *testing.T) {
+	cm := NewConfigManager()
+	cfg := cm.GetDefaultConfig()
+	cfg.Schedule = &ScheduleConfig{
+		Days:       []string{"funday"},
+		QuietHours: []QuietHours{{Start: "25:00", End: "8am"}},
+	}
+
+	err := cm.ValidateConfig(cfg)
+	if err == nil {
+		t.Fatal("expected schedule validation errors")
+	}
+	for _, want := range []string{"unknown day 'funday'", "quiet_hours[0].start", "quiet_hours[0].end"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %q", err, want)
+		}
+	}
+}
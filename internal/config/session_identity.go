@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Session identity defaults used when the matching field is unset.
+const (
+	DefaultSessionIdentityKey      = "session"
+	DefaultSessionIdentitySemitone = 1.5
+	DefaultSessionIdentityPan      = 0.6
+)
+
+// validSessionIdentityKeys lists what a session's pitch and pan can be
+// derived from.
+var validSessionIdentityKeys = []string{"session", "cwd", "repo"}
+
+// SessionIdentityConfig gives every session its own pitch and stereo
+// position, derived from the session ID, working directory or repository,
+// so concurrent sessions can be told apart by ear.
+type SessionIdentityConfig struct {
+	Enabled        bool    `json:"enabled"`                   // Whether sounds are varied per session
+	Key            string  `json:"key,omitempty"`             // What the variation derives from: session, cwd or repo (empty = session)
+	PitchSemitones float64 `json:"pitch_semitones,omitempty"` // Largest pitch shift either way (0 = default)
+	Pan            float64 `json:"pan,omitempty"`             // Largest pan either way, up to 1.0 (0 = default)
+}
+
+// EffectiveKey returns what the variation is derived from.
+func (s *SessionIdentityConfig) EffectiveKey() string {
+	if s == nil || s.Key == "" {
+		return DefaultSessionIdentityKey
+	}
+	return s.Key
+}
+
+// EffectivePitchSemitones returns the largest pitch shift either way.
+func (s *SessionIdentityConfig) EffectivePitchSemitones() float64 {
+	if s == nil || s.PitchSemitones <= 0 {
+		return DefaultSessionIdentitySemitone
+	}
+	return s.PitchSemitones
+}
+
+// EffectivePan returns the largest pan either way.
+func (s *SessionIdentityConfig) EffectivePan() float64 {
+	if s == nil || s.Pan <= 0 {
+		return DefaultSessionIdentityPan
+	}
+	return s.Pan
+}
+
+// IdentityFor returns the string a session's variation is derived from:
+// the session ID, the hook's working directory, or the repository holding
+// it. It falls back to the working directory outside a repository and
+// returns "" when the chosen value is unknown.
+func (cm *ConfigManager) IdentityFor(s *SessionIdentityConfig, sessionID, cwd string) string {
+	switch s.EffectiveKey() {
+	case "cwd":
+		return cwd
+	case "repo":
+		if root := cm.RepoRoot(cwd); root != "" {
+			return root
+		}
+		return cwd
+	default:
+		return sessionID
+	}
+}
+
+// RepoRoot returns the closest directory at or above cwd that holds .git,
+// or "" when cwd is not inside a repository.
+func (cm *ConfigManager) RepoRoot(cwd string) string {
+	if cwd == "" {
+		return ""
+	}
+	start, err := filepath.Abs(cwd)
+	if err != nil {
+		return ""
+	}
+	for dir := start; ; dir = filepath.Dir(dir) {
+		if _, err := cm.fs.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		if filepath.Dir(dir) == dir {
+			return ""
+		}
+	}
+}
+
+// validateSessionIdentity returns one message per invalid session identity
+// setting.
+func validateSessionIdentity(s *SessionIdentityConfig) []string {
+	if s == nil {
+		return nil
+	}
+	var errs []string
+	if s.Key != "" && !slices.Contains(validSessionIdentityKeys, s.Key) {
+		errs = append(errs, fmt.Sprintf("session_identity.key must be one of: %s, got %q",
+			strings.Join(validSessionIdentityKeys, ", "), s.Key))
+	}
+	if s.PitchSemitones < 0 || s.PitchSemitones > 12 {
+		errs = append(errs, fmt.Sprintf("session_identity.pitch_semitones must be between 0 and 12, got %g", s.PitchSemitones))
+	}
+	if s.Pan < 0 || s.Pan > 1 {
+		errs = append(errs, fmt.Sprintf("session_identity.pan must be between 0.0 and 1.0, got %g", s.Pan))
+	}
+	return errs
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIdentityFor(t *testing.T) {
+	cm := NewConfigManager()
+	repo := t.TempDir()
+	deep := filepath.Join(repo, "cmd", "tool")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(deep, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	outside := t.TempDir()
+
+	tests := []struct {
+		key  string
+		cwd  string
+		want string
+	}{
+		{"", deep, "sess-1"},
+		{"session", deep, "sess-1"},
+		{"cwd", deep, deep},
+		{"repo", deep, repo},
+		{"repo", outside, outside}, // no repository: fall back to cwd
+	}
+	for _, tt := range tests {
+		got := cm.IdentityFor(&SessionIdentityConfig{Enabled: true, Key: tt.key}, "sess-1", tt.cwd)
+		if got != tt.want {
+			t.Errorf("IdentityFor(key=%q, cwd=%s) = %q, want %q", tt.key, tt.cwd, got, tt.want)
+		}
+	}
+}
+
+func TestValidateConfig_SessionIdentity(t *testing.T) {
+	cfg := NewConfigManager().GetDefaultConfig()
+	cfg.SessionIdentity = &SessionIdentityConfig{Enabled: true, Key: "branch", Pan: 1.5}
+	err := NewConfigManager().ValidateConfig(cfg)
+	if err == nil {
+		t.Fatal("expected invalid session identity settings to be rejected")
+	}
+	for _, want := range []string{"session_identity.key", "session_identity.pan"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %s", err, want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+)
+
+// Speech engines for say: templates. The names match the audio package's
+// SpeechEngine constants.
+const (
+	SpeechEngineAuto     = "auto"      // first installed engine: piper (with a model), espeak-ng, say, spd-say
+	SpeechEngineEspeakNG = "espeak-ng" // eSpeak NG, or classic espeak when only it is installed
+	SpeechEngineSay      = "say"       // macOS say
+	SpeechEngineSpdSay   = "spd-say"   // speech-dispatcher; speaks directly, bypassing audio_backend
+	SpeechEnginePiper    = "piper"     // piper neural TTS; needs piper_model
+)
+
+// SpeechConfig selects the local text-to-speech engine that speaks say:
+// templates from soundpack mappings and routing rules.
+type SpeechConfig struct {
+	Engine     string `json:"engine"`                // auto, espeak-ng, say, spd-say, or piper
+	Voice      string `json:"voice,omitempty"`       // Engine-specific voice name (empty = engine default)
+	Rate       int    `json:"rate,omitempty"`        // Words per minute (0 = engine default)
+	PiperModel string `json:"piper_model,omitempty"` // Path to a piper .onnx voice model
+}
+
+// GetDefaultSpeechConfig returns the default speech configuration.
+func GetDefaultSpeechConfig() *SpeechConfig {
+	return &SpeechConfig{
+		Engine: SpeechEngineAuto,
+	}
+}
+
+// GetSpeechEngines returns the accepted speech engine values.
+func GetSpeechEngines() []string {
+	return []string{
+		SpeechEngineAuto,
+		SpeechEngineEspeakNG,
+		SpeechEngineSay,
+		SpeechEngineSpdSay,
+		SpeechEnginePiper,
+	}
+}
+
+// IsValidSpeechEngine reports whether engine is an accepted speech engine.
+// The empty string is valid and means auto.
+func IsValidSpeechEngine(engine string) bool {
+	if engine == "" {
+		return true
+	}
+	for _, e := range GetSpeechEngines() {
+		if e == engine {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplySpeechEnvironmentOverrides applies environment variable overrides to speech config
+func ApplySpeechEnvironmentOverrides(config *SpeechConfig) *SpeechConfig {
+	return applySpeechEnvironment(config, os.Getenv)
+}
+
+// applySpeechEnvironment applies the overrides getenv reports.
+func applySpeechEnvironment(config *SpeechConfig, getenv func(string) string) *SpeechConfig {
+	slog.Debug("applying speech environment variable overrides")
+
+	// Create a copy to modify
+	result := *config
+
+	// CLAUDIO_SPEECH_ENGINE
+	if engine := getenv("CLAUDIO_SPEECH_ENGINE"); engine != "" {
+		if IsValidSpeechEngine(engine) {
+			result.Engine = engine
+			slog.Debug("applied speech engine override from environment", "value", engine)
+		} else {
+			slog.Warn("invalid CLAUDIO_SPEECH_ENGINE environment variable", "value", engine)
+		}
+	}
+
+	// CLAUDIO_SPEECH_VOICE
+	if voice := getenv("CLAUDIO_SPEECH_VOICE"); voice != "" {
+		result.Voice = voice
+		slog.Debug("applied speech voice override from environment", "value", voice)
+	}
+
+	// CLAUDIO_SPEECH_RATE
+	if rateStr := getenv("CLAUDIO_SPEECH_RATE"); rateStr != "" {
+		if rate, err := strconv.Atoi(rateStr); err == nil && rate > 0 {
+			result.Rate = rate
+			slog.Debug("applied speech rate override from environment", "value", rate)
+		} else {
+			slog.Warn("invalid CLAUDIO_SPEECH_RATE environment variable", "value", rateStr)
+		}
+	}
+
+	return &result
+}
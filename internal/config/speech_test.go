@@ -0,0 +1,57 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplySpeechEnvironmentOverrides(t *testing.T) {
+	t.Setenv("CLAUDIO_SPEECH_ENGINE", "espeak-ng")
+	t.Setenv("CLAUDIO_SPEECH_VOICE", "en-gb")
+	t.Setenv("CLAUDIO_SPEECH_RATE", "220")
+
+	result := ApplySpeechEnvironmentOverrides(GetDefaultSpeechConfig())
+	if result.Engine != SpeechEngineEspeakNG || result.Voice != "en-gb" || result.Rate != 220 {
+		t.Errorf("overrides not applied: %+v", result)
+	}
+}
+
+func TestApplySpeechEnvironmentOverrides_InvalidIgnored(t *testing.T) {
+	t.Setenv("CLAUDIO_SPEECH_ENGINE", "festival")
+	t.Setenv("CLAUDIO_SPEECH_RATE", "fast")
+
+	result := ApplySpeechEnvironmentOverrides(GetDefaultSpeechConfig())
+	if result.Engine != SpeechEngineAuto || result.Rate != 0 {
+		t.Errorf("invalid overrides should be ignored, got %+v", result)
+	}
+}
+
+func TestValidateConfig_Speech(t *testing.T) {
+	cm := NewConfigManager()
+	tests := []struct {
+		name    string
+		speech  *SpeechConfig
+		wantErr string
+	}{
+		{"unknown engine", &SpeechConfig{Engine: "festival"}, "speech engine"},
+		{"negative rate", &SpeechConfig{Engine: SpeechEngineSay, Rate: -1}, "speech rate"},
+		{"piper without model", &SpeechConfig{Engine: SpeechEnginePiper}, "piper_model"},
+		{"piper with model", &SpeechConfig{Engine: SpeechEnginePiper, PiperModel: "/v/amy.onnx"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := cm.GetDefaultConfig()
+			cfg.Speech = tt.speech
+			err := cm.ValidateConfig(cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
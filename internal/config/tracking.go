@@ -22,13 +22,18 @@ func GetDefaultSoundTrackingConfig() *SoundTrackingConfig {
 
 // ApplySoundTrackingEnvironmentOverrides applies environment variable overrides to sound tracking config
 func ApplySoundTrackingEnvironmentOverrides(config *SoundTrackingConfig) *SoundTrackingConfig {
+	return applySoundTrackingEnvironment(config, os.Getenv)
+}
+
+// applySoundTrackingEnvironment applies the overrides getenv reports.
+func applySoundTrackingEnvironment(config *SoundTrackingConfig, getenv func(string) string) *SoundTrackingConfig {
 	slog.Debug("applying sound tracking environment variable overrides")
 
 	// Create a copy to modify
 	result := *config
 
 	// CLAUDIO_SOUND_TRACKING
-	if trackingStr := os.Getenv("CLAUDIO_SOUND_TRACKING"); trackingStr != "" {
+	if trackingStr := getenv("CLAUDIO_SOUND_TRACKING"); trackingStr != "" {
 		if enabled, err := strconv.ParseBool(trackingStr); err == nil {
 			result.Enabled = enabled
 			slog.Debug("applied sound tracking override from environment", "value", enabled)
@@ -38,7 +43,7 @@ func ApplySoundTrackingEnvironmentOverrides(config *SoundTrackingConfig) *SoundT
 	}
 
 	// CLAUDIO_SOUND_TRACKING_DB
-	if dbPath := os.Getenv("CLAUDIO_SOUND_TRACKING_DB"); dbPath != "" {
+	if dbPath := getenv("CLAUDIO_SOUND_TRACKING_DB"); dbPath != "" {
 		result.DatabasePath = dbPath
 		slog.Debug("applied sound tracking database path override from environment", "value", dbPath)
 	}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultLongTurnSeconds is how long a turn must run before its completion
+// tries the "-long" sound key when turns.long_turn_seconds is unset.
+const DefaultLongTurnSeconds = 120
+
+// TurnsConfig tunes completion sounds by how long the agent's turn ran,
+// from the prompt (UserPromptSubmit or BeforeAgent) to Stop or AfterAgent.
+// Turn timing is kept in the sound tracking database.
+type TurnsConfig struct {
+	LongTurnSeconds   int `json:"long_turn_seconds,omitempty"`   // Turns at least this long try completion/agent-complete-long.wav (0 = default)
+	QuietUnderSeconds int `json:"quiet_under_seconds,omitempty"` // Skip the completion sound for shorter turns (0 = never skip)
+}
+
+// EffectiveLongTurn returns the long-turn threshold, applying the default.
+// Safe on a nil receiver.
+func (t *TurnsConfig) EffectiveLongTurn() time.Duration {
+	if t == nil || t.LongTurnSeconds <= 0 {
+		return DefaultLongTurnSeconds * time.Second
+	}
+	return time.Duration(t.LongTurnSeconds) * time.Second
+}
+
+// QuietUnder returns the duration below which a completion is silent, or 0
+// when short turns still play. Safe on a nil receiver.
+func (t *TurnsConfig) QuietUnder() time.Duration {
+	if t == nil || t.QuietUnderSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(t.QuietUnderSeconds) * time.Second
+}
+
+// validateTurns returns one message per invalid turns setting.
+func validateTurns(t *TurnsConfig) []string {
+	if t == nil {
+		return nil
+	}
+	var errs []string
+	if t.LongTurnSeconds < 0 {
+		errs = append(errs, fmt.Sprintf("turns.long_turn_seconds must be >= 0, got %d", t.LongTurnSeconds))
+	}
+	if t.QuietUnderSeconds < 0 {
+		errs = append(errs, fmt.Sprintf("turns.quiet_under_seconds must be >= 0, got %d", t.QuietUnderSeconds))
+	}
+	return errs
+}
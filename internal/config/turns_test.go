@@ -0,0 +1,39 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTurnsConfig_Thresholds(t *testing.T) {
+	var unset *TurnsConfig
+	if got := unset.EffectiveLongTurn(); got != DefaultLongTurnSeconds*time.Second {
+		t.Errorf("nil EffectiveLongTurn() = %v, want default", got)
+	}
+	if got := unset.QuietUnder(); got != 0 {
+		t.Errorf("nil QuietUnder() = %v, want 0", got)
+	}
+
+	turns := &TurnsConfig{LongTurnSeconds: 600, QuietUnderSeconds: 5}
+	if got := turns.EffectiveLongTurn(); got != 10*time.Minute {
+		t.Errorf("EffectiveLongTurn() = %v, want 10m0s", got)
+	}
+	if got := turns.QuietUnder(); got != 5*time.Second {
+		t.Errorf("QuietUnder() = %v, want 5s", got)
+	}
+}
+
+func TestValidateConfig_Turns(t *testing.T) {
+	cfg := NewConfigManager().GetDefaultConfig()
+	cfg.Turns = &TurnsConfig{LongTurnSeconds: -1, QuietUnderSeconds: -5}
+	err := NewConfigManager().ValidateConfig(cfg)
+	if err == nil {
+		t.Fatal("expected negative turn thresholds to be rejected")
+	}
+	for _, want := range []string{"turns.long_turn_seconds", "turns.quiet_under_seconds"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %s", err, want)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package hooks
+
+import "strings"
+
+// ParseMCPToolName splits an MCP tool name into its server and tool parts.
+// Claude names tools mcp__<server>__<tool>; Gemini and Qwen use
+// mcp_<server>_<tool>, where the server is taken to be the first segment
+// (a server name containing '_' cannot be told apart from the tool there).
+// ok is false for names that are not MCP tool calls or carry no server.
+func ParseMCPToolName(name string) (server, tool string, ok bool) {
+	if rest, found := strings.CutPrefix(name, "mcp__"); found {
+		server, tool, _ = strings.Cut(rest, "__")
+	} else if rest, found := strings.CutPrefix(name, "mcp_"); found {
+		server, tool, _ = strings.Cut(rest, "_")
+	} else {
+		return "", "", false
+	}
+	if server == "" {
+		return "", "", false
+	}
+	return server, tool, true
+}
@@ -0,0 +1,46 @@
+package hooks
+
+import "testing"
+
+func TestParseMCPToolName(t *testing.T) {
+	tests := []struct {
+		name       string
+		wantServer string
+		wantTool   string
+		wantOK     bool
+	}{
+		{"mcp__github__create_pull_request", "github", "create_pull_request", true},
+		{"mcp__plugin_docs__search", "plugin_docs", "search", true},
+		{"mcp__memory", "memory", "", true},
+		{"mcp_github_create_pull_request", "github", "create_pull_request", true},
+		{"mcp_filesystem_read_file", "filesystem", "read_file", true},
+		{"mcp", "", "", false},
+		{"mcp__", "", "", false},
+		{"Bash", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, tool, ok := ParseMCPToolName(tt.name)
+			if server != tt.wantServer || tool != tt.wantTool || ok != tt.wantOK {
+				t.Errorf("ParseMCPToolName(%q) = (%q, %q, %v), want (%q, %q, %v)",
+					tt.name, server, tool, ok, tt.wantServer, tt.wantTool, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGetContextSetsMCPServerAndTool(t *testing.T) {
+	for _, toolName := range []string{"mcp__github__create_pull_request", "mcp_github_create_pull_request"} {
+		t.Run(toolName, func(t *testing.T) {
+			event := &HookEvent{EventName: "PreToolUse", ToolName: &toolName}
+			ctx := event.GetContext()
+			if ctx.ToolName != "mcp" || ctx.SoundHint != "mcp-start" {
+				t.Fatalf("ToolName/SoundHint = %q/%q, want mcp/mcp-start", ctx.ToolName, ctx.SoundHint)
+			}
+			if ctx.MCPServer != "github" || ctx.MCPTool != "create_pull_request" {
+				t.Errorf("MCPServer/MCPTool = %q/%q, want github/create_pull_request", ctx.MCPServer, ctx.MCPTool)
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package hooks
+
+import (
+	"encoding/json"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// Outcome classes describe how a tool call ended. PostToolUse chains try an
+// outcome-specific sound ("error/go-test-failed.wav",
+// "success/bash-warnings.wav") before the generic success/error sounds, so
+// a pack can tell a broken build from a noisy stderr line.
+const (
+	OutcomeSuccess          = "success"           // finished cleanly
+	OutcomeWarnings         = "warnings"          // succeeded but wrote to stderr
+	OutcomeFailed           = "failed"            // failed for an unclassified reason
+	OutcomeTimeout          = "timeout"           // killed by a time limit
+	OutcomeInterrupted      = "interrupted"       // stopped by the user
+	OutcomePermissionDenied = "permission-denied" // lacked permission (exit 126, EACCES)
+	OutcomeNotFound         = "not-found"         // command or file missing (exit 127, ENOENT)
+	OutcomeTestFailed       = "test-failed"       // a test runner reported failing tests
+	OutcomeBuildFailed      = "build-failed"      // compilation or build step failed
+)
+
+// ToolOutcome is the classified result of one tool call.
+type ToolOutcome struct {
+	Class       string // One of the Outcome* constants
+	ExitCode    int    // Process exit code; meaningful only when HasExitCode
+	HasExitCode bool   // True when the tool response reported an exit code
+}
+
+// IsError reports whether the outcome counts as a failure.
+func (o ToolOutcome) IsError() bool {
+	return o.Class != OutcomeSuccess && o.Class != OutcomeWarnings
+}
+
+// IsSpecific reports whether the outcome says more than plain success or
+// failure, and so deserves its own sound hint.
+func (o ToolOutcome) IsSpecific() bool {
+	return o.Class != "" && o.Class != OutcomeSuccess && o.Class != OutcomeFailed
+}
+
+// Exit codes with a conventional meaning.
+const (
+	exitTimeout          = 124 // timeout(1) killed the command
+	exitPermissionDenied = 126 // shell: found but not executable
+	exitNotFound         = 127 // shell: command not found
+)
+
+var (
+	// stderrErrorPattern recognizes failure output from agents that report
+	// no exit code. Anything else on stderr (progress meters, deprecation
+	// notices, compiler warnings) is a warning, not a failure.
+	stderrErrorPattern = regexp.MustCompile(`(?im)^\s*(error|fatal|panic)\b|\b(error|failed|failure|exception)\b|traceback \(most recent call last\)`)
+
+	timeoutPattern          = regexp.MustCompile(`(?i)\btimed out\b|\btimeout exceeded\b|deadline exceeded`)
+	permissionDeniedPattern = regexp.MustCompile(`(?i)permission denied|operation not permitted|\beacces\b|\beperm\b|access is denied`)
+	notFoundPattern         = regexp.MustCompile(`(?i)command not found|: not found\b|no such file or directory|\benoent\b|is not recognized as an internal or external command`)
+
+	// testFailurePatterns are test-runner summaries that mean at least one
+	// test failed, whatever wrapper (make, npm run) invoked the runner.
+	testFailurePattern = regexp.MustCompile(`(?m)^--- FAIL|^FAIL\s|test result: FAILED|^=+ .*\b\d+ failed\b|^Tests:\s.*\b\d+ failed\b|^\s*\d+ failing\b|There were failing tests|Tests run:.*Failures: [1-9]`)
+
+	// buildFailurePatterns are compiler and build-tool failure markers.
+	buildFailurePattern = regexp.MustCompile(`(?m)\[build failed\]|\[setup failed\]|could not compile|^error\[E\d+\]|\berror TS\d+:|BUILD FAILED|BUILD FAILURE|Module not found: Error`)
+)
+
+// testCommands maps a command to the subcommands that run tests; a nil
+// list means the command itself is a test runner.
+var testCommands = map[string][]string{
+	"go":     {"test"},
+	"cargo":  {"test", "nextest", "bench"},
+	"npm":    {"test", "t"},
+	"yarn":   {"test"},
+	"pnpm":   {"test"},
+	"bun":    {"test"},
+	"deno":   {"test"},
+	"pytest": nil,
+	"jest":   nil,
+	"vitest": nil,
+	"mocha":  nil,
+	"tox":    nil,
+}
+
+// buildCommands maps a command to the subcommands that compile or build.
+var buildCommands = map[string][]string{
+	"go":     {"build", "install", "vet"},
+	"cargo":  {"build", "check", "clippy", "install"},
+	"npm":    {"build"},
+	"yarn":   {"build"},
+	"pnpm":   {"build"},
+	"make":   nil,
+	"cmake":  nil,
+	"ninja":  nil,
+	"tsc":    nil,
+	"gradle": nil,
+	"mvn":    nil,
+	"gcc":    nil,
+	"clang":  nil,
+	"rustc":  nil,
+}
+
+// toolResponseFields holds the parts of a tool response that matter for
+// classification, across the response shapes different agents send.
+type toolResponseFields struct {
+	interrupted bool
+	timedOut    bool
+	isError     bool
+	errorText   string
+	hasError    bool // error field present and non-empty
+	exitCode    int
+	hasExitCode bool
+	stdout      string
+	stderr      string
+	text        string // plain-text response or content string
+	hasContent  bool
+	success     *bool // explicit success field (Edit/Write)
+	structured  bool  // response was a JSON object rather than plain text
+}
+
+// classifyToolOutcome examines the tool response and classifies how the
+// call ended. forceError is set for failure events (PostToolUseFailure),
+// which are failures whatever the response says.
+func (e *HookEvent) classifyToolOutcome(forceError bool) ToolOutcome {
+	outcome := e.classifyResponse()
+	if forceError && !outcome.IsError() {
+		outcome.Class = OutcomeFailed
+	}
+	slog.Debug("classified tool outcome",
+		"class", outcome.Class,
+		"exit_code", outcome.ExitCode,
+		"has_exit_code", outcome.HasExitCode,
+		"forced", forceError)
+	return outcome
+}
+
+func (e *HookEvent) classifyResponse() ToolOutcome {
+	if e.ToolResponse == nil {
+		slog.Debug("no tool response to analyze")
+		return ToolOutcome{Class: OutcomeSuccess} // No response usually means success
+	}
+
+	r, ok := parseToolResponse(*e.ToolResponse)
+	if !ok {
+		slog.Error("failed to parse tool response")
+		return ToolOutcome{Class: OutcomeFailed}
+	}
+
+	outcome := ToolOutcome{ExitCode: r.exitCode, HasExitCode: r.hasExitCode}
+	output := strings.Join([]string{r.stderr, r.stdout, r.errorText, r.text}, "\n")
+
+	// Check for interruption first (more specific than anything else)
+	if r.interrupted {
+		slog.Debug("tool was interrupted")
+		outcome.Class = OutcomeInterrupted
+		return outcome
+	}
+	if r.timedOut {
+		outcome.Class = OutcomeTimeout
+		return outcome
+	}
+
+	failed := r.isError || r.hasError || (r.hasExitCode && r.exitCode != 0)
+	if !failed && !r.hasExitCode && r.stderr != "" && stderrErrorPattern.MatchString(r.stderr) {
+		// Without an exit code, stderr that reads like an error is the
+		// only failure signal some agents give.
+		slog.Debug("stderr looks like an error", "stderr_length", len(r.stderr))
+		failed = true
+	}
+	if !failed {
+		failed = e.toolReportsFailure(r)
+	}
+
+	if failed {
+		outcome.Class = e.classifyFailure(r, output)
+		return outcome
+	}
+	if r.stderr != "" {
+		slog.Debug("tool succeeded with stderr output", "stderr_length", len(r.stderr))
+		outcome.Class = OutcomeWarnings
+		return outcome
+	}
+	outcome.Class = OutcomeSuccess
+	return outcome
+}
+
+// toolReportsFailure applies tool-specific success conventions to a
+// response that carried no generic failure signal.
+func (e *HookEvent) toolReportsFailure(r toolResponseFields) bool {
+	switch e.NormalizedToolName() {
+	case "Read", "LS", "Glob":
+		// File tools are success if they have content
+		return r.structured && !r.hasContent
+	case "Edit", "Write", "MultiEdit":
+		// Edit tools should indicate success/failure explicitly
+		return r.success != nil && !*r.success
+	}
+	return false
+}
+
+// classifyFailure narrows a failed call down to the most specific class
+// its exit code, command, and output support.
+func (e *HookEvent) classifyFailure(r toolResponseFields, output string) string {
+	if r.hasExitCode {
+		switch r.exitCode {
+		case exitPermissionDenied:
+			return OutcomePermissionDenied
+		case exitNotFound:
+			return OutcomeNotFound
+		case exitTimeout:
+			return OutcomeTimeout
+		}
+	}
+
+	info := e.CommandInfo()
+
+	// Output markers beat command defaults: `go test` that fails to
+	// compile is a build failure, and `make check` can run a test suite.
+	switch {
+	case buildFailurePattern.MatchString(output):
+		return OutcomeBuildFailed
+	case testFailurePattern.MatchString(output):
+		return OutcomeTestFailed
+	case commandMatches(testCommands, info):
+		return OutcomeTestFailed
+	case commandMatches(buildCommands, info):
+		return OutcomeBuildFailed
+	case timeoutPattern.MatchString(output):
+		return OutcomeTimeout
+	case permissionDeniedPattern.MatchString(output):
+		return OutcomePermissionDenied
+	case notFoundPattern.MatchString(output):
+		return OutcomeNotFound
+	}
+	return OutcomeFailed
+}
+
+// commandMatches reports whether info names a command (and, where the
+// table lists them, one of its subcommands) in table.
+func commandMatches(table map[string][]string, info CommandInfo) bool {
+	subcommands, ok := table[info.Command]
+	if !ok {
+		return false
+	}
+	if subcommands == nil {
+		return true
+	}
+	return containsString(subcommands, info.Subcommand)
+}
+
+// parseToolResponse extracts classification fields from a JSON object or
+// plain-text tool response.
+func parseToolResponse(raw json.RawMessage) (toolResponseFields, bool) {
+	var r toolResponseFields
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(raw, &response); err != nil {
+		var responseText string
+		if stringErr := json.Unmarshal(raw, &responseText); stringErr != nil {
+			return r, false
+		}
+		r.text = responseText
+		r.exitCode, r.hasExitCode = parseExitCode(responseText)
+		return r, true
+	}
+
+	slog.Debug("analyzing tool response", "response_keys", getMapKeys(response))
+	r.structured = true
+
+	r.interrupted, _ = response["interrupted"].(bool)
+	for _, key := range []string{"timed_out", "timedOut"} {
+		if v, ok := response[key].(bool); ok && v {
+			r.timedOut = true
+		}
+	}
+	r.isError, _ = response["isError"].(bool)
+	if errorValue, ok := response["error"]; ok && errorValue != nil {
+		if errorString, ok := errorValue.(string); ok {
+			r.errorText = errorString
+			r.hasError = errorString != ""
+		} else {
+			r.hasError = true
+		}
+	}
+	for _, key := range []string{"exit_code", "exitCode", "returncode", "return_code"} {
+		if v, ok := response[key].(float64); ok {
+			r.exitCode, r.hasExitCode = int(v), true
+			break
+		}
+	}
+	r.stdout, _ = response["stdout"].(string)
+	r.stderr, _ = response["stderr"].(string)
+	if content, ok := response["content"]; ok && content != nil {
+		r.hasContent = true
+		if s, ok := content.(string); ok {
+			r.text = s
+		}
+	}
+	if output, ok := response["output"].(string); ok {
+		r.text = strings.TrimSpace(r.text + "\n" + output)
+		if !r.hasExitCode {
+			r.exitCode, r.hasExitCode = parseExitCode(output)
+		}
+	}
+	if success, ok := response["success"].(bool); ok {
+		r.success = &success
+	}
+	return r, true
+}
@@ -0,0 +1,102 @@
+package hooks
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestClassifyToolOutcome(t *testing.T) {
+	tests := []struct {
+		name      string
+		tool      string
+		command   string
+		response  string
+		wantClass string
+		wantExit  int
+	}{
+		{"clean success", "Bash", "ls", `{"stdout":"a\nb","stderr":"","interrupted":false}`, OutcomeSuccess, 0},
+		{"git push progress is a warning", "Bash", "git push", `{"stdout":"","stderr":"To github.com:o/r.git\n   1a2b..3c4d  main -> main","interrupted":false}`, OutcomeWarnings, 0},
+		{"error-looking stderr without exit code fails", "Bash", "git status", `{"stdout":"","stderr":"fatal: not a git repository","interrupted":false}`, OutcomeFailed, 0},
+		{"zero exit with stderr is a warning", "Bash", "cargo build", `{"stdout":"","stderr":"warning: unused variable","exit_code":0}`, OutcomeWarnings, 0},
+		{"interrupted", "Bash", "sleep 100", `{"stdout":"","stderr":"","interrupted":true}`, OutcomeInterrupted, 0},
+		{"timed out flag", "Bash", "sleep 100", `{"timed_out":true}`, OutcomeTimeout, 0},
+		{"timeout wrapper exit", "Bash", "timeout 5 make", `{"exit_code":124}`, OutcomeTimeout, 124},
+		{"exit 126", "Bash", "./run.sh", `{"exit_code":126}`, OutcomePermissionDenied, 126},
+		{"exit 127", "Bash", "frobnicate", `{"exitCode":127,"stderr":"frobnicate: command not found"}`, OutcomeNotFound, 127},
+		{"permission text", "Bash", "cat /root/x", `{"exit_code":1,"stderr":"cat: /root/x: Permission denied"}`, OutcomePermissionDenied, 1},
+		{"missing file text", "Bash", "cat nope", `{"exit_code":1,"stderr":"cat: nope: No such file or directory"}`, OutcomeNotFound, 1},
+		{"go test failure", "Bash", "go test ./...", `{"exit_code":1,"stdout":"--- FAIL: TestX (0.00s)\nFAIL\tpkg\t0.01s"}`, OutcomeTestFailed, 1},
+		{"go test build failure", "Bash", "go test ./...", `{"exit_code":1,"stdout":"FAIL\tpkg [build failed]"}`, OutcomeBuildFailed, 1},
+		{"go build failure", "Bash", "go build ./...", `{"exit_code":1,"stderr":"./main.go:3:2: undefined: x"}`, OutcomeBuildFailed, 1},
+		{"cargo compile error", "Bash", "cargo test", `{"exit_code":101,"stderr":"error[E0425]: cannot find value\nerror: could not compile"}`, OutcomeBuildFailed, 101},
+		{"cargo test failure", "Bash", "cargo test", `{"exit_code":101,"stdout":"test result: FAILED. 1 passed; 1 failed"}`, OutcomeTestFailed, 101},
+		{"npm test failure", "Bash", "npm test", `{"exit_code":1,"stdout":"Tests:       1 failed, 4 passed, 5 total"}`, OutcomeTestFailed, 1},
+		{"pytest failure via wrapper", "Bash", "uv run pytest", `{"exit_code":1,"stdout":"===== 2 failed, 10 passed in 0.5s ====="}`, OutcomeTestFailed, 1},
+		{"make running go test", "Bash", "make check", `{"exit_code":2,"stdout":"--- FAIL: TestY\nmake: *** [check] Error 1"}`, OutcomeTestFailed, 2},
+		{"make build failure", "Bash", "make", `{"exit_code":2,"stderr":"main.c:1: error: x\nmake: *** [all] Error 1"}`, OutcomeBuildFailed, 2},
+		{"generic failure", "Bash", "false", `{"exit_code":1}`, OutcomeFailed, 1},
+		{"codex text exit code", "Bash", "go test ./...", `"Exit code: 1\nWall time: 1.2 seconds\nOutput:\n--- FAIL: TestZ"`, OutcomeTestFailed, 1},
+		{"mcp isError", "mcp__github__create_issue", "", `{"isError":true,"content":[{"type":"text","text":"Permission denied"}]}`, OutcomeFailed, 0},
+		{"error field", "Edit", "", `{"error":"File not found: x.go"}`, OutcomeFailed, 0},
+		{"edit success false", "Edit", "", `{"success":false}`, OutcomeFailed, 0},
+		{"read without content", "Read", "", `{"stdout":"","stderr":"boom"}`, OutcomeFailed, 0},
+		{"read text response", "Read", "", `"file contents"`, OutcomeSuccess, 0},
+		{"webfetch code is not an exit code", "WebFetch", "", `{"code":200,"result":"ok"}`, OutcomeSuccess, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := tt.tool
+			input := json.RawMessage(`{"command":` + mustJSON(t, tt.command) + `}`)
+			resp := json.RawMessage(tt.response)
+			event := &HookEvent{EventName: "PostToolUse", ToolName: &tool, ToolInput: &input, ToolResponse: &resp}
+
+			got := event.classifyToolOutcome(false)
+			if got.Class != tt.wantClass {
+				t.Errorf("class = %q, want %q", got.Class, tt.wantClass)
+			}
+			if got.ExitCode != tt.wantExit {
+				t.Errorf("exit code = %d, want %d", got.ExitCode, tt.wantExit)
+			}
+		})
+	}
+}
+
+func TestClassifyToolOutcome_ForceError(t *testing.T) {
+	tool := "Bash"
+	resp := json.RawMessage(`{"stdout":"ok","stderr":""}`)
+	event := &HookEvent{EventName: "PostToolUseFailure", ToolName: &tool, ToolResponse: &resp}
+	if got := event.classifyToolOutcome(true); got.Class != OutcomeFailed {
+		t.Errorf("forced failure class = %q, want %q", got.Class, OutcomeFailed)
+	}
+}
+
+func TestGetContext_Outcome(t *testing.T) {
+	tool := "Bash"
+	input := json.RawMessage(`{"command":"go test ./..."}`)
+	resp := json.RawMessage(`{"exit_code":1,"stdout":"--- FAIL: TestX"}`)
+	event := &HookEvent{SessionID: "s", CWD: "/tmp", EventName: "PostToolUse", ToolName: &tool, ToolInput: &input, ToolResponse: &resp}
+
+	ctx := event.GetContext()
+	if ctx.Category != Error || ctx.Outcome != OutcomeTestFailed {
+		t.Fatalf("category/outcome = %v/%q, want error/%q", ctx.Category, ctx.Outcome, OutcomeTestFailed)
+	}
+	if ctx.SoundHint != "go-test-error" {
+		t.Errorf("hint = %q, want go-test-error", ctx.SoundHint)
+	}
+
+	resp = json.RawMessage(`{"stdout":"","stderr":"Everything up-to-date"}`)
+	input = json.RawMessage(`{"command":"git push"}`)
+	ctx = event.GetContext()
+	if ctx.Category != Success || ctx.Outcome != OutcomeWarnings {
+		t.Errorf("category/outcome = %v/%q, want success/%q", ctx.Category, ctx.Outcome, OutcomeWarnings)
+	}
+}
+
+func mustJSON(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
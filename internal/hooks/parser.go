@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // EventCategory represents the type of hook event for sound mapping
@@ -59,6 +60,16 @@ type HookEvent struct {
 	ToolResponse *json.RawMessage `json:"tool_response,omitempty"`
 	Prompt       *string          `json:"prompt,omitempty"`
 	Message      *string          `json:"message,omitempty"`
+
+	// Agent is the coding agent that invoked the hook ("claude", "codex",
+	// ...). It is not part of the payload; the CLI fills it in from
+	// --hook-agent after parsing.
+	Agent string `json:"-"`
+
+	// CommandSelection picks which command of a compound Bash command
+	// line names the sound hint (see CommandSelectionMostSpecific). Set
+	// by the CLI from config; empty means most-specific.
+	CommandSelection string `json:"-"`
 }
 
 // EventContext provides processed context for sound mapping
@@ -70,7 +81,14 @@ type EventContext struct {
 	HasError     bool
 	SoundHint    string
 	FileType     string
+	FileGroup    string // Extension group of the tool's file (code, docs, config, tests), set by the CLI from config
 	Operation    string
+	Agent        string        // Invoking agent from HookEvent.Agent, lowercased; empty when unknown
+	Outcome      string        // Tool outcome class for PostToolUse events (OutcomeTestFailed, ...); empty otherwise
+	MCPServer    string        // MCP server of an "mcp" tool call, e.g. "github"; empty otherwise
+	MCPTool      string        // MCP tool on that server, e.g. "create_pull_request"
+	TurnDuration time.Duration // How long the finished turn ran (Stop/AfterAgent), set by the CLI; 0 when unknown
+	LongTurn     bool          // TurnDuration reached the configured long-turn threshold
 }
 
 // CommandInfo represents parsed command information from Bash tool input
@@ -202,6 +220,7 @@ func (e *HookEvent) applyCompatibilityAliases(data []byte) error {
 func (e *HookEvent) GetContext() *EventContext {
 	context := &EventContext{
 		ToolName: normalizeToolName(getStringPtr(e.ToolName)),
+		Agent:    strings.ToLower(strings.TrimSpace(e.Agent)),
 	}
 
 	slog.Debug("extracting event context",
@@ -425,6 +444,11 @@ func (e *HookEvent) GetContext() *EventContext {
 		context.FileType = e.extractFileType()
 	}
 
+	// Keep the server and tool of an MCP call, which ToolName collapses to "mcp"
+	if context.ToolName == "mcp" {
+		context.MCPServer, context.MCPTool, _ = ParseMCPToolName(context.OriginalTool)
+	}
+
 	slog.Debug("event context extracted",
 		"event_name", e.EventName,
 		"category", context.Category.String(),
@@ -434,6 +458,7 @@ func (e *HookEvent) GetContext() *EventContext {
 		"is_success", context.IsSuccess,
 		"has_error", context.HasError,
 		"file_type", context.FileType,
+		"mcp_server", context.MCPServer,
 		"operation", context.Operation)
 
 	return context
@@ -470,13 +495,18 @@ func (e *HookEvent) populatePreToolContext(context *EventContext) {
 }
 
 func (e *HookEvent) populatePostToolContext(context *EventContext, forceError bool) {
-	success, hasError, errorType := e.analyzeToolResponse()
-	if forceError {
-		success = false
-		hasError = true
-	}
-	context.IsSuccess = success
+	outcome := e.classifyToolOutcome(forceError)
+	hasError := outcome.IsError()
+	context.IsSuccess = !hasError
 	context.HasError = hasError
+	context.Outcome = outcome.Class
+
+	// Interruption keeps its historical hint; every other class reaches
+	// the chain through context.Outcome.
+	errorType := ""
+	if outcome.Class == OutcomeInterrupted {
+		errorType = "tool-interrupted"
+	}
 
 	if hasError {
 		context.Category = Error
@@ -553,6 +583,21 @@ func (e *HookEvent) populatePostToolSuccessHint(context *EventContext) {
 	}
 }
 
+// NormalizedToolName returns the event's tool name mapped to its canonical
+// form ("Bash", "Edit", "mcp", ...), or "" for events without a tool.
+func (e *HookEvent) NormalizedToolName() string {
+	return normalizeToolName(getStringPtr(e.ToolName))
+}
+
+// CommandInfo returns the parsed command of a Bash tool call, or the zero
+// CommandInfo for any other tool.
+func (e *HookEvent) CommandInfo() CommandInfo {
+	if e.NormalizedToolName() != "Bash" {
+		return CommandInfo{}
+	}
+	return e.extractCommandInfo()
+}
+
 func normalizeToolName(toolName string) string {
 	if toolName == "" {
 		return ""
@@ -597,97 +642,6 @@ func isMCPToolName(toolName string) bool {
 	return toolName == "mcp" || strings.HasPrefix(toolName, "mcp__") || strings.HasPrefix(toolName, "mcp_")
 }
 
-// analyzeToolResponse examines tool response to determine success/error status and error type
-func (e *HookEvent) analyzeToolResponse() (success bool, hasError bool, errorType string) {
-	if e.ToolResponse == nil {
-		slog.Debug("no tool response to analyze")
-		return true, false, "" // No response usually means success
-	}
-
-	var response map[string]interface{}
-	err := json.Unmarshal(*e.ToolResponse, &response)
-	if err != nil {
-		var responseText string
-		if stringErr := json.Unmarshal(*e.ToolResponse, &responseText); stringErr == nil {
-			return analyzeTextToolResponse(responseText)
-		}
-		slog.Error("failed to parse tool response", "error", err)
-		return false, true, ""
-	}
-
-	slog.Debug("analyzing tool response", "response_keys", getMapKeys(response))
-
-	// Check for interruption first (more specific than stderr)
-	if interrupted, ok := response["interrupted"].(bool); ok && interrupted {
-		slog.Debug("tool was interrupted")
-		return false, true, "tool-interrupted"
-	}
-
-	// Check for MCP-style isError field
-	if isError, ok := response["isError"].(bool); ok && isError {
-		slog.Debug("tool response has isError=true (MCP error)")
-		return false, true, ""
-	}
-
-	// Check for common error indicators
-	if errorValue, ok := response["error"]; ok && errorValue != nil {
-		if errorString, ok := errorValue.(string); !ok || errorString != "" {
-			slog.Debug("tool response has error field")
-			return false, true, ""
-		}
-	}
-
-	// Check stderr output after structured error fields.
-	if stderr, ok := response["stderr"].(string); ok && stderr != "" {
-		slog.Debug("tool response has stderr", "stderr_length", len(stderr))
-		return false, true, ""
-	}
-
-	// Check for tool-specific error patterns
-	if e.ToolName != nil {
-		switch normalizeToolName(*e.ToolName) {
-		case "Bash":
-			// Bash is success if no stderr and not interrupted
-			return true, false, ""
-
-		case "Read", "LS", "Glob":
-			// File tools are success if they have content
-			if content, ok := response["content"]; ok && content != nil {
-				return true, false, ""
-			}
-			return false, true, ""
-
-		case "Edit", "Write", "MultiEdit":
-			// Edit tools should indicate success/failure explicitly
-			if success, ok := response["success"].(bool); ok {
-				return success, !success, ""
-			}
-			// If no explicit success field, assume success
-			return true, false, ""
-
-		case "Grep":
-			// Grep is success if it returns results
-			if numLines, ok := response["numLines"].(float64); ok {
-				return numLines >= 0, false, "" // Even 0 results is success
-			}
-			return true, false, ""
-
-		default:
-			slog.Debug("unknown tool type for response analysis", "tool_name", *e.ToolName)
-		}
-	}
-
-	// Default: assume success if no clear error indicators
-	return true, false, ""
-}
-
-func analyzeTextToolResponse(responseText string) (success bool, hasError bool, errorType string) {
-	if exitCode, ok := parseExitCode(responseText); ok && exitCode != 0 {
-		return false, true, ""
-	}
-	return true, false, ""
-}
-
 func parseExitCode(responseText string) (int, bool) {
 	for _, line := range strings.Split(responseText, "\n") {
 		line = strings.TrimSpace(line)
@@ -735,6 +689,23 @@ func (e *HookEvent) extractFileType() string {
 	return ""
 }
 
+// ToolFilePath returns the file a tool call operates on, or "".
+func (e *HookEvent) ToolFilePath() string {
+	if e.ToolInput == nil {
+		return ""
+	}
+	var input map[string]interface{}
+	if err := json.Unmarshal(*e.ToolInput, &input); err != nil {
+		return ""
+	}
+	for _, field := range []string{"file_path", "path", "filename"} {
+		if path, ok := input[field].(string); ok && path != "" {
+			return path
+		}
+	}
+	return ""
+}
+
 // extractCommandInfo parses command information from Bash tool input
 func (e *HookEvent) extractCommandInfo() CommandInfo {
 	if e.ToolInput == nil {
@@ -753,41 +724,24 @@ func (e *HookEvent) extractCommandInfo() CommandInfo {
 		return CommandInfo{}
 	}
 
-	// Split command string into words
-	words := strings.Fields(strings.TrimSpace(command))
-	if len(words) == 0 {
-		return CommandInfo{}
-	}
-
-	var cmd, subCmd string
-
-	// Find first and second non-flag words
-	for _, word := range words {
-		if !strings.HasPrefix(word, "-") {
-			if cmd == "" {
-				cmd = word
-			} else if subCmd == "" && isValidSubcommand(cmd, word) {
-				subCmd = word
-				break
-			}
-		}
-	}
-
-	result := CommandInfo{
-		Command:       cmd,
-		Subcommand:    subCmd,
-		HasSubcommand: subCmd != "",
-	}
-
-	slog.Debug("extracted command info",
-		"original", command,
-		"command", result.Command,
-		"subcommand", result.Subcommand,
-		"has_subcommand", result.HasSubcommand)
-
+	result := parseShellCommand(command, e.CommandSelection)
+	logShellParse(command, e.CommandSelection, result)
 	return result
 }
 
+// knownSubcommands lists the subcommands of tools whose second word is
+// only a subcommand when it is one of these.
+var knownSubcommands = map[string][]string{
+	"git":     {"add", "commit", "push", "pull", "clone", "checkout", "branch", "merge", "rebase", "status", "log", "diff", "fetch", "remote", "tag", "stash", "reset", "revert"},
+	"npm":     {"install", "uninstall", "update", "start", "stop", "restart", "test", "run", "build", "publish", "pack", "init", "config", "cache", "audit", "fund", "outdated"},
+	"docker":  {"build", "run", "pull", "push", "start", "stop", "restart", "kill", "rm", "rmi", "ps", "images", "logs", "exec", "compose", "volume", "network"},
+	"cargo":   {"build", "run", "test", "doc", "new", "init", "add", "install", "update", "search", "publish", "bench", "clean", "check", "fmt", "clippy"},
+	"go":      {"build", "run", "test", "install", "get", "mod", "fmt", "vet", "generate", "clean", "env", "bug", "version", "doc"},
+	"pip":     {"install", "uninstall", "list", "show", "freeze", "search", "download", "wheel", "hash", "completion", "debug", "help"},
+	"yarn":    {"add", "install", "remove", "upgrade", "start", "build", "test", "run", "init", "cache", "config", "info", "why"},
+	"kubectl": {"get", "describe", "create", "apply", "delete", "patch", "replace", "expose", "scale", "autoscale", "rollout", "logs", "exec", "port-forward", "proxy", "cp", "auth", "config"},
+}
+
 // isValidSubcommand determines if a word is likely a subcommand rather than an argument
 func isValidSubcommand(command, word string) bool {
 	// Paths and file names are not subcommands
@@ -800,18 +754,6 @@ func isValidSubcommand(command, word string) bool {
 		return false
 	}
 
-	// Known command patterns that have subcommands
-	knownSubcommands := map[string][]string{
-		"git":     {"add", "commit", "push", "pull", "clone", "checkout", "branch", "merge", "rebase", "status", "log", "diff", "fetch", "remote", "tag", "stash", "reset", "revert"},
-		"npm":     {"install", "uninstall", "update", "start", "stop", "restart", "test", "run", "build", "publish", "pack", "init", "config", "cache", "audit", "fund", "outdated"},
-		"docker":  {"build", "run", "pull", "push", "start", "stop", "restart", "kill", "rm", "rmi", "ps", "images", "logs", "exec", "compose", "volume", "network"},
-		"cargo":   {"build", "run", "test", "doc", "new", "init", "add", "install", "update", "search", "publish", "bench", "clean", "check", "fmt", "clippy"},
-		"go":      {"build", "run", "test", "install", "get", "mod", "fmt", "vet", "generate", "clean", "env", "bug", "version", "doc"},
-		"pip":     {"install", "uninstall", "list", "show", "freeze", "search", "download", "wheel", "hash", "completion", "debug", "help"},
-		"yarn":    {"add", "install", "remove", "upgrade", "start", "build", "test", "run", "init", "cache", "config", "info", "why"},
-		"kubectl": {"get", "describe", "create", "apply", "delete", "patch", "replace", "expose", "scale", "autoscale", "rollout", "logs", "exec", "port-forward", "proxy", "cp", "auth", "config"},
-	}
-
 	if subcommands, exists := knownSubcommands[command]; exists {
 		for _, subCmd := range subcommands {
 			if word == subCmd {
@@ -872,6 +814,19 @@ var (
 	idleKeywords       = []string{"idle", "been idle", "idle for"}
 )
 
+// NeedsAttention reports whether the event leaves the agent waiting on
+// the user: a permission request, or a permission or idle notification.
+func (e *HookEvent) NeedsAttention() bool {
+	switch e.EventName {
+	case "PermissionRequest":
+		return true
+	case "Notification":
+		kind := e.detectNotificationType()
+		return kind == "notification-permission" || kind == "notification-idle"
+	}
+	return false
+}
+
 // detectNotificationType analyzes notification message content to generate specific sound hints
 func (e *HookEvent) detectNotificationType() string {
 	if e.Message == nil {
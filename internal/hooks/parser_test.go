@@ -1097,7 +1097,7 @@ func TestPostToolUseSuffixesUnchanged(t *testing.T) {
 		{
 			name:         "Git commit error should use -error suffix",
 			command:      "git commit -m 'test'",
-			stderr:       "nothing to commit",
+			stderr:       "error: pathspec 'x' did not match any file(s) known to git",
 			expectedHint: "git-commit-error",
 			description:  "PostToolUse error events should continue using -error suffix",
 		},
@@ -1746,3 +1746,25 @@ func TestGetContextCurrentLifecycleEvents(t *testing.T) {
 		})
 	}
 }
+
+func TestNeedsAttention(t *testing.T) {
+	message := func(s string) *string { return &s }
+	tests := []struct {
+		name  string
+		event HookEvent
+		want  bool
+	}{
+		{"permission request", HookEvent{EventName: "PermissionRequest"}, true},
+		{"permission notification", HookEvent{EventName: "Notification", Message: message("Claude needs your permission to use Bash")}, true},
+		{"idle notification", HookEvent{EventName: "Notification", Message: message("Claude is waiting for your input (idle for 60s)")}, true},
+		{"other notification", HookEvent{EventName: "Notification", Message: message("Build finished")}, false},
+		{"tool use", HookEvent{EventName: "PostToolUse"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.event.NeedsAttention(); got != tt.want {
+				t.Errorf("NeedsAttention() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,684 @@
+package hooks
+
+import (
+	"log/slog"
+	"path"
+	"strings"
+)
+
+// Command selection modes decide which command of a compound Bash command
+// line ("cd app && go test ./...") names the sound hint.
+const (
+	CommandSelectionFirst        = "first"         // first command in the line
+	CommandSelectionLast         = "last"          // last command in the line
+	CommandSelectionMostSpecific = "most-specific" // best-known tool, preferring ones with a subcommand (default)
+)
+
+// GetCommandSelectionModes returns the accepted command selection modes.
+func GetCommandSelectionModes() []string {
+	return []string{CommandSelectionFirst, CommandSelectionLast, CommandSelectionMostSpecific}
+}
+
+// IsValidCommandSelection reports whether mode is an accepted command
+// selection mode. The empty string is valid and means most-specific.
+func IsValidCommandSelection(mode string) bool {
+	if mode == "" {
+		return true
+	}
+	for _, m := range GetCommandSelectionModes() {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// maxShellNesting bounds recursion into `bash -c "..."` and `eval` bodies.
+const maxShellNesting = 3
+
+// shellToken is one word or control operator of a command line. Words have
+// their quoting removed; command substitutions are kept verbatim.
+type shellToken struct {
+	text  string
+	op    bool // control operator: && || | |& ; ;; & ( ) or a newline (as ";")
+	redir bool // redirection operator; target is the next word unless complete
+	done  bool // redirection already carries its target (2>&1, >&-)
+}
+
+// tokenizeShell splits a POSIX shell command line into words and operators.
+// It handles single and double quotes, backslash escapes, comments, line
+// continuations, $(...) and backtick substitutions (kept as part of the
+// word), redirections, and here-documents (whose bodies are skipped). It is
+// a best-effort lexer for picking sound hints, not a validator: unbalanced
+// quotes simply run to the end of the input.
+func tokenizeShell(s string) []shellToken {
+	var tokens []shellToken
+	var word strings.Builder
+	inWord := false
+	var heredocs []heredoc
+	expectDelim := false
+	stripTabs := false
+
+	flush := func() {
+		if !inWord {
+			return
+		}
+		text := word.String()
+		word.Reset()
+		inWord = false
+		if expectDelim {
+			heredocs = append(heredocs, heredoc{delim: text, stripTabs: stripTabs})
+			expectDelim = false
+			return
+		}
+		tokens = append(tokens, shellToken{text: text})
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r':
+			flush()
+
+		case c == '\n':
+			flush()
+			tokens = append(tokens, shellToken{text: ";", op: true})
+			for _, h := range heredocs {
+				i = skipHeredoc(s, i+1, h) - 1
+			}
+			heredocs = nil
+
+		case c == '#' && !inWord:
+			for i+1 < len(s) && s[i+1] != '\n' {
+				i++
+			}
+
+		case c == '\\':
+			if i+1 < len(s) {
+				i++
+				if s[i] != '\n' {
+					word.WriteByte(s[i])
+					inWord = true
+				}
+			}
+
+		case c == '\'':
+			inWord = true
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				word.WriteString(s[i+1:])
+				i = len(s)
+			} else {
+				word.WriteString(s[i+1 : i+1+end])
+				i += end + 1
+			}
+
+		case c == '"':
+			inWord = true
+			i = readDoubleQuoted(s, i+1, &word)
+
+		case c == '$' && i+1 < len(s) && s[i+1] == '(':
+			inWord = true
+			end := matchingParen(s, i+1)
+			word.WriteString(s[i:end])
+			i = end - 1
+
+		case c == '`':
+			inWord = true
+			end := strings.IndexByte(s[i+1:], '`')
+			if end < 0 {
+				word.WriteString(s[i:])
+				i = len(s)
+			} else {
+				word.WriteString(s[i : i+end+2])
+				i += end + 1
+			}
+
+		case c == '<' || c == '>' || (c == '&' && i+1 < len(s) && s[i+1] == '>'):
+			// A word made only of digits directly before the operator is
+			// its file descriptor (2>&1), not an argument.
+			if inWord && isAllDigits(word.String()) {
+				word.Reset()
+				inWord = false
+			}
+			flush()
+			op, n := readRedirection(s[i:])
+			i += n - 1
+			tok := shellToken{text: op, redir: true}
+			if strings.HasSuffix(op, "&") {
+				// >&2, <&0, >&- duplicate or close a descriptor inline.
+				j := i + 1
+				for j < len(s) && (isDigit(s[j]) || s[j] == '-') {
+					j++
+				}
+				if j > i+1 {
+					tok.text += s[i+1 : j]
+					tok.done = true
+					i = j - 1
+				}
+			}
+			if op == "<<" || op == "<<-" {
+				expectDelim = true
+				stripTabs = op == "<<-"
+				tok.done = true
+			}
+			tokens = append(tokens, tok)
+
+		case c == '|' || c == '&' || c == ';' || c == '(' || c == ')':
+			flush()
+			op := string(c)
+			if i+1 < len(s) {
+				switch two := s[i : i+2]; two {
+				case "&&", "||", "|&", ";;":
+					op = two
+					i++
+				}
+			}
+			tokens = append(tokens, shellToken{text: op, op: true})
+
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	flush()
+	return tokens
+}
+
+type heredoc struct {
+	delim     string
+	stripTabs bool
+}
+
+// skipHeredoc returns the index just past the line that terminates h,
+// scanning from start (the beginning of the here-document body).
+func skipHeredoc(s string, start int, h heredoc) int {
+	i := start
+	for i < len(s) {
+		end := strings.IndexByte(s[i:], '\n')
+		line := s[i:]
+		next := len(s)
+		if end >= 0 {
+			line = s[i : i+end]
+			next = i + end + 1
+		}
+		line = strings.TrimSuffix(line, "\r")
+		if h.stripTabs {
+			line = strings.TrimLeft(line, "\t")
+		}
+		if line == h.delim {
+			return next
+		}
+		i = next
+	}
+	return len(s)
+}
+
+// readDoubleQuoted appends the body of a double-quoted string starting at
+// s[start] to word and returns the index of the closing quote.
+func readDoubleQuoted(s string, start int, word *strings.Builder) int {
+	for i := start; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"':
+			return i
+		case c == '\\' && i+1 < len(s):
+			switch s[i+1] {
+			case '"', '\\', '$', '`':
+				word.WriteByte(s[i+1])
+				i++
+			case '\n':
+				i++
+			default:
+				word.WriteByte(c)
+			}
+		case c == '$' && i+1 < len(s) && s[i+1] == '(':
+			end := matchingParen(s, i+1)
+			word.WriteString(s[i:end])
+			i = end - 1
+		default:
+			word.WriteByte(c)
+		}
+	}
+	return len(s)
+}
+
+// matchingParen returns the index just past the ')' matching the '(' at
+// s[open], honoring quotes, or len(s) when it is unbalanced.
+func matchingParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		case '\\':
+			i++
+		case '\'':
+			if end := strings.IndexByte(s[i+1:], '\''); end >= 0 {
+				i += end + 1
+			}
+		case '"':
+			var discard strings.Builder
+			i = readDoubleQuoted(s, i+1, &discard)
+		}
+	}
+	return len(s)
+}
+
+// readRedirection returns the redirection operator at the start of s and
+// its length.
+func readRedirection(s string) (string, int) {
+	for _, op := range []string{"&>>", "<<<", "<<-", "&>", ">>", ">&", ">|", "<<", "<&", "<>", ">", "<"} {
+		if strings.HasPrefix(s, op) {
+			return op, len(op)
+		}
+	}
+	return s[:1], 1
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isAllDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// shellPipeline is one pipeline of a command list; each stage is the
+// words of one simple command with redirections removed.
+type shellPipeline [][]string
+
+// splitShellPipelines groups tokens into pipelines separated by list
+// operators (&&, ||, ;, &, newline) and stages separated by | or |&.
+// Subshell parentheses only separate; their contents are flattened into
+// the surrounding list.
+func splitShellPipelines(tokens []shellToken) []shellPipeline {
+	var pipelines []shellPipeline
+	var current shellPipeline
+	var stage []string
+
+	endStage := func() {
+		if len(stage) > 0 {
+			current = append(current, stage)
+			stage = nil
+		}
+	}
+	endPipeline := func() {
+		endStage()
+		if len(current) > 0 {
+			pipelines = append(pipelines, current)
+			current = nil
+		}
+	}
+
+	skipTarget := false
+	for _, tok := range tokens {
+		switch {
+		case tok.op && (tok.text == "|" || tok.text == "|&"):
+			endStage()
+		case tok.op:
+			endPipeline()
+		case tok.redir:
+			skipTarget = !tok.done
+		case skipTarget:
+			skipTarget = false
+		default:
+			stage = append(stage, tok.text)
+		}
+	}
+	endPipeline()
+	return pipelines
+}
+
+// parseShellCommand returns the significant command of a shell command
+// line according to mode.
+func parseShellCommand(command, mode string) CommandInfo {
+	return parseShellCommandDepth(command, mode, 0)
+}
+
+func parseShellCommandDepth(command, mode string, depth int) CommandInfo {
+	pipelines := splitShellPipelines(tokenizeShell(command))
+
+	var candidates []CommandInfo // one per pipeline, in order
+	for _, p := range pipelines {
+		var best CommandInfo
+		bestScore := -1
+		for _, words := range p {
+			info := commandInfoFromWords(words, mode, depth)
+			if info.Command == "" {
+				continue
+			}
+			// Within a pipeline the first stage is the producer; later
+			// stages are usually filters (| tee, | grep), so only a
+			// strictly better score displaces it.
+			if s := commandScore(info); s > bestScore {
+				best, bestScore = info, s
+			}
+			if mode == CommandSelectionFirst {
+				break
+			}
+		}
+		if best.Command != "" {
+			candidates = append(candidates, best)
+		}
+	}
+	if len(candidates) == 0 {
+		return CommandInfo{}
+	}
+
+	switch mode {
+	case CommandSelectionFirst:
+		return candidates[0]
+	case CommandSelectionLast:
+		return candidates[len(candidates)-1]
+	default:
+		// Across a list the action usually comes last (cd dir && make),
+		// so ties go to the later command.
+		best := candidates[0]
+		for _, c := range candidates[1:] {
+			if commandScore(c) >= commandScore(best) {
+				best = c
+			}
+		}
+		return best
+	}
+}
+
+// commandScore ranks how well a command identifies what the agent is
+// doing: navigation and shell plumbing lowest, then arbitrary commands,
+// then known developer tools, then known tools with a subcommand.
+func commandScore(info CommandInfo) int {
+	if _, noise := noiseCommands[info.Command]; noise {
+		return 0
+	}
+	_, known := knownSubcommands[info.Command]
+	if !known {
+		_, known = knownTools[info.Command]
+	}
+	switch {
+	case known && info.HasSubcommand:
+		return 3
+	case known:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// noiseCommands rarely say anything about the task at hand; most-specific
+// selection only picks them when nothing else is on the line.
+var noiseCommands = map[string]struct{}{
+	"cd": {}, "pushd": {}, "popd": {}, "export": {}, "set": {}, "unset": {},
+	"source": {}, ".": {}, "echo": {}, "printf": {}, "true": {}, "false": {},
+	":": {}, "test": {}, "[": {}, "[[": {}, "sleep": {}, "exit": {}, "return": {},
+	"local": {}, "declare": {}, "alias": {}, "shift": {}, "read": {}, "wait": {},
+}
+
+// knownTools are developer tools without a subcommand table that still
+// make a better sound hint than generic shell utilities.
+var knownTools = map[string]struct{}{
+	"make": {}, "cmake": {}, "ninja": {}, "pytest": {}, "jest": {}, "vitest": {},
+	"mocha": {}, "tsc": {}, "eslint": {}, "prettier": {}, "ruff": {}, "mypy": {},
+	"black": {}, "python": {}, "python3": {}, "node": {}, "deno": {}, "bun": {},
+	"pnpm": {}, "rustc": {}, "gcc": {}, "clang": {}, "mvn": {}, "gradle": {},
+	"gradlew": {}, "terraform": {}, "helm": {}, "gh": {}, "rg": {}, "golangci-lint": {},
+}
+
+// reservedWords that can precede a command without being one.
+var reservedWords = map[string]struct{}{
+	"if": {}, "then": {}, "else": {}, "elif": {}, "do": {}, "while": {}, "until": {},
+	"!": {}, "{": {}, "fi": {}, "done": {}, "esac": {}, "}": {},
+}
+
+// compoundHeads start shell constructs whose first "simple command" is not
+// a command at all (for x in a b; case $x in).
+var compoundHeads = map[string]struct{}{
+	"for": {}, "case": {}, "select": {}, "function": {},
+}
+
+// runWrappers are "tool verb" pairs that run another command, mapped to
+// the flags that consume a value.
+var runWrappers = map[string]struct {
+	verbs     []string
+	argFlags  []string
+	allowBare bool // the wrapper runs a command without a verb (uvx jest)
+}{
+	"uv":     {verbs: []string{"run"}, argFlags: []string{"--with", "--python", "-p", "--package", "--project", "--directory", "--env-file", "--extra", "--group", "--index", "--from"}},
+	"poetry": {verbs: []string{"run"}},
+	"pipenv": {verbs: []string{"run"}},
+	"pdm":    {verbs: []string{"run"}},
+	"hatch":  {verbs: []string{"run"}},
+	"rye":    {verbs: []string{"run"}},
+	"bundle": {verbs: []string{"exec"}},
+	"pnpm":   {verbs: []string{"exec", "dlx"}},
+	"yarn":   {verbs: []string{"dlx", "exec"}},
+	"npm":    {verbs: []string{"exec"}, argFlags: []string{"-p", "--package", "-c", "--call"}},
+	"npx":    {allowBare: true, argFlags: []string{"-p", "--package", "-c", "--call"}},
+	"bunx":   {allowBare: true, argFlags: []string{"-p", "--package"}},
+	"pnpx":   {allowBare: true},
+	"uvx":    {allowBare: true, argFlags: []string{"--with", "--python", "-p", "--from", "--index"}},
+}
+
+// prefixWrappers run the rest of their arguments as a command, mapped to
+// the flags that consume a value. Positional counts how many non-flag
+// arguments come before the command (timeout's DURATION).
+var prefixWrappers = map[string]struct {
+	argFlags   []string
+	positional int
+}{
+	"sudo":    {argFlags: []string{"-u", "-g", "-C", "-D", "-h", "-p", "-r", "-t", "-U", "-T"}},
+	"doas":    {argFlags: []string{"-u", "-C"}},
+	"env":     {argFlags: []string{"-u", "-C", "-S"}},
+	"time":    {argFlags: []string{"-f", "-o"}},
+	"nice":    {argFlags: []string{"-n"}},
+	"ionice":  {argFlags: []string{"-c", "-n", "-p"}},
+	"nohup":   {},
+	"exec":    {argFlags: []string{"-a"}},
+	"command": {},
+	"builtin": {},
+	"stdbuf":  {argFlags: []string{"-i", "-o", "-e"}},
+	"timeout": {argFlags: []string{"-s", "-k", "--signal", "--kill-after"}, positional: 1},
+	"xargs":   {argFlags: []string{"-I", "-n", "-P", "-d", "-E", "-L", "-s", "-a"}},
+}
+
+// shellInterpreters run their -c argument as a nested command line.
+var shellInterpreters = map[string]struct{}{
+	"bash": {}, "sh": {}, "zsh": {}, "dash": {}, "ksh": {},
+}
+
+// commandInfoFromWords finds the command and subcommand of one simple
+// command, looking through environment assignments, wrappers like sudo
+// and `uv run`, and `bash -c` bodies.
+func commandInfoFromWords(words []string, mode string, depth int) CommandInfo {
+	i := 0
+	for i < len(words) {
+		w := words[i]
+		if _, ok := reservedWords[w]; ok {
+			i++
+			continue
+		}
+		if _, ok := compoundHeads[w]; ok {
+			return CommandInfo{}
+		}
+		if isAssignment(w) {
+			i++
+			continue
+		}
+
+		name := commandName(w)
+		if wrapper, ok := prefixWrappers[name]; ok && i+1 < len(words) {
+			i = skipFlags(words, i+1, wrapper.argFlags)
+			for n := 0; n < wrapper.positional && i < len(words); n++ {
+				i++
+			}
+			continue
+		}
+		if wrapper, ok := runWrappers[name]; ok && i+1 < len(words) {
+			j := skipFlags(words, i+1, wrapper.argFlags)
+			if j < len(words) && containsString(wrapper.verbs, words[j]) {
+				i = skipFlags(words, j+1, wrapper.argFlags)
+				continue
+			}
+			if wrapper.allowBare {
+				i = j
+				if i < len(words) {
+					words = append([]string(nil), words...)
+					words[i] = stripPackageVersion(words[i])
+				}
+				continue
+			}
+		}
+		if _, ok := shellInterpreters[name]; ok && depth < maxShellNesting {
+			if script, found := interpreterScript(words[i+1:]); found {
+				return parseShellCommandDepth(script, mode, depth+1)
+			}
+		}
+		if name == "eval" && i+1 < len(words) && depth < maxShellNesting {
+			return parseShellCommandDepth(strings.Join(words[i+1:], " "), mode, depth+1)
+		}
+		break
+	}
+
+	// Leading flags are not commands ("--verbose git status").
+	for i < len(words) && strings.HasPrefix(words[i], "-") {
+		i++
+	}
+	if i >= len(words) {
+		return CommandInfo{}
+	}
+
+	cmd := commandName(words[i])
+	var subCmd string
+	for _, word := range words[i+1:] {
+		if !strings.HasPrefix(word, "-") && isValidSubcommand(cmd, word) {
+			subCmd = word
+			break
+		}
+	}
+
+	return CommandInfo{
+		Command:       cmd,
+		Subcommand:    subCmd,
+		HasSubcommand: subCmd != "",
+	}
+}
+
+// skipFlags returns the index of the first word at or after i that is not
+// a flag or an environment assignment, stepping over the value of any flag
+// in argFlags and stopping after a "--" terminator.
+func skipFlags(words []string, i int, argFlags []string) int {
+	for i < len(words) {
+		w := words[i]
+		switch {
+		case w == "--":
+			return i + 1
+		case strings.HasPrefix(w, "-") && len(w) > 1:
+			if containsString(argFlags, w) {
+				i++
+			}
+			i++
+		case isAssignment(w):
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// interpreterScript returns the -c script of a shell interpreter's
+// arguments (bash -c 'git push', sh -ec "make"): the first operand after
+// the options, once -c is among them. The values of -o/-O/+o/+O and of
+// --rcfile/--init-file are skipped (bash -o pipefail -c 'make').
+func interpreterScript(args []string) (string, bool) {
+	script := false
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "--":
+			if script && i+1 < len(args) {
+				return args[i+1], true
+			}
+			return "", false
+		case a == "--rcfile" || a == "--init-file":
+			i++
+		case strings.HasPrefix(a, "--"):
+		case len(a) > 1 && (a[0] == '-' || a[0] == '+'):
+			if a[0] == '-' && strings.Contains(a, "c") {
+				script = true
+			}
+			if strings.ContainsAny(a[1:], "oO") {
+				i++
+			}
+		case script:
+			return a, true
+		default:
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// isAssignment reports whether w is a NAME=value environment assignment.
+func isAssignment(w string) bool {
+	eq := strings.IndexByte(w, '=')
+	if eq <= 0 {
+		return false
+	}
+	for i := 0; i < eq; i++ {
+		c := w[i]
+		if !(c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (i > 0 && isDigit(c))) {
+			return false
+		}
+	}
+	return true
+}
+
+// commandName strips the directory from a command path (/usr/bin/git,
+// ./gradlew) so hints key on the tool rather than where it lives.
+func commandName(w string) string {
+	if strings.Contains(w, "/") && !strings.HasSuffix(w, "/") {
+		return path.Base(w)
+	}
+	return w
+}
+
+// stripPackageVersion turns an npx-style package spec into its name
+// (jest@29 -> jest, @scope/pkg@1 -> @scope/pkg).
+func stripPackageVersion(spec string) string {
+	if at := strings.LastIndexByte(spec, '@'); at > 0 {
+		return spec[:at]
+	}
+	return spec
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// logShellParse records how a command line was interpreted.
+func logShellParse(command, mode string, info CommandInfo) {
+	slog.Debug("extracted command info",
+		"original", command,
+		"selection", mode,
+		"command", info.Command,
+		"subcommand", info.Subcommand,
+		"has_subcommand", info.HasSubcommand)
+}
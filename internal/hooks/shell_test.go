@@ -0,0 +1,118 @@
+package hooks
+
+import (
+	"testing"
+)
+
+func TestParseShellCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		wantCmd string
+		wantSub string
+	}{
+		{"simple", "git commit -m 'fix bug'", "git", "commit"},
+		{"cd then go test", "cd foo && go test ./...", "go", "test"},
+		{"env assignment", "FOO=1 npm test", "npm", "test"},
+		{"several assignments", "A=1 B='x y' cargo build", "cargo", "build"},
+		{"sudo", "sudo docker build .", "docker", "build"},
+		{"sudo with user", "sudo -u deploy git pull", "git", "pull"},
+		{"time", "time make", "make", ""},
+		{"nice", "nice -n 10 cargo test", "cargo", "test"},
+		{"env wrapper", "env -i PATH=/bin go vet ./...", "go", "vet"},
+		{"timeout duration", "timeout 30s go test ./...", "go", "test"},
+		{"npx", "npx jest --watch", "jest", ""},
+		{"npx versioned", "npx -y prettier@3 --write .", "prettier", ""},
+		{"uv run", "uv run pytest -x", "pytest", ""},
+		{"uv run with flag value", "uv run --with rich python main.py", "python", ""},
+		{"poetry run", "poetry run pytest", "pytest", ""},
+		{"bash -c", `bash -c "git push origin main"`, "git", "push"},
+		{"sh -ec nested list", `sh -ec 'cd app && cargo test'`, "cargo", "test"},
+		{"bash -o before -c", `bash -o pipefail -c "npm test"`, "npm", "test"},
+		{"sh -eo before -c", `sh -eo pipefail -c 'cargo build'`, "cargo", "build"},
+		{"bash +O before -c", `bash +O extglob -c 'make build'`, "make", "build"},
+		{"bash -c after -o", `bash -c -o pipefail 'go test ./...'`, "go", "test"},
+		{"bash --rcfile before -c", `bash --rcfile ~/.bashrc -c "git status"`, "git", "status"},
+		{"bash --init-file before -c", `bash --init-file init.sh -c 'npm install'`, "npm", "install"},
+		{"bash -- after -c", `bash -c -- "git push"`, "git", "push"},
+		{"eval", `eval "npm install"`, "npm", "install"},
+		{"pipeline keeps producer", "go test ./... 2>&1 | tee out.txt", "go", "test"},
+		{"pipeline of unknowns keeps first", "cat log.txt | grep error", "cat", ""},
+		{"semicolon list", "mkdir -p build; cd build; cmake ..", "cmake", ""},
+		{"noise only", "cd /tmp", "cd", ""},
+		{"ties go to last", "npm install && npm test", "npm", "test"},
+		{"redirect target is not a command", "echo hi > out.txt && git add out.txt", "git", "add"},
+		{"path command", "/usr/bin/git status", "git", "status"},
+		{"relative path command", "./gradlew build", "gradlew", "build"},
+		{"leading flag", "--verbose git status", "git", "status"},
+		{"subshell", "(cd web && yarn build)", "yarn", "build"},
+		{"heredoc body skipped", "cat <<'EOF' > notes.md\nrm -rf /\nEOF\ngit add notes.md", "git", "add"},
+		{"comment", "# run tests\ngo test ./...", "go", "test"},
+		{"line continuation", "cargo \\\n  test", "cargo", "test"},
+		{"command substitution stays a word", `git commit -m "$(cat msg.txt)"`, "git", "commit"},
+		{"if compound", "if go build ./...; then echo ok; fi", "go", "build"},
+		{"for loop head ignored", "for f in *.go; do gofmt -l $f; done", "gofmt", ""},
+		{"empty", "", "", ""},
+		{"only assignments", "FOO=1", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseShellCommand(tt.command, CommandSelectionMostSpecific)
+			if got.Command != tt.wantCmd || got.Subcommand != tt.wantSub {
+				t.Errorf("parseShellCommand(%q) = %q %q, want %q %q",
+					tt.command, got.Command, got.Subcommand, tt.wantCmd, tt.wantSub)
+			}
+			if got.HasSubcommand != (tt.wantSub != "") {
+				t.Errorf("HasSubcommand = %v for subcommand %q", got.HasSubcommand, got.Subcommand)
+			}
+		})
+	}
+}
+
+func TestParseShellCommand_SelectionModes(t *testing.T) {
+	const line = "cd app && go test ./... | tee log.txt && echo done"
+	tests := []struct {
+		mode    string
+		wantCmd string
+	}{
+		{CommandSelectionFirst, "cd"},
+		{CommandSelectionLast, "echo"},
+		{CommandSelectionMostSpecific, "go"},
+		{"", "go"},
+	}
+	for _, tt := range tests {
+		if got := parseShellCommand(line, tt.mode); got.Command != tt.wantCmd {
+			t.Errorf("mode %q: command = %q, want %q", tt.mode, got.Command, tt.wantCmd)
+		}
+	}
+}
+
+func TestTokenizeShell_Quoting(t *testing.T) {
+	tokens := tokenizeShell(`echo 'a b' "c \"d\"" e\ f 2>&1 >>log`)
+	var words []string
+	for _, tok := range tokens {
+		if !tok.op && !tok.redir {
+			words = append(words, tok.text)
+		}
+	}
+	want := []string{"echo", "a b", `c "d"`, "e f", "log"}
+	if len(words) != len(want) {
+		t.Fatalf("words = %q, want %q", words, want)
+	}
+	for i := range want {
+		if words[i] != want[i] {
+			t.Errorf("word %d = %q, want %q", i, words[i], want[i])
+		}
+	}
+}
+
+func TestIsValidCommandSelection(t *testing.T) {
+	for _, mode := range append(GetCommandSelectionModes(), "") {
+		if !IsValidCommandSelection(mode) {
+			t.Errorf("IsValidCommandSelection(%q) = false", mode)
+		}
+	}
+	if IsValidCommandSelection("random") {
+		t.Error("IsValidCommandSelection(\"random\") = true")
+	}
+}
@@ -0,0 +1,77 @@
+package hooks
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// MaxSpeechLength caps rendered speech text. Payload fields such as file
+// paths are agent-controlled; a notification should never read out a
+// paragraph.
+const MaxSpeechLength = 300
+
+// speechPlaceholderPattern matches {{name}} placeholders, tolerating
+// spaces inside the braces.
+var speechPlaceholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_]+)\s*\}\}`)
+
+// SpeechFields returns the values speech templates can reference, keyed by
+// placeholder name: agent, event, category, tool, command, subcommand,
+// outcome, hint, file, cwd, and cwd_basename.
+func (e *HookEvent) SpeechFields(ctx *EventContext) map[string]string {
+	fields := map[string]string{
+		"agent": "agent",
+		"event": e.EventName,
+		"tool":  e.NormalizedToolName(),
+		"cwd":   e.CWD,
+	}
+	if ctx != nil {
+		if ctx.Agent != "" {
+			fields["agent"] = ctx.Agent
+		}
+		fields["category"] = ctx.Category.String()
+		fields["outcome"] = ctx.Outcome
+		fields["hint"] = ctx.SoundHint
+	}
+	if e.CWD != "" {
+		fields["cwd_basename"] = filepath.Base(filepath.Clean(e.CWD))
+	}
+	info := e.CommandInfo()
+	fields["command"] = info.Command
+	fields["subcommand"] = info.Subcommand
+	if path := e.ToolFilePath(); path != "" {
+		fields["file"] = filepath.Base(path)
+	}
+	return fields
+}
+
+// RenderSpeech fills a speech template such as "{{agent}} finished in
+// {{cwd_basename}}" from fields. Unknown or empty placeholders render as
+// nothing, runs of whitespace collapse, and the result is capped at
+// MaxSpeechLength.
+func RenderSpeech(template string, fields map[string]string) string {
+	rendered := speechPlaceholderPattern.ReplaceAllStringFunc(template, func(match string) string {
+		name := speechPlaceholderPattern.FindStringSubmatch(match)[1]
+		return fields[strings.ToLower(name)]
+	})
+	rendered = strings.Join(strings.Fields(rendered), " ")
+	if len(rendered) > MaxSpeechLength {
+		rendered = strings.TrimSpace(truncateUTF8(rendered, MaxSpeechLength))
+	}
+	return rendered
+}
+
+// truncateUTF8 cuts s to at most n bytes without splitting a rune.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !isRuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,66 @@
+package hooks
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRenderSpeech(t *testing.T) {
+	fields := map[string]string{"agent": "codex", "cwd_basename": "claudio", "tool": "Bash"}
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"agent and cwd", "{{agent}} finished in {{cwd_basename}}", "codex finished in claudio"},
+		{"spaces inside braces", "{{ tool }} failed", "Bash failed"},
+		{"case-insensitive names", "{{TOOL}} failed", "Bash failed"},
+		{"unknown placeholder renders empty", "{{nope}} done", "done"},
+		{"whitespace collapses", "  {{tool}}\n\tdone  ", "Bash done"},
+		{"no placeholders", "build finished", "build finished"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderSpeech(tt.template, fields); got != tt.want {
+				t.Errorf("RenderSpeech(%q) = %q, want %q", tt.template, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderSpeech_CapsLength(t *testing.T) {
+	got := RenderSpeech("{{file}}", map[string]string{"file": strings.Repeat("é", MaxSpeechLength)})
+	if len(got) > MaxSpeechLength {
+		t.Fatalf("rendered %d bytes, want at most %d", len(got), MaxSpeechLength)
+	}
+	if !strings.HasPrefix(strings.Repeat("é", MaxSpeechLength), got) {
+		t.Errorf("truncation split a rune: %q", got[len(got)-4:])
+	}
+}
+
+func TestSpeechFields(t *testing.T) {
+	tool := "Edit"
+	input := json.RawMessage(`{"file_path":"/home/u/src/claudio/main.go","old_string":"a","new_string":"b"}`)
+	event := &HookEvent{EventName: "PostToolUse", CWD: "/home/u/src/claudio/", ToolName: &tool, ToolInput: &input}
+	ctx := event.GetContext()
+	ctx.Agent = "gemini"
+
+	fields := event.SpeechFields(ctx)
+	want := map[string]string{
+		"agent":        "gemini",
+		"event":        "PostToolUse",
+		"tool":         "Edit",
+		"cwd_basename": "claudio",
+		"file":         "main.go",
+	}
+	for key, value := range want {
+		if fields[key] != value {
+			t.Errorf("fields[%q] = %q, want %q", key, fields[key], value)
+		}
+	}
+
+	if got := (&HookEvent{EventName: "Stop"}).SpeechFields(nil)["agent"]; got != "agent" {
+		t.Errorf("agent without a name should read as %q, got %q", "agent", got)
+	}
+}
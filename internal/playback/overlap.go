@@ -0,0 +1,336 @@
+// Package playback arbitrates overlapping sounds across the independent
+// processes that play them. Every hook invocation runs in its own detached
+// worker (or in a goroutine of `claudio daemon`), so "is something already
+// playing for this session?" cannot be answered from memory. The answer
+// lives in advisory lock files under the XDG cache dir instead: the flock
+// pattern config.LockConfigDir already uses, with the same property that a
+// crashed holder releases its lock when the kernel closes its descriptor.
+package playback
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"claudio.click/internal/config"
+	"github.com/gofrs/flock"
+)
+
+// ErrDropped is returned by Acquire when the overlap policy decides the
+// new sound should not play at all. Callers treat it as a normal outcome,
+// not a failure.
+var ErrDropped = errors.New("playback dropped by overlap policy")
+
+// lockRetryDelay is the poll interval for blocking lock acquisition and for
+// interrupt-previous preemption checks. flock has no wakeup notification,
+// so both are polled; 25ms is well under the shortest UI sound.
+const lockRetryDelay = 25 * time.Millisecond
+
+// maxQueueWait bounds how long a queued sound waits for the one ahead of
+// it. A sound that has waited this long is stale; dropping it beats
+// playing a "tool finished" chime half a minute late.
+const maxQueueWait = 30 * time.Second
+
+// Coordinator enforces one overlap policy for every session.
+type Coordinator struct {
+	dir           string
+	policy        string
+	maxQueueDepth int
+}
+
+// NewCoordinator returns a Coordinator keeping its lock and state files in
+// dir. An empty or unknown policy behaves as mix; a non-positive
+// maxQueueDepth falls back to config.DefaultMaxQueueDepth.
+func NewCoordinator(dir, policy string, maxQueueDepth int) *Coordinator {
+	if maxQueueDepth <= 0 {
+		maxQueueDepth = config.DefaultMaxQueueDepth
+	}
+	return &Coordinator{dir: dir, policy: policy, maxQueueDepth: maxQueueDepth}
+}
+
+// Policy returns the overlap policy this coordinator enforces.
+func (c *Coordinator) Policy() string {
+	return c.policy
+}
+
+// Ticket is the right to play one sound. Play with Context() so an
+// interrupt-previous arrival can cut the sound short, and always Release.
+type Ticket struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	lock   *flock.Flock
+	done   chan struct{} // closed when the preemption watcher exits; nil without one
+}
+
+// Context is cancelled when the ticket is released or preempted.
+func (t *Ticket) Context() context.Context {
+	return t.ctx
+}
+
+// Release gives up the ticket. Safe to call more than once.
+func (t *Ticket) Release() {
+	t.cancel()
+	if t.done != nil {
+		<-t.done
+	}
+	if t.lock != nil {
+		if err := t.lock.Unlock(); err != nil {
+			slog.Warn("failed to release playback lock", "path", t.lock.Path(), "error", err)
+		}
+		t.lock = nil
+	}
+}
+
+// Acquire applies the overlap policy for sessionID. It returns ErrDropped
+// when the policy says not to play, and blocks under queue and
+// interrupt-previous until the session's previous sound has stopped.
+func (c *Coordinator) Acquire(ctx context.Context, sessionID string) (*Ticket, error) {
+	switch c.policy {
+	case config.OverlapPolicyQueue:
+		return c.acquireQueued(ctx, sessionID)
+	case config.OverlapPolicyInterruptPrevious:
+		return c.acquireInterrupting(ctx, sessionID)
+	case config.OverlapPolicyDropIfBusy:
+		return c.acquireIfIdle(ctx, sessionID)
+	default:
+		ticketCtx, cancel := context.WithCancel(ctx)
+		return &Ticket{ctx: ticketCtx, cancel: cancel}, nil
+	}
+}
+
+// sessionBase returns the path prefix for one session's lock files. The
+// session ID comes straight from the hook payload, so it is hashed rather
+// than trusted as a filename. Every caller is about to create such files,
+// so this is also where files of long-gone sessions are swept up.
+func (c *Coordinator) sessionBase(sessionID string) (string, error) {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create playback state directory: %w", err)
+	}
+	c.pruneIfDue()
+	sum := sha256.Sum256([]byte(sessionID))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:8])), nil
+}
+
+// acquireIfIdle implements drop-if-busy: one non-blocking attempt at the
+// session's play lock.
+func (c *Coordinator) acquireIfIdle(ctx context.Context, sessionID string) (*Ticket, error) {
+	base, err := c.sessionBase(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	lock := flock.New(base + ".play.lock")
+	ok, err := lock.TryLock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to try-lock %s: %w", lock.Path(), err)
+	}
+	if !ok {
+		slog.Debug("session busy; dropping sound", "session_id", sessionID)
+		return nil, ErrDropped
+	}
+	return newLockedTicket(ctx, lock), nil
+}
+
+// acquireQueued implements queue. Waiting sounds each hold one of
+// maxQueueDepth slot locks while they wait for the play lock, so the queue
+// depth is counted by the kernel and a crashed waiter frees its slot
+// automatically — there is no counter file to go stale.
+func (c *Coordinator) acquireQueued(ctx context.Context, sessionID string) (*Ticket, error) {
+	base, err := c.sessionBase(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	play := flock.New(base + ".play.lock")
+	if ok, err := play.TryLock(); err != nil {
+		return nil, fmt.Errorf("failed to try-lock %s: %w", play.Path(), err)
+	} else if ok {
+		return newLockedTicket(ctx, play), nil
+	}
+
+	var slot *flock.Flock
+	for i := 0; i < c.maxQueueDepth; i++ {
+		candidate := flock.New(base + ".slot" + strconv.Itoa(i) + ".lock")
+		ok, err := candidate.TryLock()
+		if err != nil {
+			return nil, fmt.Errorf("failed to try-lock %s: %w", candidate.Path(), err)
+		}
+		if ok {
+			markUsed(candidate)
+			slot = candidate
+			break
+		}
+	}
+	if slot == nil {
+		slog.Debug("playback queue full; dropping sound", "session_id", sessionID, "max_queue_depth", c.maxQueueDepth)
+		return nil, ErrDropped
+	}
+	defer func() {
+		if err := slot.Unlock(); err != nil {
+			slog.Warn("failed to release playback queue slot", "path", slot.Path(), "error", err)
+		}
+	}()
+
+	waitCtx, cancel := context.WithTimeout(ctx, maxQueueWait)
+	defer cancel()
+	ok, err := play.TryLockContext(waitCtx, lockRetryDelay)
+	if err != nil || !ok {
+		slog.Debug("gave up waiting in playback queue", "session_id", sessionID, "error", err)
+		return nil, ErrDropped
+	}
+	return newLockedTicket(ctx, play), nil
+}
+
+// acquireInterrupting implements interrupt-previous. Each arrival bumps a
+// per-session generation counter; the current player polls that counter
+// and cancels its own playback when it moves. An arrival that is itself
+// overtaken before it reaches the play lock drops out, so only the newest
+// sound of a burst plays.
+func (c *Coordinator) acquireInterrupting(ctx context.Context, sessionID string) (*Ticket, error) {
+	return c.acquireLatest(ctx, sessionID, "")
+}
+
+// AcquireAmbient returns the right to run sessionID's ambient loop,
+// whatever the overlap policy. Like interrupt-previous, starting a loop
+// stops the session's previous loop; the ticket's context is also
+// cancelled when StopAmbient is called for the session from any process.
+// Ambient loops keep their own lock files, so they never hold up the
+// session's other sounds.
+func (c *Coordinator) AcquireAmbient(ctx context.Context, sessionID string) (*Ticket, error) {
+	return c.acquireLatest(ctx, sessionID, ambientKind)
+}
+
+// StopAmbient stops sessionID's ambient loop, if one is running, by moving
+// its generation counter. A session that never started a loop is left
+// untouched.
+func (c *Coordinator) StopAmbient(sessionID string) error {
+	base, err := c.sessionBase(sessionID)
+	if err != nil {
+		return err
+	}
+	genPath := base + ambientKind + ".generation"
+	if _, err := os.Stat(genPath); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	_, err = bumpGeneration(base+ambientKind+".generation.lock", genPath)
+	return err
+}
+
+// ambientKind separates a session's ambient loop files from its one-shot
+// sound files.
+const ambientKind = ".ambient"
+
+// acquireLatest is the newest-wins protocol behind interrupt-previous and
+// ambient loops. kind namespaces the session's lock and generation files.
+func (c *Coordinator) acquireLatest(ctx context.Context, sessionID, kind string) (*Ticket, error) {
+	base, err := c.sessionBase(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	base += kind
+	genPath := base + ".generation"
+
+	myGen, err := bumpGeneration(base+".generation.lock", genPath)
+	if err != nil {
+		return nil, err
+	}
+
+	play := flock.New(base + ".play.lock")
+	waitCtx, cancel := context.WithTimeout(ctx, maxQueueWait)
+	defer cancel()
+	ok, err := play.TryLockContext(waitCtx, lockRetryDelay)
+	if err != nil || !ok {
+		slog.Debug("gave up waiting for interrupted sound to stop", "session_id", sessionID, "error", err)
+		return nil, ErrDropped
+	}
+
+	if readGeneration(genPath) != myGen {
+		_ = play.Unlock()
+		slog.Debug("superseded by a newer sound before playback", "session_id", sessionID)
+		return nil, ErrDropped
+	}
+
+	ticket := newLockedTicket(ctx, play)
+	ticket.done = make(chan struct{})
+	go func() {
+		defer close(ticket.done)
+		ticker := time.NewTicker(lockRetryDelay)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticket.ctx.Done():
+				return
+			case <-ticker.C:
+				if readGeneration(genPath) != myGen {
+					slog.Debug("interrupted by a newer sound", "session_id", sessionID)
+					ticket.cancel()
+					return
+				}
+			}
+		}
+	}()
+	return ticket, nil
+}
+
+func newLockedTicket(ctx context.Context, lock *flock.Flock) *Ticket {
+	markUsed(lock)
+	ticketCtx, cancel := context.WithCancel(ctx)
+	return &Ticket{ctx: ticketCtx, cancel: cancel, lock: lock}
+}
+
+// bumpGeneration increments the counter in genPath under lockPath and
+// returns the new value.
+func bumpGeneration(lockPath, genPath string) (uint64, error) {
+	lock := flock.New(lockPath)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	ok, err := lock.TryLockContext(ctx, lockRetryDelay)
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock %s: %w", lockPath, err)
+	}
+	if !ok {
+		return 0, fmt.Errorf("timed out locking %s", lockPath)
+	}
+	defer func() { _ = lock.Unlock() }()
+	markUsed(lock)
+
+	next := readGeneration(genPath) + 1
+
+	// Temp file + rename so the polling player never reads a truncated
+	// counter and mistakes it for a newer generation.
+	tmp, err := os.CreateTemp(filepath.Dir(genPath), ".generation-*.tmp")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create generation temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+	_, writeErr := tmp.WriteString(strconv.FormatUint(next, 10))
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		_ = os.Remove(tmpName)
+		return 0, fmt.Errorf("failed to write %s: %w", genPath, errors.Join(writeErr, closeErr))
+	}
+	if err := os.Rename(tmpName, genPath); err != nil {
+		_ = os.Remove(tmpName)
+		return 0, fmt.Errorf("failed to replace %s: %w", genPath, err)
+	}
+	return next, nil
+}
+
+// readGeneration returns the counter in genPath, or 0 if it is missing or
+// unreadable.
+func readGeneration(genPath string) uint64 {
+	data, err := os.ReadFile(genPath)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	return n
+}
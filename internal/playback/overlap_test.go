@@ -0,0 +1,215 @@
+package playback
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"claudio.click/internal/config"
+	"github.com/gofrs/flock"
+)
+
+// Every test acquires through fresh flock handles, which conflict with
+// each other even inside one process — exactly the daemon's situation,
+// and a faithful stand-in for separate worker processes.
+
+func TestAcquire_MixNeverBlocks(t *testing.T) {
+	c := NewCoordinator(t.TempDir(), config.OverlapPolicyMix, 0)
+	first, err := c.Acquire(context.Background(), "s")
+	if err != nil {
+		t.Fatalf("first Acquire: %v", err)
+	}
+	defer first.Release()
+	second, err := c.Acquire(context.Background(), "s")
+	if err != nil {
+		t.Fatalf("second Acquire under mix: %v", err)
+	}
+	second.Release()
+}
+
+func TestAcquire_DropIfBusy(t *testing.T) {
+	c := NewCoordinator(t.TempDir(), config.OverlapPolicyDropIfBusy, 0)
+	first, err := c.Acquire(context.Background(), "s")
+	if err != nil {
+		t.Fatalf("first Acquire: %v", err)
+	}
+
+	if _, err := c.Acquire(context.Background(), "s"); !errors.Is(err, ErrDropped) {
+		t.Fatalf("expected ErrDropped while busy, got %v", err)
+	}
+
+	other, err := c.Acquire(context.Background(), "other-session")
+	if err != nil {
+		t.Fatalf("a different session must not be affected: %v", err)
+	}
+	other.Release()
+
+	first.Release()
+	again, err := c.Acquire(context.Background(), "s")
+	if err != nil {
+		t.Fatalf("Acquire after release: %v", err)
+	}
+	again.Release()
+}
+
+func TestAcquire_QueueBoundsDepth(t *testing.T) {
+	c := NewCoordinator(t.TempDir(), config.OverlapPolicyQueue, 1)
+	first, err := c.Acquire(context.Background(), "s")
+	if err != nil {
+		t.Fatalf("first Acquire: %v", err)
+	}
+	defer first.Release()
+
+	// Stand in for another process already waiting in the only slot.
+	base, err := c.sessionBase("s")
+	if err != nil {
+		t.Fatalf("sessionBase: %v", err)
+	}
+	waiter := flock.New(base + ".slot0.lock")
+	if ok, err := waiter.TryLock(); err != nil || !ok {
+		t.Fatalf("occupy slot: ok=%v err=%v", ok, err)
+	}
+	defer waiter.Unlock()
+
+	if _, err := c.Acquire(context.Background(), "s"); !errors.Is(err, ErrDropped) {
+		t.Fatalf("expected queue-full drop, got %v", err)
+	}
+}
+
+func TestAcquire_QueueWaitsForPrevious(t *testing.T) {
+	c := NewCoordinator(t.TempDir(), config.OverlapPolicyQueue, 1)
+	first, err := c.Acquire(context.Background(), "s")
+	if err != nil {
+		t.Fatalf("first Acquire: %v", err)
+	}
+
+	queued := make(chan error, 1)
+	go func() {
+		ticket, err := c.Acquire(context.Background(), "s")
+		if err == nil {
+			ticket.Release()
+		}
+		queued <- err
+	}()
+
+	select {
+	case err := <-queued:
+		t.Fatalf("queued sound played before the first finished (err=%v)", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	first.Release()
+	select {
+	case err := <-queued:
+		if err != nil {
+			t.Fatalf("queued Acquire failed: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("queued sound never acquired after release")
+	}
+}
+
+func TestAcquire_InterruptPreviousCancelsPlayer(t *testing.T) {
+	c := NewCoordinator(t.TempDir(), config.OverlapPolicyInterruptPrevious, 0)
+	first, err := c.Acquire(context.Background(), "s")
+	if err != nil {
+		t.Fatalf("first Acquire: %v", err)
+	}
+
+	// Simulate a backend that plays until its context is cancelled, then
+	// releases — as playSoundWithBackend does.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-first.Context().Done()
+		first.Release()
+	}()
+
+	second, err := c.Acquire(context.Background(), "s")
+	if err != nil {
+		t.Fatalf("interrupting Acquire: %v", err)
+	}
+	defer second.Release()
+	wg.Wait()
+
+	if second.Context().Err() != nil {
+		t.Error("the newest sound must not be cancelled")
+	}
+}
+
+func TestAmbient_StopAmbientCancelsOnlyThatSession(t *testing.T) {
+	dir := t.TempDir()
+	// The ambient handle ignores the overlap policy.
+	c := NewCoordinator(dir, config.OverlapPolicyDropIfBusy, 0)
+
+	if err := c.StopAmbient("a"); err != nil {
+		t.Fatalf("StopAmbient with no loop: %v", err)
+	}
+
+	loopA, err := c.AcquireAmbient(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("AcquireAmbient a: %v", err)
+	}
+	defer loopA.Release()
+	loopB, err := c.AcquireAmbient(context.Background(), "b")
+	if err != nil {
+		t.Fatalf("AcquireAmbient b: %v", err)
+	}
+	defer loopB.Release()
+
+	// A one-shot sound is not blocked by the session's ambient loop.
+	sound, err := c.Acquire(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("Acquire alongside ambient loop: %v", err)
+	}
+	sound.Release()
+
+	// Another coordinator stands in for the Stop hook's process.
+	if err := NewCoordinator(dir, config.OverlapPolicyMix, 0).StopAmbient("a"); err != nil {
+		t.Fatalf("StopAmbient: %v", err)
+	}
+	select {
+	case <-loopA.Context().Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("session a's loop was not stopped")
+	}
+	if loopB.Context().Err() != nil {
+		t.Error("stopping session a must not stop session b's loop")
+	}
+}
+
+func TestAmbient_NewLoopReplacesOld(t *testing.T) {
+	c := NewCoordinator(t.TempDir(), config.OverlapPolicyMix, 0)
+	old, err := c.AcquireAmbient(context.Background(), "s")
+	if err != nil {
+		t.Fatalf("AcquireAmbient: %v", err)
+	}
+
+	acquired := make(chan *Ticket, 1)
+	go func() {
+		next, err := c.AcquireAmbient(context.Background(), "s")
+		if err != nil {
+			t.Errorf("second AcquireAmbient: %v", err)
+		}
+		acquired <- next
+	}()
+
+	select {
+	case <-old.Context().Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("old loop was not told to stop")
+	}
+	old.Release()
+
+	select {
+	case next := <-acquired:
+		if next != nil {
+			next.Release()
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("new loop did not start after the old one stopped")
+	}
+}
@@ -0,0 +1,125 @@
+package playback
+
+import (
+	"encoding/hex"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// staleFileAge is how long a session's lock and generation files may sit
+// unused before a sweep removes them. Sessions that quiet for a day have
+// ended; one that resumes simply creates its files again.
+const staleFileAge = 24 * time.Hour
+
+// pruneInterval spaces out sweeps of the state directory, which every
+// process shares, so a burst of hooks does not list it on each sound.
+const pruneInterval = time.Hour
+
+// pruneMarker is the file whose modification time records the last sweep.
+const pruneMarker = ".pruned"
+
+// pruneIfDue sweeps stale session files when no process has done so for
+// pruneInterval. Failures only leave files for the next sweep.
+func (c *Coordinator) pruneIfDue() {
+	marker := filepath.Join(c.dir, pruneMarker)
+	if info, err := os.Stat(marker); err == nil && time.Since(info.ModTime()) < pruneInterval {
+		return
+	}
+	// Claim the sweep first so concurrent hooks do not all run one.
+	if err := touch(marker); err != nil {
+		slog.Debug("playback state not pruned", "error", err)
+		return
+	}
+	if removed := c.prune(staleFileAge); removed > 0 {
+		slog.Debug("pruned stale playback state", "dir", c.dir, "removed", removed)
+	}
+}
+
+// prune removes session files last used more than maxAge ago and returns
+// how many it removed. A lock file still held by a player or a waiting
+// sound is kept whatever its age.
+func (c *Coordinator) prune(maxAge time.Duration) int {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return 0
+	}
+	removed := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !isSessionFile(name) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < maxAge {
+			continue
+		}
+		path := filepath.Join(c.dir, name)
+		if strings.HasSuffix(name, ".lock") {
+			if removeUnheldLock(path) {
+				removed++
+			}
+			continue
+		}
+		if err := os.Remove(path); err == nil {
+			removed++
+		}
+	}
+	return removed
+}
+
+// isSessionFile reports whether name is one of the files sessionBase and
+// bumpGeneration create: a hashed session prefix with a suffix, or a
+// generation temp file a crashed writer left behind.
+func isSessionFile(name string) bool {
+	if strings.HasPrefix(name, ".generation-") && strings.HasSuffix(name, ".tmp") {
+		return true
+	}
+	const prefixLen = 16 // hex of sessionBase's 8 hash bytes
+	if len(name) <= prefixLen || name[prefixLen] != '.' {
+		return false
+	}
+	_, err := hex.DecodeString(name[:prefixLen])
+	return err == nil
+}
+
+// removeUnheldLock removes the lock file at path while holding it, so a
+// lock in use is never pulled out from under its holder.
+func removeUnheldLock(path string) bool {
+	lock := flock.New(path)
+	if ok, err := lock.TryLock(); err != nil || !ok {
+		return false
+	}
+	if err := os.Remove(path); err == nil {
+		_ = lock.Unlock()
+		return true
+	}
+	// Windows will not remove a file anyone has open, this lock included,
+	// so there the lock must go first; the removal still fails if another
+	// process opened the file in between.
+	_ = lock.Unlock()
+	return os.Remove(path) == nil
+}
+
+// markUsed refreshes a lock file's modification time, which opening it
+// for flock does not, so prune sees when it was last used.
+func markUsed(lock *flock.Flock) {
+	_ = touch(lock.Path())
+}
+
+// touch sets path's modification time to now, creating it if missing.
+func touch(path string) error {
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err == nil || !os.IsNotExist(err) {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,128 @@
+package playback
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"claudio.click/internal/config"
+)
+
+// ageFiles backdates every file in dir by age.
+func ageFiles(t *testing.T, dir string, age time.Duration) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-age)
+	for _, entry := range entries {
+		if err := os.Chtimes(filepath.Join(dir, entry.Name()), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestPrune_RemovesStaleSessionFiles(t *testing.T) {
+	dir := t.TempDir()
+	c := NewCoordinator(dir, config.OverlapPolicyDropIfBusy, 0)
+
+	done, err := c.Acquire(context.Background(), "done")
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	done.Release()
+	if err := c.StopAmbient("done"); err != nil {
+		t.Fatalf("StopAmbient: %v", err)
+	}
+	loop, err := c.AcquireAmbient(context.Background(), "done")
+	if err != nil {
+		t.Fatalf("AcquireAmbient: %v", err)
+	}
+	loop.Release()
+	held, err := c.Acquire(context.Background(), "held")
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	defer held.Release()
+	unrelated := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(unrelated, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ageFiles(t, dir, 2*staleFileAge)
+	c.prune(staleFileAge)
+
+	doneBase, _ := c.sessionBase("done")
+	heldBase, _ := c.sessionBase("held")
+	for _, gone := range []string{
+		doneBase + ".play.lock",
+		doneBase + ambientKind + ".play.lock",
+		doneBase + ambientKind + ".generation",
+		doneBase + ambientKind + ".generation.lock",
+	} {
+		if _, err := os.Stat(gone); !os.IsNotExist(err) {
+			t.Errorf("%s should be pruned, stat err = %v", filepath.Base(gone), err)
+		}
+	}
+	for _, kept := range []string{heldBase + ".play.lock", unrelated} {
+		if _, err := os.Stat(kept); err != nil {
+			t.Errorf("%s should be kept: %v", filepath.Base(kept), err)
+		}
+	}
+}
+
+func TestPrune_KeepsRecentFiles(t *testing.T) {
+	dir := t.TempDir()
+	c := NewCoordinator(dir, config.OverlapPolicyDropIfBusy, 0)
+	ticket, err := c.Acquire(context.Background(), "s")
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	ticket.Release()
+
+	// A lock file opened long ago but used just now is not stale.
+	ageFiles(t, dir, 2*staleFileAge)
+	again, err := c.Acquire(context.Background(), "s")
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	again.Release()
+	if removed := c.prune(staleFileAge); removed != 0 {
+		t.Errorf("prune removed %d files in use within staleFileAge", removed)
+	}
+}
+
+func TestPruneIfDue_SweepsOncePerInterval(t *testing.T) {
+	dir := t.TempDir()
+	c := NewCoordinator(dir, config.OverlapPolicyDropIfBusy, 0)
+	ticket, err := c.Acquire(context.Background(), "s")
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	ticket.Release()
+	base, _ := c.sessionBase("s")
+	lockPath := base + ".play.lock"
+
+	// The sweep above just ran, so a stale file waits for the next one.
+	old := time.Now().Add(-2 * staleFileAge)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+	c.pruneIfDue()
+	if _, err := os.Stat(lockPath); err != nil {
+		t.Fatalf("swept again within pruneInterval: %v", err)
+	}
+
+	marker := filepath.Join(dir, pruneMarker)
+	due := time.Now().Add(-2 * pruneInterval)
+	if err := os.Chtimes(marker, due, due); err != nil {
+		t.Fatal(err)
+	}
+	c.pruneIfDue()
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Errorf("stale lock file not swept once due, stat err = %v", err)
+	}
+}
@@ -0,0 +1,213 @@
+// Package ratelimit debounces and throttles hook sounds according to the
+// config's rate_limits rules. Every hook event is handled by a fresh,
+// short-lived process, so the limiter keeps its buckets in a small JSON
+// state file under the XDG cache dir and serializes read-modify-write
+// cycles with an flock, the same pattern config.LockConfigDir uses.
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"claudio.click/internal/config"
+	"github.com/gofrs/flock"
+)
+
+// stateVersion is bumped when the state file layout changes; a file with a
+// different version is discarded rather than misread.
+const stateVersion = 1
+
+// lockTimeout bounds how long Allow waits for another hook's update. The
+// critical section is one small file read and write, so contention past
+// this means something is wedged and the caller should just play.
+const lockTimeout = time.Second
+
+// minStaleAfter is the minimum age at which an idle bucket is pruned.
+const minStaleAfter = time.Hour
+
+type bucket struct {
+	LastSeen    int64 `json:"last_seen"`    // unix nanos of the last matching event
+	LastAllowed int64 `json:"last_allowed"` // unix nanos of the last event the limiter let through
+}
+
+type state struct {
+	Version int                `json:"version"`
+	Buckets map[string]*bucket `json:"buckets"`
+}
+
+// Limiter decides whether a hook event may produce a sound.
+type Limiter struct {
+	statePath string
+	rules     []config.RateLimitRule
+	now       func() time.Time
+}
+
+// Option configures a Limiter.
+type Option func(*Limiter)
+
+// WithClock replaces time.Now, for tests.
+func WithClock(now func() time.Time) Option {
+	return func(l *Limiter) {
+		l.now = now
+	}
+}
+
+// NewLimiter returns a Limiter enforcing rules with state in statePath.
+func NewLimiter(statePath string, rules []config.RateLimitRule, opts ...Option) *Limiter {
+	l := &Limiter{statePath: statePath, rules: rules, now: time.Now}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Allow reports whether an event with the given category and sound hint
+// may play for sessionID, and records the event either way. Events no rule
+// matches are allowed without touching the state file. On error the
+// decision is "allowed": rate limiting is a comfort feature and must never
+// be the reason a hook goes silent.
+func (l *Limiter) Allow(sessionID, category, hint string) (bool, error) {
+	var matched []config.RateLimitRule
+	for _, r := range l.rules {
+		if r.Matches(category, hint) {
+			matched = append(matched, r)
+		}
+	}
+	if len(matched) == 0 {
+		return true, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.statePath), 0o755); err != nil {
+		return true, fmt.Errorf("failed to create rate limit state directory: %w", err)
+	}
+	lock := flock.New(l.statePath + ".lock")
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+	ok, err := lock.TryLockContext(ctx, 10*time.Millisecond)
+	if err != nil {
+		return true, fmt.Errorf("failed to lock rate limit state: %w", err)
+	}
+	if !ok {
+		return true, fmt.Errorf("timed out locking rate limit state %s", l.statePath)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	st := l.load()
+	now := l.now().UnixNano()
+
+	allowed := true
+	for _, r := range matched {
+		b := st.Buckets[bucketKey(r, sessionID)]
+		if b == nil {
+			continue
+		}
+		if r.MinIntervalMs > 0 && b.LastAllowed != 0 && now-b.LastAllowed < msToNanos(r.MinIntervalMs) {
+			slog.Debug("rate limit: within min interval",
+				"category", category, "hint", hint, "rule", r.Key(), "min_interval_ms", r.MinIntervalMs)
+			allowed = false
+		}
+		if r.CoalesceMs > 0 && b.LastSeen != 0 && now-b.LastSeen < msToNanos(r.CoalesceMs) {
+			slog.Debug("rate limit: coalesced with previous event",
+				"category", category, "hint", hint, "rule", r.Key(), "coalesce_ms", r.CoalesceMs)
+			allowed = false
+		}
+	}
+
+	for _, r := range matched {
+		key := bucketKey(r, sessionID)
+		b := st.Buckets[key]
+		if b == nil {
+			b = &bucket{}
+			st.Buckets[key] = b
+		}
+		b.LastSeen = now
+		if allowed {
+			b.LastAllowed = now
+		}
+	}
+	l.prune(st, now)
+
+	if err := l.save(st); err != nil {
+		return allowed, err
+	}
+	return allowed, nil
+}
+
+func bucketKey(r config.RateLimitRule, sessionID string) string {
+	if r.IsGlobal() {
+		return r.Key() + "|*"
+	}
+	return r.Key() + "|" + sessionID
+}
+
+func msToNanos(ms int) int64 {
+	return int64(ms) * int64(time.Millisecond)
+}
+
+// load reads the state file, starting fresh when it is missing, corrupt,
+// or from a different layout version.
+func (l *Limiter) load() *state {
+	fresh := &state{Version: stateVersion, Buckets: map[string]*bucket{}}
+	data, err := os.ReadFile(l.statePath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("failed to read rate limit state; starting fresh", "path", l.statePath, "error", err)
+		}
+		return fresh
+	}
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil || st.Version != stateVersion || st.Buckets == nil {
+		slog.Warn("discarding unreadable rate limit state", "path", l.statePath, "error", err)
+		return fresh
+	}
+	return &st
+}
+
+// prune drops buckets idle for longer than any rule could care about, so
+// the file does not grow with every session ever seen.
+func (l *Limiter) prune(st *state, now int64) {
+	staleAfter := minStaleAfter
+	for _, r := range l.rules {
+		for _, ms := range []int{r.MinIntervalMs, r.CoalesceMs} {
+			if d := 2 * time.Duration(ms) * time.Millisecond; d > staleAfter {
+				staleAfter = d
+			}
+		}
+	}
+	for key, b := range st.Buckets {
+		if now-b.LastSeen > int64(staleAfter) {
+			delete(st.Buckets, key)
+		}
+	}
+}
+
+// save writes the state via temp file + rename so a crash mid-write never
+// leaves a torn file for the next hook.
+func (l *Limiter) save(st *state) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rate limit state: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(l.statePath), ".ratelimit-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create rate limit temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("failed to write rate limit state: %w", errors.Join(writeErr, closeErr))
+	}
+	if err := os.Rename(tmpName, l.statePath); err != nil {
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("failed to replace rate limit state: %w", err)
+	}
+	return nil
+}
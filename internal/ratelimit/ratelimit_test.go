@@ -0,0 +1,117 @@
+package ratelimit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"claudio.click/internal/config"
+)
+
+// fakeClock is a manually advanced clock for deterministic windows.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestLimiter(t *testing.T, rules ...config.RateLimitRule) (*Limiter, *fakeClock) {
+	t.Helper()
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	path := filepath.Join(t.TempDir(), "state.json")
+	return NewLimiter(path, rules, WithClock(clock.now)), clock
+}
+
+func mustAllow(t *testing.T, l *Limiter, session, category, hint string) bool {
+	t.Helper()
+	ok, err := l.Allow(session, category, hint)
+	if err != nil {
+		t.Fatalf("Allow(%q, %q, %q): %v", session, category, hint, err)
+	}
+	return ok
+}
+
+func TestAllow_UnmatchedEventsSkipStateFile(t *testing.T) {
+	l, _ := newTestLimiter(t, config.RateLimitRule{Category: "loading", MinIntervalMs: 2000})
+	if !mustAllow(t, l, "s", "success", "bash-success") {
+		t.Fatal("unmatched event must be allowed")
+	}
+	if _, err := os.Stat(l.statePath); !os.IsNotExist(err) {
+		t.Errorf("unmatched event should not create state, stat err = %v", err)
+	}
+}
+
+func TestAllow_MinIntervalPerSession(t *testing.T) {
+	l, clock := newTestLimiter(t, config.RateLimitRule{Category: "loading", MinIntervalMs: 2000})
+
+	if !mustAllow(t, l, "s1", "loading", "read-start") {
+		t.Fatal("first loading sound must play")
+	}
+	clock.advance(500 * time.Millisecond)
+	if mustAllow(t, l, "s1", "loading", "grep-start") {
+		t.Error("second loading sound within 2s must be suppressed, regardless of hint")
+	}
+	if !mustAllow(t, l, "s2", "loading", "read-start") {
+		t.Error("a different session has its own bucket")
+	}
+	clock.advance(1600 * time.Millisecond)
+	if !mustAllow(t, l, "s1", "loading", "read-start") {
+		t.Error("sound after the interval since the last allowed one must play")
+	}
+}
+
+func TestAllow_CoalesceExtendsWhileEventsKeepArriving(t *testing.T) {
+	l, clock := newTestLimiter(t, config.RateLimitRule{Hint: "read-start", CoalesceMs: 500})
+
+	if !mustAllow(t, l, "s", "loading", "read-start") {
+		t.Fatal("first event must play")
+	}
+	for i := 0; i < 5; i++ {
+		clock.advance(400 * time.Millisecond)
+		if mustAllow(t, l, "s", "loading", "read-start") {
+			t.Fatalf("burst event %d within 500ms of the previous must be coalesced", i)
+		}
+	}
+	clock.advance(600 * time.Millisecond)
+	if !mustAllow(t, l, "s", "loading", "read-start") {
+		t.Error("event after a quiet period must play")
+	}
+}
+
+func TestAllow_GlobalScopeSharesBucket(t *testing.T) {
+	l, _ := newTestLimiter(t, config.RateLimitRule{Hint: "bash-*", Scope: "global", MinIntervalMs: 1000})
+
+	if !mustAllow(t, l, "s1", "loading", "bash-start") {
+		t.Fatal("first event must play")
+	}
+	if mustAllow(t, l, "s2", "loading", "bash-start") {
+		t.Error("global scope must limit across sessions")
+	}
+}
+
+// TestAllow_StatePersistsAcrossLimiters stands in for two hook processes:
+// the second limiter only sees the first's decision through the file.
+func TestAllow_StatePersistsAcrossLimiters(t *testing.T) {
+	rules := []config.RateLimitRule{{Category: "loading", MinIntervalMs: 2000}}
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	first := NewLimiter(path, rules, WithClock(clock.now))
+	if !mustAllow(t, first, "s", "loading", "read-start") {
+		t.Fatal("first event must play")
+	}
+	second := NewLimiter(path, rules, WithClock(clock.now))
+	if mustAllow(t, second, "s", "loading", "read-start") {
+		t.Error("a fresh limiter must see persisted state")
+	}
+}
+
+func TestAllow_CorruptStateStartsFresh(t *testing.T) {
+	l, _ := newTestLimiter(t, config.RateLimitRule{Category: "loading", MinIntervalMs: 2000})
+	if err := os.WriteFile(l.statePath, []byte("{not json"), 0o644); err != nil {
+		t.Fatalf("write corrupt state: %v", err)
+	}
+	if !mustAllow(t, l, "s", "loading", "read-start") {
+		t.Error("corrupt state must not suppress sounds")
+	}
+}
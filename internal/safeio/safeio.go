@@ -30,6 +30,12 @@ const (
 	// 16-bit/44.1kHz/stereo WAV. The cap exists to prevent OOM on a
 	// pathological file, not to be tight.
 	MaxAudioFileBytes int64 = 100 * 1024 * 1024 // 100 MiB
+
+	// MaxSoundpackArchiveBytes caps the total bytes extracted from one
+	// .claudiopack archive. Compression ratios make the archive's own size
+	// meaningless as a bound, so extraction counts what it writes. 1 GiB
+	// is far beyond any real pack of short UI sounds.
+	MaxSoundpackArchiveBytes int64 = 1024 * 1024 * 1024 // 1 GiB
 )
 
 // ReadAllCapped reads up to max+1 bytes from r. If the input exceeds max,
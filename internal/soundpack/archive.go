@@ -0,0 +1,552 @@
+package soundpack
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"claudio.click/internal/safeio"
+)
+
+// Portable soundpack archives. A .claudiopack is a zip or tar.gz holding a
+// JSON manifest whose mappings are relative, the audio files laid out by
+// sound key (so the extracted tree also works as a directory pack), and a
+// sha256sum-style checksum list covering every other entry.
+const (
+	ArchiveExtension     = ".claudiopack"
+	ArchiveManifestName  = "soundpack.json"
+	ArchiveChecksumsName = "checksums.sha256"
+
+	ArchiveFormatZip   = "zip"
+	ArchiveFormatTarGz = "tar.gz"
+)
+
+// MaxArchiveEntries caps the entries read from an archive: every mapping
+// may hold a few variations, plus the manifest and checksum list.
+const MaxArchiveEntries = 4 * MaxSoundpackMappings
+
+// GetArchiveFormats returns the accepted archive formats.
+func GetArchiveFormats() []string {
+	return []string{ArchiveFormatZip, ArchiveFormatTarGz}
+}
+
+// PlanArchive turns a pack whose mappings hold absolute paths into the
+// manifest stored in an archive, with every file renamed after its sound
+// key, and returns that manifest plus the archive path of each source
+// file. A file mapped by several keys is stored once. say: templates are
+// kept as they are and empty mappings are dropped. Loudness gains, keyed
+// by absolute path like the mappings, follow their files' new names.
+func PlanArchive(sp JSONSoundpackFile) (JSONSoundpackFile, map[string]string, error) {
+	if err := validateArchiveName(sp.Name); err != nil {
+		return JSONSoundpackFile{}, nil, err
+	}
+
+	manifest := JSONSoundpackFile{
+		Name:        sp.Name,
+		Description: sp.Description,
+		Version:     sp.Version,
+		Extends:     sp.Extends,
+		Mappings:    make(map[string]MappingValue, len(sp.Mappings)),
+	}
+	files := make(map[string]string)  // archive path -> source path
+	stored := make(map[string]string) // source path -> archive path
+	keys := make([]string, 0, len(sp.Mappings))
+	for key := range sp.Mappings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys) // stable names when several keys share a file
+
+	for _, key := range keys {
+		value := sp.Mappings[key]
+		if value.IsEmpty() {
+			continue
+		}
+		if err := archiveKeyIsSafe(key); err != nil {
+			return JSONSoundpackFile{}, nil, err
+		}
+
+		stem := strings.TrimSuffix(key, path.Ext(key))
+		relative := MappingValue{Mode: value.Mode}
+		keep := func(i int, file string) {
+			relative.Files = append(relative.Files, file)
+			if len(value.Weights) == len(value.Files) {
+				relative.Weights = append(relative.Weights, value.Weights[i])
+			}
+		}
+		// Several files are numbered like a directory pack's variations.
+		audioFiles := 0
+		for _, file := range value.Files {
+			if file != "" && !IsSpeechTemplate(file) {
+				audioFiles++
+			}
+		}
+		n := 0
+		for i, file := range value.Files {
+			if file == "" {
+				continue
+			}
+			if IsSpeechTemplate(file) {
+				keep(i, file)
+				continue
+			}
+			n++
+			if existing, ok := stored[file]; ok {
+				keep(i, existing)
+				continue
+			}
+			if info, err := os.Stat(file); err != nil {
+				return JSONSoundpackFile{}, nil, fmt.Errorf("sound file not found for mapping '%s' -> '%s': %w", key, file, err)
+			} else if !info.Mode().IsRegular() {
+				return JSONSoundpackFile{}, nil, fmt.Errorf("mapping '%s' -> '%s' is not a regular file", key, file)
+			}
+
+			name := stem
+			if audioFiles > 1 {
+				name += "." + strconv.Itoa(n)
+			}
+			name += strings.ToLower(filepath.Ext(file))
+			files[name] = file
+			stored[file] = name
+			keep(i, name)
+		}
+		if len(relative.Files) == 0 {
+			continue
+		}
+		manifest.Mappings[key] = relative
+	}
+
+	if len(manifest.Mappings) == 0 {
+		return JSONSoundpackFile{}, nil, fmt.Errorf("soundpack %q has no sounds to export", sp.Name)
+	}
+	if sp.Loudness != nil {
+		gains := make(map[string]float64)
+		for source, name := range stored {
+			if gain, ok := sp.Loudness.Gains[source]; ok {
+				gains[name] = gain
+			}
+		}
+		if len(gains) > 0 {
+			manifest.Loudness = &Loudness{TargetLUFS: sp.Loudness.TargetLUFS, Gains: gains}
+		}
+	}
+	return manifest, files, nil
+}
+
+// WriteArchive writes manifest and files (archive path -> source path) to
+// w in format, followed by the checksum list.
+func WriteArchive(w io.Writer, format string, manifest JSONSoundpackFile, files map[string]string) error {
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal soundpack manifest: %w", err)
+	}
+	manifestData = append(manifestData, '\n')
+
+	var writer archiveWriter
+	switch format {
+	case ArchiveFormatZip:
+		writer = &zipArchiveWriter{zw: zip.NewWriter(w)}
+	case ArchiveFormatTarGz:
+		gz := gzip.NewWriter(w)
+		writer = &tarArchiveWriter{gz: gz, tw: tar.NewWriter(gz)}
+	default:
+		return fmt.Errorf("unknown archive format %q, must be one of: %s", format, strings.Join(GetArchiveFormats(), ", "))
+	}
+
+	sums := map[string]string{ArchiveManifestName: sha256Hex(manifestData)}
+	if err := writer.add(ArchiveManifestName, manifestData); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data, err := readSourceFile(files[name])
+		if err != nil {
+			return err
+		}
+		sums[name] = sha256Hex(data)
+		if err := writer.add(name, data); err != nil {
+			return err
+		}
+	}
+
+	if err := writer.add(ArchiveChecksumsName, formatChecksums(sums)); err != nil {
+		return err
+	}
+	return writer.close()
+}
+
+// ExtractArchive unpacks the archive at archivePath into destDir, which
+// must be empty or missing, verifies every checksum, and returns the
+// manifest, which it writes to destDir as <name>.json. Entry names pass the same checks as mapping values: no
+// absolute paths, no `..`, nothing outside destDir. Links and other
+// special entries are rejected, and every read is capped.
+func ExtractArchive(archivePath, destDir string) (*JSONSoundpackFile, error) {
+	slog.Debug("extracting soundpack archive", "archive", archivePath, "dest", destDir)
+
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open soundpack archive: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat soundpack archive: %w", err)
+	}
+
+	magic := make([]byte, 2)
+	if _, err := io.ReadFull(f, magic); err != nil {
+		return nil, fmt.Errorf("soundpack archive is too short: %w", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to rewind soundpack archive: %w", err)
+	}
+
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create extraction directory: %w", err)
+	}
+	x := &archiveExtractor{destDir: destDir, sums: make(map[string]string)}
+
+	switch {
+	case magic[0] == 'P' && magic[1] == 'K':
+		err = x.extractZip(f, info.Size())
+	case magic[0] == 0x1f && magic[1] == 0x8b:
+		err = x.extractTarGz(f)
+	default:
+		err = fmt.Errorf("not a soundpack archive (expected zip or tar.gz): %s", archivePath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return x.finish()
+}
+
+type archiveWriter interface {
+	add(name string, data []byte) error
+	close() error
+}
+
+type zipArchiveWriter struct {
+	zw *zip.Writer
+}
+
+func (z *zipArchiveWriter) add(name string, data []byte) error {
+	w, err := z.zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate})
+	if err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %w", name, err)
+	}
+	return nil
+}
+
+func (z *zipArchiveWriter) close() error {
+	if err := z.zw.Close(); err != nil {
+		return fmt.Errorf("failed to finish zip archive: %w", err)
+	}
+	return nil
+}
+
+type tarArchiveWriter struct {
+	gz *gzip.Writer
+	tw *tar.Writer
+}
+
+func (t *tarArchiveWriter) add(name string, data []byte) error {
+	hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg}
+	if err := t.tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+	if _, err := t.tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %w", name, err)
+	}
+	return nil
+}
+
+func (t *tarArchiveWriter) close() error {
+	if err := t.tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish tar archive: %w", err)
+	}
+	if err := t.gz.Close(); err != nil {
+		return fmt.Errorf("failed to finish gzip stream: %w", err)
+	}
+	return nil
+}
+
+// archiveExtractor writes entries under destDir and remembers what it
+// wrote so the checksum list can be checked against it.
+type archiveExtractor struct {
+	destDir   string
+	entries   int
+	total     int64
+	sums      map[string]string // entry name -> sha256 of what was written
+	manifest  []byte
+	checksums []byte
+}
+
+func (x *archiveExtractor) extractZip(r io.ReaderAt, size int64) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("failed to read zip archive: %w", err)
+	}
+	for _, zf := range zr.File {
+		if zf.FileInfo().IsDir() {
+			continue
+		}
+		if !zf.Mode().IsRegular() {
+			return fmt.Errorf("archive entry %q is not a regular file", zf.Name)
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open archive entry %q: %w", zf.Name, err)
+		}
+		err = x.add(zf.Name, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (x *archiveExtractor) extractTarGz(r io.Reader) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to read gzip stream: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar archive: %w", err)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			continue
+		case tar.TypeReg:
+			if err := x.add(hdr.Name, tr); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("archive entry %q is not a regular file", hdr.Name)
+		}
+	}
+}
+
+// add extracts one entry. The manifest and checksum list are kept in
+// memory; everything else is written under destDir.
+func (x *archiveExtractor) add(name string, r io.Reader) error {
+	x.entries++
+	if x.entries > MaxArchiveEntries {
+		return fmt.Errorf("soundpack archive exceeds limit of %d entries", MaxArchiveEntries)
+	}
+	name = strings.TrimPrefix(name, "./")
+	if _, seen := x.sums[name]; seen {
+		return fmt.Errorf("archive entry %q appears twice", name)
+	}
+
+	limit, kind := safeio.MaxAudioFileBytes, "audio file"
+	if name == ArchiveManifestName || name == ArchiveChecksumsName {
+		limit, kind = safeio.MaxSoundpackJSONBytes, name
+	}
+	data, err := safeio.ReadAllCapped(r, limit, kind)
+	if err != nil {
+		return fmt.Errorf("archive entry %q: %w", name, err)
+	}
+	x.total += int64(len(data))
+	if x.total > safeio.MaxSoundpackArchiveBytes {
+		return fmt.Errorf("soundpack archive exceeds %d byte limit", safeio.MaxSoundpackArchiveBytes)
+	}
+	x.sums[name] = sha256Hex(data)
+
+	switch name {
+	case ArchiveManifestName:
+		x.manifest = data
+		return nil
+	case ArchiveChecksumsName:
+		x.checksums = data
+		return nil
+	}
+
+	target, err := validateMappingValue(name, x.destDir)
+	if err != nil {
+		return fmt.Errorf("unsafe archive entry: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %q: %w", name, err)
+	}
+	// O_EXCL refuses to follow anything already at the target.
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create %q: %w", name, err)
+	}
+	if _, err := out.Write(data); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to write %q: %w", name, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to close %q: %w", name, err)
+	}
+	return nil
+}
+
+// finish checks the checksum list against the extracted entries and
+// parses the manifest.
+func (x *archiveExtractor) finish() (*JSONSoundpackFile, error) {
+	if x.manifest == nil {
+		return nil, fmt.Errorf("soundpack archive has no %s", ArchiveManifestName)
+	}
+	if x.checksums == nil {
+		return nil, fmt.Errorf("soundpack archive has no %s", ArchiveChecksumsName)
+	}
+	want, err := parseChecksums(x.checksums)
+	if err != nil {
+		return nil, err
+	}
+	for name, sum := range x.sums {
+		if name == ArchiveChecksumsName {
+			continue
+		}
+		expected, listed := want[name]
+		if !listed {
+			return nil, fmt.Errorf("archive entry %q is not in %s", name, ArchiveChecksumsName)
+		}
+		if !strings.EqualFold(expected, sum) {
+			return nil, fmt.Errorf("checksum mismatch for %q", name)
+		}
+	}
+	for name := range want {
+		if _, ok := x.sums[name]; !ok {
+			return nil, fmt.Errorf("archive is missing %q listed in %s", name, ArchiveChecksumsName)
+		}
+	}
+
+	sp, err := PeekJSONSoundpackFromBytes(x.manifest)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateArchiveName(sp.Name); err != nil {
+		return nil, err
+	}
+	manifestPath := filepath.Join(x.destDir, sp.Name+".json")
+	out, err := os.OpenFile(manifestPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create soundpack manifest: %w", err)
+	}
+	if _, err := out.Write(x.manifest); err != nil {
+		out.Close()
+		return nil, fmt.Errorf("failed to write soundpack manifest: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close soundpack manifest: %w", err)
+	}
+
+	slog.Debug("soundpack archive extracted",
+		"name", sp.Name,
+		"entries", x.entries,
+		"bytes", x.total)
+	return sp, nil
+}
+
+// validateArchiveName checks that a pack name can name its install
+// directory and manifest file.
+func validateArchiveName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\:`) {
+		return fmt.Errorf("invalid soundpack name %q: must be a plain name", name)
+	}
+	return nil
+}
+
+// archiveKeyIsSafe checks that a sound key can double as an archive path.
+func archiveKeyIsSafe(key string) error {
+	if isAnyPlatformAbsolute(key) {
+		return fmt.Errorf("sound key %q is an absolute path", key)
+	}
+	for _, seg := range strings.Split(filepath.ToSlash(key), "/") {
+		if seg == ".." || seg == "" {
+			return fmt.Errorf("sound key %q cannot be stored in an archive", key)
+		}
+	}
+	if key == ArchiveManifestName || key == ArchiveChecksumsName {
+		return fmt.Errorf("sound key %q clashes with an archive file", key)
+	}
+	return nil
+}
+
+func readSourceFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+	data, err := safeio.ReadAllCapped(f, safeio.MaxAudioFileBytes, "audio file")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return data, nil
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// formatChecksums writes sums in sha256sum's "<hex>  <name>" format,
+// sorted by name.
+func formatChecksums(sums map[string]string) []byte {
+	names := make([]string, 0, len(sums))
+	for name := range sums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var buf bytes.Buffer
+	for _, name := range names {
+		fmt.Fprintf(&buf, "%s  %s\n", sums[name], name)
+	}
+	return buf.Bytes()
+}
+
+func parseChecksums(data []byte) (map[string]string, error) {
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		sum, name, ok := strings.Cut(text, "  ")
+		if !ok || len(sum) != sha256.Size*2 || name == "" {
+			return nil, fmt.Errorf("%s line %d is malformed", ArchiveChecksumsName, line)
+		}
+		if _, err := hex.DecodeString(sum); err != nil {
+			return nil, fmt.Errorf("%s line %d is malformed", ArchiveChecksumsName, line)
+		}
+		sums[strings.TrimPrefix(name, "*")] = sum
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ArchiveChecksumsName, err)
+	}
+	return sums, nil
+}
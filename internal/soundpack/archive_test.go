@@ -0,0 +1,248 @@
+package soundpack
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestArchive_RoundTrip(t *testing.T) {
+	src := t.TempDir()
+	one := touch(t, filepath.Join(src, "elsewhere", "one.mp3"))
+	two := touch(t, filepath.Join(src, "two.wav"))
+	if err := os.WriteFile(two, []byte("second sound"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	pack := JSONSoundpackFile{
+		Name:    "portable",
+		Version: "1.0.0",
+		Extends: []string{"default"},
+		Mappings: map[string]MappingValue{
+			"success/bash-success.wav":  {Files: []string{one, two}, Mode: VariationRoundRobin},
+			"error/error.wav":           {Files: []string{"", two}, Weights: []float64{1, 3}},
+			"default.wav":               SingleFile(one),
+			"completion/completion.wav": SingleFile("say:done"),
+			"loading/loading.wav":       SingleFile(""),
+		},
+		Loudness: &Loudness{TargetLUFS: -23, Gains: map[string]float64{two: -3}},
+	}
+	manifest, files, err := PlanArchive(pack)
+	if err != nil {
+		t.Fatalf("PlanArchive: %v", err)
+	}
+
+	wantMappings := map[string]MappingValue{
+		"completion/completion.wav": SingleFile("say:done"),
+		"default.wav":               SingleFile("default.mp3"),
+		"error/error.wav":           {Files: []string{"error/error.wav"}, Weights: []float64{3}},
+		"success/bash-success.wav":  {Files: []string{"default.mp3", "error/error.wav"}, Mode: VariationRoundRobin},
+	}
+	if !reflect.DeepEqual(manifest.Mappings, wantMappings) {
+		t.Errorf("manifest mappings = %+v, want %+v", manifest.Mappings, wantMappings)
+	}
+	if want := map[string]string{"default.mp3": one, "error/error.wav": two}; !reflect.DeepEqual(files, want) {
+		t.Errorf("archive files = %v, want %v", files, want)
+	}
+	if want := (&Loudness{TargetLUFS: -23, Gains: map[string]float64{"error/error.wav": -3}}); !reflect.DeepEqual(manifest.Loudness, want) {
+		t.Errorf("manifest loudness = %+v, want %+v", manifest.Loudness, want)
+	}
+
+	for _, format := range GetArchiveFormats() {
+		t.Run(format, func(t *testing.T) {
+			archivePath := filepath.Join(t.TempDir(), "portable"+ArchiveExtension)
+			writeArchiveFile(t, archivePath, format, manifest, files)
+
+			dest := filepath.Join(t.TempDir(), "installed")
+			got, err := ExtractArchive(archivePath, dest)
+			if err != nil {
+				t.Fatalf("ExtractArchive: %v", err)
+			}
+			if got.Name != "portable" || !reflect.DeepEqual(got.Extends, []string{"default"}) {
+				t.Errorf("manifest = %+v", got)
+			}
+
+			mapper, err := LoadJSONSoundpack(filepath.Join(dest, "portable.json"))
+			if err != nil {
+				t.Fatalf("extracted pack does not load: %v", err)
+			}
+			resolved, err := NewSoundpackResolver(mapper).ResolveSound("default.wav")
+			if err != nil || resolved != filepath.Join(dest, "default.mp3") {
+				t.Errorf("ResolveSound = %q, %v", resolved, err)
+			}
+			data, err := os.ReadFile(filepath.Join(dest, "error", "error.wav"))
+			if err != nil || string(data) != "second sound" {
+				t.Errorf("extracted file = %q, %v", data, err)
+			}
+			if gain, ok := mapper.(GainMapper).FileGain(filepath.Join(dest, "error", "error.wav")); !ok || gain != -3 {
+				t.Errorf("extracted gain = %v, %v; want -3", gain, ok)
+			}
+		})
+	}
+}
+
+func TestPlanArchive_Rejects(t *testing.T) {
+	dir := t.TempDir()
+	file := touch(t, filepath.Join(dir, "a.wav"))
+	tests := []struct {
+		name string
+		pack JSONSoundpackFile
+		want string
+	}{
+		{"unsafe name", JSONSoundpackFile{Name: "../x", Mappings: map[string]MappingValue{"default.wav": SingleFile(file)}}, "invalid soundpack name"},
+		{"missing file", JSONSoundpackFile{Name: "p", Mappings: map[string]MappingValue{"default.wav": SingleFile(filepath.Join(dir, "gone.wav"))}}, "not found"},
+		{"traversing key", JSONSoundpackFile{Name: "p", Mappings: map[string]MappingValue{"../default.wav": SingleFile(file)}}, "cannot be stored"},
+		{"nothing to export", JSONSoundpackFile{Name: "p", Mappings: map[string]MappingValue{"default.wav": SingleFile("")}}, "no sounds"},
+	}
+	for _, tt := range tests {
+		if _, _, err := PlanArchive(tt.pack); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: PlanArchive error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestExtractArchive_RejectsTamperedArchives(t *testing.T) {
+	manifest := []byte(`{"name":"evil","mappings":{"default.wav":"default.wav"}}`)
+	sum := func(data []byte) string { return sha256Hex(data) + "  " }
+	audio := []byte("RIFF")
+
+	tests := []struct {
+		name    string
+		entries []testEntry
+		want    string
+	}{
+		{
+			"path traversal",
+			[]testEntry{
+				{name: ArchiveManifestName, data: manifest},
+				{name: "../evil.wav", data: audio},
+				{name: ArchiveChecksumsName, data: []byte(sum(manifest) + ArchiveManifestName + "\n" + sum(audio) + "../evil.wav\n")},
+			},
+			"path traversal",
+		},
+		{
+			"absolute path",
+			[]testEntry{
+				{name: ArchiveManifestName, data: manifest},
+				{name: "/tmp/evil.wav", data: audio},
+			},
+			"absolute paths not allowed",
+		},
+		{
+			"checksum mismatch",
+			[]testEntry{
+				{name: ArchiveManifestName, data: manifest},
+				{name: "default.wav", data: audio},
+				{name: ArchiveChecksumsName, data: []byte(sum(manifest) + ArchiveManifestName + "\n" + sum([]byte("other")) + "default.wav\n")},
+			},
+			"checksum mismatch",
+		},
+		{
+			"unlisted entry",
+			[]testEntry{
+				{name: ArchiveManifestName, data: manifest},
+				{name: "default.wav", data: audio},
+				{name: ArchiveChecksumsName, data: []byte(sum(manifest) + ArchiveManifestName + "\n")},
+			},
+			"not in " + ArchiveChecksumsName,
+		},
+		{
+			"missing checksums",
+			[]testEntry{
+				{name: ArchiveManifestName, data: manifest},
+				{name: "default.wav", data: audio},
+			},
+			"has no " + ArchiveChecksumsName,
+		},
+		{
+			"symlink",
+			[]testEntry{
+				{name: ArchiveManifestName, data: manifest},
+				{name: "default.wav", link: "/etc/passwd"},
+			},
+			"not a regular file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archivePath := filepath.Join(t.TempDir(), "evil"+ArchiveExtension)
+			writeTarGz(t, archivePath, tt.entries)
+			dest := filepath.Join(t.TempDir(), "dest")
+			if _, err := ExtractArchive(archivePath, dest); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ExtractArchive error = %v, want %q", err, tt.want)
+			}
+			if _, err := os.Stat(filepath.Join(filepath.Dir(dest), "evil.wav")); err == nil {
+				t.Error("an entry escaped the extraction directory")
+			}
+		})
+	}
+
+	// The zip reader applies the same checks.
+	archivePath := filepath.Join(t.TempDir(), "evil"+ArchiveExtension)
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("../evil.wav")
+	w.Write(audio)
+	zw.Close()
+	if err := os.WriteFile(archivePath, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExtractArchive(archivePath, filepath.Join(t.TempDir(), "dest")); err == nil || !strings.Contains(err.Error(), "path traversal") {
+		t.Errorf("zip traversal error = %v", err)
+	}
+
+	if err := os.WriteFile(archivePath, []byte("not an archive"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExtractArchive(archivePath, filepath.Join(t.TempDir(), "dest")); err == nil {
+		t.Error("a file that is neither zip nor tar.gz should be rejected")
+	}
+}
+
+type testEntry struct {
+	name string
+	data []byte
+	link string
+}
+
+func writeArchiveFile(t *testing.T, path, format string, manifest JSONSoundpackFile, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := WriteArchive(f, format, manifest, files); err != nil {
+		t.Fatalf("WriteArchive: %v", err)
+	}
+}
+
+func writeTarGz(t *testing.T, path string, entries []testEntry) {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: 0o644, Size: int64(len(e.data)), Typeflag: tar.TypeReg}
+		if e.link != "" {
+			hdr = &tar.Header{Name: e.name, Mode: 0o777, Linkname: e.link, Typeflag: tar.TypeSymlink}
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(e.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tw.Close()
+	gz.Close()
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -5,14 +5,18 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
-var directoryAudioExtensions = []string{".wav", ".mp3", ".aiff", ".aif", ".mpeg"}
+var directoryAudioExtensions = []string{".wav", ".mp3", ".aiff", ".aif", ".mpeg", ".flac", ".ogg", ".oga", ".opus"}
 
 // DirectoryMapper maps relative paths to directory-based candidates
 type DirectoryMapper struct {
 	name      string
 	basePaths []string
+
+	gainsOnce sync.Once
+	gains     map[string]float64 // absolute file path -> gain in dB, from each base path's LoudnessFileName
 }
 
 // NewDirectoryMapper creates a new directory-based path mapper
@@ -59,6 +63,18 @@ func (d *DirectoryMapper) MapPath(relativePath string) ([]string, error) {
 				"relative_path", relativePath,
 				"candidate", alternateCandidate)
 		}
+
+		// Numbered variations (bash-success.1.wav, ...) also stand in for
+		// the key, so a pack may ship only those.
+		for _, variation := range numberedVariationPaths(basePath, relativePath) {
+			candidates = append(candidates, variation)
+
+			slog.Debug("generated variation directory candidate",
+				"index", i,
+				"base_path", basePath,
+				"relative_path", relativePath,
+				"candidate", variation)
+		}
 	}
 
 	slog.Debug("directory mapping completed",
@@ -115,11 +115,23 @@ func isAnyPlatformAbsolute(value string) bool {
 // JSONMapper maps relative paths to absolute paths defined in a JSON mapping
 type JSONMapper struct {
 	name    string
-	mapping map[string]string
+	mapping map[string]MappingValue
+	extends []string
+	baseDir string             // directory of the manifest, for relative extends
+	gains   map[string]float64 // absolute file path -> gain in dB, from the manifest's loudness
 }
 
-// NewJSONMapper creates a new JSON-based path mapper
+// NewJSONMapper creates a new JSON-based path mapper with one file per key
 func NewJSONMapper(name string, mapping map[string]string) PathMapper {
+	values := make(map[string]MappingValue, len(mapping))
+	for key, value := range mapping {
+		values[key] = SingleFile(value)
+	}
+	return newJSONMapper(name, values)
+}
+
+// newJSONMapper creates a JSON mapper whose keys may hold variations
+func newJSONMapper(name string, mapping map[string]MappingValue) *JSONMapper {
 	slog.Debug("creating JSON mapper",
 		"name", name,
 		"mapping_keys_count", len(mapping))
@@ -153,14 +165,15 @@ func (j *JSONMapper) MapPath(relativePath string) ([]string, error) {
 		"mapper_name", j.name,
 		"total_mappings", len(j.mapping))
 
-	// Look up the relative path in the JSON mapping
-	if absolutePath, exists := j.mapping[relativePath]; exists {
+	// Look up the relative path in the JSON mapping. Every variation is a
+	// candidate; the resolver picks among them through MapVariations.
+	if value, exists := j.mapping[relativePath]; exists {
 		slog.Debug("JSON mapping found",
 			"relative_path", relativePath,
-			"absolute_path", absolutePath,
+			"absolute_paths", value.Files,
 			"mapper_name", j.name)
 
-		return []string{absolutePath}, nil
+		return append([]string(nil), value.Files...), nil
 	}
 
 	slog.Debug("JSON mapping not found",
@@ -172,6 +185,28 @@ func (j *JSONMapper) MapPath(relativePath string) ([]string, error) {
 	return []string{}, nil
 }
 
+// Mappings returns a copy of the pack's mappings, keyed by sound key
+func (j *JSONMapper) Mappings() map[string]MappingValue {
+	mappings := make(map[string]MappingValue, len(j.mapping))
+	for key, value := range j.mapping {
+		value.Files = append([]string(nil), value.Files...)
+		value.Weights = append([]float64(nil), value.Weights...)
+		mappings[key] = value
+	}
+	return mappings
+}
+
+// Extends returns the packs named by the manifest's "extends" field
+func (j *JSONMapper) Extends() []string {
+	return append([]string(nil), j.extends...)
+}
+
+// ExtendsBaseDir returns the manifest's directory, against which relative
+// paths in "extends" resolve; empty for an embedded pack.
+func (j *JSONMapper) ExtendsBaseDir() string {
+	return j.baseDir
+}
+
 // GetName returns the name of this JSON mapper
 func (j *JSONMapper) GetName() string {
 	return j.name
@@ -0,0 +1,250 @@
+package soundpack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"claudio.click/internal/safeio"
+)
+
+// LoudnessFileName is the file at a directory soundpack's root that holds
+// its loudness gains. JSON soundpacks keep them in their manifest instead.
+const LoudnessFileName = "loudness.json"
+
+// MaxGainDB bounds a stored gain in either direction. Analysis never comes
+// near it; a larger value is a hand-editing mistake and is ignored.
+const MaxGainDB = 24.0
+
+// Loudness records the gains that level a pack's sounds to one loudness,
+// as written by 'claudio soundpack normalize'.
+type Loudness struct {
+	TargetLUFS float64            `json:"target_lufs"`
+	Gains      map[string]float64 `json:"gains"` // sound file as the pack names it -> gain in dB
+}
+
+// GainMapper is implemented by mappers that know the loudness gain of the
+// files they map. path is a file the mapper resolved a sound to.
+type GainMapper interface {
+	FileGain(path string) (float64, bool)
+}
+
+// ReadLoudnessFile reads a loudness sidecar.
+func ReadLoudnessFile(path string) (*Loudness, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := safeio.ReadAllCapped(f, safeio.MaxSoundpackJSONBytes, "soundpack loudness")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read loudness file: %w", err)
+	}
+	var loudness Loudness
+	if err := json.Unmarshal(data, &loudness); err != nil {
+		return nil, fmt.Errorf("failed to parse loudness file: %w", err)
+	}
+	return &loudness, nil
+}
+
+// WriteLoudnessFile writes a loudness sidecar.
+func WriteLoudnessFile(path string, loudness *Loudness) error {
+	data, err := json.MarshalIndent(loudness, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode loudness: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write loudness file: %w", err)
+	}
+	slog.Debug("wrote soundpack loudness", "path", path, "gains", len(loudness.Gains))
+	return nil
+}
+
+// WriteManifestLoudness stores loudness in the JSON soundpack manifest at
+// path. Only the manifest's "loudness" value is rewritten, or added after
+// its last field; the rest of the file keeps its order and formatting.
+func WriteManifestLoudness(path string, loudness *Loudness) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open JSON soundpack file: %w", err)
+	}
+	data, err := safeio.ReadAllCapped(f, safeio.MaxSoundpackJSONBytes, "soundpack JSON")
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read JSON soundpack file: %w", err)
+	}
+
+	encoded, err := json.MarshalIndent(loudness, "  ", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode loudness: %w", err)
+	}
+	out, err := spliceLoudness(data, encoded)
+	if err != nil {
+		return fmt.Errorf("failed to parse JSON soundpack: %w", err)
+	}
+	if err := os.WriteFile(path, out, 0o644); err != nil {
+		return fmt.Errorf("failed to write JSON soundpack file: %w", err)
+	}
+	slog.Debug("wrote soundpack loudness to manifest", "path", path, "gains", len(loudness.Gains))
+	return nil
+}
+
+// spliceLoudness returns the JSON object manifest with its top-level
+// "loudness" value replaced by encoded, or with a "loudness" field added
+// after the last field when it has none.
+func spliceLoudness(manifest, encoded []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(manifest))
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('{') {
+		return nil, fmt.Errorf("manifest is not a JSON object")
+	}
+
+	valueStart, valueEnd := -1, -1
+	lastEnd := int(dec.InputOffset())
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		keyEnd := int(dec.InputOffset())
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		lastEnd = int(dec.InputOffset())
+		if tok == "loudness" {
+			// The value starts after the colon and any space around it.
+			valueStart = keyEnd + len(manifest[keyEnd:lastEnd]) - len(bytes.TrimLeft(manifest[keyEnd:lastEnd], " \t\r\n:"))
+			valueEnd = lastEnd
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	if valueStart >= 0 {
+		out.Write(manifest[:valueStart])
+		out.Write(encoded)
+		out.Write(manifest[valueEnd:])
+		return out.Bytes(), nil
+	}
+	out.Write(manifest[:lastEnd])
+	if lastEnd > bytes.IndexByte(manifest, '{')+1 {
+		out.WriteByte(',')
+	}
+	out.WriteString("\n  \"loudness\": ")
+	out.Write(encoded)
+	rest := manifest[lastEnd:]
+	if !bytes.HasPrefix(bytes.TrimLeft(rest, " \t\r"), []byte("\n")) {
+		out.WriteByte('\n')
+	}
+	out.Write(rest)
+	return out.Bytes(), nil
+}
+
+// resolveGains keys loudness's gains by the absolute path resolve maps
+// each file name to. Names resolve rejects and gains out of range are
+// dropped.
+func resolveGains(loudness *Loudness, resolve func(file string) (string, error)) map[string]float64 {
+	if loudness == nil || len(loudness.Gains) == 0 {
+		return nil
+	}
+	gains := make(map[string]float64, len(loudness.Gains))
+	for file, gain := range loudness.Gains {
+		if math.IsNaN(gain) || math.Abs(gain) > MaxGainDB {
+			slog.Warn("ignoring out-of-range loudness gain", "file", file, "gain_db", gain)
+			continue
+		}
+		abs, err := resolve(file)
+		if err != nil {
+			slog.Warn("ignoring loudness gain for invalid file", "file", file, "error", err)
+			continue
+		}
+		gains[filepath.Clean(abs)] = gain
+	}
+	return gains
+}
+
+// directoryGains reads the loudness sidecar of a directory pack rooted at
+// basePath. A pack without one has no gains.
+func directoryGains(basePath string) map[string]float64 {
+	loudness, err := ReadLoudnessFile(filepath.Join(basePath, LoudnessFileName))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Warn("ignoring unreadable soundpack loudness", "base_path", basePath, "error", err)
+		}
+		return nil
+	}
+	return resolveGains(loudness, func(file string) (string, error) {
+		return gainFilePath(file, basePath)
+	})
+}
+
+// gainFilePath resolves a gain's file name under baseDir with the same
+// syntax rules as a mapping value. It does not touch the disk: the name
+// is only a lookup key and no file is opened through it.
+func gainFilePath(file, baseDir string) (string, error) {
+	if file == "" {
+		return "", fmt.Errorf("empty file name")
+	}
+	if isAnyPlatformAbsolute(file) {
+		return "", fmt.Errorf("absolute paths not allowed: %q", file)
+	}
+	for _, seg := range strings.Split(filepath.ToSlash(file), "/") {
+		if seg == ".." {
+			return "", fmt.Errorf("path traversal not allowed: %q", file)
+		}
+	}
+	return filepath.Join(baseDir, file), nil
+}
+
+// FileGain returns the gain stored for path in the manifest.
+func (j *JSONMapper) FileGain(path string) (float64, bool) {
+	gain, ok := j.gains[filepath.Clean(path)]
+	return gain, ok
+}
+
+// FileGain returns the gain stored for path in the sidecar of the base
+// path it lies under.
+func (d *DirectoryMapper) FileGain(path string) (float64, bool) {
+	d.gainsOnce.Do(func() {
+		d.gains = make(map[string]float64)
+		for _, basePath := range d.basePaths {
+			for file, gain := range directoryGains(basePath) {
+				if _, seen := d.gains[file]; !seen {
+					d.gains[file] = gain
+				}
+			}
+		}
+	})
+	gain, ok := d.gains[filepath.Clean(path)]
+	return gain, ok
+}
+
+// FileGain returns the gain the first layer that knows path stores for it.
+func (s *StackMapper) FileGain(path string) (float64, bool) {
+	for _, layer := range s.layers {
+		if gm, ok := layer.(GainMapper); ok {
+			if gain, ok := gm.FileGain(path); ok {
+				return gain, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// FileGain returns the loudness gain of a file the resolver returned.
+func (u *UnifiedSoundpackResolver) FileGain(path string) (float64, bool) {
+	if gm, ok := u.mapper.(GainMapper); ok {
+		return gm.FileGain(path)
+	}
+	return 0, false
+}
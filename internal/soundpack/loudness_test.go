@@ -0,0 +1,176 @@
+package soundpack
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestJSONMapper_FileGainFromManifest(t *testing.T) {
+	dir := t.TempDir()
+	success := touch(t, filepath.Join(dir, "sounds", "success.wav"))
+	errorSound := touch(t, filepath.Join(dir, "sounds", "error.wav"))
+	manifest := filepath.Join(dir, "pack.json")
+	writeFile(t, manifest, `{
+  "name": "pack",
+  "mappings": {"success/success.wav": "sounds/success.wav", "error/error.wav": "sounds/error.wav"},
+  "loudness": {
+    "target_lufs": -23,
+    "gains": {"sounds/success.wav": -4.5, "../outside.wav": 3, "sounds/error.wav": 90}
+  }
+}`)
+
+	mapper, err := LoadJSONSoundpack(manifest)
+	if err != nil {
+		t.Fatalf("LoadJSONSoundpack: %v", err)
+	}
+	resolver := NewSoundpackResolver(mapper)
+	path, err := resolver.ResolveSound("success/success.wav")
+	if err != nil {
+		t.Fatalf("ResolveSound: %v", err)
+	}
+	gm, ok := resolver.(GainMapper)
+	if !ok {
+		t.Fatal("the resolver should expose the mapper's gains")
+	}
+	if gain, ok := gm.FileGain(path); !ok || gain != -4.5 {
+		t.Errorf("FileGain(%s) = %v, %v; want -4.5", success, gain, ok)
+	}
+	if _, ok := gm.FileGain(errorSound); ok {
+		t.Error("a gain beyond MaxGainDB should be ignored")
+	}
+	if _, ok := gm.FileGain(filepath.Join(filepath.Dir(dir), "outside.wav")); ok {
+		t.Error("a gain naming a file outside the pack should be ignored")
+	}
+}
+
+func TestDirectoryMapper_FileGainFromSidecar(t *testing.T) {
+	dir := t.TempDir()
+	variation := touch(t, filepath.Join(dir, "success", "success.2.wav"))
+	plain := touch(t, filepath.Join(dir, "default.wav"))
+	if err := WriteLoudnessFile(filepath.Join(dir, LoudnessFileName), &Loudness{
+		TargetLUFS: -23,
+		Gains:      map[string]float64{"success/success.2.wav": 2.5},
+	}); err != nil {
+		t.Fatalf("WriteLoudnessFile: %v", err)
+	}
+
+	mapper := NewDirectoryMapper("pack", []string{dir}).(GainMapper)
+	if gain, ok := mapper.FileGain(variation); !ok || gain != 2.5 {
+		t.Errorf("FileGain(variation) = %v, %v; want 2.5", gain, ok)
+	}
+	if _, ok := mapper.FileGain(plain); ok {
+		t.Error("a file without a stored gain should have none")
+	}
+	if _, ok := NewDirectoryMapper("bare", []string{t.TempDir()}).(GainMapper).FileGain(plain); ok {
+		t.Error("a pack without a sidecar should have no gains")
+	}
+}
+
+func TestStackMapper_FileGainFromOwningLayer(t *testing.T) {
+	upperDir, lowerDir := t.TempDir(), t.TempDir()
+	upper := touch(t, filepath.Join(upperDir, "default.wav"))
+	lower := touch(t, filepath.Join(lowerDir, "default.wav"))
+	if err := WriteLoudnessFile(filepath.Join(lowerDir, LoudnessFileName), &Loudness{
+		Gains: map[string]float64{"default.wav": -6},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	stack := NewStackMapper([]PathMapper{
+		NewDirectoryMapper("upper", []string{upperDir}),
+		NewDirectoryMapper("lower", []string{lowerDir}),
+	}).(GainMapper)
+	if gain, ok := stack.FileGain(lower); !ok || gain != -6 {
+		t.Errorf("FileGain(lower) = %v, %v; want -6", gain, ok)
+	}
+	if _, ok := stack.FileGain(upper); ok {
+		t.Error("the lower layer's gain should not apply to the upper layer's file")
+	}
+}
+
+func TestWriteManifestLoudness_KeepsOtherFields(t *testing.T) {
+	dir := t.TempDir()
+	touch(t, filepath.Join(dir, "a.wav"))
+	manifest := filepath.Join(dir, "pack.json")
+	writeFile(t, manifest, `{"name": "pack", "description": "kept", "homepage": "https://example.com",
+  "mappings": {"success/success.wav": "a.wav"}}`)
+
+	if err := WriteManifestLoudness(manifest, &Loudness{TargetLUFS: -20, Gains: map[string]float64{"a.wav": 1.5}}); err != nil {
+		t.Fatalf("WriteManifestLoudness: %v", err)
+	}
+	sp, err := PeekJSONSoundpackFromFile(manifest)
+	if err != nil {
+		t.Fatalf("PeekJSONSoundpackFromFile: %v", err)
+	}
+	if sp.Description != "kept" || sp.Loudness == nil || sp.Loudness.TargetLUFS != -20 || sp.Loudness.Gains["a.wav"] != 1.5 {
+		t.Errorf("unexpected manifest after write: %+v", sp)
+	}
+	data, err := os.ReadFile(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"homepage": "https://example.com"`) {
+		t.Errorf("fields the loader does not know should survive, got %s", data)
+	}
+
+	ResolveJSONSoundpackMappings(sp, dir)
+	if sp.Loudness.Gains[filepath.Join(dir, "a.wav")] != 1.5 {
+		t.Errorf("ResolveJSONSoundpackMappings should key gains by absolute path, got %v", sp.Loudness.Gains)
+	}
+}
+
+func TestWriteManifestLoudness_KeepsLayout(t *testing.T) {
+	loudness := &Loudness{TargetLUFS: -20, Gains: map[string]float64{"a.wav": 1.5}}
+	want := `{
+  "name": "pack",
+  "mappings": {"success/success.wav": "a.wav"},
+  "loudness": {
+    "target_lufs": -20,
+    "gains": {
+      "a.wav": 1.5
+    }
+  }
+}
+`
+	tests := []struct {
+		name     string
+		manifest string
+	}{
+		{"added", `{
+  "name": "pack",
+  "mappings": {"success/success.wav": "a.wav"}
+}
+`},
+		{"replaced", `{
+  "name": "pack",
+  "mappings": {"success/success.wav": "a.wav"},
+  "loudness": {"target_lufs": -14, "gains": {}}
+}
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest := filepath.Join(t.TempDir(), "pack.json")
+			writeFile(t, manifest, tt.manifest)
+			if err := WriteManifestLoudness(manifest, loudness); err != nil {
+				t.Fatalf("WriteManifestLoudness: %v", err)
+			}
+			data, err := os.ReadFile(manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != want {
+				t.Errorf("manifest after write:\n%s\nwant:\n%s", data, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package soundpack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// pickerStateVersion is bumped when the state file layout changes; a file
+// with a different version is discarded rather than misread.
+const pickerStateVersion = 1
+
+// pickerLockTimeout bounds how long a pick waits for another hook's
+// update before falling back to a random pick.
+const pickerLockTimeout = time.Second
+
+// rotation is the saved position of one round-robin or shuffle key.
+type rotation struct {
+	Count int   `json:"count"`          // number of files when the position was saved
+	Next  int   `json:"next,omitempty"` // round-robin: index of the next file
+	Bag   []int `json:"bag,omitempty"`  // shuffle: indexes not yet played this round
+}
+
+type pickerState struct {
+	Version int                             `json:"version"`
+	Packs   map[string]map[string]*rotation `json:"packs"` // pack name -> sound key -> position
+}
+
+// Picker chooses which variation of a multi-file sound key plays. Every
+// hook runs in a fresh process, so round-robin and shuffle positions are
+// kept per pack in a JSON state file and read-modify-written under an
+// flock, like the rate limiter's buckets. A Picker with no state path
+// keeps positions in memory.
+type Picker struct {
+	statePath string
+	intN      func(n int) int
+	float     func() float64
+
+	mu     sync.Mutex
+	memory *pickerState // used when statePath is empty
+}
+
+// NewPicker returns a Picker keeping rotation state in statePath, or in
+// memory when statePath is "".
+func NewPicker(statePath string) *Picker {
+	return &Picker{statePath: statePath, intN: rand.IntN, float: rand.Float64}
+}
+
+// Pick returns the index into value.Files to play for key in pack. Picking
+// never fails: when the state file cannot be used, round-robin and shuffle
+// keys fall back to a random pick.
+func (p *Picker) Pick(pack, key string, value MappingValue) int {
+	n := len(value.Files)
+	if n < 2 {
+		return 0
+	}
+	switch value.EffectiveMode() {
+	case VariationRoundRobin, VariationShuffle:
+		i, err := p.advance(pack, key, value.EffectiveMode(), n)
+		if err == nil {
+			return i
+		}
+		slog.Warn("variation state unavailable; picking at random", "pack", pack, "key", key, "error", err)
+		return p.intN(n)
+	default:
+		return p.weighted(value.Weights, n)
+	}
+}
+
+// weighted picks an index with odds proportional to weights, or uniformly
+// when there are none.
+func (p *Picker) weighted(weights []float64, n int) int {
+	if len(weights) != n {
+		return p.intN(n)
+	}
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+	if total <= 0 {
+		return p.intN(n)
+	}
+	target := p.float() * total
+	for i, w := range weights {
+		if target < w {
+			return i
+		}
+		target -= w
+	}
+	return n - 1
+}
+
+// advance moves key's saved position on by one and returns the index it
+// was at.
+func (p *Picker) advance(pack, key, mode string, n int) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var st *pickerState
+	if p.statePath == "" {
+		if p.memory == nil {
+			p.memory = &pickerState{Version: pickerStateVersion, Packs: map[string]map[string]*rotation{}}
+		}
+		st = p.memory
+	} else {
+		if err := os.MkdirAll(filepath.Dir(p.statePath), 0o755); err != nil {
+			return 0, fmt.Errorf("failed to create variation state directory: %w", err)
+		}
+		lock := flock.New(p.statePath + ".lock")
+		ctx, cancel := context.WithTimeout(context.Background(), pickerLockTimeout)
+		defer cancel()
+		ok, err := lock.TryLockContext(ctx, 10*time.Millisecond)
+		if err != nil {
+			return 0, fmt.Errorf("failed to lock variation state: %w", err)
+		}
+		if !ok {
+			return 0, fmt.Errorf("timed out locking variation state %s", p.statePath)
+		}
+		defer func() { _ = lock.Unlock() }()
+		st = p.load()
+	}
+
+	keys := st.Packs[pack]
+	if keys == nil {
+		keys = map[string]*rotation{}
+		st.Packs[pack] = keys
+	}
+	r := keys[key]
+	// A pack edit that changes the number of files restarts the rotation.
+	if r == nil || r.Count != n {
+		r = &rotation{Count: n}
+		keys[key] = r
+	}
+
+	var i int
+	if mode == VariationRoundRobin {
+		i = r.Next % n
+		r.Next = (i + 1) % n
+	} else {
+		if len(r.Bag) == 0 {
+			r.Bag = rand.Perm(n)
+		}
+		i, r.Bag = r.Bag[0], r.Bag[1:]
+	}
+
+	if p.statePath == "" {
+		return i, nil
+	}
+	return i, p.save(st)
+}
+
+// load reads the state file, starting fresh when it is missing, corrupt,
+// or from a different layout version.
+func (p *Picker) load() *pickerState {
+	fresh := &pickerState{Version: pickerStateVersion, Packs: map[string]map[string]*rotation{}}
+	data, err := os.ReadFile(p.statePath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("failed to read variation state; starting fresh", "path", p.statePath, "error", err)
+		}
+		return fresh
+	}
+	var st pickerState
+	if err := json.Unmarshal(data, &st); err != nil || st.Version != pickerStateVersion || st.Packs == nil {
+		slog.Warn("discarding unreadable variation state", "path", p.statePath, "error", err)
+		return fresh
+	}
+	return &st
+}
+
+// save writes the state via temp file + rename so a crash mid-write never
+// leaves a torn file for the next hook.
+func (p *Picker) save(st *pickerState) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return fmt.Errorf("failed to marshal variation state: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p.statePath), ".variations-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create variation temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write variation state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to close variation temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, p.statePath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace variation state: %w", err)
+	}
+	return nil
+}
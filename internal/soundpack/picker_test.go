@@ -0,0 +1,78 @@
+package soundpack
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPicker_RoundRobinPersistsAcrossPickers(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "variations", "state.json")
+	value := MappingValue{Files: []string{"a", "b", "c"}, Mode: VariationRoundRobin}
+
+	// Each hook builds a fresh Picker; the position must carry over.
+	var got []int
+	for i := 0; i < 4; i++ {
+		got = append(got, NewPicker(statePath).Pick("pack", "key", value))
+	}
+	if want := []int{0, 1, 2, 0}; !equalInts(got, want) {
+		t.Errorf("round-robin picks = %v, want %v", got, want)
+	}
+
+	// Positions are per pack and per key.
+	if i := NewPicker(statePath).Pick("other-pack", "key", value); i != 0 {
+		t.Errorf("another pack should start at 0, got %d", i)
+	}
+	if i := NewPicker(statePath).Pick("pack", "other-key", value); i != 0 {
+		t.Errorf("another key should start at 0, got %d", i)
+	}
+
+	// Changing the number of files restarts the rotation.
+	value.Files = append(value.Files, "d")
+	if i := NewPicker(statePath).Pick("pack", "key", value); i != 0 {
+		t.Errorf("a changed file list should restart at 0, got %d", i)
+	}
+}
+
+func TestPicker_ShufflePlaysEachOncePerRound(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	value := MappingValue{Files: []string{"a", "b", "c", "d"}, Mode: VariationShuffle}
+
+	for round := 0; round < 3; round++ {
+		seen := map[int]bool{}
+		for i := 0; i < len(value.Files); i++ {
+			seen[NewPicker(statePath).Pick("pack", "key", value)] = true
+		}
+		if len(seen) != len(value.Files) {
+			t.Errorf("round %d played %v, want every file once", round, seen)
+		}
+	}
+}
+
+func TestPicker_Weighted(t *testing.T) {
+	p := NewPicker("")
+	value := MappingValue{Files: []string{"never", "always"}, Weights: []float64{0, 1}}
+	for i := 0; i < 100; i++ {
+		if got := p.Pick("pack", "key", value); got != 1 {
+			t.Fatalf("zero-weight file picked")
+		}
+	}
+
+	// Deterministic draws land in the matching weight band.
+	p.float = func() float64 { return 0.7 }
+	value = MappingValue{Files: []string{"a", "b", "c"}, Weights: []float64{1, 1, 2}}
+	if got := p.Pick("pack", "key", value); got != 2 {
+		t.Errorf("draw 0.7 of weights 1,1,2 = %d, want 2", got)
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
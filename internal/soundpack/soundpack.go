@@ -75,32 +75,64 @@ func buildResolveConfig(opts []ResolveOption) resolveConfig {
 // UnifiedSoundpackResolver implements SoundpackResolver using any PathMapper
 type UnifiedSoundpackResolver struct {
 	mapper PathMapper
+	picker *Picker // chooses among a key's variations
 }
 
-// NewSoundpackResolver creates a new unified soundpack resolver
+// NewSoundpackResolver creates a new unified soundpack resolver. Keys with
+// several variations are picked with an in-memory Picker, so round-robin
+// and shuffle positions last only as long as the resolver.
 func NewSoundpackResolver(mapper PathMapper) SoundpackResolver {
+	return NewSoundpackResolverWithPicker(mapper, NewPicker(""))
+}
+
+// NewSoundpackResolverWithPicker creates a unified soundpack resolver that
+// picks among a key's variations with picker.
+func NewSoundpackResolverWithPicker(mapper PathMapper, picker *Picker) SoundpackResolver {
 	slog.Debug("creating unified soundpack resolver",
 		"mapper_name", mapper.GetName(),
 		"mapper_type", mapper.GetType())
 
 	return &UnifiedSoundpackResolver{
 		mapper: mapper,
+		picker: picker,
 	}
 }
 
 // ResolveSound resolves a single sound path using the configured mapper
 func (u *UnifiedSoundpackResolver) ResolveSound(relativePath string) (string, error) {
+	return u.resolveSound(relativePath, true)
+}
+
+// resolveSound resolves relativePath. With pick false a key with several
+// variations resolves to its first existing file without consulting the
+// picker, so probing a fallback chain does not advance a round-robin.
+func (u *UnifiedSoundpackResolver) resolveSound(relativePath string, pick bool) (string, error) {
 	if relativePath == "" {
 		err := fmt.Errorf("sound path cannot be empty")
 		slog.Error("resolve sound failed", "error", err)
 		return "", err
 	}
 
+	// A say: template (from a routing rule) is spoken, not looked up.
+	if IsSpeechTemplate(relativePath) {
+		slog.Debug("sound path is a speech template", "relative_path", relativePath)
+		return relativePath, nil
+	}
+
 	slog.Debug("resolving sound path",
 		"relative_path", relativePath,
 		"mapper_type", u.mapper.GetType(),
 		"mapper_name", u.mapper.GetName())
 
+	// A key with several variations plays one of those that exist.
+	if vm, ok := u.mapper.(VariationMapper); ok {
+		if variations, ok := vm.MapVariations(relativePath); ok {
+			if chosen, ok := u.pickVariation(relativePath, variations, pick); ok {
+				return chosen, nil
+			}
+		}
+	}
+
 	// Get candidate paths from mapper
 	candidates, err := u.mapper.MapPath(relativePath)
 	if err != nil {
@@ -117,6 +149,13 @@ func (u *UnifiedSoundpackResolver) ResolveSound(relativePath string) (string, er
 	for i, candidate := range candidates {
 		slog.Debug("checking candidate", "index", i, "candidate", candidate)
 
+		if IsSpeechTemplate(candidate) {
+			slog.Debug("sound path resolved to speech template",
+				"relative_path", relativePath,
+				"candidate_index", i)
+			return candidate, nil
+		}
+
 		if _, err := os.Stat(candidate); err == nil {
 			slog.Debug("sound path resolved successfully",
 				"relative_path", relativePath,
@@ -144,6 +183,39 @@ func (u *UnifiedSoundpackResolver) ResolveSound(relativePath string) (string, er
 	return "", err
 }
 
+// pickVariation chooses one of the variations whose file exists (or that
+// is a say: template), or the first of them when pick is false. ok is false
+// when none does, leaving the key to the ordinary candidate walk and its
+// not-found error.
+func (u *UnifiedSoundpackResolver) pickVariation(relativePath string, variations MappingValue, pick bool) (string, bool) {
+	present := MappingValue{Mode: variations.Mode}
+	for i, file := range variations.Files {
+		if !IsSpeechTemplate(file) && !fileExists(file) {
+			slog.Debug("variation not found", "relative_path", relativePath, "candidate", file)
+			continue
+		}
+		present.Files = append(present.Files, file)
+		if len(variations.Weights) == len(variations.Files) {
+			present.Weights = append(present.Weights, variations.Weights[i])
+		}
+	}
+	if len(present.Files) == 0 {
+		return "", false
+	}
+	if !pick {
+		return present.Files[0], true
+	}
+
+	i := u.picker.Pick(u.mapper.GetName(), relativePath, present)
+	slog.Debug("sound variation picked",
+		"relative_path", relativePath,
+		"mode", present.EffectiveMode(),
+		"variations", len(present.Files),
+		"index", i,
+		"resolved_path", present.Files[i])
+	return present.Files[i], true
+}
+
 // ResolveSoundWithFallback tries multiple sound paths in order until one is
 // found. Optional ResolveOptions configure per-call behavior — most notably
 // WithObserver(...) which fires a PathObserver callback for every candidate
@@ -154,6 +226,10 @@ func (u *UnifiedSoundpackResolver) ResolveSound(relativePath string) (string, er
 // The observer is invoked with exists=true ONLY when the candidate resolved
 // to a physical file present on disk. A mapping miss (ResolveSound returns
 // an error) is reported as exists=false.
+//
+// The walk only probes for existence: a key with variations resolves to its
+// first existing file and its round-robin or shuffle position is left for
+// the ResolveSound call that plays it.
 func (u *UnifiedSoundpackResolver) ResolveSoundWithFallback(paths []string, opts ...ResolveOption) (string, error) {
 	cfg := buildResolveConfig(opts)
 
@@ -186,7 +262,7 @@ func (u *UnifiedSoundpackResolver) ResolveSoundWithFallback(paths []string, opts
 			continue
 		}
 
-		resolved, err := u.ResolveSound(path)
+		resolved, err := u.resolveSound(path, false)
 		if err == nil {
 			if cfg.observer != nil {
 				cfg.observer(path, sequence, true)
@@ -253,7 +329,9 @@ type JSONSoundpackFile struct {
 	Name        string            `json:"name"`
 	Description string            `json:"description,omitempty"`
 	Version     string            `json:"version,omitempty"`
-	Mappings    map[string]string `json:"mappings"`
+	Mappings    map[string]MappingValue `json:"mappings"`
+	Extends     []string          `json:"extends,omitempty"` // Packs searched, in order, for keys this one does not map
+	Loudness    *Loudness         `json:"loudness,omitempty"` // Gains that level the pack's sounds, keyed like mapping files
 }
 
 // MaxSoundpackMappings caps the number of entries in a soundpack JSON.
@@ -352,18 +430,31 @@ func loadJSONSoundpackUntrusted(data []byte, baseDir string) (PathMapper, error)
 	}
 
 	// Resolve and validate each mapping value through the trust boundary.
-	resolved := make(map[string]string, len(soundpack.Mappings))
+	resolved := make(map[string]MappingValue, len(soundpack.Mappings))
 	for key, value := range soundpack.Mappings {
-		abs, err := validateMappingValue(value, baseDir)
-		if err != nil {
-			slog.Error("mapping value rejected",
-				"key", key,
-				"value", value,
-				"base_dir", baseDir,
-				"error", err)
+		if err := value.Validate(); err != nil {
+			slog.Error("mapping value rejected", "key", key, "error", err)
 			return nil, fmt.Errorf("invalid mapping %q: %w", key, err)
 		}
-		resolved[key] = abs
+		files := make([]string, len(value.Files))
+		for i, file := range value.Files {
+			if IsSpeechTemplate(file) {
+				files[i] = file
+				continue
+			}
+			abs, err := validateMappingValue(file, baseDir)
+			if err != nil {
+				slog.Error("mapping value rejected",
+					"key", key,
+					"value", file,
+					"base_dir", baseDir,
+					"error", err)
+				return nil, fmt.Errorf("invalid mapping %q: %w", key, err)
+			}
+			files[i] = abs
+		}
+		value.Files = files
+		resolved[key] = value
 	}
 	soundpack.Mappings = resolved
 
@@ -373,9 +464,16 @@ func loadJSONSoundpackUntrusted(data []byte, baseDir string) (PathMapper, error)
 
 	slog.Debug("untrusted JSON soundpack parsed",
 		"name", soundpack.Name,
-		"mappings_count", len(soundpack.Mappings))
-
-	return NewJSONMapper(soundpack.Name, soundpack.Mappings), nil
+		"mappings_count", len(soundpack.Mappings),
+		"extends", soundpack.Extends)
+
+	mapper := newJSONMapper(soundpack.Name, soundpack.Mappings)
+	mapper.extends = soundpack.Extends
+	mapper.baseDir = baseDir
+	mapper.gains = resolveGains(soundpack.Loudness, func(file string) (string, error) {
+		return gainFilePath(file, baseDir)
+	})
+	return mapper, nil
 }
 
 // loadJSONSoundpackTrusted is the internal entry point for trusted
@@ -392,6 +490,11 @@ func loadJSONSoundpackTrusted(data []byte, basePaths []string) (PathMapper, erro
 	if err := validateJSONSoundpackBasics(soundpack); err != nil {
 		return nil, err
 	}
+	for key, value := range soundpack.Mappings {
+		if err := value.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid mapping %q: %w", key, err)
+		}
+	}
 
 	resolveTrustedRelativeMappings(&soundpack, basePaths)
 
@@ -401,9 +504,15 @@ func loadJSONSoundpackTrusted(data []byte, basePaths []string) (PathMapper, erro
 
 	slog.Debug("trusted JSON soundpack parsed",
 		"name", soundpack.Name,
-		"mappings_count", len(soundpack.Mappings))
-
-	return NewJSONMapper(soundpack.Name, soundpack.Mappings), nil
+		"mappings_count", len(soundpack.Mappings),
+		"extends", soundpack.Extends)
+
+	mapper := newJSONMapper(soundpack.Name, soundpack.Mappings)
+	mapper.extends = soundpack.Extends
+	mapper.gains = resolveGains(soundpack.Loudness, func(file string) (string, error) {
+		return resolveTrustedRelativeFile(file, basePaths), nil
+	})
+	return mapper, nil
 }
 
 func resolveTrustedRelativeMappings(soundpack *JSONSoundpackFile, basePaths []string) {
@@ -411,33 +520,43 @@ func resolveTrustedRelativeMappings(soundpack *JSONSoundpackFile, basePaths []st
 		return
 	}
 
-	for key, value := range soundpack.Mappings {
-		if value == "" || isAnyPlatformAbsolute(value) {
-			continue
+	for _, value := range soundpack.Mappings {
+		for i, file := range value.Files {
+			value.Files[i] = resolveTrustedRelativeFile(file, basePaths)
 		}
+	}
+}
 
-		var firstCandidate string
-		for _, basePath := range basePaths {
-			if basePath == "" {
-				continue
-			}
-			candidate := filepath.Clean(filepath.Join(basePath, value))
-			if firstCandidate == "" {
-				firstCandidate = candidate
-			}
-			if _, err := os.Stat(candidate); err == nil {
-				soundpack.Mappings[key] = candidate
-				break
-			}
+// resolveTrustedRelativeFile returns the first base-path candidate for a
+// relative file that exists, else the first candidate. Absolute files and
+// say: templates are returned as they are.
+func resolveTrustedRelativeFile(file string, basePaths []string) string {
+	if file == "" || isAnyPlatformAbsolute(file) || IsSpeechTemplate(file) {
+		return file
+	}
+
+	var firstCandidate string
+	for _, basePath := range basePaths {
+		if basePath == "" {
+			continue
 		}
-		if soundpack.Mappings[key] == value && firstCandidate != "" {
-			soundpack.Mappings[key] = firstCandidate
+		candidate := filepath.Clean(filepath.Join(basePath, file))
+		if firstCandidate == "" {
+			firstCandidate = candidate
+		}
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
 		}
 	}
+	if firstCandidate != "" {
+		return firstCandidate
+	}
+	return file
 }
 
-// ResolveJSONSoundpackMappings converts non-empty relative mapping values to
-// absolute paths rooted at baseDir. Absolute values are preserved.
+// ResolveJSONSoundpackMappings converts non-empty relative mapping values,
+// and the file names of loudness gains, to absolute paths rooted at
+// baseDir. Absolute values are preserved.
 //
 // Retained for backward compatibility with code paths that build a
 // JSONSoundpackFile by hand and want to canonicalize relative values
@@ -448,11 +567,24 @@ func ResolveJSONSoundpackMappings(soundpack *JSONSoundpackFile, baseDir string)
 		return
 	}
 
-	for relativePath, mappedPath := range soundpack.Mappings {
-		if mappedPath == "" || filepath.IsAbs(mappedPath) {
-			continue
+	for _, value := range soundpack.Mappings {
+		for i, mappedPath := range value.Files {
+			if mappedPath == "" || filepath.IsAbs(mappedPath) || IsSpeechTemplate(mappedPath) {
+				continue
+			}
+			value.Files[i] = filepath.Clean(filepath.Join(baseDir, mappedPath))
+		}
+	}
+
+	if soundpack.Loudness != nil {
+		gains := make(map[string]float64, len(soundpack.Loudness.Gains))
+		for file, gain := range soundpack.Loudness.Gains {
+			if !filepath.IsAbs(file) {
+				file = filepath.Clean(filepath.Join(baseDir, file))
+			}
+			gains[file] = gain
 		}
-		soundpack.Mappings[relativePath] = filepath.Clean(filepath.Join(baseDir, mappedPath))
+		soundpack.Loudness = &Loudness{TargetLUFS: soundpack.Loudness.TargetLUFS, Gains: gains}
 	}
 }
 
@@ -470,26 +602,32 @@ func validateJSONSoundpackBasics(soundpack JSONSoundpackFile) error {
 		return fmt.Errorf("soundpack mappings exceed limit of %d entries (got %d)",
 			MaxSoundpackMappings, len(soundpack.Mappings))
 	}
-	return nil
+	return validateExtends(soundpack.Extends)
 }
 
 // validateMappingFilesExist runs os.Stat on each mapping value and
 // returns an error if any referenced file is missing. The mappings-count
 // cap (validateJSONSoundpackBasics) bounds the number of stat calls.
+// say: templates name no file and are skipped.
 func validateMappingFilesExist(soundpack JSONSoundpackFile) error {
-	for relativePath, absolutePath := range soundpack.Mappings {
-		if _, err := os.Stat(absolutePath); err != nil {
-			slog.Error("sound file not found",
+	for relativePath, value := range soundpack.Mappings {
+		for _, absolutePath := range value.Files {
+			if IsSpeechTemplate(absolutePath) {
+				continue
+			}
+			if _, err := os.Stat(absolutePath); err != nil {
+				slog.Error("sound file not found",
+					"relative_path", relativePath,
+					"absolute_path", absolutePath,
+					"error", err)
+				return fmt.Errorf("sound file not found for mapping '%s' -> '%s': %w",
+					relativePath, absolutePath, err)
+			}
+
+			slog.Debug("sound file validation passed",
 				"relative_path", relativePath,
-				"absolute_path", absolutePath,
-				"error", err)
-			return fmt.Errorf("sound file not found for mapping '%s' -> '%s': %w",
-				relativePath, absolutePath, err)
+				"absolute_path", absolutePath)
 		}
-
-		slog.Debug("sound file validation passed",
-			"relative_path", relativePath,
-			"absolute_path", absolutePath)
 	}
 	return nil
 }
@@ -0,0 +1,19 @@
+package soundpack
+
+import "strings"
+
+// SpeechPrefix marks a mapping value or routing sound key as a spoken
+// template rather than a file, e.g. "say:{{agent}} finished in
+// {{cwd_basename}}". Templates are rendered and synthesized at play time,
+// so they skip the path checks and always resolve.
+const SpeechPrefix = "say:"
+
+// IsSpeechTemplate reports whether value is a say: template.
+func IsSpeechTemplate(value string) bool {
+	return strings.HasPrefix(value, SpeechPrefix)
+}
+
+// SpeechTemplateText returns the template after the say: prefix.
+func SpeechTemplateText(value string) string {
+	return strings.TrimSpace(strings.TrimPrefix(value, SpeechPrefix))
+}
@@ -0,0 +1,44 @@
+package soundpack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadJSONSoundpack_SpeechTemplates(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "done.wav"), []byte("RIFF"), 0o644); err != nil {
+		t.Fatalf("write wav: %v", err)
+	}
+	data := []byte(`{"name":"speech","mappings":{
+		"default.wav":"done.wav",
+		"completion/agent-complete.wav":"say:{{agent}} finished in {{cwd_basename}}"
+	}}`)
+
+	mapper, err := LoadJSONSoundpackFromBytes(data, dir)
+	if err != nil {
+		t.Fatalf("say: mappings should load without a file: %v", err)
+	}
+	resolver := NewSoundpackResolver(mapper)
+
+	got, err := resolver.ResolveSound("completion/agent-complete.wav")
+	if err != nil || got != "say:{{agent}} finished in {{cwd_basename}}" {
+		t.Errorf("ResolveSound = %q, %v; want the template unchanged", got, err)
+	}
+
+	// A routing rule can name a template directly as its sound key.
+	got, err = resolver.ResolveSound("say:{{tool}} failed")
+	if err != nil || got != "say:{{tool}} failed" {
+		t.Errorf("ResolveSound(template key) = %q, %v", got, err)
+	}
+}
+
+func TestSpeechTemplateText(t *testing.T) {
+	if got := SpeechTemplateText("say: {{tool}} failed "); got != "{{tool}} failed" {
+		t.Errorf("SpeechTemplateText = %q", got)
+	}
+	if IsSpeechTemplate("sounds/say.wav") {
+		t.Error("a path is not a speech template")
+	}
+}
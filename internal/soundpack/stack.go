@@ -0,0 +1,206 @@
+package soundpack
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// MaxStackLayers caps how many packs one stack may hold once every
+// manifest's "extends" list is expanded. Legitimate stacks are a few packs
+// deep; the cap keeps a runaway chain of manifests from fanning out.
+const MaxStackLayers = 16
+
+// ExtendingMapper is implemented by mappers whose manifest names parent
+// packs with "extends". Keys the pack does not map are looked up in its
+// parents, in order. ExtendsBaseDir is the directory relative paths in
+// "extends" are resolved against, the manifest's own; empty when the pack
+// has no directory on disk.
+type ExtendingMapper interface {
+	Extends() []string
+	ExtendsBaseDir() string
+}
+
+// StackMapper layers several packs into one. Every sound key is looked up
+// in each layer in order before the resolver moves on to the next key of a
+// fallback chain, so a small overlay pack wins for the keys it maps and
+// the packs below it supply the rest.
+type StackMapper struct {
+	name   string
+	layers []PathMapper
+}
+
+// NewStackMapper returns a mapper that searches layers in order, highest
+// priority first. A single layer is returned as it is.
+func NewStackMapper(layers []PathMapper) PathMapper {
+	if len(layers) == 1 {
+		return layers[0]
+	}
+
+	names := make([]string, len(layers))
+	for i, layer := range layers {
+		names[i] = layer.GetName()
+	}
+	slog.Debug("creating stack mapper", "layers", names)
+
+	return &StackMapper{
+		name:   strings.Join(names, "+"),
+		layers: layers,
+	}
+}
+
+// Layers returns the stacked packs, highest priority first.
+func (s *StackMapper) Layers() []PathMapper {
+	return append([]PathMapper(nil), s.layers...)
+}
+
+// MapPath returns every layer's candidates, upper layers first, so the
+// resolver's first existing candidate comes from the highest layer that
+// has the sound.
+func (s *StackMapper) MapPath(relativePath string) ([]string, error) {
+	var candidates []string
+	for _, layer := range s.layers {
+		layerCandidates, err := layer.MapPath(relativePath)
+		if err != nil {
+			slog.Debug("stack layer mapping failed",
+				"relative_path", relativePath,
+				"layer", layer.GetName(),
+				"error", err)
+			continue
+		}
+		candidates = append(candidates, layerCandidates...)
+	}
+
+	slog.Debug("stack mapping completed",
+		"relative_path", relativePath,
+		"candidates_count", len(candidates),
+		"mapper_name", s.name)
+
+	return candidates, nil
+}
+
+// MapVariations returns the variations of the highest layer that has the
+// sound. A layer that has it as a single file hides variations further
+// down the stack.
+func (s *StackMapper) MapVariations(relativePath string) (MappingValue, bool) {
+	for _, layer := range s.layers {
+		if vm, ok := layer.(VariationMapper); ok {
+			if variations, ok := vm.MapVariations(relativePath); ok && anyVariationPresent(variations) {
+				return variations, true
+			}
+		}
+		if layerHasSound(layer, relativePath) {
+			return MappingValue{}, false
+		}
+	}
+	return MappingValue{}, false
+}
+
+// GetName returns the layer names joined with "+"
+func (s *StackMapper) GetName() string {
+	return s.name
+}
+
+// GetType returns the type identifier for stacked mappers
+func (s *StackMapper) GetType() string {
+	return "stack"
+}
+
+// ExpandStack follows "extends" below each root pack: every root is
+// followed by its parents, loaded with load by name and the extending
+// pack's ExtendsBaseDir, and theirs in turn, depth first. A pack that appears twice keeps its first, highest place,
+// which also stops cycles. Parents that fail to load are skipped with a
+// warning so one missing pack does not silence the rest.
+func ExpandStack(roots []PathMapper, load func(name, baseDir string) (PathMapper, error)) []PathMapper {
+	var layers []PathMapper
+	seen := make(map[string]bool)
+
+	// requested is the name the pack was loaded by, which may differ from
+	// the name in its manifest; either one marks it as seen.
+	var add func(mapper PathMapper, requested string, chain []string)
+	add = func(mapper PathMapper, requested string, chain []string) {
+		if seen[mapper.GetName()] || seen[requested] {
+			return
+		}
+		if len(layers) >= MaxStackLayers {
+			slog.Warn("soundpack stack too deep, ignoring pack",
+				"pack", mapper.GetName(),
+				"max_layers", MaxStackLayers)
+			return
+		}
+		seen[mapper.GetName()] = true
+		if requested != "" {
+			seen[requested] = true
+		}
+		layers = append(layers, mapper)
+
+		extending, ok := mapper.(ExtendingMapper)
+		if !ok {
+			return
+		}
+		chain = append(chain, mapper.GetName())
+		for _, parent := range extending.Extends() {
+			if seen[parent] {
+				continue
+			}
+			parentMapper, err := load(parent, extending.ExtendsBaseDir())
+			if err != nil {
+				slog.Warn("failed to load extended soundpack, skipping",
+					"pack", mapper.GetName(),
+					"extends", parent,
+					"chain", chain,
+					"error", err)
+				continue
+			}
+			add(parentMapper, parent, chain)
+		}
+	}
+
+	for _, root := range roots {
+		add(root, "", nil)
+	}
+	return layers
+}
+
+// validateExtends checks a manifest's "extends" list.
+func validateExtends(extends []string) error {
+	if len(extends) > MaxStackLayers {
+		return fmt.Errorf("soundpack extends %d packs, limit is %d", len(extends), MaxStackLayers)
+	}
+	for i, name := range extends {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("soundpack extends[%d] is empty", i)
+		}
+	}
+	return nil
+}
+
+// anyVariationPresent reports whether any of the variations exists or is a
+// say: template.
+func anyVariationPresent(variations MappingValue) bool {
+	for _, file := range variations.Files {
+		if IsSpeechTemplate(file) || fileExists(file) {
+			return true
+		}
+	}
+	return false
+}
+
+// layerHasSound reports whether layer resolves relativePath to an existing
+// file or a say: template.
+func layerHasSound(layer PathMapper, relativePath string) bool {
+	candidates, err := layer.MapPath(relativePath)
+	if err != nil {
+		return false
+	}
+	for _, candidate := range candidates {
+		if IsSpeechTemplate(candidate) {
+			return true
+		}
+		if _, err := os.Stat(candidate); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package soundpack
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestStackMapper_UpperLayerWins(t *testing.T) {
+	upperDir := t.TempDir()
+	lowerDir := t.TempDir()
+	upperGit := touch(t, filepath.Join(upperDir, "success", "git-success.wav"))
+	touch(t, filepath.Join(lowerDir, "success", "git-success.wav"))
+	lowerDefault := touch(t, filepath.Join(lowerDir, "default.wav"))
+
+	stack := NewStackMapper([]PathMapper{
+		NewDirectoryMapper("git-sounds", []string{upperDir}),
+		NewDirectoryMapper("base", []string{lowerDir}),
+	})
+	if stack.GetName() != "git-sounds+base" || stack.GetType() != "stack" {
+		t.Errorf("stack name/type = %q/%q", stack.GetName(), stack.GetType())
+	}
+
+	resolver := NewSoundpackResolver(stack)
+	if got, err := resolver.ResolveSound("success/git-success.wav"); err != nil || got != upperGit {
+		t.Errorf("overlay key resolved to %q, %v; want %s", got, err, upperGit)
+	}
+	if got, err := resolver.ResolveSound("default.wav"); err != nil || got != lowerDefault {
+		t.Errorf("base key resolved to %q, %v; want %s", got, err, lowerDefault)
+	}
+
+	// Each key walks the whole stack before the chain falls back, so the
+	// base pack's specific sound beats the overlay's generic one.
+	touch(t, filepath.Join(upperDir, "success", "success.wav"))
+	lowerBash := touch(t, filepath.Join(lowerDir, "success", "bash-success.wav"))
+	got, err := resolver.ResolveSoundWithFallback([]string{"success/bash-success.wav", "success/success.wav"})
+	if err != nil || got != lowerBash {
+		t.Errorf("fallback resolved to %q, %v; want %s", got, err, lowerBash)
+	}
+}
+
+func TestStackMapper_SingleFileHidesLowerVariations(t *testing.T) {
+	upperDir := t.TempDir()
+	lowerDir := t.TempDir()
+	upper := touch(t, filepath.Join(upperDir, "error", "error.wav"))
+	touch(t, filepath.Join(lowerDir, "error", "error.1.wav"))
+	touch(t, filepath.Join(lowerDir, "error", "error.2.wav"))
+
+	stack := NewStackMapper([]PathMapper{
+		NewDirectoryMapper("upper", []string{upperDir}),
+		NewDirectoryMapper("lower", []string{lowerDir}),
+	})
+	if _, ok := stack.(VariationMapper).MapVariations("error/error.wav"); ok {
+		t.Error("the upper layer's single file should hide the lower variations")
+	}
+	resolver := NewSoundpackResolver(stack)
+	for i := 0; i < 5; i++ {
+		if got, err := resolver.ResolveSound("error/error.wav"); err != nil || got != upper {
+			t.Fatalf("ResolveSound = %q, %v; want %s", got, err, upper)
+		}
+	}
+
+	// Without the upper file the lower pack's variations apply.
+	stack = NewStackMapper([]PathMapper{
+		NewDirectoryMapper("upper", []string{t.TempDir()}),
+		NewDirectoryMapper("lower", []string{lowerDir}),
+	})
+	if v, ok := stack.(VariationMapper).MapVariations("error/error.wav"); !ok || len(v.Files) != 2 {
+		t.Errorf("MapVariations = %v, %v; want the two lower variations", v, ok)
+	}
+}
+
+func TestExpandStack_FollowsExtends(t *testing.T) {
+	dir := t.TempDir()
+	touch(t, filepath.Join(dir, "a.wav"))
+	load := func(data string) PathMapper {
+		mapper, err := LoadJSONSoundpackFromBytes([]byte(data), dir)
+		if err != nil {
+			t.Fatalf("load %s: %v", data, err)
+		}
+		return mapper
+	}
+	packs := map[string]PathMapper{
+		"base":   load(`{"name":"base","mappings":{"default.wav":"a.wav"}}`),
+		"middle": load(`{"name":"middle","extends":["base","overlay"],"mappings":{"default.wav":"a.wav"}}`),
+	}
+	loader := func(name, baseDir string) (PathMapper, error) {
+		if baseDir != dir {
+			t.Errorf("%s loaded against %q, want the manifest directory %q", name, baseDir, dir)
+		}
+		if mapper, ok := packs[name]; ok {
+			return mapper, nil
+		}
+		return nil, fmt.Errorf("soundpack %q not found", name)
+	}
+
+	overlay := load(`{"name":"overlay","extends":["middle","missing","base"],"mappings":{"default.wav":"a.wav"}}`)
+	other := load(`{"name":"other","mappings":{"default.wav":"a.wav"}}`)
+
+	var names []string
+	for _, layer := range ExpandStack([]PathMapper{overlay, other}, loader) {
+		names = append(names, layer.GetName())
+	}
+	if want := []string{"overlay", "middle", "base", "other"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("stack = %v, want %v", names, want)
+	}
+}
+
+func TestJSONSoundpack_Extends(t *testing.T) {
+	dir := t.TempDir()
+	touch(t, filepath.Join(dir, "a.wav"))
+
+	mapper, err := LoadJSONSoundpackFromBytes([]byte(`{"name":"git-sounds","extends":["default"],"mappings":{"default.wav":"a.wav"}}`), dir)
+	if err != nil {
+		t.Fatalf("LoadJSONSoundpackFromBytes: %v", err)
+	}
+	extending, ok := mapper.(ExtendingMapper)
+	if !ok || !reflect.DeepEqual(extending.Extends(), []string{"default"}) {
+		t.Errorf("Extends() = %v", extending)
+	}
+
+	if _, err := LoadJSONSoundpackFromBytes([]byte(`{"name":"bad","extends":[""],"mappings":{"default.wav":"a.wav"}}`), dir); err == nil {
+		t.Error("an empty extends entry should be rejected")
+	}
+}
@@ -0,0 +1,253 @@
+package soundpack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Variation modes for sound keys that map to several files.
+const (
+	VariationRandom     = "random"      // an independent (optionally weighted) pick every time
+	VariationRoundRobin = "round-robin" // each file in turn, in the order listed
+	VariationShuffle    = "shuffle"     // every file once in random order, then reshuffle
+)
+
+// GetVariationModes returns the accepted mapping mode values.
+func GetVariationModes() []string {
+	return []string{VariationRandom, VariationRoundRobin, VariationShuffle}
+}
+
+// MappingValue is one soundpack mapping: a single file, or several
+// interchangeable variations of the same sound. In JSON it is a string, a
+// list of files (picked at random), or an object:
+//
+//	"success/bash-success.wav": {
+//	  "files": ["bash-1.wav", "bash-2.wav", "bash-3.wav"],
+//	  "mode": "round-robin"
+//	}
+//
+// A single file without mode or weights is written back as a plain string,
+// so existing packs round-trip unchanged.
+type MappingValue struct {
+	Files   []string  `json:"files"`
+	Mode    string    `json:"mode,omitempty"`    // random (default), round-robin or shuffle
+	Weights []float64 `json:"weights,omitempty"` // relative odds per file for random
+}
+
+// SingleFile returns a mapping to one file.
+func SingleFile(path string) MappingValue {
+	return MappingValue{Files: []string{path}}
+}
+
+// UnmarshalJSON accepts a string, a list of strings, or an object.
+func (m *MappingValue) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case len(data) > 0 && data[0] == '"':
+		var file string
+		if err := json.Unmarshal(data, &file); err != nil {
+			return err
+		}
+		*m = SingleFile(file)
+		return nil
+	case len(data) > 0 && data[0] == '[':
+		var files []string
+		if err := json.Unmarshal(data, &files); err != nil {
+			return fmt.Errorf("mapping list must hold file names: %w", err)
+		}
+		*m = MappingValue{Files: files}
+		return nil
+	default:
+		// The alias drops these methods so the object decodes field by field.
+		type plain MappingValue
+		var v plain
+		if err := json.Unmarshal(data, &v); err != nil {
+			return fmt.Errorf("mapping must be a file, a list of files, or an object with files: %w", err)
+		}
+		*m = MappingValue(v)
+		return nil
+	}
+}
+
+// MarshalJSON writes a plain single file as a string and anything else as
+// a list or object.
+func (m MappingValue) MarshalJSON() ([]byte, error) {
+	if m.Mode == "" && len(m.Weights) == 0 {
+		if len(m.Files) == 1 {
+			return json.Marshal(m.Files[0])
+		}
+		if m.Files == nil {
+			return json.Marshal("")
+		}
+		return json.Marshal(m.Files)
+	}
+	type plain MappingValue
+	return json.Marshal(plain(m))
+}
+
+// IsEmpty reports whether the mapping names no file, like the "" values a
+// scaffolded pack starts with.
+func (m MappingValue) IsEmpty() bool {
+	for _, f := range m.Files {
+		if f != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// EffectiveMode returns the variation mode, random when unset.
+func (m MappingValue) EffectiveMode() string {
+	if m.Mode == "" {
+		return VariationRandom
+	}
+	return m.Mode
+}
+
+// String lists the mapping's files for logs and reports.
+func (m MappingValue) String() string {
+	return strings.Join(m.Files, ", ")
+}
+
+// Validate checks the parts of a mapping that do not depend on
+// where its files live.
+func (m MappingValue) Validate() error {
+	if len(m.Files) == 0 {
+		return fmt.Errorf("mapping lists no files")
+	}
+	if m.Mode != "" && m.Mode != VariationRandom && m.Mode != VariationRoundRobin && m.Mode != VariationShuffle {
+		return fmt.Errorf("unknown mapping mode %q, must be one of: %s", m.Mode, strings.Join(GetVariationModes(), ", "))
+	}
+	if len(m.Weights) == 0 {
+		return nil
+	}
+	if m.EffectiveMode() != VariationRandom {
+		return fmt.Errorf("weights apply only to random mappings, not %s", m.Mode)
+	}
+	if len(m.Weights) != len(m.Files) {
+		return fmt.Errorf("mapping has %d weights for %d files", len(m.Weights), len(m.Files))
+	}
+	var total float64
+	for _, w := range m.Weights {
+		if w < 0 {
+			return fmt.Errorf("mapping weights must be >= 0, got %g", w)
+		}
+		total += w
+	}
+	if total == 0 {
+		return fmt.Errorf("mapping weights must not all be zero")
+	}
+	return nil
+}
+
+// VariationMapper is implemented by mappers whose sound keys can map to
+// several interchangeable files. The resolver asks it before MapPath and
+// lets its Picker choose among the files that exist.
+type VariationMapper interface {
+	// MapVariations returns the variations of relativePath, or false when
+	// the key has at most one file.
+	MapVariations(relativePath string) (MappingValue, bool)
+}
+
+// MapVariations returns the files of a multi-file JSON mapping.
+func (j *JSONMapper) MapVariations(relativePath string) (MappingValue, bool) {
+	value, exists := j.mapping[relativePath]
+	if !exists || len(value.Files) < 2 {
+		return MappingValue{}, false
+	}
+	return value, true
+}
+
+// MapVariations finds numbered siblings of relativePath in the first base
+// path that has any: success/bash-success.wav gathers bash-success.wav
+// itself plus bash-success.1.wav, bash-success.2.wav and so on, in any
+// supported audio format, up to the first missing number. Directory
+// variations are picked at random.
+func (d *DirectoryMapper) MapVariations(relativePath string) (MappingValue, bool) {
+	for _, basePath := range d.basePaths {
+		files := numberedVariationPaths(basePath, relativePath)
+		if len(files) == 0 {
+			continue
+		}
+		if exact := filepath.Join(basePath, relativePath); fileExists(exact) {
+			files = append([]string{exact}, files...)
+		}
+		if len(files) < 2 {
+			return MappingValue{}, false
+		}
+		return MappingValue{Files: files}, true
+	}
+	return MappingValue{}, false
+}
+
+// numberedVariationPaths returns the existing stem.N.ext siblings of
+// relativePath under basePath, N counting up from 1.
+func numberedVariationPaths(basePath, relativePath string) []string {
+	ext := filepath.Ext(relativePath)
+	if !isDirectoryAudioExtension(ext) {
+		return nil
+	}
+	stem := strings.TrimSuffix(relativePath, ext)
+
+	var files []string
+	for n := 1; ; n++ {
+		found := ""
+		for _, candidateExt := range append([]string{ext}, directoryAudioExtensions...) {
+			candidate := filepath.Join(basePath, stem+"."+strconv.Itoa(n)+candidateExt)
+			if fileExists(candidate) {
+				found = candidate
+				break
+			}
+		}
+		if found == "" {
+			return files
+		}
+		files = append(files, found)
+	}
+}
+
+// numberedVariationPattern matches a numbered variation file name such as
+// bash-success.2.wav.
+var numberedVariationPattern = regexp.MustCompile(`^(.+)\.[0-9]+(\.[A-Za-z0-9]+)$`)
+
+// VariationBaseKey returns the sound key a numbered variation file belongs
+// to, keeping its extension: success/bash-success.2.wav belongs to
+// success/bash-success.wav. ok is false for ordinary file names.
+func VariationBaseKey(key string) (string, bool) {
+	dir, base := filepath.Split(filepath.ToSlash(key))
+	m := numberedVariationPattern.FindStringSubmatch(base)
+	if m == nil || !isDirectoryAudioExtension(m[2]) {
+		return "", false
+	}
+	return dir + m[1] + m[2], true
+}
+
+// IsAudioFile reports whether path has an extension of a format Claudio
+// decodes.
+func IsAudioFile(path string) bool {
+	return isDirectoryAudioExtension(filepath.Ext(path))
+}
+
+// isDirectoryAudioExtension reports whether ext is an audio extension
+// directory packs resolve.
+func isDirectoryAudioExtension(ext string) bool {
+	ext = strings.ToLower(ext)
+	for _, audioExt := range directoryAudioExtensions {
+		if ext == audioExt {
+			return true
+		}
+	}
+	return false
+}
+
+// fileExists reports whether path names an existing file.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,215 @@
+package soundpack
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// touch creates an empty file at path, making parent directories.
+func touch(t *testing.T, path string) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("fake audio"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMappingValue_JSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want MappingValue
+		out  string
+	}{
+		{`"a.wav"`, SingleFile("a.wav"), `"a.wav"`},
+		{`""`, SingleFile(""), `""`},
+		{`["a.wav","b.wav"]`, MappingValue{Files: []string{"a.wav", "b.wav"}}, `["a.wav","b.wav"]`},
+		{
+			`{"files":["a.wav","b.wav"],"mode":"round-robin"}`,
+			MappingValue{Files: []string{"a.wav", "b.wav"}, Mode: VariationRoundRobin},
+			`{"files":["a.wav","b.wav"],"mode":"round-robin"}`,
+		},
+		{
+			`{"files":["a.wav","b.wav"],"weights":[3,1]}`,
+			MappingValue{Files: []string{"a.wav", "b.wav"}, Weights: []float64{3, 1}},
+			`{"files":["a.wav","b.wav"],"weights":[3,1]}`,
+		},
+	}
+	for _, tt := range tests {
+		var got MappingValue
+		if err := json.Unmarshal([]byte(tt.in), &got); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", tt.in, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Unmarshal(%s) = %+v, want %+v", tt.in, got, tt.want)
+		}
+		out, err := json.Marshal(got)
+		if err != nil {
+			t.Fatalf("Marshal(%+v): %v", got, err)
+		}
+		if string(out) != tt.out {
+			t.Errorf("Marshal(%s) = %s, want %s", tt.in, out, tt.out)
+		}
+	}
+
+	var bad MappingValue
+	if err := json.Unmarshal([]byte(`42`), &bad); err == nil {
+		t.Error("a number is not a mapping")
+	}
+}
+
+func TestMappingValue_Validate(t *testing.T) {
+	tests := []struct {
+		value MappingValue
+		want  string
+	}{
+		{MappingValue{}, "no files"},
+		{MappingValue{Files: []string{"a", "b"}, Mode: "sometimes"}, "unknown mapping mode"},
+		{MappingValue{Files: []string{"a", "b"}, Weights: []float64{1}}, "1 weights for 2 files"},
+		{MappingValue{Files: []string{"a", "b"}, Weights: []float64{1, -1}}, ">= 0"},
+		{MappingValue{Files: []string{"a", "b"}, Weights: []float64{0, 0}}, "all be zero"},
+		{MappingValue{Files: []string{"a", "b"}, Mode: VariationShuffle, Weights: []float64{1, 2}}, "only to random"},
+	}
+	for _, tt := range tests {
+		err := tt.value.Validate()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Validate(%+v) = %v, want error containing %q", tt.value, err, tt.want)
+		}
+	}
+	if err := (MappingValue{Files: []string{"a", "b"}, Weights: []float64{0, 1}}).Validate(); err != nil {
+		t.Errorf("valid weighted mapping rejected: %v", err)
+	}
+}
+
+func TestJSONSoundpack_RoundRobinVariations(t *testing.T) {
+	dir := t.TempDir()
+	one := touch(t, filepath.Join(dir, "bash-1.wav"))
+	two := touch(t, filepath.Join(dir, "bash-2.wav"))
+	three := touch(t, filepath.Join(dir, "bash-3.wav"))
+	pack := `{"name":"rr","mappings":{
+		"success/bash-success.wav":{"files":["bash-1.wav","bash-2.wav","bash-3.wav"],"mode":"round-robin"},
+		"default.wav":"bash-1.wav"}}`
+
+	mapper, err := LoadJSONSoundpackFromBytes([]byte(pack), dir)
+	if err != nil {
+		t.Fatalf("LoadJSONSoundpackFromBytes: %v", err)
+	}
+	resolver := NewSoundpackResolver(mapper)
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		path, err := resolver.ResolveSound("success/bash-success.wav")
+		if err != nil {
+			t.Fatalf("ResolveSound: %v", err)
+		}
+		got = append(got, path)
+	}
+	if want := []string{one, two, three, one}; !reflect.DeepEqual(got, want) {
+		t.Errorf("round-robin order = %v, want %v", got, want)
+	}
+
+	// A missing variation is skipped rather than failing the key.
+	if err := os.Remove(two); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		path, err := resolver.ResolveSound("success/bash-success.wav")
+		if err != nil || path == two {
+			t.Fatalf("ResolveSound = %q, %v; want an existing variation", path, err)
+		}
+	}
+}
+
+func TestJSONSoundpack_RejectsInvalidVariations(t *testing.T) {
+	dir := t.TempDir()
+	touch(t, filepath.Join(dir, "a.wav"))
+	for _, mapping := range []string{
+		`{"files":["a.wav","../b.wav"]}`,
+		`{"files":["a.wav","a.wav"],"mode":"bogus"}`,
+	} {
+		pack := `{"name":"bad","mappings":{"default.wav":` + mapping + `}}`
+		if _, err := LoadJSONSoundpackFromBytes([]byte(pack), dir); err == nil {
+			t.Errorf("pack with mapping %s should be rejected", mapping)
+		}
+	}
+}
+
+func TestDirectoryMapper_NumberedVariations(t *testing.T) {
+	dir := t.TempDir()
+	base := touch(t, filepath.Join(dir, "success", "bash-success.wav"))
+	first := touch(t, filepath.Join(dir, "success", "bash-success.1.wav"))
+	second := touch(t, filepath.Join(dir, "success", "bash-success.2.mp3"))
+	touch(t, filepath.Join(dir, "success", "bash-success.4.wav")) // after a gap: ignored
+
+	mapper := NewDirectoryMapper("dir", []string{dir})
+	variations, ok := mapper.(VariationMapper).MapVariations("success/bash-success.wav")
+	if !ok {
+		t.Fatal("expected numbered siblings to form variations")
+	}
+	if want := []string{base, first, second}; !reflect.DeepEqual(variations.Files, want) {
+		t.Errorf("variations = %v, want %v", variations.Files, want)
+	}
+
+	candidates, err := mapper.MapPath("success/bash-success.wav")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{first, second} {
+		found := false
+		for _, c := range candidates {
+			found = found || c == want
+		}
+		if !found {
+			t.Errorf("MapPath candidates %v should include %s", candidates, want)
+		}
+	}
+
+	resolver := NewSoundpackResolver(mapper)
+	seen := map[string]bool{}
+	for i := 0; i < 200 && len(seen) < 3; i++ {
+		path, err := resolver.ResolveSound("success/bash-success.wav")
+		if err != nil {
+			t.Fatalf("ResolveSound: %v", err)
+		}
+		seen[path] = true
+	}
+	if len(seen) != 3 {
+		t.Errorf("random picks should reach every variation, got %v", seen)
+	}
+}
+
+func TestDirectoryMapper_OnlyNumberedVariations(t *testing.T) {
+	dir := t.TempDir()
+	only := touch(t, filepath.Join(dir, "loading", "bash-start.1.wav"))
+
+	resolver := NewSoundpackResolver(NewDirectoryMapper("dir", []string{dir}))
+	path, err := resolver.ResolveSound("loading/bash-start.wav")
+	if err != nil || path != only {
+		t.Errorf("ResolveSound = %q, %v; want the lone numbered file %s", path, err, only)
+	}
+}
+
+func TestVariationBaseKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+		ok   bool
+	}{
+		{"success/bash-success.2.wav", "success/bash-success.wav", true},
+		{"default.10.mp3", "default.mp3", true},
+		{"success/bash-success.wav", "", false},
+		{"notes.2.txt", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := VariationBaseKey(tt.key)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("VariationBaseKey(%q) = %q, %v; want %q, %v", tt.key, got, ok, tt.want, tt.ok)
+		}
+	}
+}
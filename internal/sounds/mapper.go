@@ -3,6 +3,7 @@ package sounds
 import (
 	"context"
 	"log/slog"
+	"regexp"
 	"strings"
 
 	"claudio.click/internal/hooks"
@@ -21,6 +22,13 @@ import (
 type SoundMapper struct {
 	resolver soundpack.SoundpackResolver // resolver for path existence checks (may be nil)
 	observer soundpack.PathObserver      // optional per-candidate observer
+	route    *route                      // optional user routing rule candidates
+}
+
+// route holds the sound keys a user routing rule selected for one event.
+type route struct {
+	candidates []string
+	exclusive  bool
 }
 
 // MapperOption configures a SoundMapper at construction.
@@ -36,11 +44,25 @@ func WithObserver(obs soundpack.PathObserver) MapperOption {
 	}
 }
 
+// WithRoute prepends candidates chosen by a user routing rule to the
+// built-in fallback chain. With exclusive set the built-in chain is dropped
+// and only the candidates are tried. An empty candidate list is ignored.
+func WithRoute(candidates []string, exclusive bool) MapperOption {
+	return func(m *SoundMapper) {
+		if len(candidates) == 0 {
+			m.route = nil
+			return
+		}
+		m.route = &route{candidates: candidates, exclusive: exclusive}
+	}
+}
+
 // Chain type constants for sound mapping strategy
 const (
 	ChainTypeEnhanced = "enhanced" // PreToolUse: 9-level with command-only sounds
 	ChainTypePostTool = "posttool" // PostToolUse: 6-level, skip command-only sounds
 	ChainTypeSimple   = "simple"   // Simple events: 4-level event-specific fallback
+	ChainTypeRouted   = "routed"   // User routing rule candidates, then the built-in chain
 )
 
 // SoundMappingResult contains the mapping result and metadata
@@ -49,7 +71,7 @@ type SoundMappingResult struct {
 	FallbackLevel int      // Which level was selected (1-6, 1-based)
 	TotalPaths    int      // Total number of paths generated
 	AllPaths      []string // All paths in fallback order
-	ChainType     string   // Type of fallback chain used: "enhanced", "posttool", "simple"
+	ChainType     string   // Type of fallback chain used: "enhanced", "posttool", "simple", "routed"
 }
 
 // NewSoundMapper creates a new sound mapper with no resolver (path-existence
@@ -96,6 +118,9 @@ func (m *SoundMapper) MapSound(ctx context.Context, eventCtx *hooks.EventContext
 			ChainType:     ChainTypeSimple,
 		}
 	}
+	if m.route != nil {
+		return m.mapRoutedSound(ctx, eventCtx)
+	}
 	if eventCtx.Category == hooks.Silent {
 		slog.Debug("silent context provided to sound mapper")
 		return nil
@@ -105,6 +130,34 @@ func (m *SoundMapper) MapSound(ctx context.Context, eventCtx *hooks.EventContext
 	chainType := m.determineChainType(eventCtx)
 	slog.Debug("determined fallback chain type", "chain_type", chainType, "category", eventCtx.Category.String())
 
+	return m.mapBuiltinSound(ctx, eventCtx, chainType)
+}
+
+// mapRoutedSound resolves a routing rule's candidates ahead of the built-in
+// chain. The built-in chain is built by a resolver-less copy of the mapper
+// so its paths are only resolved (and observed) once, as part of the
+// combined chain. Silent events have no built-in chain, so a rule that
+// matches one is always exclusive.
+func (m *SoundMapper) mapRoutedSound(ctx context.Context, eventCtx *hooks.EventContext) *SoundMappingResult {
+	paths := append([]string(nil), m.route.candidates...)
+	if !m.route.exclusive && eventCtx.Category != hooks.Silent {
+		// The agent is cleared here so its prefixes are added once, over
+		// the combined chain.
+		builtin := &SoundMapper{}
+		builtinCtx := *eventCtx
+		builtinCtx.Agent = ""
+		result := builtin.mapBuiltinSound(ctx, &builtinCtx, builtin.determineChainType(&builtinCtx))
+		paths = append(paths, result.AllPaths...)
+	}
+	slog.Debug("mapping sound using routing rule candidates",
+		"candidates", m.route.candidates,
+		"exclusive", m.route.exclusive,
+		"total_paths", len(paths))
+	return m.finalizeResult(ctx, eventCtx.Agent, paths, ChainTypeRouted)
+}
+
+// mapBuiltinSound builds and resolves the built-in chain of chainType.
+func (m *SoundMapper) mapBuiltinSound(ctx context.Context, eventCtx *hooks.EventContext, chainType string) *SoundMappingResult {
 	// Route to appropriate mapping method based on chain type
 	switch chainType {
 	case ChainTypeEnhanced:
@@ -162,6 +215,13 @@ func (m *SoundMapper) mapEnhancedSound(ctx context.Context, eventCtx *hooks.Even
 	command, subcommand := m.extractCommandFromHint(eventCtx.SoundHint, eventCtx.ToolName)
 	suffix := m.extractSuffixFromOperation(eventCtx.Operation)
 
+	// File-type levels come first for file tools (e.g., "edit-go.wav",
+	// then the extension group "edit-tests.wav").
+	paths = append(paths, m.fileTypePaths(categoryStr, eventCtx)...)
+
+	// MCP levels: server + tool, then server (e.g., "mcp-github-start.wav")
+	paths = append(paths, m.mcpPaths(categoryStr, eventCtx, suffix)...)
+
 	// Level 1: Exact hint match
 	if eventCtx.SoundHint != "" {
 		hintPath := categoryStr + "/" + normalizeName(eventCtx.SoundHint) + ".wav"
@@ -228,9 +288,54 @@ func (m *SoundMapper) mapEnhancedSound(ctx context.Context, eventCtx *hooks.Even
 		paths = []string{"default.wav"}
 	}
 
-	return m.finalizeResult(ctx, paths, ChainTypeEnhanced)
+	return m.finalizeResult(ctx, eventCtx.Agent, paths, ChainTypeEnhanced)
 }
 
+// fileTypePaths returns the file-type levels for a tool that operates on a
+// file: tool + extension ("loading/edit-go.wav"), then tool + extension
+// group ("loading/edit-tests.wav"). Nil when the event names no file.
+func (m *SoundMapper) fileTypePaths(categoryStr string, eventCtx *hooks.EventContext) []string {
+	if eventCtx.ToolName == "" || eventCtx.OriginalTool != "" {
+		return nil
+	}
+	var paths []string
+	if fileTypePattern.MatchString(eventCtx.FileType) {
+		typePath := m.buildPath(categoryStr, eventCtx.ToolName+"-"+eventCtx.FileType)
+		paths = append(paths, typePath)
+		slog.Debug("added file-type path (tool with extension)", "path", typePath)
+	}
+	if eventCtx.FileGroup != "" {
+		groupPath := m.buildPath(categoryStr, eventCtx.ToolName+"-"+eventCtx.FileGroup)
+		paths = append(paths, groupPath)
+		slog.Debug("added file-type path (tool with file group)", "path", groupPath)
+	}
+	return paths
+}
+
+// mcpPaths returns the MCP levels for a call to an MCP server: server +
+// tool + suffix ("loading/mcp-github-create-pull-request-start.wav"), then
+// server + suffix ("loading/mcp-github-start.wav"). They sit ahead of the
+// generic "mcp-start" hint so each server can sound different.
+func (m *SoundMapper) mcpPaths(categoryStr string, eventCtx *hooks.EventContext, suffix string) []string {
+	if eventCtx.ToolName != "mcp" || eventCtx.MCPServer == "" || suffix == "" {
+		return nil
+	}
+	var paths []string
+	if eventCtx.MCPTool != "" {
+		toolPath := m.buildPath(categoryStr, "mcp-"+eventCtx.MCPServer+"-"+eventCtx.MCPTool+"-"+suffix)
+		paths = append(paths, toolPath)
+		slog.Debug("added mcp path (server and tool)", "path", toolPath)
+	}
+	serverPath := m.buildPath(categoryStr, "mcp-"+eventCtx.MCPServer+"-"+suffix)
+	paths = append(paths, serverPath)
+	slog.Debug("added mcp path (server)", "path", serverPath)
+	return paths
+}
+
+// fileTypePattern accepts extensions that make a sensible key segment;
+// FileType can hold a path fragment when a directory name contains a dot.
+var fileTypePattern = regexp.MustCompile(`^[a-z0-9]{1,10}$`)
+
 // buildPath creates a standardized sound path with proper normalization
 func (m *SoundMapper) buildPath(category, name string) string {
 	if category == "" {
@@ -391,7 +496,37 @@ func dedupPreserveOrder(paths []string) []string {
 	return deduped
 }
 
-// finalizeResult dedups the chain, resolves the winning candidate via
+// agentNamePattern limits agent prefixes to a single plain path segment, so
+// an odd --hook-agent value can never steer lookups outside the pack.
+var agentNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// withAgentPaths puts an agent-specific variant ahead of each chain path
+// ("codex/completion/agent-complete.wav" before
+// "completion/agent-complete.wav"). Interleaving keeps specificity the
+// primary order: the agent only decides between variants of one key, so a
+// pack's "codex/default.wav" never shadows a specific shared sound. A
+// routed say: template is speech, not a path, and passes through as is.
+func withAgentPaths(agent string, paths []string) []string {
+	if agent == "" {
+		return paths
+	}
+	if !agentNamePattern.MatchString(agent) {
+		slog.Debug("ignoring agent with unusable name for sound prefixes", "agent", agent)
+		return paths
+	}
+	prefixed := make([]string, 0, 2*len(paths))
+	for _, p := range paths {
+		if soundpack.IsSpeechTemplate(p) {
+			prefixed = append(prefixed, p)
+			continue
+		}
+		prefixed = append(prefixed, agent+"/"+p, p)
+	}
+	return prefixed
+}
+
+// finalizeResult adds the agent's variants to the chain (see
+// withAgentPaths), dedups it, resolves the winning candidate via
 // soundpack.ResolveSoundWithFallback (wiring the observer through), and
 // returns the SoundMappingResult with FallbackLevel set to the 1-based
 // winner index (or len(paths) when no candidate existed).
@@ -399,9 +534,9 @@ func dedupPreserveOrder(paths []string) []string {
 // If the mapper has no resolver, returns level 1 unchecked — callers without
 // a resolver get the first path back as the selection. No observation fires
 // in that path (no candidate was actually inspected).
-func (m *SoundMapper) finalizeResult(ctx context.Context, paths []string, chainType string) *SoundMappingResult {
+func (m *SoundMapper) finalizeResult(ctx context.Context, agent string, paths []string, chainType string) *SoundMappingResult {
 	_ = ctx // reserved for future cancellable resolution
-	paths = dedupPreserveOrder(paths)
+	paths = dedupPreserveOrder(withAgentPaths(agent, paths))
 
 	fallbackLevel := 1
 	selectedPath := paths[0]
@@ -464,10 +599,12 @@ func (m *SoundMapper) finalizeResult(ctx context.Context, paths []string, chainT
 	return result
 }
 
-// mapPostToolSound handles PostToolUse events with 6-level fallback (skip command-only sounds)
+// mapPostToolSound handles PostToolUse events with 6-level fallback (skip command-only sounds),
+// extended with outcome-specific levels when the event carries a specific tool outcome
 func (m *SoundMapper) mapPostToolSound(ctx context.Context, eventCtx *hooks.EventContext) *SoundMappingResult {
 	slog.Debug("mapping sound using PostToolUse 6-level fallback (skip command-only)",
 		"category", eventCtx.Category.String(),
+		"outcome", eventCtx.Outcome,
 		"tool_name", eventCtx.ToolName,
 		"original_tool", eventCtx.OriginalTool,
 		"sound_hint", eventCtx.SoundHint,
@@ -485,6 +622,24 @@ func (m *SoundMapper) mapPostToolSound(ctx context.Context, eventCtx *hooks.Even
 	// Determine suffix based on category (success/error context)
 	suffix := m.determineCategorySuffix(eventCtx.Category, eventCtx.Operation)
 
+	// File-type levels come first for file tools (e.g., "write-md.wav",
+	// then the extension group "write-docs.wav").
+	paths = append(paths, m.fileTypePaths(categoryStr, eventCtx)...)
+
+	// Outcome levels come first when the tool outcome is more specific
+	// than plain success/error: command + outcome (e.g., "go-test-failed.wav")
+	// ahead of the exact hint, then tool + outcome ("bash-warnings.wav") and
+	// the bare outcome ("test-failed.wav") ahead of the generic levels.
+	outcome := hooks.ToolOutcome{Class: eventCtx.Outcome}
+	if outcome.IsSpecific() && eventCtx.ToolName != "" {
+		outcomePath := m.buildPath(categoryStr, eventCtx.ToolName+"-"+eventCtx.Outcome)
+		paths = append(paths, outcomePath)
+		slog.Debug("added outcome path (command with outcome)", "path", outcomePath)
+	}
+
+	// MCP levels: server + tool, then server (e.g., "mcp-github-success.wav")
+	paths = append(paths, m.mcpPaths(categoryStr, eventCtx, suffix)...)
+
 	// Level 1: Exact hint match
 	if eventCtx.SoundHint != "" {
 		hintPath := m.buildPath(categoryStr, eventCtx.SoundHint)
@@ -492,6 +647,17 @@ func (m *SoundMapper) mapPostToolSound(ctx context.Context, eventCtx *hooks.Even
 		slog.Debug("added level 1 path (exact hint)", "path", hintPath)
 	}
 
+	if outcome.IsSpecific() {
+		if eventCtx.OriginalTool != "" {
+			origOutcomePath := m.buildPath(categoryStr, eventCtx.OriginalTool+"-"+eventCtx.Outcome)
+			paths = append(paths, origOutcomePath)
+			slog.Debug("added outcome path (original tool with outcome)", "path", origOutcomePath)
+		}
+		bareOutcomePath := m.buildPath(categoryStr, eventCtx.Outcome)
+		paths = append(paths, bareOutcomePath)
+		slog.Debug("added outcome path (outcome only)", "path", bareOutcomePath)
+	}
+
 	// Level 2: Command with suffix (e.g., "git-success.wav") - skip command-only for semantic accuracy
 	if command != "" && suffix != "" {
 		cmdSuffixPath := m.buildPath(categoryStr, command+"-"+suffix)
@@ -530,7 +696,7 @@ func (m *SoundMapper) mapPostToolSound(ctx context.Context, eventCtx *hooks.Even
 		paths = []string{"default.wav"}
 	}
 
-	return m.finalizeResult(ctx, paths, ChainTypePostTool)
+	return m.finalizeResult(ctx, eventCtx.Agent, paths, ChainTypePostTool)
 }
 
 // mapSimpleSound handles simple events with 4-level fallback chain
@@ -544,6 +710,14 @@ func (m *SoundMapper) mapSimpleSound(ctx context.Context, eventCtx *hooks.EventC
 	paths := make([]string, 0, 4)
 	categoryStr := eventCtx.Category.String()
 
+	// A long-running turn first tries the hint's "-long" variant
+	// (e.g., "completion/agent-complete-long.wav")
+	if eventCtx.LongTurn && eventCtx.SoundHint != "" {
+		longPath := m.buildPath(categoryStr, eventCtx.SoundHint+"-long")
+		paths = append(paths, longPath)
+		slog.Debug("added long-turn path", "path", longPath, "turn_duration", eventCtx.TurnDuration)
+	}
+
 	// Level 1: Specific hint match
 	if eventCtx.SoundHint != "" {
 		hintPath := m.buildPath(categoryStr, eventCtx.SoundHint)
@@ -577,7 +751,7 @@ func (m *SoundMapper) mapSimpleSound(ctx context.Context, eventCtx *hooks.EventC
 		paths = []string{"default.wav"}
 	}
 
-	return m.finalizeResult(ctx, paths, ChainTypeSimple)
+	return m.finalizeResult(ctx, eventCtx.Agent, paths, ChainTypeSimple)
 }
 
 // normalizeName converts a name to lowercase and replaces invalid characters
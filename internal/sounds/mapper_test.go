@@ -4,8 +4,10 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"claudio.click/internal/hooks"
 	"claudio.click/internal/soundpack"
@@ -130,6 +132,7 @@ func TestMapSoundEventSpecificFallback(t *testing.T) {
 				IsSuccess: true,
 			},
 			expectedPaths: []string{
+				"success/write-go.wav",      // File type: tool with extension
 				"success/file-saved.wav",    // Level 1: exact hint
 				"success/write-success.wav", // Level 2: command with suffix
 				"success/tool-complete.wav", // Level 3: operation-specific
@@ -1086,3 +1089,293 @@ func TestMapSound_NilObserver_NoPanic(t *testing.T) {
 		t.Error("SelectedPath should be set even without an observer")
 	}
 }
+
+func TestMapSound_WithRoute(t *testing.T) {
+	tempDir := t.TempDir()
+	routedFile := filepath.Join(tempDir, "routed.wav")
+	defaultFile := filepath.Join(tempDir, "default.wav")
+	require.NoError(t, os.WriteFile(routedFile, []byte("test"), 0644))
+	require.NoError(t, os.WriteFile(defaultFile, []byte("test"), 0644))
+
+	eventCtx := &hooks.EventContext{
+		Category:     hooks.Error,
+		ToolName:     "cargo",
+		OriginalTool: "Bash",
+		SoundHint:    "cargo-test-error",
+		Operation:    "tool-complete",
+	}
+
+	t.Run("routed candidate wins", func(t *testing.T) {
+		resolver := newTestResolver(map[string]string{
+			"custom/cargo-broke.wav": routedFile,
+			"default.wav":            defaultFile,
+		})
+		mapper := NewSoundMapperWithResolver(resolver, WithRoute([]string{"custom/missing.wav", "custom/cargo-broke.wav"}, false))
+
+		result := mapper.MapSound(context.Background(), eventCtx)
+		require.Equal(t, ChainTypeRouted, result.ChainType)
+		require.Equal(t, "custom/cargo-broke.wav", result.SelectedPath)
+		require.Equal(t, 2, result.FallbackLevel)
+		require.Equal(t, "error/cargo-test-error.wav", result.AllPaths[2], "built-in chain follows the candidates")
+		require.Equal(t, "default.wav", result.AllPaths[len(result.AllPaths)-1])
+	})
+
+	t.Run("missing candidates fall back to built-in chain", func(t *testing.T) {
+		resolver := newTestResolver(map[string]string{"default.wav": defaultFile})
+		mapper := NewSoundMapperWithResolver(resolver, WithRoute([]string{"custom/missing.wav"}, false))
+
+		result := mapper.MapSound(context.Background(), eventCtx)
+		require.Equal(t, "default.wav", result.SelectedPath)
+	})
+
+	t.Run("exclusive drops built-in chain", func(t *testing.T) {
+		mapper := NewSoundMapperWithResolver(nil, WithRoute([]string{"custom/a.wav", "custom/b.wav"}, true))
+
+		result := mapper.MapSound(context.Background(), eventCtx)
+		require.Equal(t, []string{"custom/a.wav", "custom/b.wav"}, result.AllPaths)
+	})
+
+	t.Run("silent event becomes audible", func(t *testing.T) {
+		mapper := NewSoundMapper()
+		WithRoute([]string{"system/model.wav"}, false)(mapper)
+
+		result := mapper.MapSound(context.Background(), &hooks.EventContext{Category: hooks.Silent})
+		require.NotNil(t, result)
+		require.Equal(t, []string{"system/model.wav"}, result.AllPaths)
+	})
+
+	t.Run("empty route is ignored", func(t *testing.T) {
+		mapper := NewSoundMapperWithResolver(nil, WithRoute(nil, true))
+
+		result := mapper.MapSound(context.Background(), eventCtx)
+		require.Equal(t, ChainTypePostTool, result.ChainType)
+	})
+}
+
+func TestPostToolUse_OutcomeLevels(t *testing.T) {
+	mapper := NewSoundMapper()
+
+	result := mapper.MapSound(context.Background(), &hooks.EventContext{
+		Category:     hooks.Error,
+		ToolName:     "go",
+		OriginalTool: "Bash",
+		SoundHint:    "go-test-error",
+		Operation:    "tool-complete",
+		HasError:     true,
+		Outcome:      hooks.OutcomeTestFailed,
+	})
+	require.Equal(t, []string{
+		"error/go-test-failed.wav",
+		"error/go-test-error.wav",
+		"error/bash-test-failed.wav",
+		"error/test-failed.wav",
+		"error/go-error.wav",
+		"error/bash-error.wav",
+		"error/tool-complete.wav",
+		"error/error.wav",
+		"default.wav",
+	}, result.AllPaths)
+
+	result = mapper.MapSound(context.Background(), &hooks.EventContext{
+		Category:     hooks.Success,
+		ToolName:     "git",
+		OriginalTool: "Bash",
+		SoundHint:    "git-push-success",
+		Operation:    "tool-complete",
+		IsSuccess:    true,
+		Outcome:      hooks.OutcomeWarnings,
+	})
+	require.Equal(t, "success/git-warnings.wav", result.AllPaths[0])
+	require.Contains(t, result.AllPaths, "success/bash-warnings.wav")
+
+	// Plain success and failure add no outcome levels.
+	result = mapper.MapSound(context.Background(), &hooks.EventContext{
+		Category:  hooks.Error,
+		ToolName:  "Edit",
+		SoundHint: "edit-error",
+		Operation: "tool-complete",
+		Outcome:   hooks.OutcomeFailed,
+	})
+	require.Equal(t, "error/edit-error.wav", result.AllPaths[0])
+}
+
+func TestMapSound_AgentPrefixedKeys(t *testing.T) {
+	mapper := NewSoundMapper()
+
+	result := mapper.MapSound(context.Background(), &hooks.EventContext{
+		Category:  hooks.Completion,
+		SoundHint: "agent-complete",
+		Operation: "stop",
+		Agent:     "codex",
+	})
+	require.Equal(t, "codex/completion/agent-complete.wav", result.AllPaths[0])
+	require.Equal(t, "completion/agent-complete.wav", result.AllPaths[1])
+	require.Equal(t, []string{"codex/default.wav", "default.wav"}, result.AllPaths[len(result.AllPaths)-2:])
+
+	// Routed chains are prefixed once, over the combined chain.
+	routed := NewSoundMapperWithResolver(nil, WithRoute([]string{"custom/done.wav"}, false))
+	result = routed.MapSound(context.Background(), &hooks.EventContext{
+		Category:  hooks.Completion,
+		SoundHint: "agent-complete",
+		Operation: "stop",
+		Agent:     "codex",
+	})
+	require.Equal(t, []string{"codex/custom/done.wav", "custom/done.wav", "codex/completion/agent-complete.wav"}, result.AllPaths[:3])
+	for _, p := range result.AllPaths {
+		require.NotContains(t, p, "codex/codex/")
+	}
+
+	// A routed say: template is speech, not a path, and is never prefixed.
+	spoken := NewSoundMapperWithResolver(nil, WithRoute([]string{"say:{project} is done"}, false))
+	result = spoken.MapSound(context.Background(), &hooks.EventContext{
+		Category:  hooks.Completion,
+		SoundHint: "agent-complete",
+		Operation: "stop",
+		Agent:     "codex",
+	})
+	require.Equal(t, []string{"say:{project} is done", "codex/completion/agent-complete.wav"}, result.AllPaths[:2])
+
+	// A name that is not a single path segment adds no prefixes.
+	result = mapper.MapSound(context.Background(), &hooks.EventContext{
+		Category:  hooks.Completion,
+		SoundHint: "agent-complete",
+		Operation: "stop",
+		Agent:     "../codex",
+	})
+	require.Equal(t, "completion/agent-complete.wav", result.AllPaths[0])
+}
+
+func TestMapSound_FileTypeLevels(t *testing.T) {
+	mapper := NewSoundMapper()
+
+	pre := mapper.MapSound(context.Background(), &hooks.EventContext{
+		Category:  hooks.Loading,
+		ToolName:  "Edit",
+		SoundHint: "edit-start",
+		Operation: "tool-start",
+		FileType:  "go",
+		FileGroup: "tests",
+	})
+	wantPrefix := []string{"loading/edit-go.wav", "loading/edit-tests.wav", "loading/edit-start.wav"}
+	for i, want := range wantPrefix {
+		if pre.AllPaths[i] != want {
+			t.Errorf("PreToolUse path[%d] = %s, want %s (chain %v)", i, pre.AllPaths[i], want, pre.AllPaths)
+		}
+	}
+
+	post := mapper.MapSound(context.Background(), &hooks.EventContext{
+		Category:  hooks.Success,
+		ToolName:  "Write",
+		SoundHint: "write-success",
+		Operation: "tool-complete",
+		FileType:  "md",
+		FileGroup: "docs",
+		IsSuccess: true,
+	})
+	if post.AllPaths[0] != "success/write-md.wav" || post.AllPaths[1] != "success/write-docs.wav" {
+		t.Errorf("PostToolUse chain should start with file-type keys, got %v", post.AllPaths)
+	}
+
+	// A Bash command is not a file tool, and a path fragment is not a type.
+	bash := mapper.MapSound(context.Background(), &hooks.EventContext{
+		Category:     hooks.Loading,
+		ToolName:     "git",
+		OriginalTool: "Bash",
+		SoundHint:    "git-start",
+		Operation:    "tool-start",
+		FileGroup:    "code",
+	})
+	odd := mapper.MapSound(context.Background(), &hooks.EventContext{
+		Category:  hooks.Loading,
+		ToolName:  "Read",
+		SoundHint: "read-start",
+		Operation: "tool-start",
+		FileType:  "d/makefile",
+	})
+	for _, result := range []*SoundMappingResult{bash, odd} {
+		for _, p := range result.AllPaths {
+			if strings.Contains(p, "-code") || strings.Contains(p, "makefile") {
+				t.Errorf("unexpected file-type path %s in %v", p, result.AllPaths)
+			}
+		}
+	}
+}
+
+func TestMapSound_MCPServerLevels(t *testing.T) {
+	mapper := NewSoundMapper()
+
+	pre := mapper.MapSound(context.Background(), &hooks.EventContext{
+		Category:     hooks.Loading,
+		ToolName:     "mcp",
+		OriginalTool: "mcp__github__create_pull_request",
+		MCPServer:    "github",
+		MCPTool:      "create_pull_request",
+		SoundHint:    "mcp-start",
+		Operation:    "tool-start",
+	})
+	wantPrefix := []string{
+		"loading/mcp-github-create-pull-request-start.wav",
+		"loading/mcp-github-start.wav",
+		"loading/mcp-start.wav",
+	}
+	for i, want := range wantPrefix {
+		if pre.AllPaths[i] != want {
+			t.Errorf("PreToolUse path[%d] = %s, want %s (chain %v)", i, pre.AllPaths[i], want, pre.AllPaths)
+		}
+	}
+
+	post := mapper.MapSound(context.Background(), &hooks.EventContext{
+		Category:     hooks.Error,
+		ToolName:     "mcp",
+		OriginalTool: "mcp_github_create_pull_request",
+		MCPServer:    "github",
+		MCPTool:      "create_pull_request",
+		SoundHint:    "mcp-error",
+		Operation:    "tool-complete",
+		HasError:     true,
+	})
+	wantPrefix = []string{
+		"error/mcp-github-create-pull-request-error.wav",
+		"error/mcp-github-error.wav",
+		"error/mcp-error.wav",
+	}
+	for i, want := range wantPrefix {
+		if post.AllPaths[i] != want {
+			t.Errorf("PostToolUse path[%d] = %s, want %s (chain %v)", i, post.AllPaths[i], want, post.AllPaths)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, p := range post.AllPaths {
+		if seen[p] {
+			t.Errorf("duplicate path %s in chain %v", p, post.AllPaths)
+		}
+		seen[p] = true
+	}
+}
+
+func TestMapSound_LongTurn(t *testing.T) {
+	mapper := NewSoundMapper()
+
+	long := mapper.MapSound(context.Background(), &hooks.EventContext{
+		Category:     hooks.Completion,
+		SoundHint:    "agent-complete",
+		Operation:    "stop",
+		TurnDuration: 10 * time.Minute,
+		LongTurn:     true,
+	})
+	if long.AllPaths[0] != "completion/agent-complete-long.wav" || long.AllPaths[1] != "completion/agent-complete.wav" {
+		t.Errorf("long turn chain should start with the -long key, got %v", long.AllPaths)
+	}
+
+	short := mapper.MapSound(context.Background(), &hooks.EventContext{
+		Category:     hooks.Completion,
+		SoundHint:    "agent-complete",
+		Operation:    "stop",
+		TurnDuration: 10 * time.Second,
+	})
+	for _, p := range short.AllPaths {
+		if strings.HasSuffix(p, "-long.wav") {
+			t.Errorf("short turn chain should not try %s", p)
+		}
+	}
+}
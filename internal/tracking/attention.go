@@ -0,0 +1,52 @@
+package tracking
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MarkSessionActive records that sessionID produced a hook event at now,
+// which answers any attention request made before it.
+func MarkSessionActive(ctx context.Context, db *sql.DB, sessionID string, now time.Time) error {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO session_attention (session_id, active_at) VALUES (?, ?)
+		ON CONFLICT(session_id) DO UPDATE SET active_at = excluded.active_at`,
+		sessionID, now.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("mark session active: %w", err)
+	}
+	return nil
+}
+
+// MarkAttentionRequested records that sessionID started waiting on the
+// user at now. A later request supersedes this one.
+func MarkAttentionRequested(ctx context.Context, db *sql.DB, sessionID string, now time.Time) error {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO session_attention (session_id, attention_at) VALUES (?, ?)
+		ON CONFLICT(session_id) DO UPDATE SET attention_at = excluded.attention_at`,
+		sessionID, now.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("mark attention requested: %w", err)
+	}
+	return nil
+}
+
+// AttentionPending reports whether the attention request sessionID made
+// at since is still unanswered: no activity has followed it and no newer
+// request has replaced it.
+func AttentionPending(ctx context.Context, db *sql.DB, sessionID string, since time.Time) (bool, error) {
+	var activeAt, attentionAt int64
+	err := db.QueryRowContext(ctx,
+		`SELECT active_at, attention_at FROM session_attention WHERE session_id = ?`,
+		sessionID).Scan(&activeAt, &attentionAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("read session attention: %w", err)
+	}
+	return attentionAt == since.UnixMilli() && activeAt < attentionAt, nil
+}
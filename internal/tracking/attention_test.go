@@ -0,0 +1,59 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAttentionPending(t *testing.T) {
+	db, err := NewDatabase(":memory:")
+	if err != nil {
+		t.Fatalf("NewDatabase: %v", err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+	asked := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	pending := func(since time.Time) bool {
+		t.Helper()
+		ok, err := AttentionPending(ctx, db, "s1", since)
+		if err != nil {
+			t.Fatalf("AttentionPending: %v", err)
+		}
+		return ok
+	}
+
+	if pending(asked) {
+		t.Error("a session with no request should not be pending")
+	}
+
+	if err := MarkSessionActive(ctx, db, "s1", asked.Add(-time.Minute)); err != nil {
+		t.Fatalf("MarkSessionActive: %v", err)
+	}
+	if err := MarkAttentionRequested(ctx, db, "s1", asked); err != nil {
+		t.Fatalf("MarkAttentionRequested: %v", err)
+	}
+	if !pending(asked) {
+		t.Error("a request with no later activity should be pending")
+	}
+
+	// A newer request takes over; the older one's reminders should stop.
+	later := asked.Add(10 * time.Second)
+	if err := MarkAttentionRequested(ctx, db, "s1", later); err != nil {
+		t.Fatalf("MarkAttentionRequested: %v", err)
+	}
+	if pending(asked) {
+		t.Error("a superseded request should not be pending")
+	}
+	if !pending(later) {
+		t.Error("the newest request should be pending")
+	}
+
+	if err := MarkSessionActive(ctx, db, "s1", later.Add(time.Second)); err != nil {
+		t.Fatalf("MarkSessionActive: %v", err)
+	}
+	if pending(later) {
+		t.Error("activity after the request should answer it")
+	}
+}
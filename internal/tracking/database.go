@@ -110,6 +110,19 @@ CREATE TABLE IF NOT EXISTS path_lookups (
     UNIQUE(event_id, path)
 );
 
+-- Start of each session's unfinished turn (prompt to Stop)
+CREATE TABLE IF NOT EXISTS turns (
+    session_id TEXT    PRIMARY KEY,
+    started_at INTEGER NOT NULL
+);
+
+-- Last activity and latest unanswered attention request per session
+CREATE TABLE IF NOT EXISTS session_attention (
+    session_id   TEXT    PRIMARY KEY,
+    active_at    INTEGER NOT NULL DEFAULT 0,
+    attention_at INTEGER NOT NULL DEFAULT 0
+);
+
 -- Indexes for common queries
 CREATE INDEX IF NOT EXISTS idx_events_timestamp ON hook_events(timestamp DESC);
 CREATE INDEX IF NOT EXISTS idx_events_tool ON hook_events(tool_name);
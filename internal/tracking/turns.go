@@ -0,0 +1,43 @@
+package tracking
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RecordTurnStart notes that sessionID started a turn at now, replacing
+// any earlier turn that never finished (a crash or an interrupted
+// prompt leaves one behind).
+func RecordTurnStart(ctx context.Context, db *sql.DB, sessionID string, now time.Time) error {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO turns (session_id, started_at) VALUES (?, ?)
+		ON CONFLICT(session_id) DO UPDATE SET started_at = excluded.started_at`,
+		sessionID, now.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("record turn start: %w", err)
+	}
+	return nil
+}
+
+// FinishTurn ends sessionID's open turn at now and returns how long it
+// ran. ok is false when no start was recorded for the session.
+func FinishTurn(ctx context.Context, db *sql.DB, sessionID string, now time.Time) (time.Duration, bool, error) {
+	var startedAt int64
+	err := db.QueryRowContext(ctx,
+		`DELETE FROM turns WHERE session_id = ? RETURNING started_at`, sessionID).Scan(&startedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("finish turn: %w", err)
+	}
+
+	d := now.Sub(time.UnixMilli(startedAt))
+	if d < 0 {
+		d = 0 // clock stepped backwards
+	}
+	return d, true, nil
+}
@@ -0,0 +1,43 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTurnDuration(t *testing.T) {
+	db, err := NewDatabase(":memory:")
+	if err != nil {
+		t.Fatalf("NewDatabase: %v", err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	if _, ok, err := FinishTurn(ctx, db, "s1", start); err != nil || ok {
+		t.Fatalf("FinishTurn without a start = (ok=%v, err=%v), want (false, nil)", ok, err)
+	}
+
+	if err := RecordTurnStart(ctx, db, "s1", start); err != nil {
+		t.Fatalf("RecordTurnStart: %v", err)
+	}
+	// A second prompt before Stop restarts the turn.
+	if err := RecordTurnStart(ctx, db, "s1", start.Add(time.Minute)); err != nil {
+		t.Fatalf("RecordTurnStart again: %v", err)
+	}
+	if err := RecordTurnStart(ctx, db, "s2", start); err != nil {
+		t.Fatalf("RecordTurnStart s2: %v", err)
+	}
+
+	d, ok, err := FinishTurn(ctx, db, "s1", start.Add(4*time.Minute))
+	if err != nil || !ok || d != 3*time.Minute {
+		t.Fatalf("FinishTurn = (%v, %v, %v), want (3m0s, true, nil)", d, ok, err)
+	}
+	if _, ok, _ := FinishTurn(ctx, db, "s1", start.Add(5*time.Minute)); ok {
+		t.Error("a finished turn should not be finished twice")
+	}
+	if d, ok, _ := FinishTurn(ctx, db, "s2", start.Add(90*time.Second)); !ok || d != 90*time.Second {
+		t.Errorf("s2 turn = (%v, %v), want (1m30s, true)", d, ok)
+	}
+}